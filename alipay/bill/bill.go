@@ -0,0 +1,164 @@
+// Package bill 下载并解析支付宝对账单（DataBillDownloadUrlQuery 返回的 bill_download_url）
+//
+// 对账单为 ZIP 文件，内含 GBK 编码的 CSV：业务明细、业务明细(汇总)（bill_type = trade），
+// 账务明细、账务明细(汇总)（bill_type = signcustomer）。
+// CSV 以 # 开头的行为说明信息（账号、起止日期、合计等），可通过 Reader.Comments() 获取
+package bill
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/internal/billcsv"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+var bom = []byte("\ufeff") // UTF-8 BOM
+
+// Reader 流式账单读取器，逐行解析，读取完毕后自动关闭 ZIP 内的文件
+type Reader[R any] struct {
+	rc      io.Closer
+	comment *commentReader
+	csv     *csv.Reader
+	columns []*billcsv.Column
+	err     error
+}
+
+// NewReader 从 CSV 数据流创建读取器，R 为带 bill tag 的结构体
+// 数据为 GBK 编码时自动转为 UTF-8，带 BOM 或已是 UTF-8 时不转换
+func NewReader[R any](r io.Reader) (*Reader[R], error) {
+	br := bufio.NewReader(r)
+	src := io.Reader(br)
+	if head, _ := br.Peek(1024); !bytes.HasPrefix(head, bom) && !validUTF8(head) {
+		src = transform.NewReader(br, simplifiedchinese.GB18030.NewDecoder())
+	}
+	rd := &Reader[R]{comment: &commentReader{br: bufio.NewReader(src)}}
+	rd.csv = csv.NewReader(rd.comment)
+	rd.csv.FieldsPerRecord = -1
+	rd.csv.LazyQuotes = true
+	rd.csv.ReuseRecord = true
+
+	header, err := rd.csv.Read()
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: read header: %v", gopay.BillFormatErr, err)
+	}
+	columns, known := billcsv.Columns(reflect.TypeFor[R](), header, cleanField)
+	rd.columns = columns
+	// 识别的列不足一半时，视为账单类型不匹配
+	if known*2 < len(rd.columns) {
+		return nil, fmt.Errorf("[%w]: unknown header %v", gopay.BillFormatErr, header)
+	}
+	return rd, nil
+}
+
+// Next 读取下一行，读取完毕返回 io.EOF
+func (r *Reader[R]) Next() (record *R, err error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for {
+		row, err := r.csv.Read()
+		if err == io.EOF {
+			r.err = io.EOF
+			_ = r.Close()
+			return nil, r.err
+		}
+		if err != nil {
+			r.err = fmt.Errorf("[%w]: %v", gopay.BillFormatErr, err)
+			_ = r.Close()
+			return nil, r.err
+		}
+		if len(row) == 0 || len(row) == 1 && cleanField(row[0]) == gopay.NULL {
+			continue
+		}
+		record = new(R)
+		billcsv.Fill(reflect.ValueOf(record).Elem(), r.columns, row, cleanField)
+		return record, nil
+	}
+}
+
+// All 遍历全部行，遇到错误时返回 error 并结束
+func (r *Reader[R]) All() iter.Seq2[*R, error] {
+	return func(yield func(*R, error) bool) {
+		for {
+			record, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Comments 已读取的 # 说明行（不含 #），表头前的说明在创建后可用，末尾的合计等说明在 Next() 返回 io.EOF 后可用
+func (r *Reader[R]) Comments() []string {
+	return r.comment.lines
+}
+
+// Close 关闭读取器，未读取完毕时可提前调用
+func (r *Reader[R]) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	rc := r.rc
+	r.rc = nil
+	return rc.Close()
+}
+
+// commentReader 过滤 # 开头的说明行
+type commentReader struct {
+	br    *bufio.Reader
+	buf   []byte
+	lines []string
+}
+
+func (c *commentReader) Read(p []byte) (n int, err error) {
+	for len(c.buf) == 0 {
+		line, err := c.br.ReadBytes('\n')
+		line = bytes.TrimPrefix(line, bom)
+		if len(line) > 0 && line[0] == '#' {
+			if comment := strings.TrimSpace(string(line[1:])); comment != gopay.NULL {
+				c.lines = append(c.lines, comment)
+			}
+		} else {
+			c.buf = line
+		}
+		if err != nil {
+			if len(c.buf) == 0 {
+				return 0, err
+			}
+			break
+		}
+	}
+	n = copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// validUTF8 判断是否为 UTF-8，忽略末尾被截断的字符
+func validUTF8(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size <= 1 {
+			return !utf8.FullRune(b) && len(b) < utf8.UTFMax
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+// cleanField 去除字段首尾的空白及制表符（支付宝以 \t 防止数字被表格软件转换）
+func cleanField(s string) string {
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,222 @@
+package bill
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/w6xian/gopay"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const (
+	tradeCSV = `#支付宝业务明细查询
+#账号：[20881234567890120156]
+#起始日期：[2024年01月01日 00:00:00]   终止日期：[2024年01月02日 00:00:00]
+#-----------------------------------------业务明细列表----------------------------------------
+支付宝交易号,商户订单号,业务类型,商品名称,创建时间,完成时间,门店编号,门店名称,操作员,终端号,对方账户,订单金额（元）,商家实收（元）,支付宝红包（元）,集分宝（元）,支付宝优惠（元）,商家优惠（元）,券核销金额（元）,券名称,商家红包消费金额（元）,卡消费金额（元）,退款批次号/请求号,服务费（元）,分润（元）,备注,新增列
+2024010122001400001000000001	,GZ202401010001	,交易	,测试商品	,2024-01-01 10:00:00,2024-01-01 10:00:05,	,	,	,	,abc***@163.com	,0.01,0.01,0.00,0.00,0.00,0.00,0.00,	,0.00,0.00,	,0.00,0.00,	,x
+2024010122001400001000000001	,GZ202401010001	,退款	,测试商品	,2024-01-01 11:00:00,2024-01-01 11:00:01,	,	,	,	,abc***@163.com	,-0.01,-0.01,0.00,0.00,0.00,0.00,0.00,	,0.00,0.00,GZ202401010001R1	,0.00,0.00,	,
+#-----------------------------------------业务明细列表结束------------------------------------
+#交易合计：1笔，商家实收共：0.01元，商家优惠共：0.00元
+#退款合计：1笔，商家实收退款共：-0.01元，商家优惠退款共：0.00元
+#导出时间：[2024年01月02日 08:00:00]
+`
+	tradeSummaryCSV = `#支付宝业务汇总查询
+#账号：[20881234567890120156]
+#--------------------------------------------业务汇总列表----------------------------------------
+门店编号,门店名称,交易订单总笔数,退款订单总笔数,订单金额（元）,商家实收（元）,支付宝优惠（元）,商家优惠（元）,卡消费金额（元）,服务费（元）,分润（元）,实收净额（元）
+	,	,1,1,0.00,0.00,0.00,0.00,0.00,0.00,0.00,0.00
+合计,	,1,1,0.00,0.00,0.00,0.00,0.00,0.00,0.00,0.00
+#-----------------------------------------业务汇总列表结束-----------------------------------------
+`
+	fundFlowCSV = `#支付宝账务明细查询
+#账号：[20881234567890120156]
+#-----------------------------------------账务明细列表----------------------------------------
+账务流水号,业务流水号,商户订单号,商品名称,发生时间,对方账号,收入金额（+元）,支出金额（-元）,账户余额（元）,交易渠道,业务类型,备注
+20240101001	,2024010122001400001000000001	,GZ202401010001	,测试商品	,2024-01-01 10:00:05	,abc***@163.com	,0.01,0.00,100.01,支付宝	,在线支付	,
+#-----------------------------------------账务明细列表结束------------------------------------
+`
+	fundFlowSummaryCSV = `#支付宝账务汇总查询
+业务类型,收入笔数,收入金额（+元）,支出笔数,支出金额（-元）,小计金额（元）
+在线支付,1,0.01,0,0.00,0.01
+合计,1,0.01,0,0.00,0.01
+`
+)
+
+func gbk(t *testing.T, s string) []byte {
+	t.Helper()
+	bs, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+// buildZip 构造支付宝对账单 ZIP，文件名与内容均为 GBK 编码
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: string(gbk(t, name)), Method: zip.Deflate, NonUTF8: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write(gbk(t, content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTradeBill(t *testing.T) {
+	bs := buildZip(t, map[string]string{
+		"20881234567890120156_20240101_业务明细.csv":     tradeCSV,
+		"20881234567890120156_20240101_业务明细(汇总).csv": tradeSummaryCSV,
+	})
+	file, err := NewFile(bytes.NewReader(bs), int64(len(bs)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if names := file.Names(); len(names) != 2 || !strings.HasSuffix(names[0], "业务明细(汇总).csv") {
+		t.Fatalf("names = %v", names)
+	}
+
+	r, err := file.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []*TradeRecord
+	for record, err := range r.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %d", len(records))
+	}
+	first := records[0]
+	if first.TradeNo != "2024010122001400001000000001" || first.OutTradeNo != "GZ202401010001" ||
+		first.BizType != "交易" || first.TotalAmount != "0.01" || first.Extra["新增列"] != "x" {
+		t.Fatalf("first = %+v", first)
+	}
+	if records[1].OutRequestNo != "GZ202401010001R1" || records[1].ReceiptAmount != "-0.01" {
+		t.Fatalf("second = %+v", records[1])
+	}
+	if comments := r.Comments(); len(comments) != 8 || comments[5] != "交易合计：1笔，商家实收共：0.01元，商家优惠共：0.00元" {
+		t.Fatalf("comments = %q", comments)
+	}
+
+	total, err := file.TradeTotal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !total.IsTotal() || total.TradeCount != "1" || total.RefundCount != "1" {
+		t.Fatalf("total = %+v", total)
+	}
+
+	if _, err = file.FundFlowRecords(); !errors.Is(err, gopay.BillFormatErr) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestFundFlowBill(t *testing.T) {
+	path := t.TempDir() + "/bill.zip"
+	bs := buildZip(t, map[string]string{
+		"20881234567890120156_20240101_账务明细.csv":     fundFlowCSV,
+		"20881234567890120156_20240101_账务明细(汇总).csv": fundFlowSummaryCSV,
+	})
+	if err := os.WriteFile(path, bs, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	r, err := file.FundFlowRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.FlowNo != "20240101001" || record.IncomeAmount != "0.01" || record.Balance != "100.01" || record.BizType != "在线支付" {
+		t.Fatalf("record = %+v", record)
+	}
+	total, err := file.FundFlowTotal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.IncomeCount != "1" || total.TotalAmount != "0.01" {
+		t.Fatalf("total = %+v", total)
+	}
+}
+
+func TestNewReaderUTF8(t *testing.T) {
+	r, err := NewReader[FundFlowSummary](strings.NewReader("\ufeff" + fundFlowSummaryCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.BizType != "在线支付" || s.IsTotal() {
+		t.Fatalf("summary = %+v", s)
+	}
+	if _, err = NewReader[TradeRecord](strings.NewReader(fundFlowSummaryCSV)); !errors.Is(err, gopay.BillFormatErr) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	bs := buildZip(t, map[string]string{"20881234567890120156_20240101_业务明细.csv": tradeCSV})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bill.zip" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(bs)
+	}))
+	defer ts.Close()
+
+	if _, err := Download(context.Background(), nil, ts.URL+"/none"); err == nil {
+		t.Fatal("expect error")
+	}
+	file, err := Download(context.Background(), nil, ts.URL+"/bill.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	temp := file.temp
+	r, err := file.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record, err := r.Next(); err != nil || record.OutTradeNo != "GZ202401010001" {
+		t.Fatalf("record = %+v, err = %v", record, err)
+	}
+	if err = r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(temp); !os.IsNotExist(err) {
+		t.Fatalf("temp file not removed: %v", err)
+	}
+}
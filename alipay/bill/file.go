@@ -0,0 +1,214 @@
+package bill
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/pkg/xhttp"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+const (
+	tradeName    = "业务明细"
+	fundFlowName = "账务明细"
+	summaryName  = "汇总"
+	totalRow     = "合计"
+)
+
+// File 对账单 ZIP 文件，使用完毕需调用 Close()
+type File struct {
+	zr     *zip.Reader
+	closer io.Closer
+	temp   string // Download() 下载的临时文件，Close() 时删除
+	files  map[string]*zip.File
+	names  []string
+}
+
+// Download 下载对账单 ZIP 文件，bill_download_url 有效期 30 秒
+// 文件写入临时目录而非内存，适用于较大的月账单；hc 为 nil 时使用 xhttp.NewClient()
+// 注意：下载不经过 xhttp.Client 的重试与拦截器
+func Download(ctx context.Context, hc *xhttp.Client, url string) (file *File, err error) {
+	if url == gopay.NULL {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "url")
+	}
+	if hc == nil {
+		hc = xhttp.NewClient()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := hc.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Request Error, StatusCode = %d", res.StatusCode)
+	}
+	tmp, err := os.CreateTemp("", "alipay_bill_*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = io.Copy(tmp, res.Body); err != nil {
+		_ = tmp.Close()
+		return nil, err
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, err
+	}
+	if file, err = Open(tmp.Name()); err != nil {
+		return nil, err
+	}
+	file.temp = tmp.Name()
+	return file, nil
+}
+
+// Open 打开本地对账单 ZIP 文件
+func Open(path string) (file *File, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if file, err = NewFile(f, fi.Size()); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	file.closer = f
+	return file, nil
+}
+
+// NewFile 从 io.ReaderAt 读取对账单 ZIP 文件
+func NewFile(r io.ReaderAt, size int64) (file *File, err error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: zip: %v", gopay.BillFormatErr, err)
+	}
+	file = &File{zr: zr, files: make(map[string]*zip.File)}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := f.Name
+		// 支付宝文件名为 GBK 编码
+		if !utf8.ValidString(name) {
+			if decoded, err := simplifiedchinese.GB18030.NewDecoder().String(name); err == nil {
+				name = decoded
+			}
+		}
+		file.files[name] = f
+		file.names = append(file.names, name)
+	}
+	sort.Strings(file.names)
+	return file, nil
+}
+
+// Names ZIP 内的文件名（已转为 UTF-8）
+func (f *File) Names() []string {
+	return f.names
+}
+
+// TradeRecords 业务明细读取器
+func (f *File) TradeRecords() (*Reader[TradeRecord], error) {
+	return openEntry[TradeRecord](f, tradeName, false)
+}
+
+// TradeSummaries 业务明细汇总读取器
+func (f *File) TradeSummaries() (*Reader[TradeSummary], error) {
+	return openEntry[TradeSummary](f, tradeName, true)
+}
+
+// FundFlowRecords 账务明细读取器
+func (f *File) FundFlowRecords() (*Reader[FundFlowRecord], error) {
+	return openEntry[FundFlowRecord](f, fundFlowName, false)
+}
+
+// FundFlowSummaries 账务明细汇总读取器
+func (f *File) FundFlowSummaries() (*Reader[FundFlowSummary], error) {
+	return openEntry[FundFlowSummary](f, fundFlowName, true)
+}
+
+// TradeTotal 业务明细汇总的合计行
+func (f *File) TradeTotal() (total *TradeSummary, err error) {
+	r, err := f.TradeSummaries()
+	if err != nil {
+		return nil, err
+	}
+	return findTotal(r, (*TradeSummary).IsTotal)
+}
+
+// FundFlowTotal 账务明细汇总的合计行
+func (f *File) FundFlowTotal() (total *FundFlowSummary, err error) {
+	r, err := f.FundFlowSummaries()
+	if err != nil {
+		return nil, err
+	}
+	return findTotal(r, (*FundFlowSummary).IsTotal)
+}
+
+// Close 关闭文件，删除下载的临时文件
+func (f *File) Close() (err error) {
+	if f.closer != nil {
+		err = f.closer.Close()
+	}
+	if f.temp != gopay.NULL {
+		if rmErr := os.Remove(f.temp); err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
+
+func openEntry[R any](f *File, kind string, summary bool) (*Reader[R], error) {
+	for _, name := range f.names {
+		if !strings.Contains(name, kind) || strings.Contains(name, summaryName) != summary {
+			continue
+		}
+		rc, err := f.files[name].Open()
+		if err != nil {
+			return nil, fmt.Errorf("[%w]: %s: %v", gopay.BillFormatErr, name, err)
+		}
+		r, err := NewReader[R](rc)
+		if err != nil {
+			_ = rc.Close()
+			return nil, err
+		}
+		r.rc = rc
+		return r, nil
+	}
+	if summary {
+		kind += "(" + summaryName + ")"
+	}
+	return nil, fmt.Errorf("[%w]: %s not found in %v", gopay.BillFormatErr, kind, f.names)
+}
+
+func findTotal[S any](r *Reader[S], isTotal func(*S) bool) (*S, error) {
+	defer r.Close()
+	for s, err := range r.All() {
+		if err != nil {
+			return nil, err
+		}
+		if isTotal(s) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("[%w]: %s row not found", gopay.BillFormatErr, totalRow)
+}
@@ -0,0 +1,89 @@
+package bill
+
+// 账单字段通过 bill tag 与表头对应，多个表头名称用 | 分隔（兼容全角、半角括号等不同版本表头）
+// 金额单位为元，保留账单原始字符串
+
+// TradeRecord 业务明细（bill_type = trade）
+type TradeRecord struct {
+	TradeNo          string            `bill:"支付宝交易号"`
+	OutTradeNo       string            `bill:"商户订单号"`
+	BizType          string            `bill:"业务类型"`
+	Subject          string            `bill:"商品名称"`
+	CreateTime       string            `bill:"创建时间"`
+	FinishTime       string            `bill:"完成时间"`
+	StoreId          string            `bill:"门店编号"`
+	StoreName        string            `bill:"门店名称"`
+	OperatorId       string            `bill:"操作员"`
+	TerminalId       string            `bill:"终端号"`
+	BuyerLogonId     string            `bill:"对方账户"`
+	TotalAmount      string            `bill:"订单金额（元）|订单金额(元)"`
+	ReceiptAmount    string            `bill:"商家实收（元）|商家实收(元)"`
+	RedPacketAmount  string            `bill:"支付宝红包（元）|支付宝红包(元)"`
+	PointAmount      string            `bill:"集分宝（元）|集分宝(元)"`
+	AlipayDiscount   string            `bill:"支付宝优惠（元）|支付宝优惠(元)"`
+	MerchantDiscount string            `bill:"商家优惠（元）|商家优惠(元)"`
+	VoucherAmount    string            `bill:"券核销金额（元）|券核销金额(元)"`
+	VoucherName      string            `bill:"券名称"`
+	MerchantRedPack  string            `bill:"商家红包消费金额（元）|商家红包消费金额(元)"`
+	CardAmount       string            `bill:"卡消费金额（元）|卡消费金额(元)"`
+	OutRequestNo     string            `bill:"退款批次号/请求号"`
+	ServiceFee       string            `bill:"服务费（元）|服务费(元)"`
+	SplitAmount      string            `bill:"分润（元）|分润(元)"`
+	Memo             string            `bill:"备注"`
+	Extra            map[string]string `bill:"-"` // 未识别的列，key 为表头
+}
+
+// TradeSummary 业务明细汇总，每个门店一行，最后一行为合计
+type TradeSummary struct {
+	StoreId          string            `bill:"门店编号"`
+	StoreName        string            `bill:"门店名称"`
+	TradeCount       string            `bill:"交易订单总笔数"`
+	RefundCount      string            `bill:"退款订单总笔数"`
+	TotalAmount      string            `bill:"订单金额（元）|订单金额(元)"`
+	ReceiptAmount    string            `bill:"商家实收（元）|商家实收(元)"`
+	AlipayDiscount   string            `bill:"支付宝优惠（元）|支付宝优惠(元)"`
+	MerchantDiscount string            `bill:"商家优惠（元）|商家优惠(元)"`
+	CardAmount       string            `bill:"卡消费金额（元）|卡消费金额(元)"`
+	ServiceFee       string            `bill:"服务费（元）|服务费(元)"`
+	SplitAmount      string            `bill:"分润（元）|分润(元)"`
+	NetAmount        string            `bill:"实收净额（元）|实收净额(元)"`
+	Extra            map[string]string `bill:"-"`
+}
+
+// IsTotal 是否为合计行
+func (s *TradeSummary) IsTotal() bool {
+	return s.StoreId == totalRow
+}
+
+// FundFlowRecord 账务明细（bill_type = signcustomer）
+type FundFlowRecord struct {
+	FlowNo        string            `bill:"账务流水号"`
+	BizNo         string            `bill:"业务流水号"`
+	OutTradeNo    string            `bill:"商户订单号"`
+	Subject       string            `bill:"商品名称"`
+	TransTime     string            `bill:"发生时间"`
+	OtherAccount  string            `bill:"对方账号"`
+	IncomeAmount  string            `bill:"收入金额（+元）|收入金额(+元)"`
+	ExpenseAmount string            `bill:"支出金额（-元）|支出金额(-元)"`
+	Balance       string            `bill:"账户余额（元）|账户余额(元)"`
+	Channel       string            `bill:"交易渠道"`
+	BizType       string            `bill:"业务类型"`
+	Memo          string            `bill:"备注"`
+	Extra         map[string]string `bill:"-"`
+}
+
+// FundFlowSummary 账务明细汇总，每个业务类型一行，最后一行为合计
+type FundFlowSummary struct {
+	BizType       string            `bill:"业务类型"`
+	IncomeCount   string            `bill:"收入笔数"`
+	IncomeAmount  string            `bill:"收入金额（+元）|收入金额(+元)"`
+	ExpenseCount  string            `bill:"支出笔数"`
+	ExpenseAmount string            `bill:"支出金额（-元）|支出金额(-元)"`
+	TotalAmount   string            `bill:"小计金额（元）|小计金额(元)"`
+	Extra         map[string]string `bill:"-"`
+}
+
+// IsTotal 是否为合计行
+func (s *FundFlowSummary) IsTotal() bool {
+	return s.BizType == totalRow
+}
@@ -0,0 +1,180 @@
+package alipay
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 统一收单核心接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度、金额格式），再传给对应的方法
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&alipay.TradeOrderRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], total_amount: must be > 0
+//	}
+//	aliRsp, err := client.TradePrecreate(ctx, bm)
+
+// TradeOrderRequest 下单公共请求参数
+// 用于 TradePrecreate()、TradeAppPay()、TradeWapPay()、TradePagePay()，也是 TradePay()、TradeCreate() 的公共参数
+type TradeOrderRequest struct {
+	OutTradeNo     string                `json:"out_trade_no" validate:"required,maxlen=64"`
+	TotalAmount    string                `json:"total_amount" validate:"required,decimal,max=100000000"` // 单位：元，如 "88.88"
+	Subject        string                `json:"subject" validate:"required,maxlen=256"`
+	Body           string                `json:"body,omitempty" validate:"maxlen=128"`
+	ProductCode    string                `json:"product_code,omitempty" validate:"maxlen=64"`
+	SellerId       string                `json:"seller_id,omitempty" validate:"maxlen=28"`
+	TimeoutExpress string                `json:"timeout_express,omitempty" validate:"maxlen=6,pattern=^[0-9]+[mhdc]$"` // 如 90m
+	TimeExpire     string                `json:"time_expire,omitempty" validate:"maxlen=32"`                           // 如 2016-12-31 10:05:01
+	PassbackParams string                `json:"passback_params,omitempty" validate:"maxlen=512"`
+	StoreId        string                `json:"store_id,omitempty" validate:"maxlen=32"`
+	OperatorId     string                `json:"operator_id,omitempty" validate:"maxlen=28"`
+	TerminalId     string                `json:"terminal_id,omitempty" validate:"maxlen=32"`
+	GoodsDetail    []*TradeGoodsDetail   `json:"goods_detail,omitempty"`
+	ExtendParams   *TradeExtendParams    `json:"extend_params,omitempty"`
+	SettleInfo     *TradeSettleInfoParam `json:"settle_info,omitempty"`
+	Extra          gopay.BodyMap         `json:"-"`
+}
+
+// TradePayRequest 统一收单交易支付（付款码支付）请求参数，TradePay() 使用
+type TradePayRequest struct {
+	TradeOrderRequest
+	AuthCode string `json:"auth_code" validate:"required,maxlen=64"`
+	Scene    string `json:"scene" validate:"required,enum=bar_code|security_code"`
+}
+
+// TradeCreateRequest 统一收单交易创建请求参数，TradeCreate() 使用，buyer_id 与 buyer_open_id 必填其一
+type TradeCreateRequest struct {
+	TradeOrderRequest
+	BuyerId     string `json:"buyer_id,omitempty" validate:"required_without=buyer_open_id,maxlen=28"`
+	BuyerOpenId string `json:"buyer_open_id,omitempty" validate:"maxlen=128"`
+}
+
+type TradeGoodsDetail struct {
+	GoodsId        string `json:"goods_id" validate:"required,maxlen=64"`
+	AlipayGoodsId  string `json:"alipay_goods_id,omitempty" validate:"maxlen=32"`
+	GoodsName      string `json:"goods_name" validate:"required,maxlen=256"`
+	Quantity       int64  `json:"quantity" validate:"required,gt=0"`
+	Price          string `json:"price" validate:"required,decimal"`
+	GoodsCategory  string `json:"goods_category,omitempty" validate:"maxlen=24"`
+	CategoriesTree string `json:"categories_tree,omitempty" validate:"maxlen=128"`
+	ShowUrl        string `json:"show_url,omitempty" validate:"maxlen=400"`
+}
+
+type TradeExtendParams struct {
+	SysServiceProviderId string `json:"sys_service_provider_id,omitempty" validate:"maxlen=64"`
+	HbFqNum              string `json:"hb_fq_num,omitempty" validate:"enum=3|6|12"`
+	HbFqSellerPercent    string `json:"hb_fq_seller_percent,omitempty" validate:"enum=0|100"`
+	SpecifiedSellerName  string `json:"specified_seller_name,omitempty" validate:"maxlen=32"`
+}
+
+type TradeSettleInfoParam struct {
+	SettlePeriodTime  string                    `json:"settle_period_time,omitempty" validate:"maxlen=10"`
+	SettleDetailInfos []*TradeSettleDetailParam `json:"settle_detail_infos" validate:"required,maxlen=10"`
+}
+
+type TradeSettleDetailParam struct {
+	TransInType string `json:"trans_in_type" validate:"required,enum=cardAliasNo|userId|loginName|defaultSettle"`
+	TransIn     string `json:"trans_in,omitempty" validate:"maxlen=64"`
+	Amount      string `json:"amount" validate:"required,decimal"`
+}
+
+// TradeQueryRequest 统一收单交易查询请求参数，TradeQuery() 使用，out_trade_no 与 trade_no 必填其一
+type TradeQueryRequest struct {
+	OutTradeNo   string        `json:"out_trade_no,omitempty" validate:"required_without=trade_no,maxlen=64"`
+	TradeNo      string        `json:"trade_no,omitempty" validate:"maxlen=64"`
+	OrgPid       string        `json:"org_pid,omitempty" validate:"maxlen=16"`
+	QueryOptions []string      `json:"query_options,omitempty" validate:"maxlen=1024"`
+	Extra        gopay.BodyMap `json:"-"`
+}
+
+// TradeRefundRequest 统一收单交易退款请求参数，TradeRefund() 使用，out_trade_no 与 trade_no 必填其一
+// 部分退款时 out_request_no 必填，同一笔交易的不同退款请求需不同
+type TradeRefundRequest struct {
+	OutTradeNo   string              `json:"out_trade_no,omitempty" validate:"required_without=trade_no,maxlen=64"`
+	TradeNo      string              `json:"trade_no,omitempty" validate:"maxlen=64"`
+	RefundAmount string              `json:"refund_amount" validate:"required,decimal,max=100000000"`
+	RefundReason string              `json:"refund_reason,omitempty" validate:"maxlen=256"`
+	OutRequestNo string              `json:"out_request_no,omitempty" validate:"maxlen=64"`
+	GoodsDetail  []*TradeRefundGoods `json:"refund_goods_detail,omitempty"`
+	QueryOptions []string            `json:"query_options,omitempty"`
+	Extra        gopay.BodyMap       `json:"-"`
+}
+
+// TradeRefundGoods 退款包含的商品
+type TradeRefundGoods struct {
+	GoodsId      string `json:"goods_id" validate:"required,maxlen=64"`
+	RefundAmount string `json:"refund_amount" validate:"required,decimal"`
+	OutItemId    string `json:"out_item_id,omitempty" validate:"maxlen=64"`
+	OutSkuId     string `json:"out_sku_id,omitempty" validate:"maxlen=64"`
+}
+
+// TradeCancelRequest 统一收单交易撤销请求参数，TradeCancel() 使用，out_trade_no 与 trade_no 必填其一
+type TradeCancelRequest struct {
+	OutTradeNo string        `json:"out_trade_no,omitempty" validate:"required_without=trade_no,maxlen=64"`
+	TradeNo    string        `json:"trade_no,omitempty" validate:"maxlen=64"`
+	Extra      gopay.BodyMap `json:"-"`
+}
+
+// TradeFastPayRefundQueryRequest 统一收单交易退款查询请求参数，TradeFastPayRefundQuery() 使用，out_trade_no 与 trade_no 必填其一
+// out_request_no 为退款请求号，退款时未传入则为 out_trade_no
+type TradeFastPayRefundQueryRequest struct {
+	OutTradeNo   string        `json:"out_trade_no,omitempty" validate:"required_without=trade_no,maxlen=64"`
+	TradeNo      string        `json:"trade_no,omitempty" validate:"maxlen=64"`
+	OutRequestNo string        `json:"out_request_no" validate:"required,maxlen=64"`
+	QueryOptions []string      `json:"query_options,omitempty"`
+	Extra        gopay.BodyMap `json:"-"`
+}
+
+// TradeCloseRequest 统一收单交易关闭请求参数，TradeClose() 使用，out_trade_no 与 trade_no 必填其一
+type TradeCloseRequest struct {
+	OutTradeNo string        `json:"out_trade_no,omitempty" validate:"required_without=trade_no,maxlen=64"`
+	TradeNo    string        `json:"trade_no,omitempty" validate:"maxlen=64"`
+	OperatorId string        `json:"operator_id,omitempty" validate:"maxlen=28"`
+	Extra      gopay.BodyMap `json:"-"`
+}
+
+// BodyMap 校验并转换为 TradePrecreate()、TradeAppPay()、TradeWapPay()、TradePagePay() 的请求参数
+func (r *TradeOrderRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradePay() 的请求参数
+func (r *TradePayRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeCreate() 的请求参数
+func (r *TradeCreateRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeQuery() 的请求参数
+func (r *TradeQueryRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeRefund() 的请求参数
+func (r *TradeRefundRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeCancel() 的请求参数
+func (r *TradeCancelRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeFastPayRefundQuery() 的请求参数
+func (r *TradeFastPayRefundQueryRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 TradeClose() 的请求参数
+func (r *TradeCloseRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+func buildBodyMap(req any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(req, extra)
+}
@@ -0,0 +1,48 @@
+package alipay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestTradeRequestBodyMap(t *testing.T) {
+	req := &TradePayRequest{
+		TradeOrderRequest: TradeOrderRequest{
+			OutTradeNo:  "GZ201909081743431443",
+			TotalAmount: "0.01",
+			Subject:     "条码支付",
+			GoodsDetail: []*TradeGoodsDetail{{GoodsId: "apple-01", GoodsName: "ipad", Quantity: 1, Price: "0.01"}},
+			Extra:       gopay.BodyMap{"query_options": []string{"fund_bill_list"}},
+		},
+		AuthCode: "28763443825664394",
+		Scene:    "bar_code",
+	}
+	bm, err := req.BodyMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bm.CheckEmptyError("out_trade_no", "total_amount", "subject", "auth_code", "scene", "query_options"); err != nil {
+		t.Fatal(err)
+	}
+	if bm.GetString("goods_detail") != `[{"goods_id":"apple-01","goods_name":"ipad","price":"0.01","quantity":1}]` {
+		t.Errorf("goods_detail = %s", bm.GetString("goods_detail"))
+	}
+
+	_, err = (&TradePayRequest{
+		TradeOrderRequest: TradeOrderRequest{OutTradeNo: "A", TotalAmount: "0", Subject: "S"},
+		Scene:             "wave_code",
+	}).BodyMap()
+	if !errors.Is(err, gopay.ParamValidateErr) || !errors.Is(err, gopay.MissParamErr) ||
+		err.Error() != "[invalid parameter], total_amount: must be > 0; auth_code: is required; scene: must be one of bar_code|security_code" {
+		t.Errorf("err = %v", err)
+	}
+
+	if _, err = (&TradeRefundRequest{RefundAmount: "1.00"}).BodyMap(); !errors.Is(err, gopay.MissParamErr) {
+		t.Errorf("err = %v", err)
+	}
+	if bm, err = (&TradeCloseRequest{TradeNo: "2013112611001004680073956707"}).BodyMap(); err != nil || len(bm) != 1 {
+		t.Errorf("bm = %v, err = %v", bm, err)
+	}
+}
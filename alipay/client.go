@@ -32,6 +32,7 @@ type Client struct {
 	privateKey         *rsa.PrivateKey
 	aliPayPublicKey    *rsa.PublicKey // 支付宝证书公钥内容 alipayPublicCert.crt
 	autoSign           bool
+	validateRequest    bool // 请求前按接口的 Schema 校验参数
 	DebugSwitch        gopay.DebugSwitch
 	logger             xlog.XLogger
 	location           *time.Location
@@ -61,7 +62,7 @@ func NewClient(appid, privateKey string, isProd bool) (client *Client, err error
 		IsProd:      isProd,
 		privateKey:  priKey,
 		DebugSwitch: gopay.DebugOff,
-		logger:      logger,
+		logger:      gopay.NewRedactLogger(logger, nil),
 		hc:          xhttp.NewClient(),
 	}
 	return client, nil
@@ -81,6 +82,20 @@ func (a *Client) AutoVerifySign(alipayPublicKeyContent []byte) {
 	}
 }
 
+// SetValidateRequest 设置是否在请求前按接口的 Schema 本地校验参数（默认不开启）
+// 开启后，统一收单 下单、查询、撤销、关单、退款、退款查询 接口在签名前使用 SchemaTradePay、SchemaTradeRefund 等校验 BodyMap，不合法时返回 gopay.ParamValidateErr，不发起请求
+func (a *Client) SetValidateRequest(validate bool) {
+	a.validateRequest = validate
+}
+
+// checkRequest 开启 SetValidateRequest() 时按 schema 校验请求参数
+func (a *Client) checkRequest(schema *gopay.Schema, bm gopay.BodyMap) error {
+	if !a.validateRequest {
+		return nil
+	}
+	return schema.Validate(bm)
+}
+
 // SetBodySize 设置http response body size(MB)
 func (a *Client) SetBodySize(sizeMB int) {
 	if sizeMB > 0 {
@@ -88,6 +103,12 @@ func (a *Client) SetBodySize(sizeMB int) {
 	}
 }
 
+// SetRetryPolicy 设置请求重试策略，policy.Classifier 为空时使用 RetryClassifier
+// 重试复用首次请求的请求体（out_trade_no、sign 不变），之后调用 SetHttpClient 需重新设置
+func (a *Client) SetRetryPolicy(policy *xhttp.RetryPolicy) {
+	a.hc.SetRetryPolicy(withClassifier(policy))
+}
+
 // SetHttpClient 设置自定义的xhttp.Client
 func (a *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
@@ -103,7 +124,7 @@ func (a *Client) GetHttpClient() *xhttp.Client {
 // SetLogger 设置自定义的logger
 func (a *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		a.logger = logger
+		a.logger = gopay.NewRedactLogger(logger, nil)
 	}
 }
 
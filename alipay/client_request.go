@@ -76,7 +76,7 @@ func (a *Client) PostFileAliPayAPISelfV2(ctx context.Context, bm gopay.BodyMap,
 	} else {
 		url = sandboxBaseUrlUtf8
 	}
-	res, bs, err := a.hc.Req(xhttp.TypeMultipartFormData).Post(url).
+	res, bs, err := a.hc.Req(xhttp.TypeMultipartFormData).SetApi(gopay.ProviderAlipay, method).Post(url).
 		SendMultipartBodyMap(bm).EndBytes(ctx)
 	if err != nil {
 		return nil
@@ -117,7 +117,7 @@ func (a *Client) doAliPaySelf(ctx context.Context, bm gopay.BodyMap, method stri
 	} else {
 		url = sandboxBaseUrlUtf8
 	}
-	res, bs, err := a.hc.Req(xhttp.TypeFormData).Post(url).SendString(bm.EncodeURLParams()).EndBytes(ctx)
+	res, bs, err := a.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAlipay, method).Post(url).SendString(bm.EncodeURLParams()).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +176,7 @@ func (a *Client) doAliPay(ctx context.Context, bm gopay.BodyMap, method string,
 		if !a.IsProd {
 			url = sandboxBaseUrlUtf8
 		}
-		res, bs, err := a.hc.Req(xhttp.TypeFormData).Post(url).SendString(param).EndBytes(ctx)
+		res, bs, err := a.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAlipay, method).Post(url).SendString(param).EndBytes(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -234,7 +234,7 @@ func (a *Client) DoAliPay(ctx context.Context, bm gopay.BodyMap, method string,
 		if !a.IsProd {
 			url = sandboxBaseUrlUtf8
 		}
-		res, bs, err := a.hc.Req(xhttp.TypeFormData).Post(url).SendString(param).EndBytes(ctx)
+		res, bs, err := a.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAlipay, method).Post(url).SendString(param).EndBytes(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -346,7 +346,7 @@ func (a *Client) FileUploadRequest(ctx context.Context, bm gopay.BodyMap, method
 	}
 	url := baseUrlUtf8 + "&" + pubBody.EncodeURLParams()
 
-	res, bs, err := a.hc.Req(xhttp.TypeMultipartFormData).Post(url).
+	res, bs, err := a.hc.Req(xhttp.TypeMultipartFormData).SetApi(gopay.ProviderAlipay, method).Post(url).
 		SendMultipartBodyMap(bm).EndBytes(ctx)
 	if err != nil {
 		return nil, err
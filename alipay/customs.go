@@ -78,7 +78,7 @@ func (a *Client) doAliPayCustoms(ctx context.Context, bm gopay.BodyMap, service
 		a.logger.Debugf("Alipay_Request: %s", bm.JsonBody())
 	}
 	// request
-	res, bs, err := a.hc.Req(xhttp.TypeFormData).Post("https://mapi.alipay.com/gateway.do").SendString(bm.EncodeURLParams()).EndBytes(ctx)
+	res, bs, err := a.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAlipay, service).Post("https://mapi.alipay.com/gateway.do").SendString(bm.EncodeURLParams()).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
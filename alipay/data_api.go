@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay/bill"
 )
 
 // alipay.data.bill.balance.query(支付宝商家账户当前余额查询)
@@ -114,3 +115,15 @@ func (a *Client) DataBillDownloadUrlQuery(ctx context.Context, bm gopay.BodyMap)
 	aliRsp.SignData = signData
 	return aliRsp, a.autoVerifySignByCert(aliRsp.Sign, signData, signDataErr)
 }
+
+// 下载并解压对账单，bm 同 DataBillDownloadUrlQuery()
+// 使用完毕需调用 file.Close() 删除临时文件
+// bill_type = trade：file.TradeRecords()、file.TradeSummaries()
+// bill_type = signcustomer：file.FundFlowRecords()、file.FundFlowSummaries()
+func (a *Client) DataBillDownload(ctx context.Context, bm gopay.BodyMap) (file *bill.File, err error) {
+	aliRsp, err := a.DataBillDownloadUrlQuery(ctx, bm)
+	if err != nil {
+		return nil, err
+	}
+	return bill.Download(ctx, a.hc, aliRsp.Response.BillDownloadUrl)
+}
@@ -0,0 +1,105 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-pay/util"
+	"github.com/go-pay/xtime"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay"
+)
+
+// SendNotify 向 notifyUrl 发送异步通知
+// bm 中缺少的 notify_time、notify_id、app_id、charset、version、sign_type 自动补全，sign 使用模拟支付宝私钥计算
+// 商户应答非 success 时返回 error
+func (s *Server) SendNotify(ctx context.Context, notifyUrl string, bm gopay.BodyMap) (err error) {
+	if notifyUrl == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "notify_url")
+	}
+	defaults := map[string]string{
+		"notify_time": time.Now().Format(xtime.TimeLayout),
+		"notify_type": alipay.NotifyTypeTradeStatusSync,
+		"notify_id":   util.RandomString(32),
+		"app_id":      s.AppId,
+		"charset":     alipay.UTF8,
+		"version":     "1.0",
+	}
+	for k, v := range defaults {
+		if bm.GetString(k) == gopay.NULL {
+			bm.Set(k, v)
+		}
+	}
+	// 异步通知验签不包含 sign、sign_type
+	bm.Remove("sign")
+	bm.Remove("sign_type")
+	sign, err := s.sign([]byte(bm.EncodeAliPaySignParams()))
+	if err != nil {
+		return err
+	}
+	bm.Set("sign_type", alipay.RSA2).Set("sign", sign)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyUrl, strings.NewReader(bm.EncodeURLParams()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+	res, err := s.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if res.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "success" {
+		return fmt.Errorf("notify[%s] response: %d > %s", bm.GetString("notify_id"), res.StatusCode, string(body))
+	}
+	return nil
+}
+
+// NotifyTrade 向 notifyUrl 发送交易当前状态的异步通知（trade_status_sync）
+// notifyUrl 为空时使用下单请求中的 notify_url
+func (s *Server) NotifyTrade(ctx context.Context, notifyUrl, outTradeNo string) (err error) {
+	t, ok := s.Trade(outTradeNo)
+	if !ok {
+		return fmt.Errorf("trade[%s] not exist", outTradeNo)
+	}
+	if notifyUrl == gopay.NULL {
+		notifyUrl = t.NotifyUrl
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("trade_no", t.TradeNo).
+		Set("out_trade_no", t.OutTradeNo).
+		Set("subject", t.Subject).
+		Set("trade_status", t.TradeStatus).
+		Set("total_amount", t.TotalAmount).
+		Set("buyer_logon_id", t.BuyerLogonId).
+		Set("buyer_id", t.BuyerUserId).
+		Set("refund_fee", t.RefundFee).
+		Set("gmt_create", t.GmtCreate).
+		Set("gmt_payment", t.GmtPayment).
+		Set("gmt_close", t.GmtClose)
+	if t.GmtPayment != gopay.NULL {
+		bm.Set("receipt_amount", t.TotalAmount).
+			Set("buyer_pay_amount", t.TotalAmount).
+			Set("invoice_amount", t.TotalAmount)
+	}
+	// 移除空值，与支付宝通知保持一致
+	for k := range bm {
+		if bm.GetString(k) == gopay.NULL {
+			bm.Remove(k)
+		}
+	}
+	return s.SendNotify(ctx, notifyUrl, bm)
+}
+
+// NotifyPay 模拟买家完成支付，并向 notifyUrl 发送 TRADE_SUCCESS 异步通知
+func (s *Server) NotifyPay(ctx context.Context, notifyUrl, outTradeNo string) (err error) {
+	if _, err = s.PayTrade(outTradeNo); err != nil {
+		return err
+	}
+	return s.NotifyTrade(ctx, notifyUrl, outTradeNo)
+}
@@ -0,0 +1,585 @@
+// Package mock 支付宝 OpenAPI v2 网关模拟服务端，基于 httptest 实现，用于商户侧单元测试与离线联调
+//
+// 模拟网关使用随机生成的根证书与支付宝公钥证书对应答签名，并校验请求的 RSA2 签名。
+// 客户端通过 client.SetHttpClient(srv.HttpClient()) 将请求转发至模拟网关，
+// 并使用 srv.IssueAppCert()、srv.RootCert()、srv.AlipayPublicCert() 设置证书后即可完成同步验签。
+package mock
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-pay/xtime"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay"
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+const (
+	codeSuccess         = "10000"
+	codeInvalidArgument = "40002"
+	codeBusinessFailed  = "40004"
+)
+
+// Trade 模拟网关中的交易
+type Trade struct {
+	TradeNo      string
+	OutTradeNo   string
+	Subject      string
+	TotalAmount  string
+	RefundFee    string // 累计退款金额
+	TradeStatus  string // alipay.TradeStatusWaitBuyerPay 等
+	BuyerLogonId string
+	BuyerUserId  string
+	NotifyUrl    string
+	GmtCreate    string
+	GmtPayment   string
+	GmtClose     string
+}
+
+// Server 支付宝 v2 网关模拟服务端
+// 已实现接口：
+//   - alipay.trade.precreate
+//   - alipay.trade.pay
+//   - alipay.trade.query
+//   - alipay.trade.refund
+//   - alipay.trade.close
+type Server struct {
+	*httptest.Server
+	AppId string // 应用ID，请求参数 app_id 需与之一致
+
+	rootKey        *rsa.PrivateKey
+	rootCert       *x509.Certificate
+	rootCertPem    []byte
+	alipayKey      *rsa.PrivateKey
+	alipayCertPem  []byte
+	alipayCertSn   string
+	appPublicKey   *rsa.PublicKey
+	appCertSn      string
+	certSerialSeed int64
+
+	mu      sync.Mutex
+	seq     int64
+	trades  map[string]*Trade  // key: out_trade_no
+	refunds map[string]*refund // key: out_trade_no + out_request_no
+}
+
+type refund struct {
+	amount string
+	gmtPay string
+}
+
+// NewServer 初始化并启动模拟网关，使用完毕请调用 srv.Close()
+// appid：应用ID
+func NewServer(appid string) (srv *Server, err error) {
+	if appid == gopay.NULL {
+		return nil, gopay.MissAlipayInitParamErr
+	}
+	srv = &Server{
+		AppId:   appid,
+		trades:  make(map[string]*Trade),
+		refunds: make(map[string]*refund),
+	}
+	if srv.rootKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rootTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(now.UnixNano()),
+		Subject:               pkix.Name{CommonName: "Ant Financial Certification Authority Mock Root", Organization: []string{"Ant Financial"}, Country: []string{"CN"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, rootTpl, rootTpl, &srv.rootKey.PublicKey, srv.rootKey)
+	if err != nil {
+		return nil, err
+	}
+	if srv.rootCert, err = x509.ParseCertificate(der); err != nil {
+		return nil, err
+	}
+	srv.rootCertPem = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if srv.alipayKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		return nil, err
+	}
+	if srv.alipayCertPem, err = srv.issueCert("支付宝(中国)网络技术有限公司", &srv.alipayKey.PublicKey); err != nil {
+		return nil, err
+	}
+	if srv.alipayCertSn, err = alipay.GetCertSN(srv.alipayCertPem); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /gateway.do", srv.handleGateway)
+	srv.Server = httptest.NewServer(mux)
+	return srv, nil
+}
+
+// IssueAppCert 使用模拟根证书为应用公钥签发应用公钥证书，并使用该公钥校验请求签名
+// 返回的证书可用于 client.SetCertSnByContent(appCert, srv.RootCert(), srv.AlipayPublicCert())
+func (s *Server) IssueAppCert(appPublicKey *rsa.PublicKey) (appCert []byte, err error) {
+	if appCert, err = s.issueCert(s.AppId, appPublicKey); err != nil {
+		return nil, err
+	}
+	sn, err := alipay.GetCertSN(appCert)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.appPublicKey, s.appCertSn = appPublicKey, sn
+	s.mu.Unlock()
+	return appCert, nil
+}
+
+// SetAppPublicKey 设置应用公钥（公钥模式），用于校验请求签名
+func (s *Server) SetAppPublicKey(appPublicKey *rsa.PublicKey) *Server {
+	s.mu.Lock()
+	s.appPublicKey, s.appCertSn = appPublicKey, gopay.NULL
+	s.mu.Unlock()
+	return s
+}
+
+// RootCert 返回模拟支付宝根证书 PEM 内容
+func (s *Server) RootCert() []byte {
+	return s.rootCertPem
+}
+
+// AlipayPublicCert 返回模拟支付宝公钥证书 PEM 内容，可用于 client.AutoVerifySign() 及异步通知验签
+func (s *Server) AlipayPublicCert() []byte {
+	return s.alipayCertPem
+}
+
+// Transport 返回将所有请求转发至模拟网关的 http.RoundTripper
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &rewriteTransport{target: target, base: s.Client().Transport}
+}
+
+// HttpClient 返回将所有请求转发至模拟网关的 xhttp.Client，用于 client.SetHttpClient()
+func (s *Server) HttpClient() *xhttp.Client {
+	return xhttp.NewClient().SetTransport(s.Transport())
+}
+
+// Trade 返回商户订单号对应的交易
+func (s *Server) Trade(outTradeNo string) (trade *Trade, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trades[outTradeNo]
+	if !ok {
+		return nil, false
+	}
+	cp := *t
+	return &cp, true
+}
+
+// PayTrade 模拟买家扫码完成支付，交易状态置为 TRADE_SUCCESS
+func (s *Server) PayTrade(outTradeNo string) (trade *Trade, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trades[outTradeNo]
+	if !ok {
+		return nil, fmt.Errorf("trade[%s] not exist", outTradeNo)
+	}
+	if t.TradeStatus == alipay.TradeStatusWaitBuyerPay {
+		s.paid(t)
+	}
+	cp := *t
+	return &cp, nil
+}
+
+// =============================== gateway ===============================
+
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	bm := make(gopay.BodyMap, len(r.PostForm))
+	for k, v := range r.PostForm {
+		if len(v) == 1 {
+			bm.Set(k, v[0])
+		}
+	}
+	method := bm.GetString("method")
+	withCertSn := bm.GetString("app_cert_sn") != gopay.NULL
+	if errRsp := s.verifyRequest(bm); errRsp != nil {
+		s.writeResponse(w, method, withCertSn, errRsp)
+		return
+	}
+	biz := make(gopay.BodyMap)
+	if bz := bm.GetString("biz_content"); bz != gopay.NULL {
+		if err := json.Unmarshal([]byte(bz), &biz); err != nil {
+			s.writeResponse(w, method, withCertSn, invalidArgument("isv.invalid-biz-content", "biz_content 格式错误"))
+			return
+		}
+	}
+	var rsp any
+	switch method {
+	case "alipay.trade.precreate":
+		rsp = s.tradePrecreate(bm, biz)
+	case "alipay.trade.pay":
+		rsp = s.tradePay(bm, biz)
+	case "alipay.trade.query":
+		rsp = s.tradeQuery(biz)
+	case "alipay.trade.refund":
+		rsp = s.tradeRefund(biz)
+	case "alipay.trade.close":
+		rsp = s.tradeClose(biz)
+	default:
+		rsp = invalidArgument("isv.invalid-method", "不存在的方法名")
+	}
+	s.writeResponse(w, method, withCertSn, rsp)
+}
+
+// verifyRequest 校验公共参数及请求签名
+func (s *Server) verifyRequest(bm gopay.BodyMap) *alipay.ErrorResponse {
+	switch {
+	case bm.GetString("method") == gopay.NULL:
+		return invalidArgument("isv.missing-method", "缺少方法名参数")
+	case bm.GetString("app_id") != s.AppId:
+		return invalidArgument("isv.invalid-app-id", "无效的AppID参数")
+	case bm.GetString("sign_type") != alipay.RSA2:
+		return invalidArgument("isv.invalid-signature-type", "无效的签名类型")
+	case bm.GetString("sign") == gopay.NULL:
+		return invalidArgument("isv.missing-signature", "缺少签名参数")
+	}
+	s.mu.Lock()
+	pubKey, appCertSn := s.appPublicKey, s.appCertSn
+	s.mu.Unlock()
+	if pubKey == nil {
+		return invalidArgument("isv.invalid-signature", "应用未配置公钥")
+	}
+	if sn := bm.GetString("app_cert_sn"); appCertSn != gopay.NULL && sn != appCertSn {
+		return invalidArgument("isv.app-cert-sn-not-match", "应用公钥证书SN不匹配")
+	}
+	if sn := bm.GetString("alipay_root_cert_sn"); sn != gopay.NULL && !strings.Contains(sn, s.rootCertSn()) {
+		return invalidArgument("isv.alipay-root-cert-sn-not-match", "支付宝根证书SN不匹配")
+	}
+	sign := bm.GetString("sign")
+	bm.Remove("sign")
+	signBytes, _ := base64.StdEncoding.DecodeString(sign)
+	sum256 := sha256.Sum256([]byte(bm.EncodeAliPaySignParams()))
+	if err := rsa.VerifyPKCS1v15(pubKey, crypto.SHA256, sum256[:], signBytes); err != nil {
+		return invalidArgument("isv.invalid-signature", "验签出错")
+	}
+	return nil
+}
+
+func (s *Server) tradePrecreate(pub, biz gopay.BodyMap) any {
+	if err := biz.CheckEmptyError("out_trade_no", "total_amount", "subject"); err != nil {
+		return invalidArgument("isv.missing-parameter", err.Error())
+	}
+	if _, err := parseAmount(biz.GetString("total_amount")); err != nil {
+		return invalidArgument("isv.invalid-parameter", "total_amount 格式错误")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trades[biz.GetString("out_trade_no")]
+	if ok && t.TradeStatus != alipay.TradeStatusWaitBuyerPay {
+		return businessFailed("ACQ.TRADE_HAS_SUCCESS", "交易已被支付")
+	}
+	if !ok {
+		t = s.newTrade(pub, biz)
+	}
+	return &alipay.TradePrecreate{
+		ErrorResponse: success(),
+		OutTradeNo:    t.OutTradeNo,
+		QrCode:        "https://qr.alipay.com/" + t.TradeNo,
+	}
+}
+
+func (s *Server) tradePay(pub, biz gopay.BodyMap) any {
+	if err := biz.CheckEmptyError("out_trade_no", "total_amount", "subject", "scene", "auth_code"); err != nil {
+		return invalidArgument("isv.missing-parameter", err.Error())
+	}
+	if _, err := parseAmount(biz.GetString("total_amount")); err != nil {
+		return invalidArgument("isv.invalid-parameter", "total_amount 格式错误")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.trades[biz.GetString("out_trade_no")]
+	if ok && t.TradeStatus != alipay.TradeStatusWaitBuyerPay {
+		return businessFailed("ACQ.TRADE_HAS_SUCCESS", "交易已被支付")
+	}
+	if !ok {
+		t = s.newTrade(pub, biz)
+	}
+	s.paid(t)
+	return &alipay.TradePay{
+		ErrorResponse:  success(),
+		TradeNo:        t.TradeNo,
+		OutTradeNo:     t.OutTradeNo,
+		BuyerLogonId:   t.BuyerLogonId,
+		BuyerUserId:    t.BuyerUserId,
+		TotalAmount:    t.TotalAmount,
+		ReceiptAmount:  t.TotalAmount,
+		BuyerPayAmount: t.TotalAmount,
+		InvoiceAmount:  t.TotalAmount,
+		FundBillList:   []*alipay.TradeFundBill{{FundChannel: "ALIPAYACCOUNT", Amount: t.TotalAmount}},
+	}
+}
+
+func (s *Server) tradeQuery(biz gopay.BodyMap) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.findTrade(biz)
+	if t == nil {
+		return businessFailed("ACQ.TRADE_NOT_EXIST", "交易不存在")
+	}
+	rsp := &alipay.TradeQuery{
+		ErrorResponse: success(),
+		TradeNo:       t.TradeNo,
+		OutTradeNo:    t.OutTradeNo,
+		BuyerLogonId:  t.BuyerLogonId,
+		BuyerUserId:   t.BuyerUserId,
+		TradeStatus:   t.TradeStatus,
+		TotalAmount:   t.TotalAmount,
+	}
+	if t.GmtPayment != gopay.NULL {
+		rsp.BuyerPayAmount = t.TotalAmount
+		rsp.ReceiptAmount = t.TotalAmount
+		rsp.InvoiceAmount = t.TotalAmount
+		rsp.SendPayDate = t.GmtPayment
+	}
+	return rsp
+}
+
+func (s *Server) tradeRefund(biz gopay.BodyMap) any {
+	if err := biz.CheckEmptyError("refund_amount"); err != nil {
+		return invalidArgument("isv.missing-parameter", err.Error())
+	}
+	amount, err := parseAmount(biz.GetString("refund_amount"))
+	if err != nil || amount <= 0 {
+		return invalidArgument("isv.invalid-parameter", "refund_amount 格式错误")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.findTrade(biz)
+	if t == nil {
+		return businessFailed("ACQ.TRADE_NOT_EXIST", "交易不存在")
+	}
+	// 未传 out_request_no 时，以 out_trade_no 作为退款请求号
+	outRequestNo := biz.GetString("out_request_no")
+	if outRequestNo == gopay.NULL {
+		outRequestNo = t.OutTradeNo
+	}
+	rsp := &alipay.TradeRefund{
+		ErrorResponse: success(),
+		TradeNo:       t.TradeNo,
+		OutTradeNo:    t.OutTradeNo,
+		BuyerLogonId:  t.BuyerLogonId,
+		BuyerUserId:   t.BuyerUserId,
+	}
+	key := t.OutTradeNo + "|" + outRequestNo
+	// 同一退款请求号重复请求，不再退款
+	if rf, ok := s.refunds[key]; ok {
+		if rf.amount != formatAmount(amount) {
+			return businessFailed("ACQ.DISCORDANT_REPEAT_REQUEST", "请求信息不一致")
+		}
+		rsp.FundChange = "N"
+		rsp.RefundFee = t.RefundFee
+		rsp.GmtRefundPay = rf.gmtPay
+		return rsp
+	}
+	if t.TradeStatus != alipay.TradeStatusSuccess {
+		return businessFailed("ACQ.TRADE_STATUS_ERROR", "交易状态不合法")
+	}
+	total, _ := parseAmount(t.TotalAmount)
+	refunded, _ := parseAmount(t.RefundFee)
+	if refunded+amount > total {
+		return businessFailed("ACQ.REFUND_AMT_NOT_EQUAL_TOTAL", "退款金额超限")
+	}
+	refunded += amount
+	t.RefundFee = formatAmount(refunded)
+	now := time.Now().Format(xtime.TimeLayout)
+	if refunded == total {
+		t.TradeStatus = alipay.TradeStatusClosed
+		t.GmtClose = now
+	}
+	s.refunds[key] = &refund{amount: formatAmount(amount), gmtPay: now}
+	rsp.FundChange = "Y"
+	rsp.RefundFee = t.RefundFee
+	rsp.GmtRefundPay = now
+	return rsp
+}
+
+func (s *Server) tradeClose(biz gopay.BodyMap) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.findTrade(biz)
+	if t == nil {
+		return businessFailed("ACQ.TRADE_NOT_EXIST", "交易不存在")
+	}
+	if t.TradeStatus != alipay.TradeStatusWaitBuyerPay {
+		return businessFailed("ACQ.REASON_TRADE_STATUS_INVALID", "交易状态不合法")
+	}
+	t.TradeStatus = alipay.TradeStatusClosed
+	t.GmtClose = time.Now().Format(xtime.TimeLayout)
+	return &alipay.TradeClose{ErrorResponse: success(), TradeNo: t.TradeNo, OutTradeNo: t.OutTradeNo}
+}
+
+// =============================== helpers ===============================
+
+// newTrade 创建交易，调用方需持有 s.mu
+func (s *Server) newTrade(pub, biz gopay.BodyMap) *Trade {
+	s.seq++
+	now := time.Now()
+	t := &Trade{
+		TradeNo:     fmt.Sprintf("%s22001%011d", now.Format("20060102"), s.seq),
+		OutTradeNo:  biz.GetString("out_trade_no"),
+		Subject:     biz.GetString("subject"),
+		TradeStatus: alipay.TradeStatusWaitBuyerPay,
+		NotifyUrl:   pub.GetString("notify_url"),
+		GmtCreate:   now.Format(xtime.TimeLayout),
+	}
+	amount, _ := parseAmount(biz.GetString("total_amount"))
+	t.TotalAmount = formatAmount(amount)
+	s.trades[t.OutTradeNo] = t
+	return t
+}
+
+// paid 交易置为已支付，调用方需持有 s.mu
+func (s *Server) paid(t *Trade) {
+	t.TradeStatus = alipay.TradeStatusSuccess
+	t.BuyerLogonId = "159****5620"
+	t.BuyerUserId = "2088101117955611"
+	t.GmtPayment = time.Now().Format(xtime.TimeLayout)
+}
+
+// findTrade 通过 out_trade_no 或 trade_no 查找交易，调用方需持有 s.mu
+func (s *Server) findTrade(biz gopay.BodyMap) *Trade {
+	if outTradeNo := biz.GetString("out_trade_no"); outTradeNo != gopay.NULL {
+		return s.trades[outTradeNo]
+	}
+	tradeNo := biz.GetString("trade_no")
+	for _, t := range s.trades {
+		if t.TradeNo == tradeNo {
+			return t
+		}
+	}
+	return nil
+}
+
+// writeResponse 按 {"xxx_response":{},"alipay_cert_sn":"","sign":""} 格式写入应答并签名
+// 请求携带 app_cert_sn（证书模式）时应答携带 alipay_cert_sn
+func (s *Server) writeResponse(w http.ResponseWriter, method string, withCertSn bool, rsp any) {
+	key := "error_response"
+	if method != gopay.NULL {
+		key = strings.ReplaceAll(method, ".", "_") + "_response"
+	}
+	signData, _ := json.Marshal(rsp)
+	sign, err := s.sign(signData)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var buf strings.Builder
+	buf.WriteString(`{"` + key + `":`)
+	buf.Write(signData)
+	if withCertSn {
+		buf.WriteString(`,"alipay_cert_sn":"` + s.alipayCertSn + `"`)
+	}
+	buf.WriteString(`,"sign":"` + sign + `"}`)
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(buf.String()))
+}
+
+// sign 使用模拟支付宝私钥 RSA2 签名
+func (s *Server) sign(data []byte) (string, error) {
+	sum256 := sha256.Sum256(data)
+	bs, err := rsa.SignPKCS1v15(rand.Reader, s.alipayKey, crypto.SHA256, sum256[:])
+	if err != nil {
+		return gopay.NULL, fmt.Errorf("[%w]: %+v", gopay.SignatureErr, err)
+	}
+	return base64.StdEncoding.EncodeToString(bs), nil
+}
+
+// issueCert 使用模拟根证书签发证书
+func (s *Server) issueCert(commonName string, pub *rsa.PublicKey) ([]byte, error) {
+	s.mu.Lock()
+	s.certSerialSeed++
+	serial := big.NewInt(time.Now().UnixNano() + s.certSerialSeed)
+	s.mu.Unlock()
+	now := time.Now()
+	tpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Country: []string{"CN"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(5, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, s.rootCert, pub, s.rootKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func (s *Server) rootCertSn() string {
+	sn, _ := alipay.GetRootCertSN(s.rootCertPem)
+	return sn
+}
+
+func success() alipay.ErrorResponse {
+	return alipay.ErrorResponse{Code: codeSuccess, Msg: "Success"}
+}
+
+func invalidArgument(subCode, subMsg string) *alipay.ErrorResponse {
+	return &alipay.ErrorResponse{Code: codeInvalidArgument, Msg: "Invalid Arguments", SubCode: subCode, SubMsg: subMsg}
+}
+
+func businessFailed(subCode, subMsg string) *alipay.ErrorResponse {
+	return &alipay.ErrorResponse{Code: codeBusinessFailed, Msg: "Business Failed", SubCode: subCode, SubMsg: subMsg}
+}
+
+// parseAmount 解析金额（元）为分
+func parseAmount(amount string) (int64, error) {
+	if amount == gopay.NULL {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 {
+		return 0, fmt.Errorf("negative amount: %s", amount)
+	}
+	return int64(f*100 + 0.5), nil
+}
+
+// formatAmount 分转为元，保留两位小数
+func formatAmount(fen int64) string {
+	return fmt.Sprintf("%d.%02d", fen/100, fen%100)
+}
+
+// rewriteTransport 将请求的 scheme、host 替换为模拟网关地址
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return t.base.RoundTrip(r)
+}
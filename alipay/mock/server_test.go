@@ -0,0 +1,166 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay"
+)
+
+const testAppId = "2016091200494382"
+
+func newTestClient(t *testing.T, srv *Server) *alipay.Client {
+	priKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := alipay.NewClient(testAppId, base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(priKey)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appCert, err := srv.IssueAppCert(&priKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.SetCertSnByContent(appCert, srv.RootCert(), srv.AlipayPublicCert()); err != nil {
+		t.Fatal(err)
+	}
+	client.AutoVerifySign(srv.AlipayPublicCert())
+	client.SetHttpClient(srv.HttpClient())
+	return client
+}
+
+func TestServer(t *testing.T) {
+	srv, err := NewServer(testAppId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	// 异步通知
+	var notified *alipay.NotifyRequest
+	notifySrv := httptest.NewServer(alipay.NewNotifyHandlerWithCert(srv.AlipayPublicCert()).
+		OnTrade(func(ctx context.Context, notifyReq *alipay.NotifyRequest) error {
+			notified = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Errorf("notify err: %v", err)
+		}))
+	defer notifySrv.Close()
+	client.SetNotifyUrl(notifySrv.URL)
+
+	// 预下单
+	bm := make(gopay.BodyMap)
+	bm.Set("subject", "预创建创建订单").
+		Set("out_trade_no", "GZ201907301420334577").
+		Set("total_amount", "100")
+	precreateRsp, err := client.TradePrecreate(ctx, bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if precreateRsp.Response.QrCode == gopay.NULL {
+		t.Fatalf("TradePrecreate: %+v", precreateRsp.Response)
+	}
+
+	// 未支付交易不可退款
+	refundBm := make(gopay.BodyMap)
+	refundBm.Set("out_trade_no", "GZ201907301420334577").
+		Set("refund_amount", "40").
+		Set("out_request_no", "GZ201907301420334577-1")
+	_, err = client.TradeRefund(ctx, refundBm)
+	var bizErr *alipay.BizErr
+	if !errors.As(err, &bizErr) || bizErr.SubCode != "ACQ.TRADE_STATUS_ERROR" {
+		t.Fatalf("TradeRefund before pay err: %v", err)
+	}
+
+	// 扫码支付并通知
+	if err = srv.NotifyPay(ctx, "", "GZ201907301420334577"); err != nil {
+		t.Fatal(err)
+	}
+	if notified == nil || notified.TradeStatus != alipay.TradeStatusSuccess || notified.TotalAmount != "100.00" {
+		t.Fatalf("OnTrade got: %+v", notified)
+	}
+
+	// 查询
+	queryBm := make(gopay.BodyMap)
+	queryBm.Set("out_trade_no", "GZ201907301420334577")
+	queryRsp, err := client.TradeQuery(ctx, queryBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRsp.Response.TradeStatus != alipay.TradeStatusSuccess || queryRsp.Response.TradeNo != notified.TradeNo {
+		t.Fatalf("TradeQuery: %+v", queryRsp.Response)
+	}
+
+	// 部分退款，重复请求不重复退款
+	for i := 0; i < 2; i++ {
+		refundRsp, err := client.TradeRefund(ctx, refundBm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if refundRsp.Response.RefundFee != "40.00" {
+			t.Fatalf("TradeRefund: %+v", refundRsp.Response)
+		}
+	}
+	// 超额退款
+	refundBm.Set("refund_amount", "60.01").Set("out_request_no", "GZ201907301420334577-2")
+	if _, err = client.TradeRefund(ctx, refundBm); !errors.As(err, &bizErr) {
+		t.Fatalf("TradeRefund over amount err: %v", err)
+	}
+
+	// 付款码支付
+	payBm := make(gopay.BodyMap)
+	payBm.Set("subject", "条码支付").
+		Set("scene", "bar_code").
+		Set("auth_code", "286248566432274952").
+		Set("out_trade_no", "GZ201909081743431443").
+		Set("total_amount", "0.01")
+	payRsp, err := client.TradePay(ctx, payBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payRsp.Response.TradeNo == gopay.NULL || payRsp.Response.TotalAmount != "0.01" {
+		t.Fatalf("TradePay: %+v", payRsp.Response)
+	}
+
+	// 已支付交易不可关闭
+	closeBm := make(gopay.BodyMap)
+	closeBm.Set("out_trade_no", "GZ201909081743431443")
+	if _, err = client.TradeClose(ctx, closeBm); !errors.As(err, &bizErr) || bizErr.SubCode != "ACQ.REASON_TRADE_STATUS_INVALID" {
+		t.Fatalf("TradeClose paid trade err: %v", err)
+	}
+	bm.Set("out_trade_no", "GZ201909081743431444")
+	if _, err = client.TradePrecreate(ctx, bm); err != nil {
+		t.Fatal(err)
+	}
+	closeBm.Set("out_trade_no", "GZ201909081743431444")
+	closeRsp, err := client.TradeClose(ctx, closeBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trade, _ := srv.Trade(closeRsp.Response.OutTradeNo); trade.TradeStatus != alipay.TradeStatusClosed {
+		t.Fatalf("TradeClose: %+v", trade)
+	}
+
+	// 请求签名与应用公钥不匹配，验签失败
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.SetAppPublicKey(&otherKey.PublicKey)
+	if _, err = client.TradeQuery(ctx, queryBm); !errors.As(err, &bizErr) || bizErr.SubCode != "isv.invalid-signature" {
+		t.Fatalf("TradeQuery with wrong app public key err: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package alipay
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,6 +14,8 @@ import (
 // req：*http.Request
 // 返回参数bm：Notify请求的参数
 // 返回参数err：错误信息
+// 注意：仅解析参数，不验签、不去重：去重需在验签通过后进行，且业务处理成功后才能记为已处理，
+// 请使用 NotifyHandler.SetDeduper()，或验签后调用 gopay.HandleNotifyOnce(ctx, deduper, NotifyDedupeKey(bm.GetString("notify_id")), fn)
 // 文档：https://opendocs.alipay.com/open/203/105286
 func ParseNotifyToBodyMap(req *http.Request) (bm gopay.BodyMap, err error) {
 	if err = req.ParseForm(); err != nil {
@@ -43,6 +46,21 @@ func ParseNotifyByURLValues(value url.Values) (bm gopay.BodyMap, err error) {
 	return
 }
 
+// CheckNotifyDuplicate 根据 notify_id 对异步通知去重，已处理成功返回 gopay.NotifyDuplicateErr，正在处理返回 gopay.NotifyProcessingErr
+// 注意：需在验签通过后调用，业务处理成功后调用 deduper.Commit(ctx, NotifyDedupeKey(notifyId))，失败时调用 deduper.Delete() 以便支付宝重试时可再次处理
+// 推荐直接使用 gopay.HandleNotifyOnce(ctx, deduper, NotifyDedupeKey(notifyId), fn)
+func CheckNotifyDuplicate(ctx context.Context, deduper gopay.NotifyDeduper, notifyId string) error {
+	if notifyId == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "notify_id")
+	}
+	return gopay.CheckNotifyDuplicate(ctx, deduper, NotifyDedupeKey(notifyId))
+}
+
+// NotifyDedupeKey 通知去重使用的 key
+func NotifyDedupeKey(notifyId string) string {
+	return "alipay:" + notifyId
+}
+
 // Deprecated
 // 推荐使用 ParseNotifyToBodyMap()，以防阿里云通知参数变动，NotifyRequest 无法解析。
 // 解析支付宝支付异步通知的参数到Struct
@@ -51,42 +69,47 @@ func ParseNotifyByURLValues(value url.Values) (bm gopay.BodyMap, err error) {
 // 返回参数err：错误信息
 // 文档：https://opendocs.alipay.com/open/203/105286
 func ParseNotifyResult(req *http.Request) (notifyReq *NotifyRequest, err error) {
-	notifyReq = new(NotifyRequest)
 	if err = req.ParseForm(); err != nil {
 		return
 	}
-	notifyReq.NotifyTime = req.Form.Get("notify_time")
-	notifyReq.NotifyType = req.Form.Get("notify_type")
-	notifyReq.NotifyId = req.Form.Get("notify_id")
-	notifyReq.AppId = req.Form.Get("app_id")
-	notifyReq.Charset = req.Form.Get("charset")
-	notifyReq.Version = req.Form.Get("version")
-	notifyReq.SignType = req.Form.Get("sign_type")
-	notifyReq.Sign = req.Form.Get("sign")
-	notifyReq.AuthAppId = req.Form.Get("auth_app_id")
-	notifyReq.TradeNo = req.Form.Get("trade_no")
-	notifyReq.OutTradeNo = req.Form.Get("out_trade_no")
-	notifyReq.OutBizNo = req.Form.Get("out_biz_no")
-	notifyReq.BuyerId = req.Form.Get("buyer_id")
-	notifyReq.BuyerLogonId = req.Form.Get("buyer_logon_id")
-	notifyReq.SellerId = req.Form.Get("seller_id")
-	notifyReq.SellerEmail = req.Form.Get("seller_email")
-	notifyReq.TradeStatus = req.Form.Get("trade_status")
-	notifyReq.TotalAmount = req.Form.Get("total_amount")
-	notifyReq.ReceiptAmount = req.Form.Get("receipt_amount")
-	notifyReq.InvoiceAmount = req.Form.Get("invoice_amount")
-	notifyReq.BuyerPayAmount = req.Form.Get("buyer_pay_amount")
-	notifyReq.PointAmount = req.Form.Get("point_amount")
-	notifyReq.RefundFee = req.Form.Get("refund_fee")
-	notifyReq.Subject = req.Form.Get("subject")
-	notifyReq.Body = req.Form.Get("body")
-	notifyReq.GmtCreate = req.Form.Get("gmt_create")
-	notifyReq.GmtPayment = req.Form.Get("gmt_payment")
-	notifyReq.GmtRefund = req.Form.Get("gmt_refund")
-	notifyReq.GmtClose = req.Form.Get("gmt_close")
-	notifyReq.PassbackParams = req.Form.Get("passback_params")
+	return parseNotifyForm(req.Form)
+}
+
+// 解析异步通知表单到 NotifyRequest
+func parseNotifyForm(form url.Values) (notifyReq *NotifyRequest, err error) {
+	notifyReq = new(NotifyRequest)
+	notifyReq.NotifyTime = form.Get("notify_time")
+	notifyReq.NotifyType = form.Get("notify_type")
+	notifyReq.NotifyId = form.Get("notify_id")
+	notifyReq.AppId = form.Get("app_id")
+	notifyReq.Charset = form.Get("charset")
+	notifyReq.Version = form.Get("version")
+	notifyReq.SignType = form.Get("sign_type")
+	notifyReq.Sign = form.Get("sign")
+	notifyReq.AuthAppId = form.Get("auth_app_id")
+	notifyReq.TradeNo = form.Get("trade_no")
+	notifyReq.OutTradeNo = form.Get("out_trade_no")
+	notifyReq.OutBizNo = form.Get("out_biz_no")
+	notifyReq.BuyerId = form.Get("buyer_id")
+	notifyReq.BuyerLogonId = form.Get("buyer_logon_id")
+	notifyReq.SellerId = form.Get("seller_id")
+	notifyReq.SellerEmail = form.Get("seller_email")
+	notifyReq.TradeStatus = form.Get("trade_status")
+	notifyReq.TotalAmount = form.Get("total_amount")
+	notifyReq.ReceiptAmount = form.Get("receipt_amount")
+	notifyReq.InvoiceAmount = form.Get("invoice_amount")
+	notifyReq.BuyerPayAmount = form.Get("buyer_pay_amount")
+	notifyReq.PointAmount = form.Get("point_amount")
+	notifyReq.RefundFee = form.Get("refund_fee")
+	notifyReq.Subject = form.Get("subject")
+	notifyReq.Body = form.Get("body")
+	notifyReq.GmtCreate = form.Get("gmt_create")
+	notifyReq.GmtPayment = form.Get("gmt_payment")
+	notifyReq.GmtRefund = form.Get("gmt_refund")
+	notifyReq.GmtClose = form.Get("gmt_close")
+	notifyReq.PassbackParams = form.Get("passback_params")
 
-	billList := req.Form.Get("fund_bill_list")
+	billList := form.Get("fund_bill_list")
 	if billList != gopay.NULL {
 		bills := make([]*FundBillListInfo, 0)
 		if err = json.Unmarshal([]byte(billList), &bills); err != nil {
@@ -97,7 +120,7 @@ func ParseNotifyResult(req *http.Request) (notifyReq *NotifyRequest, err error)
 		notifyReq.FundBillList = nil
 	}
 
-	detailList := req.Form.Get("voucher_detail_list")
+	detailList := form.Get("voucher_detail_list")
 	if detailList != gopay.NULL {
 		details := make([]*NotifyVoucherDetail, 0)
 		if err = json.Unmarshal([]byte(detailList), &details); err != nil {
@@ -0,0 +1,136 @@
+package alipay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// NotifyTypeTradeStatusSync 交易状态同步通知（支付、退款、关闭）
+	NotifyTypeTradeStatusSync = "trade_status_sync"
+
+	notifyAckSuccess = "success"
+	notifyAckFail    = "fail"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 支付宝异步通知处理器，实现 http.Handler
+// 依次完成：解析表单 -> 验签 -> 按 notify_type 分发到回调 -> 应答 success/fail
+// 回调返回 error 时应答 fail，支付宝会按策略重试通知
+// 未注册对应回调的通知直接应答 success；设置 NotifyDeduper 后，已处理成功的重复 notify_id 通知不再回调并直接应答 success，处理中的重复通知应答 fail 等待重试
+type NotifyHandler struct {
+	verify   func(bm gopay.BodyMap) (bool, error)
+	deduper  gopay.NotifyDeduper
+	onTrade  func(ctx context.Context, notifyReq *NotifyRequest) error
+	onNotify func(ctx context.Context, bm gopay.BodyMap) error
+	onError  func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化异步通知处理器（公钥模式）
+// alipayPublicKey：支付宝平台获取的支付宝公钥
+func NewNotifyHandler(alipayPublicKey string) *NotifyHandler {
+	return &NotifyHandler{
+		verify: func(bm gopay.BodyMap) (bool, error) {
+			return VerifySign(alipayPublicKey, bm)
+		},
+	}
+}
+
+// NewNotifyHandlerWithCert 初始化异步通知处理器（公钥证书模式）
+// alipayPublicKeyCert：支付宝公钥证书存放路径 alipayPublicCert.crt 或文件内容[]byte
+func NewNotifyHandlerWithCert(alipayPublicKeyCert any) *NotifyHandler {
+	return &NotifyHandler{
+		verify: func(bm gopay.BodyMap) (bool, error) {
+			return VerifySignWithCert(alipayPublicKeyCert, bm)
+		},
+	}
+}
+
+// SetDeduper 设置通知去重存储，多副本部署时请使用共享存储
+func (h *NotifyHandler) SetDeduper(deduper gopay.NotifyDeduper) *NotifyHandler {
+	h.deduper = deduper
+	return h
+}
+
+// OnTrade 注册交易状态同步通知（notify_type=trade_status_sync）回调，支付、退款、关闭均通过此通知，可根据 TradeStatus 区分
+func (h *NotifyHandler) OnTrade(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onTrade = fn
+	return h
+}
+
+// OnNotify 注册其他类型通知回调，参数为验签通过的通知参数
+func (h *NotifyHandler) OnNotify(fn func(ctx context.Context, bm gopay.BodyMap) error) *NotifyHandler {
+	h.onNotify = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(notifyAckFail))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(notifyAckSuccess))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	if err = req.ParseForm(); err != nil {
+		return err
+	}
+	// 验签会移除 sign、sign_type，需单独解析一份
+	signBm, err := ParseNotifyByURLValues(req.Form)
+	if err != nil {
+		return err
+	}
+	ok, err := h.verify(signBm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, "notify sign verify failed")
+	}
+	ctx := req.Context()
+	if h.deduper == nil {
+		return h.dispatch(ctx, req.Form)
+	}
+	notifyId := req.Form.Get("notify_id")
+	if notifyId == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "notify_id")
+	}
+	return gopay.HandleNotifyOnce(ctx, h.deduper, NotifyDedupeKey(notifyId), func(ctx context.Context) error {
+		return h.dispatch(ctx, req.Form)
+	})
+}
+
+func (h *NotifyHandler) dispatch(ctx context.Context, form url.Values) (err error) {
+	if form.Get("notify_type") == NotifyTypeTradeStatusSync && h.onTrade != nil {
+		notifyReq, err := parseNotifyForm(form)
+		if err != nil {
+			return err
+		}
+		return h.onTrade(ctx, notifyReq)
+	}
+	if h.onNotify != nil {
+		bm, err := ParseNotifyByURLValues(form)
+		if err != nil {
+			return err
+		}
+		return h.onNotify(ctx, bm)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pay/crypto/xpem"
+	"github.com/go-pay/crypto/xrsa"
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay/cert"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	priKey, err := xpem.DecodePrivateKey([]byte(xrsa.FormatAlipayPrivateKey(cert.PrivateKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("notify_type", NotifyTypeTradeStatusSync).
+		Set("notify_id", "2020011000222172519052691418981337").
+		Set("app_id", cert.Appid).
+		Set("out_trade_no", "GZ202001101420131999").
+		Set("trade_no", "2020011022001432791000051426").
+		Set("trade_status", TradeStatusSuccess).
+		Set("total_amount", "0.01")
+	// 异步通知的 sign_type 不参与签名
+	sign, err := GetRsaSign(bm, RSA2, priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := make(url.Values)
+	bm.Range(func(k string, v any) bool {
+		form.Set(k, bm.GetString(k))
+		return true
+	})
+	form.Set("sign_type", RSA2)
+	form.Set("sign", sign)
+
+	var got *NotifyRequest
+	h := NewNotifyHandler(cert.PublicKey).
+		OnTrade(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Debugf("notify err: %v", err)
+		})
+
+	serve := func(f url.Values) string {
+		req := httptest.NewRequest(http.MethodPost, "/notify/alipay", strings.NewReader(f.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if ack := serve(form); ack != notifyAckSuccess {
+		t.Fatalf("ack = %s, want %s", ack, notifyAckSuccess)
+	}
+	if got == nil || got.OutTradeNo != "GZ202001101420131999" || got.TradeStatus != TradeStatusSuccess {
+		t.Fatalf("OnTrade got: %+v", got)
+	}
+
+	// 设置去重后，重复通知不再回调
+	calls := 0
+	h.SetDeduper(gopay.NewMemoryNotifyDeduper(100, time.Hour)).
+		OnTrade(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			calls++
+			return nil
+		})
+	if ack := serve(form); ack != notifyAckSuccess || calls != 1 {
+		t.Fatalf("first ack = %s, calls = %d", ack, calls)
+	}
+	if ack := serve(form); ack != notifyAckSuccess || calls != 1 {
+		t.Fatalf("duplicate ack = %s, calls = %d", ack, calls)
+	}
+	h.SetDeduper(nil)
+
+	// 篡改金额，验签失败
+	form.Set("total_amount", "100.00")
+	if ack := serve(form); ack != notifyAckFail {
+		t.Errorf("tampered ack = %s, want %s", ack, notifyAckFail)
+	}
+
+	// 回调返回错误，应答 fail
+	form.Set("total_amount", "0.01")
+	h.OnTrade(func(ctx context.Context, notifyReq *NotifyRequest) error {
+		return errors.New("db unavailable")
+	})
+	if ack := serve(form); ack != notifyAckFail {
+		t.Errorf("callback err ack = %s, want %s", ack, notifyAckFail)
+	}
+}
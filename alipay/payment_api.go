@@ -17,6 +17,9 @@ func (a *Client) TradePay(ctx context.Context, bm gopay.BodyMap) (aliRsp *TradeP
 		return nil, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradePay, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.pay"); err != nil {
 		return nil, err
 	}
@@ -40,6 +43,9 @@ func (a *Client) TradePrecreate(ctx context.Context, bm gopay.BodyMap) (aliRsp *
 		return nil, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradePrecreate, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.precreate"); err != nil {
 		return nil, err
 	}
@@ -67,6 +73,9 @@ func (a *Client) TradeAppPay(ctx context.Context, bm gopay.BodyMap) (orderStr st
 		return gopay.NULL, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeAppPay, bm); err != nil {
+		return gopay.NULL, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.app.pay"); err != nil {
 		return gopay.NULL, err
 	}
@@ -83,6 +92,9 @@ func (a *Client) TradeWapPay(ctx context.Context, bm gopay.BodyMap) (payUrl stri
 		return gopay.NULL, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeWapPay, bm); err != nil {
+		return gopay.NULL, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.wap.pay"); err != nil {
 		return gopay.NULL, err
 	}
@@ -99,6 +111,9 @@ func (a *Client) TradePagePay(ctx context.Context, bm gopay.BodyMap) (payUrl str
 		return gopay.NULL, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradePagePay, bm); err != nil {
+		return gopay.NULL, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.page.pay"); err != nil {
 		return gopay.NULL, err
 	}
@@ -114,6 +129,9 @@ func (a *Client) TradeCreate(ctx context.Context, bm gopay.BodyMap) (aliRsp *Tra
 		return nil, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeCreate, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.create"); err != nil {
 		return nil, err
 	}
@@ -159,6 +177,9 @@ func (a *Client) TradeQuery(ctx context.Context, bm gopay.BodyMap) (aliRsp *Trad
 		return nil, errors.New("out_trade_no and trade_no are not allowed to be null at the same time")
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeQuery, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.query"); err != nil {
 		return nil, err
 	}
@@ -181,6 +202,9 @@ func (a *Client) TradeCancel(ctx context.Context, bm gopay.BodyMap) (aliRsp *Tra
 		return nil, errors.New("out_trade_no and trade_no are not allowed to be null at the same time")
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeCancel, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.cancel"); err != nil {
 		return nil, err
 	}
@@ -203,6 +227,9 @@ func (a *Client) TradeClose(ctx context.Context, bm gopay.BodyMap) (aliRsp *Trad
 		return nil, errors.New("out_trade_no and trade_no are not allowed to be null at the same time")
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeClose, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.close"); err != nil {
 		return nil, err
 	}
@@ -229,6 +256,9 @@ func (a *Client) TradeRefund(ctx context.Context, bm gopay.BodyMap) (aliRsp *Tra
 		return nil, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeRefund, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.refund"); err != nil {
 		return nil, err
 	}
@@ -281,6 +311,9 @@ func (a *Client) TradeFastPayRefundQuery(ctx context.Context, bm gopay.BodyMap)
 		return nil, err
 	}
 	var bs []byte
+	if err = a.checkRequest(SchemaTradeFastPayRefundQuery, bm); err != nil {
+		return nil, err
+	}
 	if bs, err = a.doAliPay(ctx, bm, "alipay.trade.fastpay.refund.query"); err != nil {
 		return nil, err
 	}
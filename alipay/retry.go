@@ -0,0 +1,53 @@
+package alipay
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// retrySubCodes 支付宝可使用相同 out_trade_no 重试的业务错误码
+var retrySubCodes = map[string]bool{
+	"ACQ.SYSTEM_ERROR":     true, // 系统错误
+	"aop.ACQ.SYSTEM_ERROR": true,
+	"isp.unknow-error":     true, // 服务暂不可用
+	"isp.unknown-error":    true,
+}
+
+// RetryClassifier 支付宝重试判断
+// 支付宝接口以 out_trade_no、out_request_no 等业务单号保证幂等，按 xhttp.IdempotentRetryClassifier 重试网络错误与 HTTP 状态码，
+// 另外 code 为 20000（服务不可用）或 sub_code 为 ACQ.SYSTEM_ERROR 等系统错误时重试
+func RetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if xhttp.IdempotentRetryClassifier(req, res, body, err) {
+		return true
+	}
+	if err != nil || len(body) == 0 {
+		return false
+	}
+	rsp := make(map[string]json.RawMessage)
+	if json.Unmarshal(body, &rsp) != nil {
+		return false
+	}
+	for k, v := range rsp {
+		if !strings.HasSuffix(k, "_response") {
+			continue
+		}
+		errRsp := new(ErrorResponse)
+		if json.Unmarshal(v, errRsp) != nil {
+			return false
+		}
+		return errRsp.Code == "20000" || retrySubCodes[errRsp.SubCode]
+	}
+	return false
+}
+
+func withClassifier(policy *xhttp.RetryPolicy) *xhttp.RetryPolicy {
+	if policy == nil || policy.Classifier != nil {
+		return policy
+	}
+	p := *policy
+	p.Classifier = RetryClassifier
+	return &p
+}
@@ -0,0 +1,36 @@
+package alipay
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 统一收单核心接口的 BodyMap 校验规则，由强类型请求参数（见 builder.go）的 validate 标签生成，对应方法默认仅校验必填参数，可通过 client.SetValidateRequest(true) 开启请求前自动校验，或在调用前按需校验：alipay.SchemaTradePrecreate.Validate(bm)
+// 错误中包含全部不合法的字段路径，如：[invalid parameter], total_amount: must be > 0; goods_detail[0].goods_id: is required
+var (
+	// SchemaTradePay 统一收单交易支付，用于 TradePay()
+	SchemaTradePay = gopay.SchemaOf(&TradePayRequest{})
+	// SchemaTradePrecreate 统一收单线下交易预创建，用于 TradePrecreate()
+	SchemaTradePrecreate = gopay.SchemaOf(&TradeOrderRequest{})
+	// SchemaTradeAppPay app支付，用于 TradeAppPay()
+	SchemaTradeAppPay = SchemaTradePrecreate
+	// SchemaTradeWapPay 手机网站支付，用于 TradeWapPay()
+	SchemaTradeWapPay = SchemaTradePrecreate.Extend(
+		gopay.String("quit_url").MaxLen(400),
+	)
+	// SchemaTradePagePay 电脑网站支付，用于 TradePagePay()
+	SchemaTradePagePay = SchemaTradePrecreate.Extend(
+		gopay.Any("qr_pay_mode").Enum("0", "1", "2", "3", "4"),
+	)
+	// SchemaTradeCreate 统一收单交易创建，用于 TradeCreate()
+	SchemaTradeCreate = gopay.SchemaOf(&TradeCreateRequest{})
+	// SchemaTradeQuery 统一收单交易查询，用于 TradeQuery()
+	SchemaTradeQuery = gopay.SchemaOf(&TradeQueryRequest{})
+	// SchemaTradeCancel 统一收单交易撤销，用于 TradeCancel()
+	SchemaTradeCancel = gopay.SchemaOf(&TradeCancelRequest{})
+	// SchemaTradeClose 统一收单交易关闭，用于 TradeClose()
+	SchemaTradeClose = gopay.SchemaOf(&TradeCloseRequest{})
+	// SchemaTradeRefund 统一收单交易退款，用于 TradeRefund()
+	SchemaTradeRefund = gopay.SchemaOf(&TradeRefundRequest{})
+	// SchemaTradeFastPayRefundQuery 统一收单交易退款查询，用于 TradeFastPayRefundQuery()
+	SchemaTradeFastPayRefundQuery = gopay.SchemaOf(&TradeFastPayRefundQueryRequest{})
+)
@@ -0,0 +1,81 @@
+package alipay
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay/cert"
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+func TestTradeSchema(t *testing.T) {
+	bm := make(gopay.BodyMap)
+	bm.Set("refund_amount", "-1").
+		Set("refund_goods_detail", []gopay.BodyMap{{"refund_amount": "1.001"}})
+	err := SchemaTradeRefund.Validate(bm)
+	want := "[invalid parameter], out_trade_no: is required when trade_no is empty; refund_amount: must be > 0; " +
+		"refund_goods_detail[0].goods_id: is required; refund_goods_detail[0].refund_amount: must be a decimal amount with at most 2 decimal places"
+	if !errors.Is(err, gopay.MissParamErr) || err.Error() != want {
+		t.Errorf("err = %v", err)
+	}
+
+	bm = make(gopay.BodyMap)
+	bm.Set("out_trade_no", "GZ201909081743431443").
+		Set("total_amount", 88.88).
+		Set("subject", "预创建").
+		Set("timeout_express", "2 hours")
+	if err = SchemaTradePrecreate.Validate(bm); !errors.Is(err, gopay.ParamValidateErr) || errors.Is(err, gopay.MissParamErr) {
+		t.Errorf("err = %v", err)
+	}
+	bm.Set("timeout_express", "2h")
+	if err = SchemaTradePrecreate.Validate(bm); err != nil {
+		t.Errorf("err = %v", err)
+	}
+
+	// 与强类型请求参数的校验规则一致
+	req := &TradeFastPayRefundQueryRequest{TradeNo: "2013112611001004680073956707"}
+	if _, err = req.BodyMap(); err == nil || err.Error() != "[invalid parameter], out_request_no: is required" {
+		t.Errorf("err = %v", err)
+	}
+	bm = gopay.BodyMap{"trade_no": req.TradeNo}
+	if err = SchemaTradeFastPayRefundQuery.Validate(bm); err == nil || err.Error() != "[invalid parameter], out_request_no: is required" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	errOffline := errors.New("offline")
+	hits := 0
+	c, err := NewClient(cert.Appid, cert.PrivateKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetHttpClient(xhttp.NewClient().SetHttpTransport(&http.Transport{Proxy: func(*http.Request) (*url.URL, error) {
+		hits++
+		return nil, errOffline
+	}}))
+
+	bm := make(gopay.BodyMap)
+	bm.Set("out_trade_no", "GZ201909081743431443").
+		Set("total_amount", "-1").
+		Set("subject", "预创建")
+	// 默认不校验，直接发起请求
+	if _, err = c.TradePrecreate(ctx, bm); !errors.Is(err, errOffline) || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+	// 开启后校验不通过，不发起请求
+	c.SetValidateRequest(true)
+	if _, err = c.TradePrecreate(ctx, bm); !errors.Is(err, gopay.ParamValidateErr) || err.Error() != "[invalid parameter], total_amount: must be > 0" || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+	if _, err = c.TradeRefund(ctx, gopay.BodyMap{"out_trade_no": "GZ201909081743431443", "refund_amount": "1.001"}); !errors.Is(err, gopay.ParamValidateErr) || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+	bm.Set("total_amount", "88.88")
+	if _, err = c.TradePrecreate(ctx, bm); !errors.Is(err, errOffline) || hits != 2 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+}
@@ -0,0 +1,27 @@
+package alipay
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 交易状态 trade_status
+	TradeStatusWaitBuyerPay = "WAIT_BUYER_PAY" // 交易创建，等待买家付款
+	TradeStatusClosed       = "TRADE_CLOSED"   // 未付款交易超时关闭，或支付完成后全额退款
+	TradeStatusSuccess      = "TRADE_SUCCESS"  // 交易支付成功
+	TradeStatusFinished     = "TRADE_FINISHED" // 交易结束，不可退款
+)
+
+// NormalizeTradeStatus 支付宝 trade_status 转统一订单状态
+// 适用于 client.TradeQuery() 返回及异步通知中的 trade_status
+func NormalizeTradeStatus(tradeStatus string) gopay.TradeStatus {
+	switch tradeStatus {
+	case TradeStatusWaitBuyerPay:
+		return gopay.TradeStatusPending
+	case TradeStatusSuccess, TradeStatusFinished:
+		return gopay.TradeStatusSucceeded
+	case TradeStatusClosed:
+		return gopay.TradeStatusClosed
+	}
+	return gopay.TradeStatusUnknown
+}
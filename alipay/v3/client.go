@@ -51,7 +51,7 @@ func NewClientV3(appid, privateKey string, isProd bool) (client *ClientV3, err e
 		IsProd:        isProd,
 		privateKey:    priKey,
 		DebugSwitch:   gopay.DebugOff,
-		logger:        logger,
+		logger:        gopay.NewRedactLogger(logger, nil),
 		requestIdFunc: defaultRequestIdFunc,
 		hc:            xhttp.NewClient(),
 	}
@@ -122,7 +122,7 @@ func (a *ClientV3) SetHttpClient(client *xhttp.Client) *ClientV3 {
 // SetLogger 设置自定义的logger
 func (a *ClientV3) SetLogger(logger xlog.XLogger) *ClientV3 {
 	if logger != nil {
-		a.logger = logger
+		a.logger = gopay.NewRedactLogger(logger, nil)
 	}
 	return a
 }
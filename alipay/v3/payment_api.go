@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-pay/xtime"
 	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay/bill"
 )
 
 // 统一收单交易支付接口 alipay.trade.pay
@@ -220,6 +221,21 @@ func (a *ClientV3) DataBillDownloadUrlQuery(ctx context.Context, bm gopay.BodyMa
 	return aliRsp, a.autoVerifySignByCert(res, bs)
 }
 
+// 下载并解压对账单，bm 同 DataBillDownloadUrlQuery()
+// 使用完毕需调用 file.Close() 删除临时文件
+// bill_type = trade：file.TradeRecords()、file.TradeSummaries()
+// bill_type = signcustomer：file.FundFlowRecords()、file.FundFlowSummaries()
+func (a *ClientV3) DataBillDownload(ctx context.Context, bm gopay.BodyMap) (file *bill.File, err error) {
+	aliRsp, err := a.DataBillDownloadUrlQuery(ctx, bm)
+	if err != nil {
+		return nil, err
+	}
+	if aliRsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(`{"status_code":%d,"code":"%s","message":"%s"}`, aliRsp.StatusCode, aliRsp.ErrResponse.Code, aliRsp.ErrResponse.Message)
+	}
+	return bill.Download(ctx, a.hc, aliRsp.BillDownloadUrl)
+}
+
 // 统一收单线下交易预创建 alipay.trade.precreate
 // StatusCode = 200 is success
 func (a *ClientV3) TradePrecreate(ctx context.Context, bm gopay.BodyMap) (aliRsp *TradePrecreateRsp, err error) {
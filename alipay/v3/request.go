@@ -76,7 +76,7 @@ func (a *ClientV3) doPost(ctx context.Context, bm gopay.BodyMap, uri, authorizat
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req() // default json
+	req := a.hc.Req().SetApi(gopay.ProviderAlipayV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
@@ -111,7 +111,7 @@ func (a *ClientV3) doPatch(ctx context.Context, bm gopay.BodyMap, uri, authoriza
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req() // default json
+	req := a.hc.Req().SetApi(gopay.ProviderAlipayV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
@@ -146,7 +146,7 @@ func (a *ClientV3) doPut(ctx context.Context, bm gopay.BodyMap, uri, authorizati
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req() // default json
+	req := a.hc.Req().SetApi(gopay.ProviderAlipayV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
@@ -181,7 +181,7 @@ func (a *ClientV3) doGet(ctx context.Context, uri, authorization, aat string) (r
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req() // default json
+	req := a.hc.Req().SetApi(gopay.ProviderAlipayV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
@@ -212,7 +212,7 @@ func (a *ClientV3) doProdPostFile(ctx context.Context, bm gopay.BodyMap, uri, au
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req(xhttp.TypeMultipartFormData)
+	req := a.hc.Req(xhttp.TypeMultipartFormData).SetApi(gopay.ProviderAlipayV3, uri)
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
@@ -246,7 +246,7 @@ func (a *ClientV3) doDelete(ctx context.Context, bm gopay.BodyMap, uri, authoriz
 	if a.proxyHost != "" {
 		url = a.proxyHost + uri
 	}
-	req := a.hc.Req() // default json
+	req := a.hc.Req().SetApi(gopay.ProviderAlipayV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, a.requestIdFunc.RequestId())
 	req.Header.Add(HeaderSdkVersion, "gopay/"+gopay.Version)
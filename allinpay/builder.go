@@ -0,0 +1,101 @@
+package allinpay
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 核心支付接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度），再传给对应的方法
+// cusid、appid、signtype、version、randomstr 由 Client 自动填充
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&allinpay.PayRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], trxamt: must be > 0
+//	}
+//	rsp, err := client.Pay(ctx, bm)
+
+// PayRequest 统一支付请求参数，Pay() 使用，paytype 取值见 PayTypeWXScan 等
+type PayRequest struct {
+	Trxamt    int64         `json:"trxamt" validate:"required,gt=0"` // 单位：分
+	Reqsn     string        `json:"reqsn" validate:"required,maxlen=32"`
+	Paytype   string        `json:"paytype" validate:"required,enum=W01|W02|W03|A01|A02|A03|Q01|Q02|U01|U02|S01"`
+	Body      string        `json:"body,omitempty" validate:"maxlen=100"`
+	Remark    string        `json:"remark,omitempty" validate:"maxlen=160"`
+	Validtime string        `json:"validtime,omitempty" validate:"maxlen=4"` // 有效时间，单位：分钟
+	Acct      string        `json:"acct,omitempty" validate:"maxlen=32"`     // 微信 openid、支付宝 userid 等
+	NotifyUrl string        `json:"notify_url,omitempty" validate:"maxlen=256"`
+	LimitPay  string        `json:"limit_pay,omitempty" validate:"enum=no_credit"`
+	SubAppid  string        `json:"sub_appid,omitempty" validate:"maxlen=32"`
+	GoodsTag  string        `json:"goods_tag,omitempty" validate:"maxlen=32"`
+	FrontUrl  string        `json:"front_url,omitempty" validate:"maxlen=128"`
+	Extra     gopay.BodyMap `json:"-"`
+}
+
+// ScanPayRequest 统一扫码（付款码）请求参数，ScanPay() 使用
+type ScanPayRequest struct {
+	Trxamt   int64         `json:"trxamt" validate:"required,gt=0"`
+	Reqsn    string        `json:"reqsn" validate:"required,maxlen=32"`
+	Authcode string        `json:"authcode" validate:"required,maxlen=32"`
+	Terminfo string        `json:"terminfo" validate:"required,maxlen=256"` // 终端信息，json 字符串
+	Body     string        `json:"body,omitempty" validate:"maxlen=100"`
+	Remark   string        `json:"remark,omitempty" validate:"maxlen=160"`
+	LimitPay string        `json:"limit_pay,omitempty" validate:"enum=no_credit"`
+	GoodsTag string        `json:"goods_tag,omitempty" validate:"maxlen=32"`
+	Extra    gopay.BodyMap `json:"-"`
+}
+
+// RefundRequest 统一退款、撤销请求参数，Refund()、Cancel() 使用，oldreqsn 与 oldtrxid 必填其一
+type RefundRequest struct {
+	Trxamt   int64         `json:"trxamt" validate:"required,gt=0"`
+	Reqsn    string        `json:"reqsn" validate:"required,maxlen=32"` // 退款单号
+	Oldreqsn string        `json:"oldreqsn,omitempty" validate:"required_without=oldtrxid,maxlen=32"`
+	Oldtrxid string        `json:"oldtrxid,omitempty" validate:"maxlen=20"`
+	Remark   string        `json:"remark,omitempty" validate:"maxlen=160"`
+	Extra    gopay.BodyMap `json:"-"`
+}
+
+// QueryRequest 交易结果查询请求参数，QueryTrade() 使用，reqsn 与 trxid 必填其一
+type QueryRequest struct {
+	Reqsn string        `json:"reqsn,omitempty" validate:"required_without=trxid,maxlen=32"`
+	Trxid string        `json:"trxid,omitempty" validate:"maxlen=20"`
+	Extra gopay.BodyMap `json:"-"`
+}
+
+// CloseRequest 订单关闭请求参数，Close() 使用，oldreqsn 与 oldtrxid 必填其一
+type CloseRequest struct {
+	Oldreqsn string        `json:"oldreqsn,omitempty" validate:"required_without=oldtrxid,maxlen=32"`
+	Oldtrxid string        `json:"oldtrxid,omitempty" validate:"maxlen=20"`
+	Extra    gopay.BodyMap `json:"-"`
+}
+
+// BodyMap 校验并转换为 Pay() 的请求参数
+func (r *PayRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 ScanPay() 的请求参数
+func (r *ScanPayRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 Refund()、Cancel() 的请求参数
+func (r *RefundRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 QueryTrade() 的请求参数
+func (r *QueryRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 Close() 的请求参数
+func (r *CloseRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+func buildBodyMap(req any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(req, extra)
+}
@@ -0,0 +1,31 @@
+package allinpay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	bm, err := (&PayRequest{Trxamt: 1, Reqsn: "larry01", Paytype: PayTypeWXJS, Acct: "openid", Extra: gopay.BodyMap{"sub_appid": "wx01"}}).BodyMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := bm.EncodeURLParams(); p != "acct=openid&paytype=W02&reqsn=larry01&sub_appid=wx01&trxamt=1" {
+		t.Errorf("params = %s", p)
+	}
+	_, err = (&RefundRequest{Trxamt: 1, Reqsn: "r01"}).BodyMap()
+	if !errors.Is(err, gopay.MissParamErr) || err.Error() != "[invalid parameter], oldreqsn: is required when oldtrxid is empty" {
+		t.Errorf("err = %v", err)
+	}
+	if _, err = (&PayRequest{Trxamt: 1, Reqsn: "larry01", Paytype: "X01"}).BodyMap(); !errors.Is(err, gopay.ParamValidateErr) {
+		t.Errorf("err = %v", err)
+	}
+	if bm, err = (&QueryRequest{Trxid: "231008133303"}).BodyMap(); err != nil || bm.EncodeURLParams() != "trxid=231008133303" {
+		t.Errorf("bm = %v, err = %v", bm, err)
+	}
+	if _, err = (&QueryRequest{}).BodyMap(); !errors.Is(err, gopay.MissParamErr) || err.Error() != "[invalid parameter], reqsn: is required when trxid is empty" {
+		t.Errorf("err = %v", err)
+	}
+}
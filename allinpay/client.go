@@ -127,7 +127,7 @@ func (c *Client) doPost(ctx context.Context, path string, bm gopay.BodyMap) (bs
 	if !c.isProd {
 		url = sandboxBaseUrl
 	}
-	res, bs, err := c.hc.Req(xhttp.TypeFormData).Post(url + path).SendString(param).EndBytes(ctx)
+	res, bs, err := c.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAllinpay, path).Post(url + path).SendString(param).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +138,8 @@ func (c *Client) doPost(ctx context.Context, path string, bm gopay.BodyMap) (bs
 }
 
 // SetHttpClient 设置自定义的xhttp.Client
+// 本渠道未提供 SetRetryPolicy()：client 的重试策略 Classifier 为空时按 xhttp.DefaultRetryClassifier 判断，
+// 下单、退款等 POST 请求仅在连接未建立时重试，请勿设置 xhttp.IdempotentRetryClassifier，避免重复支付、退款
 func (c *Client) SetHttpClient(client *xhttp.Client) {
 	c.hc = client
 }
@@ -98,3 +98,30 @@ type CloseRsp struct {
 	RspBase
 	TrxStatus string `json:"trxstatus"`
 }
+
+// NotifyRequest 交易结果通知
+type NotifyRequest struct {
+	Appid       string `json:"appid"`
+	Cusid       string `json:"cusid"`
+	TrxCode     string `json:"trxcode"`
+	Trxid       string `json:"trxid"`
+	InitAmt     string `json:"initamt"`
+	TrxAmt      string `json:"trxamt"`
+	TrxDate     string `json:"trxdate"`
+	PayTime     string `json:"paytime"`
+	ChnlTrxId   string `json:"chnltrxid"`
+	TrxStatus   string `json:"trxstatus"`
+	CusOrderId  string `json:"cusorderid"`
+	OutTrxId    string `json:"outtrxid"`
+	TrxReserved string `json:"trxreserved"`
+	Acct        string `json:"acct"`
+	Fee         string `json:"fee"`
+	Cmid        string `json:"cmid"`
+	Chnlid      string `json:"chnlid"`
+	ChnlData    string `json:"chnldata"`
+	AcctType    string `json:"accttype"`
+	BankCode    string `json:"bankcode"`
+	LogonId     string `json:"logonid"`
+	SignType    string `json:"signtype"`
+	Sign        string `json:"sign"`
+}
@@ -0,0 +1,71 @@
+package allinpay
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/w6xian/gopay"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 通联交易结果通知处理器，实现 http.Handler
+// 依次完成：解析表单 -> 验签 -> OnPay -> 应答 success
+// 回调返回 error 时应答 fail，通联会按策略重试通知
+type NotifyHandler struct {
+	client  *Client
+	onPay   func(ctx context.Context, notifyReq *NotifyRequest) error
+	onError func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化交易结果通知处理器，验签使用 client 中的通联公钥
+func NewNotifyHandler(client *Client) *NotifyHandler {
+	return &NotifyHandler{client: client}
+}
+
+// OnPay 注册交易结果通知回调，商户订单号为 notifyReq.CusOrderId
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		_, _ = w.Write([]byte("fail"))
+		return
+	}
+	_, _ = w.Write([]byte("success"))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	if err = req.ParseForm(); err != nil {
+		return err
+	}
+	bm := make(gopay.BodyMap, len(req.PostForm))
+	for k, v := range req.PostForm {
+		if len(v) == 1 {
+			bm.Set(k, v[0])
+		}
+	}
+	notifyReq := new(NotifyRequest)
+	if err = bm.Unmarshal(notifyReq); err != nil {
+		return err
+	}
+	if err = h.client.verifySignBodyMap(bm); err != nil {
+		return err
+	}
+	if h.onPay != nil {
+		return h.onPay(req.Context(), notifyReq)
+	}
+	return nil
+}
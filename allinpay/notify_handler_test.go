@@ -0,0 +1,73 @@
+package allinpay
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	// 模拟通联签名，使用临时密钥对
+	priKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{CusId: "990361082416001", AppId: "00012395", SignType: RSA, privateKey: priKey, publicKey: &priKey.PublicKey, sha1Hash: sha1.New()}
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", c.AppId).
+		Set("cusid", c.CusId).
+		Set("trxcode", "VSP501").
+		Set("trxid", "240110119922001").
+		Set("trxamt", "1").
+		Set("trxstatus", TrxStatusSuccess).
+		Set("cusorderid", "GZ202001101420131999").
+		Set("signtype", RSA)
+	sign, err := c.getRsaSign(bm, RSA, priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := make(url.Values)
+	bm.Range(func(k string, v any) bool {
+		form.Set(k, bm.GetString(k))
+		return true
+	})
+	form.Set("sign", sign)
+
+	var got *NotifyRequest
+	h := NewNotifyHandler(c).
+		OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Debugf("notify err: %v", err)
+		})
+	serve := func(f url.Values) string {
+		req := httptest.NewRequest(http.MethodPost, "/notify/allinpay", strings.NewReader(f.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if ack := serve(form); ack != "success" {
+		t.Fatalf("ack = %s, want success", ack)
+	}
+	if got == nil || got.CusOrderId != "GZ202001101420131999" || got.TrxStatus != TrxStatusSuccess {
+		t.Fatalf("OnPay got: %+v", got)
+	}
+
+	// 篡改金额，验签失败
+	form.Set("trxamt", "100")
+	if ack := serve(form); ack != "fail" {
+		t.Errorf("tampered ack = %s, want fail", ack)
+	}
+}
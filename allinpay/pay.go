@@ -61,6 +61,12 @@ func (c *Client) Query(ctx context.Context, orderType string, no string) (rsp *S
 	case OrderTypeTrxId:
 		bm.Set("trxid", no)
 	}
+	return c.QueryTrade(ctx, bm)
+}
+
+// QueryTrade 统一查询接口，reqsn 与 trxid 必填其一，可使用 QueryRequest 构造参数
+// https://aipboss.allinpay.com/know/devhelp/main.php?pid=15#mid=836
+func (c *Client) QueryTrade(ctx context.Context, bm gopay.BodyMap) (rsp *ScanPayRsp, err error) {
 	var bs []byte
 	if bs, err = c.doPost(ctx, queryPath, bm); err != nil {
 		return nil, err
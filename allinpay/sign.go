@@ -16,6 +16,11 @@ func (c *Client) verifySign(bs []byte) (err error) {
 	if err = json.Unmarshal(bs, &bm); err != nil {
 		return err
 	}
+	return c.verifySignBodyMap(bm)
+}
+
+// verifySignBodyMap 验证 BodyMap 签名，会移除 bm 中的 sign
+func (c *Client) verifySignBodyMap(bm gopay.BodyMap) (err error) {
 	sign := bm.Get("sign")
 	bm.Remove("sign")
 	signData := bm.EncodeAliPaySignParams()
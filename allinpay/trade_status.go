@@ -0,0 +1,33 @@
+package allinpay
+
+import (
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 交易状态 trxstatus
+	TrxStatusSuccess       = "0000" // 交易成功
+	TrxStatusNotExist      = "1001" // 交易不存在
+	TrxStatusProcessing    = "2000" // 交易处理中，请查询交易
+	TrxStatusPaying        = "2008" // 交易处理中，请查询交易
+	TrxStatusClosedTimeout = "3088" // 交易未支付（在查询时间区间内未成功支付，如已影响资金24小时内会做差错退款处理）
+	TrxStatusClosedRevoked = "3089" // 撤销异常，如已影响资金24小时内会做差错退款处理
+	TrxStatusFailPrefix    = "3"    // 3开头的错误码代表交易失败
+)
+
+// NormalizeTradeStatus 通联 trxstatus 转统一订单状态
+func NormalizeTradeStatus(trxStatus string) gopay.TradeStatus {
+	switch {
+	case trxStatus == TrxStatusSuccess:
+		return gopay.TradeStatusSucceeded
+	case trxStatus == TrxStatusProcessing || trxStatus == TrxStatusPaying:
+		return gopay.TradeStatusPaying
+	case trxStatus == TrxStatusClosedTimeout || trxStatus == TrxStatusClosedRevoked:
+		return gopay.TradeStatusClosed
+	case strings.HasPrefix(trxStatus, TrxStatusFailPrefix):
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
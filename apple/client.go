@@ -48,6 +48,13 @@ func NewClient(iss, bid, kid, privateKey string, isProd bool) (client *Client, e
 	return client, nil
 }
 
+// SetHttpClient 设置自定义的xhttp.Client
+func (c *Client) SetHttpClient(client *xhttp.Client) {
+	if client != nil {
+		c.hc = client
+	}
+}
+
 func (c *Client) doRequestGet(ctx context.Context, path string) (res *http.Response, bs []byte, err error) {
 	uri := hostUrl + path
 	if !c.isProd {
@@ -57,7 +64,7 @@ func (c *Client) doRequestGet(ctx context.Context, path string) (res *http.Respo
 	if err != nil {
 		return nil, nil, err
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderApple, path)
 	req.Header.Set("Authorization", "Bearer "+token)
 	res, bs, err = req.Get(uri).EndBytes(ctx)
 	if err != nil {
@@ -75,7 +82,7 @@ func (c *Client) doRequestPost(ctx context.Context, path string, bm gopay.BodyMa
 	if err != nil {
 		return nil, nil, err
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderApple, path)
 	req.Header.Set("Authorization", "Bearer "+token)
 	res, bs, err = req.Post(uri).SendBodyMap(bm).EndBytes(ctx)
 	if err != nil {
@@ -93,7 +100,7 @@ func (c *Client) doRequestPut(ctx context.Context, path string, bm gopay.BodyMap
 	if err != nil {
 		return nil, nil, err
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderApple, path)
 	req.Header.Set("Authorization", "Bearer "+token)
 	res, bs, err = req.Put(uri).SendBodyMap(bm).EndBytes(ctx)
 	if err != nil {
@@ -101,3 +108,41 @@ func (c *Client) doRequestPut(ctx context.Context, path string, bm gopay.BodyMap
 	}
 	return res, bs, nil
 }
+
+func (c *Client) doRequestDelete(ctx context.Context, path string) (res *http.Response, bs []byte, err error) {
+	uri := hostUrl + path
+	if !c.isProd {
+		uri = sandBoxHostUrl + path
+	}
+	token, err := c.generatingToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	req := c.hc.Req().SetApi(gopay.ProviderApple, path)
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, bs, err = req.Delete(uri).EndBytes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, bs, nil
+}
+
+// doRequestPutBytes 以指定 Content-Type 上传原始数据，如 Upload Image 的 PNG 图片
+func (c *Client) doRequestPutBytes(ctx context.Context, path, contentType string, body []byte) (res *http.Response, bs []byte, err error) {
+	uri := hostUrl + path
+	if !c.isProd {
+		uri = sandBoxHostUrl + path
+	}
+	token, err := c.generatingToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	req := c.hc.Req().SetApi(gopay.ProviderApple, path)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+	res, bs, err = req.Put(uri).SendString(string(body)).EndBytes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, bs, nil
+}
@@ -27,4 +27,32 @@ const (
 
 	// Get Notification History
 	getNotificationHistory = "/inApps/v1/notifications/history"
+
+	// Request a Test Notification
+	requestTestNotification = "/inApps/v1/notifications/test"
+
+	// Get Test Notification Status
+	getTestNotificationStatus = "/inApps/v1/notifications/test/%s" // testNotificationToken
+
+	// Extend a Subscription Renewal Date
+	extendSubscriptionRenewalDate = "/inApps/v1/subscriptions/extend/%s" // originalTransactionId
+
+	// Extend Subscription Renewal Dates for All Active Subscribers
+	massExtendSubscriptionRenewalDate = "/inApps/v1/subscriptions/extend/mass"
+
+	// Get Status of Subscription Renewal Date Extensions
+	getMassExtendRenewalDateStatus = "/inApps/v1/subscriptions/extend/mass/%s/%s" // productId, requestIdentifier
+
+	// Set App Account Token
+	setAppAccountToken = "/inApps/v1/transactions/%s/appAccountToken" // originalTransactionId
+
+	// Get App Transaction Info
+	getAppTransactionInfo = "/inApps/v1/transactions/appTransactions/%s" // transactionId
+
+	// Retention Messaging
+	retentionImage          = "/inApps/v1/messaging/image/%s" // imageIdentifier
+	retentionImageList      = "/inApps/v1/messaging/image/list"
+	retentionMessage        = "/inApps/v1/messaging/message/%s" // messageIdentifier
+	retentionMessageList    = "/inApps/v1/messaging/message/list"
+	retentionDefaultMessage = "/inApps/v1/messaging/default/%s/%s" // productId, locale
 )
@@ -1,6 +1,10 @@
 package apple
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
 
 // StatusCodeErr 用于判断Apple的status_code错误
 type StatusCodeErr struct {
@@ -19,6 +23,20 @@ func statusCodeErrCheck(errRsp StatusCodeErr) error {
 	return nil
 }
 
+// emptyRspErrCheck 检查成功时无响应体的接口，非 200 时优先返回 Apple 的 errorCode
+func emptyRspErrCheck(statusCode int, bs []byte) error {
+	if statusCode == http.StatusOK {
+		return nil
+	}
+	errRsp := StatusCodeErr{}
+	if json.Unmarshal(bs, &errRsp) == nil {
+		if err := statusCodeErrCheck(errRsp); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("http.stauts_code = %d", statusCode)
+}
+
 func (e *StatusCodeErr) Error() string {
 	return fmt.Sprintf(`{"errorCode":"%d","errorMessage":"%s"}`, e.ErrorCode, e.ErrorMessage)
 }
@@ -28,16 +28,10 @@ at+qIxUCMG1mihDK1A3UT82NQz60imOlM27jbdoXt2QfyFMm+YhidDkLF1vLUagM
 -----END CERTIFICATE-----
 `
 
-// DecodeSignedPayload 解析SignedPayload数据
+// DecodeSignedPayload 解析SignedPayload数据，使用默认校验器校验证书链、签名及 bundleId、appAppleId、environment
+// 校验规则通过 SetDefaultSignedDataVerifier() 配置
 func DecodeSignedPayload(signedPayload string) (payload *NotificationV2Payload, err error) {
-	if signedPayload == "" {
-		return nil, fmt.Errorf("signedPayload is empty")
-	}
-	payload = &NotificationV2Payload{}
-	if err = ExtractClaims(signedPayload, payload); err != nil {
-		return nil, err
-	}
-	return
+	return DefaultSignedDataVerifier().VerifyAndDecodeNotification(signedPayload)
 }
 
 // GetNotificationHistory Get Notification History
@@ -66,3 +60,46 @@ func (c *Client) GetNotificationHistory(ctx context.Context, paginationToken str
 	}
 	return rsp, nil
 }
+
+// RequestTestNotification Request a Test Notification
+// 返回的 rsp.TestNotificationToken 用于 GetTestNotificationStatus()
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/request_a_test_notification
+func (c *Client) RequestTestNotification(ctx context.Context) (rsp *TestNotificationRsp, err error) {
+	res, bs, err := c.doRequestPost(ctx, requestTestNotification, nil)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &TestNotificationRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// GetTestNotificationStatus Get Test Notification Status
+// rsp.SignedPayload use rsp.DecodeSignedPayload() to decode
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/get_test_notification_status
+func (c *Client) GetTestNotificationStatus(ctx context.Context, testNotificationToken string) (rsp *TestNotificationStatusRsp, err error) {
+	path := fmt.Sprintf(getTestNotificationStatus, testNotificationToken)
+	res, bs, err := c.doRequestGet(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &TestNotificationStatusRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
@@ -23,6 +23,8 @@ const (
 	NotificationTypeV2RenewalExtended        = "RENEWAL_EXTENDED"
 	NotificationTypeV2Revoke                 = "REVOKE"
 	NotificationTypeV2Subscribed             = "SUBSCRIBED"
+	NotificationTypeV2RenewalExtension       = "RENEWAL_EXTENSION"
+	NotificationTypeV2Test                   = "TEST"
 
 	// 子类型常量
 	// https://developer.apple.com/documentation/appstoreservernotifications/subtype
@@ -39,6 +41,8 @@ const (
 	SubTypeV2BillingRecovery   = "BILLING_RECOVERY"
 	SubTypeV2Pending           = "PENDING"
 	SubTypeV2Accepted          = "ACCEPTED"
+	SubTypeV2Summary           = "SUMMARY"
+	SubTypeV2Failure           = "FAILURE"
 )
 
 // https://developer.apple.com/documentation/appstoreservernotifications/responsebodyv2
@@ -49,11 +53,13 @@ type NotificationV2Req struct {
 // https://developer.apple.com/documentation/appstoreservernotifications/responsebodyv2decodedpayload
 type NotificationV2Payload struct {
 	jwt.StandardClaims
-	NotificationType string `json:"notificationType"`
-	Subtype          string `json:"subtype"`
-	NotificationUUID string `json:"notificationUUID"`
-	Version          string `json:"version"`
-	Data             *Data  `json:"data"`
+	NotificationType string   `json:"notificationType"`
+	Subtype          string   `json:"subtype"`
+	NotificationUUID string   `json:"notificationUUID"`
+	Version          string   `json:"version"`
+	Data             *Data    `json:"data"`
+	Summary          *Summary `json:"summary"` // 仅 RENEWAL_EXTENSION + SUMMARY 通知，批量延期的处理结果
+	SignedDate       int64    `json:"signedDate"`
 }
 
 func (d *NotificationV2Payload) DecodeRenewalInfo() (ri *RenewalInfo, err error) {
@@ -63,10 +69,12 @@ func (d *NotificationV2Payload) DecodeRenewalInfo() (ri *RenewalInfo, err error)
 	if d.Data.SignedRenewalInfo == "" {
 		return nil, fmt.Errorf("data.signedRenewalInfo is empty")
 	}
-	ri = &RenewalInfo{}
-	if err = ExtractClaims(d.Data.SignedRenewalInfo, ri); err != nil {
+	if ri, err = DefaultSignedDataVerifier().VerifyAndDecodeRenewalInfo(d.Data.SignedRenewalInfo); err != nil {
 		return nil, err
 	}
+	if d.Data.Environment != "" && ri.Environment != d.Data.Environment {
+		return nil, fmt.Errorf("[%w]: renewalInfo %s, notification %s", InvalidEnvironmentErr, ri.Environment, d.Data.Environment)
+	}
 	return
 }
 
@@ -77,10 +85,12 @@ func (d *NotificationV2Payload) DecodeTransactionInfo() (ti *TransactionInfo, er
 	if d.Data.SignedTransactionInfo == "" {
 		return nil, fmt.Errorf("data.signedTransactionInfo is empty")
 	}
-	ti = &TransactionInfo{}
-	if err = ExtractClaims(d.Data.SignedTransactionInfo, ti); err != nil {
+	if ti, err = DefaultSignedDataVerifier().VerifyAndDecodeTransaction(d.Data.SignedTransactionInfo); err != nil {
 		return nil, err
 	}
+	if d.Data.BundleID != "" && ti.BundleId != d.Data.BundleID || d.Data.Environment != "" && ti.Environment != d.Data.Environment {
+		return nil, fmt.Errorf("[%w]: transactionInfo %s/%s, notification %s/%s", InvalidAppIdentifierErr, ti.BundleId, ti.Environment, d.Data.BundleID, d.Data.Environment)
+	}
 	return
 }
 
@@ -92,6 +102,19 @@ type Data struct {
 	Environment           string `json:"environment"`
 	SignedRenewalInfo     string `json:"signedRenewalInfo"`
 	SignedTransactionInfo string `json:"signedTransactionInfo"`
+	Status                int    `json:"status"` // 自动续期订阅在 signedDate 时的状态，见 SubscriptionStatus* 常量
+}
+
+// https://developer.apple.com/documentation/appstoreservernotifications/summary
+type Summary struct {
+	RequestIdentifier      string   `json:"requestIdentifier"`
+	Environment            string   `json:"environment"`
+	AppAppleId             int64    `json:"appAppleId"`
+	BundleId               string   `json:"bundleId"`
+	ProductId              string   `json:"productId"`
+	StorefrontCountryCodes []string `json:"storefrontCountryCodes"`
+	FailedCount            int64    `json:"failedCount"`
+	SucceededCount         int64    `json:"succeededCount"`
 }
 
 // RenewalInfo https://developer.apple.com/documentation/appstoreservernotifications/jwsrenewalinfodecodedpayload
@@ -164,3 +187,20 @@ type SendAttemptItem struct {
 	AttemptDate       int64  `json:"attemptDate"`
 	SendAttemptResult string `json:"sendAttemptResult"`
 }
+
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/sendtestnotificationresponse
+type TestNotificationRsp struct {
+	StatusCodeErr
+	TestNotificationToken string `json:"testNotificationToken"`
+}
+
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/checktestnotificationresponse
+type TestNotificationStatusRsp struct {
+	StatusCodeErr
+	SignedPayload string             `json:"signedPayload"`
+	SendAttempts  []*SendAttemptItem `json:"sendAttempts"`
+}
+
+func (t *TestNotificationStatusRsp) DecodeSignedPayload() (payload *NotificationV2Payload, err error) {
+	return DecodeSignedPayload(t.SignedPayload)
+}
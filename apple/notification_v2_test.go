@@ -138,3 +138,25 @@ func TestGetNotificationHistory(t *testing.T) {
 		// do something others
 	}
 }
+
+func TestTestNotification(t *testing.T) {
+	rsp, err := client.RequestTestNotification(ctx)
+	if err != nil {
+		xlog.Errorf("client.RequestTestNotification(),err:%+v", err)
+		return
+	}
+	status, err := client.GetTestNotificationStatus(ctx, rsp.TestNotificationToken)
+	if err != nil {
+		xlog.Errorf("client.GetTestNotificationStatus(),err:%+v", err)
+		return
+	}
+	for _, v := range status.SendAttempts {
+		xlog.Debugf("sendAttempt:%+v", v)
+	}
+	payload, err := status.DecodeSignedPayload()
+	if err != nil {
+		xlog.Error(err)
+		return
+	}
+	xlog.Debugf("payload.NotificationType: %s", payload.NotificationType)
+}
@@ -0,0 +1,320 @@
+package apple
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smallstep/pkcs7"
+)
+
+// 收据 ASN.1 字段类型
+// https://developer.apple.com/library/archive/releasenotes/General/ValidateAppStoreReceipt/Chapters/ReceiptFields.html
+const (
+	receiptFieldReceiptType                = 0
+	receiptFieldAppItemId                  = 1
+	receiptFieldBundleId                   = 2
+	receiptFieldApplicationVersion         = 3
+	receiptFieldCreationDate               = 12
+	receiptFieldInApp                      = 17
+	receiptFieldOriginalPurchaseDate       = 18
+	receiptFieldOriginalApplicationVersion = 19
+	receiptFieldExpirationDate             = 21
+
+	inAppFieldQuantity              = 1701
+	inAppFieldProductId             = 1702
+	inAppFieldTransactionId         = 1703
+	inAppFieldPurchaseDate          = 1704
+	inAppFieldOriginalTransactionId = 1705
+	inAppFieldOriginalPurchaseDate  = 1706
+	inAppFieldExpiresDate           = 1708
+	inAppFieldWebOrderLineItemId    = 1711
+	inAppFieldCancellationDate      = 1712
+	inAppFieldIsTrialPeriod         = 1713
+	inAppFieldIsInIntroOfferPeriod  = 1719
+	inAppFieldPromotionalOfferId    = 1721
+)
+
+// InvalidReceiptErr 收据格式错误或签名校验失败，可使用 errors.Is() 判断
+var InvalidReceiptErr = errors.New("invalid receipt")
+
+type receiptAttribute struct {
+	Type    int
+	Version int
+	Value   []byte
+}
+
+// ReceiptDecoder 本地解析 App 收据（PKCS#7），替代已废弃的 verifyReceipt 接口
+// 校验 PKCS#7 签名及证书链：签名证书须为收据签名证书（扩展 1.2.840.113635.100.6.11.1），由 Apple WWDR 中间证书（扩展 1.2.840.113635.100.6.2.1）签发并可追溯至 Apple Inc. Root Certificate
+// 先校验签名，再按收据创建时间（而非当前时间）校验证书有效期，见 https://developer.apple.com/documentation/technotes/tn3138-handling-app-store-receipt-signing-certificate-changes
+type ReceiptDecoder struct {
+	roots    []*x509.Certificate
+	bundleId string
+}
+
+// NewReceiptDecoder 初始化收据解析器
+// rootCerts：信任的根证书，PEM 或 DER 格式，必填，通常为 Apple Inc. Root Certificate
+// 下载地址：https://www.apple.com/appleca/AppleIncRootCertificate.cer
+// bundleId：App 的 bundle ID，为空时不校验
+func NewReceiptDecoder(rootCerts [][]byte, bundleId string) (d *ReceiptDecoder, err error) {
+	if len(rootCerts) == 0 {
+		return nil, errors.New("rootCerts is empty")
+	}
+	d = &ReceiptDecoder{bundleId: bundleId}
+	for _, bs := range rootCerts {
+		if block, _ := pem.Decode(bytes.TrimSpace(bs)); block != nil {
+			bs = block.Bytes
+		}
+		cert, err := x509.ParseCertificate(bs)
+		if err != nil {
+			return nil, fmt.Errorf("parse root certificate: %w", err)
+		}
+		d.roots = append(d.roots, cert)
+	}
+	return d, nil
+}
+
+// Decode 解析并校验 base64 编码的 App 收据，返回与 VerifyReceipt() 相同结构的数据
+// rsp.LatestReceiptInfo 为收据中包含过期时间（订阅类）的交易，按购买时间升序；本地解析不返回 LatestReceipt、PendingRenewalInfo
+func (d *ReceiptDecoder) Decode(receipt string) (rsp *VerifyResponse, err error) {
+	bs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(receipt))
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: base64: %v", InvalidReceiptErr, err)
+	}
+	p7, err := parsePKCS7(bs)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidReceiptErr, err)
+	}
+	signer := p7.GetOnlySigner()
+	if signer == nil {
+		return nil, fmt.Errorf("[%w]: expect 1 signer, got %d", InvalidReceiptErr, len(p7.Signers))
+	}
+	if err = p7.Verify(); err != nil {
+		return nil, fmt.Errorf("[%w]: signature: %v", InvalidReceiptErr, err)
+	}
+	// 签名校验通过后收据内容可信，按收据创建时间校验证书链（TN3138）
+	r, err := parseReceipt(p7.Content)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidReceiptErr, err)
+	}
+	ms, err := strconv.ParseInt(r.ReceiptCreationDateTimestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: receipt creation date %q", InvalidReceiptErr, r.ReceiptCreationDate)
+	}
+	if err = verifyCertChain(signer, p7.Certificates, d.roots, time.UnixMilli(ms)); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidReceiptErr, err)
+	}
+	if d.bundleId != "" && r.BundleId != d.bundleId {
+		return nil, fmt.Errorf("[%w]: bundleId want %s, got %s", InvalidAppIdentifierErr, d.bundleId, r.BundleId)
+	}
+
+	rsp = &VerifyResponse{Environment: EnvironmentProduction, Receipt: r}
+	if strings.HasSuffix(r.ReceiptType, "Sandbox") {
+		rsp.Environment = EnvironmentSandbox
+	}
+	for _, item := range r.InApp {
+		if item.ExpiresDateTimestamp == "" {
+			continue
+		}
+		rsp.LatestReceiptInfo = append(rsp.LatestReceiptInfo, &LatestReceiptInfo{
+			CancellationDate:              item.CancellationDate,
+			CancellationDateTimestamp:     item.CancellationDateTimestamp,
+			CancellationDatePST:           item.CancellationDatePST,
+			ExpiresDate:                   item.ExpiresDate,
+			ExpiresDateTimestamp:          item.ExpiresDateTimestamp,
+			ExpiresDatePST:                item.ExpiresDatePST,
+			IsInIntroOfferPeriod:          item.IsInIntroOfferPeriod,
+			IsTrialPeriod:                 item.IsTrialPeriod,
+			OriginalPurchaseDate:          item.OriginalPurchaseDate,
+			OriginalPurchaseDateTimestamp: item.OriginalPurchaseDateTimestamp,
+			OriginalPurchaseDatePST:       item.OriginalPurchaseDatePST,
+			OriginalTransactionId:         item.OriginalTransactionId,
+			ProductId:                     item.ProductId,
+			PromotionalOfferId:            item.PromotionalOfferId,
+			PurchaseDate:                  item.PurchaseDate,
+			PurchaseDateTimestamp:         item.PurchaseDateTimestamp,
+			PurchaseDatePST:               item.PurchaseDatePST,
+			Quantity:                      item.Quantity,
+			TransactionId:                 item.TransactionId,
+			WebOrderLineItemId:            item.WebOrderLineItemId,
+		})
+	}
+	sort.SliceStable(rsp.LatestReceiptInfo, func(i, j int) bool {
+		a, _ := strconv.ParseInt(rsp.LatestReceiptInfo[i].PurchaseDateTimestamp, 10, 64)
+		b, _ := strconv.ParseInt(rsp.LatestReceiptInfo[j].PurchaseDateTimestamp, 10, 64)
+		return a < b
+	})
+	return rsp, nil
+}
+
+// parsePKCS7 解析 PKCS#7 SignedData，Apple 收据为 BER 不定长编码
+// pkcs7.Parse() 处理部分畸形 BER 时会 panic，这里转换为错误
+func parsePKCS7(bs []byte) (p7 *pkcs7.PKCS7, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			p7, err = nil, fmt.Errorf("pkcs7: malformed data: %v", e)
+		}
+	}()
+	return pkcs7.Parse(bs)
+}
+
+// ExtractTransactionId 解析并校验 App 收据，返回其中的一个交易ID，用于 GetTransactionHistoryV2() 查询该用户的全部交易，
+// 以便从 VerifyReceipt() 迁移至 App Store Server API；收据中没有内购交易时返回空字符串
+func (d *ReceiptDecoder) ExtractTransactionId(receipt string) (transactionId string, err error) {
+	rsp, err := d.Decode(receipt)
+	if err != nil {
+		return "", err
+	}
+	return rsp.Receipt.OriginalTransactionId(), nil
+}
+
+// OriginalTransactionId 收据中第一笔内购交易的 original_transaction_id，没有内购交易时返回空字符串
+// 任一交易ID均可用于 GetTransactionHistoryV2() 查询该用户的全部交易
+func (r *Receipt) OriginalTransactionId() string {
+	for _, item := range r.InApp {
+		if item.OriginalTransactionId != "" {
+			return item.OriginalTransactionId
+		}
+		if item.TransactionId != "" {
+			return item.TransactionId
+		}
+	}
+	return ""
+}
+
+func parseReceipt(content []byte) (r *Receipt, err error) {
+	attrs, err := parseReceiptAttributes(content)
+	if err != nil {
+		return nil, err
+	}
+	r = &Receipt{}
+	for _, a := range attrs {
+		switch a.Type {
+		case receiptFieldReceiptType:
+			r.ReceiptType = asn1String(a.Value)
+		case receiptFieldAppItemId:
+			r.AppItemId = asn1Int(a.Value)
+			r.AdamId = r.AppItemId
+		case receiptFieldBundleId:
+			r.BundleId = asn1String(a.Value)
+		case receiptFieldApplicationVersion:
+			r.ApplicationVersion = asn1String(a.Value)
+		case receiptFieldCreationDate:
+			r.ReceiptCreationDate, r.ReceiptCreationDateTimestamp, r.ReceiptCreationDatePST = receiptDate(a.Value)
+		case receiptFieldOriginalPurchaseDate:
+			r.OriginalPurchaseDate, r.OriginalPurchaseDateTimestamp, r.OriginalPurchaseDatePST = receiptDate(a.Value)
+		case receiptFieldOriginalApplicationVersion:
+			r.OriginalApplicationVersion = asn1String(a.Value)
+		case receiptFieldExpirationDate:
+			r.ExpirationDate, r.ExpirationDateTimestamp, r.ExpirationDatePST = receiptDate(a.Value)
+		case receiptFieldInApp:
+			item, err := parseInApp(a.Value)
+			if err != nil {
+				return nil, fmt.Errorf("in_app: %w", err)
+			}
+			r.InApp = append(r.InApp, item)
+		}
+	}
+	return r, nil
+}
+
+func parseInApp(content []byte) (item *InApp, err error) {
+	attrs, err := parseReceiptAttributes(content)
+	if err != nil {
+		return nil, err
+	}
+	item = &InApp{}
+	for _, a := range attrs {
+		switch a.Type {
+		case inAppFieldQuantity:
+			item.Quantity = strconv.FormatInt(asn1Int(a.Value), 10)
+		case inAppFieldProductId:
+			item.ProductId = asn1String(a.Value)
+		case inAppFieldTransactionId:
+			item.TransactionId = asn1String(a.Value)
+		case inAppFieldPurchaseDate:
+			item.PurchaseDate, item.PurchaseDateTimestamp, item.PurchaseDatePST = receiptDate(a.Value)
+		case inAppFieldOriginalTransactionId:
+			item.OriginalTransactionId = asn1String(a.Value)
+		case inAppFieldOriginalPurchaseDate:
+			item.OriginalPurchaseDate, item.OriginalPurchaseDateTimestamp, item.OriginalPurchaseDatePST = receiptDate(a.Value)
+		case inAppFieldExpiresDate:
+			item.ExpiresDate, item.ExpiresDateTimestamp, item.ExpiresDatePST = receiptDate(a.Value)
+		case inAppFieldWebOrderLineItemId:
+			item.WebOrderLineItemId = strconv.FormatInt(asn1Int(a.Value), 10)
+		case inAppFieldCancellationDate:
+			item.CancellationDate, item.CancellationDateTimestamp, item.CancellationDatePST = receiptDate(a.Value)
+		case inAppFieldIsTrialPeriod:
+			item.IsTrialPeriod = strconv.FormatBool(asn1Int(a.Value) == 1)
+		case inAppFieldIsInIntroOfferPeriod:
+			item.IsInIntroOfferPeriod = strconv.FormatBool(asn1Int(a.Value) == 1)
+		case inAppFieldPromotionalOfferId:
+			item.PromotionalOfferId = asn1String(a.Value)
+		}
+	}
+	return item, nil
+}
+
+// parseReceiptAttributes 解析 SET OF ReceiptAttribute
+func parseReceiptAttributes(content []byte) (attrs []receiptAttribute, err error) {
+	var set asn1.RawValue
+	if _, err = asn1.Unmarshal(content, &set); err != nil {
+		return nil, err
+	}
+	if set.Tag != asn1.TagSet {
+		return nil, fmt.Errorf("expect SET, got tag %d", set.Tag)
+	}
+	for rest := set.Bytes; len(rest) > 0; {
+		var a receiptAttribute
+		if rest, err = asn1.Unmarshal(rest, &a); err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs, nil
+}
+
+// asn1String 解析 UTF8String、IA5String 值
+func asn1String(value []byte) string {
+	var rv asn1.RawValue
+	if _, err := asn1.Unmarshal(value, &rv); err != nil {
+		return ""
+	}
+	return string(rv.Bytes)
+}
+
+func asn1Int(value []byte) int64 {
+	var n int64
+	if _, err := asn1.Unmarshal(value, &n); err != nil {
+		return 0
+	}
+	return n
+}
+
+var pstLocation, _ = time.LoadLocation("America/Los_Angeles")
+
+// receiptDate 将 RFC 3339 时间转换为 verifyReceipt 的三种格式：GMT、毫秒时间戳、太平洋时间
+func receiptDate(value []byte) (date, ms, pst string) {
+	s := asn1String(value)
+	if s == "" {
+		return "", "", ""
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s, "", ""
+	}
+	date = t.UTC().Format("2006-01-02 15:04:05") + " Etc/GMT"
+	ms = strconv.FormatInt(t.UnixMilli(), 10)
+	if pstLocation != nil {
+		pst = t.In(pstLocation).Format("2006-01-02 15:04:05") + " America/Los_Angeles"
+	}
+	return date, ms, pst
+}
@@ -0,0 +1,102 @@
+package apple
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// verifyCertChain 校验收据签名证书链：leaf（收据签名证书，扩展 1.2.840.113635.100.6.11.1）由 Apple WWDR 中间证书（扩展 1.2.840.113635.100.6.2.1）签发，并可追溯至 roots
+// 证书有效期按 at 校验，并校验基本约束、密钥用法、扩展密钥用法及路径长度
+// Apple 早期的收据证书链使用 SHA-1 签名，x509.Certificate.Verify 不再支持，这里逐级校验
+func verifyCertChain(leaf *x509.Certificate, pool []*x509.Certificate, roots []*x509.Certificate, at time.Time) error {
+	if !hasExtension(leaf, oidLeafMarker) {
+		return fmt.Errorf("leaf certificate %s missing marker %s", leaf.Subject.CommonName, oidLeafMarker)
+	}
+	if leaf.BasicConstraintsValid && leaf.IsCA {
+		return fmt.Errorf("leaf certificate %s must not be a CA", leaf.Subject.CommonName)
+	}
+	if leaf.KeyUsage != 0 && leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return fmt.Errorf("leaf certificate %s key usage must include digitalSignature", leaf.Subject.CommonName)
+	}
+	if err := checkCertUsage(leaf, at); err != nil {
+		return err
+	}
+	cert := issuerCert(leaf, pool)
+	if cert == nil {
+		return fmt.Errorf("certificate %s is not issued by a trusted root", leaf.Subject.CommonName)
+	}
+	if !hasExtension(cert, oidIntermediateMarker) {
+		return fmt.Errorf("intermediate certificate %s missing marker %s", cert.Subject.CommonName, oidIntermediateMarker)
+	}
+	// intermediates 为 cert 下方的中间证书数量，用于校验路径长度
+	for intermediates := 0; intermediates < 4; intermediates++ {
+		if err := checkCA(cert, intermediates, at); err != nil {
+			return err
+		}
+		for _, root := range roots {
+			if bytes.Equal(cert.Raw, root.Raw) {
+				return nil
+			}
+		}
+		for _, root := range roots {
+			if bytes.Equal(cert.RawIssuer, root.RawSubject) && checkCertSignature(root, cert) == nil {
+				return checkCA(root, intermediates+1, at)
+			}
+		}
+		if cert = issuerCert(cert, pool); cert == nil {
+			return errors.New("certificate chain is not issued by a trusted root")
+		}
+	}
+	return errors.New("certificate chain too long")
+}
+
+// issuerCert 在收据附带的证书中查找 cert 的签发证书
+func issuerCert(cert *x509.Certificate, pool []*x509.Certificate) *x509.Certificate {
+	for _, c := range pool {
+		if c != cert && bytes.Equal(cert.RawIssuer, c.RawSubject) && checkCertSignature(c, cert) == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// checkCertUsage 校验证书有效期及扩展密钥用法，存在扩展密钥用法时须包含 any 或 codeSigning
+func checkCertUsage(cert *x509.Certificate, at time.Time) error {
+	if at.Before(cert.NotBefore) || at.After(cert.NotAfter) {
+		return fmt.Errorf("certificate %s is not valid at %s", cert.Subject.CommonName, at.Format(time.RFC3339))
+	}
+	if len(cert.ExtKeyUsage) == 0 && len(cert.UnknownExtKeyUsage) == 0 {
+		return nil
+	}
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageAny || usage == x509.ExtKeyUsageCodeSigning {
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate %s extended key usage is incompatible", cert.Subject.CommonName)
+}
+
+// checkCA 校验签发证书：有效期及扩展密钥用法同 checkCertUsage()，基本约束 CA 为 true，密钥用法包含 keyCertSign，下方的中间证书数量 intermediates 不超过路径长度限制
+func checkCA(cert *x509.Certificate, intermediates int, at time.Time) error {
+	if err := checkCertUsage(cert, at); err != nil {
+		return err
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return fmt.Errorf("certificate %s is not a CA", cert.Subject.CommonName)
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return fmt.Errorf("certificate %s key usage must include keyCertSign", cert.Subject.CommonName)
+	}
+	if cert.MaxPathLen >= 0 && intermediates > cert.MaxPathLen {
+		return fmt.Errorf("certificate %s exceeds path length %d", cert.Subject.CommonName, cert.MaxPathLen)
+	}
+	return nil
+}
+
+// checkCertSignature 校验 child 由 parent 签发，允许 SHA-1 签名
+func checkCertSignature(parent, child *x509.Certificate) error {
+	return parent.CheckSignature(child.SignatureAlgorithm, child.RawTBSCertificate, child.Signature)
+}
@@ -0,0 +1,246 @@
+package apple
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smallstep/pkcs7"
+)
+
+func testReceiptAttr(t testing.TB, typ int, value any) receiptAttribute {
+	t.Helper()
+	bs, err := asn1.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receiptAttribute{Type: typ, Version: 1, Value: bs}
+}
+
+func testReceiptSet(t testing.TB, attrs ...receiptAttribute) []byte {
+	t.Helper()
+	bs, err := asn1.MarshalWithParams(attrs, "set")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+// testChainOption 签发前修改测试证书模板，leaf 为收据签名证书，inter 为 WWDR 中间证书
+type testChainOption func(leaf, inter *x509.Certificate)
+
+// newTestReceipt 生成 RSA 证书链签名的 PKCS#7 收据，返回 base64 收据和根证书
+func newTestReceipt(t testing.TB, content []byte, opts ...testChainOption) (receipt string, root []byte) {
+	t.Helper()
+	now := time.Now()
+	newTpl := func(serial int64, cn string, ca bool, marker asn1.ObjectIdentifier) *x509.Certificate {
+		tpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             now.Add(-96 * time.Hour),
+			NotAfter:              now.Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  ca,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		if marker != nil {
+			tpl.ExtraExtensions = []pkix.Extension{{Id: marker, Value: asn1.NullBytes}}
+		}
+		return tpl
+	}
+	newCert := func(tpl, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parent == nil {
+			parent, parentKey = tpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, _ := x509.ParseCertificate(der)
+		return cert, key
+	}
+	leafTpl := newTpl(3, "Test Mac App Store Receipt Signing", false, oidLeafMarker)
+	interTpl := newTpl(2, "Test WWDR", true, oidIntermediateMarker)
+	for _, opt := range opts {
+		opt(leafTpl, interTpl)
+	}
+	rootCert, rootKey := newCert(newTpl(1, "Test Apple Root", true, nil), nil, nil)
+	interCert, interKey := newCert(interTpl, rootCert, rootKey)
+	leafCert, leafKey := newCert(leafTpl, interCert, interKey)
+
+	// Apple 收据签名不含认证属性
+	sd, err := pkcs7.NewSignedData(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	if err = sd.SignWithoutAttr(leafCert, leafKey, pkcs7.SignerInfoConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	sd.AddCertificate(interCert)
+	bs, err := sd.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(bs), rootCert.Raw
+}
+
+func TestReceiptDecoder(t *testing.T) {
+	purchase := time.Now().Add(-30 * time.Minute).UTC().Truncate(time.Second)
+	inApp := testReceiptSet(t,
+		testReceiptAttr(t, inAppFieldQuantity, 1),
+		testReceiptAttr(t, inAppFieldProductId, "com.example.vip.month"),
+		testReceiptAttr(t, inAppFieldTransactionId, "2000000184445480"),
+		testReceiptAttr(t, inAppFieldOriginalTransactionId, "2000000184445477"),
+		testReceiptAttr(t, inAppFieldPurchaseDate, purchase.Format(time.RFC3339)),
+		testReceiptAttr(t, inAppFieldExpiresDate, purchase.Add(720*time.Hour).Format(time.RFC3339)),
+		testReceiptAttr(t, inAppFieldIsTrialPeriod, 1),
+	)
+	content := testReceiptSet(t,
+		testReceiptAttr(t, receiptFieldReceiptType, "ProductionSandbox"),
+		testReceiptAttr(t, receiptFieldBundleId, "com.example.app"),
+		testReceiptAttr(t, receiptFieldApplicationVersion, "1.0"),
+		testReceiptAttr(t, receiptFieldCreationDate, purchase.Format(time.RFC3339)),
+		receiptAttribute{Type: receiptFieldInApp, Version: 1, Value: inApp},
+	)
+	receipt, root := newTestReceipt(t, content)
+
+	decoder, err := NewReceiptDecoder([][]byte{root}, "com.example.app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := decoder.Decode(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Environment != EnvironmentSandbox || rsp.Receipt.BundleId != "com.example.app" || len(rsp.Receipt.InApp) != 1 {
+		t.Fatalf("rsp = %+v, receipt = %+v", rsp, rsp.Receipt)
+	}
+	item := rsp.Receipt.InApp[0]
+	if item.ProductId != "com.example.vip.month" || item.Quantity != "1" || item.IsTrialPeriod != "true" {
+		t.Fatalf("in_app = %+v", item)
+	}
+	if item.PurchaseDateTimestamp != strconv.FormatInt(purchase.UnixMilli(), 10) {
+		t.Fatalf("purchase_date_ms = %s", item.PurchaseDateTimestamp)
+	}
+	if len(rsp.LatestReceiptInfo) != 1 {
+		t.Fatalf("latest_receipt_info = %d", len(rsp.LatestReceiptInfo))
+	}
+	id, err := decoder.ExtractTransactionId(receipt)
+	if err != nil || id != "2000000184445477" {
+		t.Fatalf("transactionId = %s, err = %v", id, err)
+	}
+
+	// 其他 App 的收据
+	other, _ := NewReceiptDecoder([][]byte{root}, "com.other.app")
+	if _, err = other.Decode(receipt); !errors.Is(err, InvalidAppIdentifierErr) {
+		t.Fatalf("err = %v", err)
+	}
+	// 不信任的根证书
+	_, otherRoot := newTestReceipt(t, content)
+	untrusted, _ := NewReceiptDecoder([][]byte{otherRoot}, "")
+	if _, err = untrusted.Decode(receipt); !errors.Is(err, InvalidReceiptErr) {
+		t.Fatalf("err = %v", err)
+	}
+	// 篡改内容
+	bs, _ := base64.StdEncoding.DecodeString(receipt)
+	tampered := bytes.Replace(bs, []byte("com.example.vip.month"), []byte("com.example.vip.years"), 1)
+	if _, err = decoder.Decode(base64.StdEncoding.EncodeToString(tampered)); !errors.Is(err, InvalidReceiptErr) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestReceiptDecoderChain(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		created time.Time
+		opt     testChainOption
+		want    string
+	}{
+		{"ok", now, func(leaf, inter *x509.Certificate) {}, ""},
+		// 由同一 Apple 根证书签发的其他用途证书
+		{"non-receipt leaf", now, func(leaf, inter *x509.Certificate) { leaf.ExtraExtensions = nil }, "missing marker 1.2.840.113635.100.6.11.1"},
+		{"non-WWDR intermediate", now, func(leaf, inter *x509.Certificate) { inter.ExtraExtensions = nil }, "missing marker 1.2.840.113635.100.6.2.1"},
+		{"CA leaf", now, func(leaf, inter *x509.Certificate) { leaf.IsCA = true }, "must not be a CA"},
+		{"leaf key usage", now, func(leaf, inter *x509.Certificate) { leaf.KeyUsage = x509.KeyUsageKeyEncipherment }, "digitalSignature"},
+		{"leaf ext key usage", now, func(leaf, inter *x509.Certificate) {
+			leaf.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		}, "extended key usage"},
+		{"non-CA intermediate", now, func(leaf, inter *x509.Certificate) { inter.IsCA = false }, "is not a CA"},
+		{"intermediate key usage", now, func(leaf, inter *x509.Certificate) { inter.KeyUsage = x509.KeyUsageDigitalSignature }, "keyCertSign"},
+		// 证书有效期按收据创建时间校验
+		{"expired leaf", now, func(leaf, inter *x509.Certificate) {
+			leaf.NotBefore, leaf.NotAfter = now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+		}, "is not valid at"},
+		{"created before expired intermediate", now.Add(-36 * time.Hour), func(leaf, inter *x509.Certificate) {
+			leaf.NotBefore, leaf.NotAfter = now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+			inter.NotBefore, inter.NotAfter = now.Add(-72*time.Hour), now.Add(-24*time.Hour)
+		}, ""},
+		{"created after expired intermediate", now.Add(-12 * time.Hour), func(leaf, inter *x509.Certificate) {
+			leaf.NotBefore = now.Add(-48 * time.Hour)
+			inter.NotBefore, inter.NotAfter = now.Add(-72*time.Hour), now.Add(-24*time.Hour)
+		}, "Test WWDR is not valid at"},
+	}
+	for _, tt := range tests {
+		content := testReceiptSet(t,
+			testReceiptAttr(t, receiptFieldBundleId, "com.example.app"),
+			testReceiptAttr(t, receiptFieldCreationDate, tt.created.UTC().Format(time.RFC3339)),
+		)
+		receipt, root := newTestReceipt(t, content, tt.opt)
+		decoder, _ := NewReceiptDecoder([][]byte{root}, "")
+		_, err := decoder.Decode(receipt)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, InvalidReceiptErr) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %s", tt.name, err, tt.want)
+		}
+	}
+
+	// 缺少创建时间
+	receipt, root := newTestReceipt(t, testReceiptSet(t, testReceiptAttr(t, receiptFieldBundleId, "com.example.app")))
+	decoder, _ := NewReceiptDecoder([][]byte{root}, "")
+	if _, err := decoder.Decode(receipt); !errors.Is(err, InvalidReceiptErr) || !strings.Contains(err.Error(), "creation date") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func FuzzReceiptDecoder(f *testing.F) {
+	content := testReceiptSet(f,
+		testReceiptAttr(f, receiptFieldBundleId, "com.example.app"),
+		testReceiptAttr(f, receiptFieldCreationDate, time.Now().UTC().Format(time.RFC3339)),
+	)
+	receipt, root := newTestReceipt(f, content)
+	bs, _ := base64.StdEncoding.DecodeString(receipt)
+	f.Add(bs)
+	// BER 不定长编码：SEQUENCE { OCTET STRING（分段）{ "ab", "cd" }, INTEGER 5 }
+	f.Add([]byte{0x30, 0x80, 0x24, 0x80, 0x04, 0x02, 'a', 'b', 0x04, 0x02, 'c', 'd', 0x00, 0x00, 0x02, 0x01, 0x05, 0x00, 0x00})
+	decoder, err := NewReceiptDecoder([][]byte{root}, "")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// 任意输入不应 panic，失败时返回 InvalidReceiptErr
+		if _, err := decoder.Decode(base64.StdEncoding.EncodeToString(data)); err != nil && !errors.Is(err, InvalidReceiptErr) {
+			t.Fatalf("err = %v", err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package apple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/w6xian/gopay"
+)
+
+// UploadRetentionImage Upload Image
+// imageIdentifier：UUID 格式，image：PNG 图片内容
+// Doc: https://developer.apple.com/documentation/retentionmessaging/upload-image
+func (c *Client) UploadRetentionImage(ctx context.Context, imageIdentifier string, image []byte) (err error) {
+	path := fmt.Sprintf(retentionImage, imageIdentifier)
+	res, bs, err := c.doRequestPutBytes(ctx, path, "image/png", image)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// DeleteRetentionImage Delete Image
+// Doc: https://developer.apple.com/documentation/retentionmessaging/delete-image
+func (c *Client) DeleteRetentionImage(ctx context.Context, imageIdentifier string) (err error) {
+	path := fmt.Sprintf(retentionImage, imageIdentifier)
+	res, bs, err := c.doRequestDelete(ctx, path)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// GetRetentionImageList Get Image List
+// Doc: https://developer.apple.com/documentation/retentionmessaging/get-image-list
+func (c *Client) GetRetentionImageList(ctx context.Context) (rsp *RetentionImageListRsp, err error) {
+	res, bs, err := c.doRequestGet(ctx, retentionImageList)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &RetentionImageListRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// UploadRetentionMessage Upload Message
+// messageIdentifier：UUID 格式
+// bm：header、body、image（imageIdentifier、altText）
+// Doc: https://developer.apple.com/documentation/retentionmessaging/upload-message
+func (c *Client) UploadRetentionMessage(ctx context.Context, messageIdentifier string, bm gopay.BodyMap) (err error) {
+	path := fmt.Sprintf(retentionMessage, messageIdentifier)
+	res, bs, err := c.doRequestPut(ctx, path, bm)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// DeleteRetentionMessage Delete Message
+// Doc: https://developer.apple.com/documentation/retentionmessaging/delete-message
+func (c *Client) DeleteRetentionMessage(ctx context.Context, messageIdentifier string) (err error) {
+	path := fmt.Sprintf(retentionMessage, messageIdentifier)
+	res, bs, err := c.doRequestDelete(ctx, path)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// GetRetentionMessageList Get Message List
+// Doc: https://developer.apple.com/documentation/retentionmessaging/get-message-list
+func (c *Client) GetRetentionMessageList(ctx context.Context) (rsp *RetentionMessageListRsp, err error) {
+	res, bs, err := c.doRequestGet(ctx, retentionMessageList)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &RetentionMessageListRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// ConfigureDefaultRetentionMessage Configure Default Message
+// locale：如 en-US
+// Doc: https://developer.apple.com/documentation/retentionmessaging/configure-default-message
+func (c *Client) ConfigureDefaultRetentionMessage(ctx context.Context, productId, locale, messageIdentifier string) (err error) {
+	path := fmt.Sprintf(retentionDefaultMessage, productId, locale)
+	bm := make(gopay.BodyMap)
+	bm.Set("messageIdentifier", messageIdentifier)
+	res, bs, err := c.doRequestPut(ctx, path, bm)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// DeleteDefaultRetentionMessage Delete Default Message
+// Doc: https://developer.apple.com/documentation/retentionmessaging/delete-default-message
+func (c *Client) DeleteDefaultRetentionMessage(ctx context.Context, productId, locale string) (err error) {
+	path := fmt.Sprintf(retentionDefaultMessage, productId, locale)
+	res, bs, err := c.doRequestDelete(ctx, path)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
@@ -0,0 +1,31 @@
+package apple
+
+const (
+	// 图片、消息的审核状态
+	// https://developer.apple.com/documentation/retentionmessaging/imagestate
+	RetentionStatePending  = "PENDING"
+	RetentionStateApproved = "APPROVED"
+	RetentionStateRejected = "REJECTED"
+)
+
+// Doc: https://developer.apple.com/documentation/retentionmessaging/getimagelistresponse
+type RetentionImageListRsp struct {
+	StatusCodeErr
+	ImageIdentifiers []*RetentionImageItem `json:"imageIdentifiers"`
+}
+
+type RetentionImageItem struct {
+	ImageIdentifier string `json:"imageIdentifier"`
+	ImageState      string `json:"imageState"`
+}
+
+// Doc: https://developer.apple.com/documentation/retentionmessaging/getmessagelistresponse
+type RetentionMessageListRsp struct {
+	StatusCodeErr
+	MessageIdentifiers []*RetentionMessageItem `json:"messageIdentifiers"`
+}
+
+type RetentionMessageItem struct {
+	MessageIdentifier string `json:"messageIdentifier"`
+	MessageState      string `json:"messageState"`
+}
@@ -0,0 +1,32 @@
+package apple
+
+import (
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+)
+
+func TestRetentionMessage(t *testing.T) {
+	messageIdentifier := "8c4e6f2a-1b3d-4e5f-a6b7-c8d9e0f1a2b3"
+	bm := make(gopay.BodyMap)
+	bm.Set("header", "Before you go").
+		Set("body", "Get 50% off your next month")
+
+	if err := client.UploadRetentionMessage(ctx, messageIdentifier, bm); err != nil {
+		xlog.Errorf("client.UploadRetentionMessage(),err:%+v", err)
+		return
+	}
+	rsp, err := client.GetRetentionMessageList(ctx)
+	if err != nil {
+		xlog.Errorf("client.GetRetentionMessageList(),err:%+v", err)
+		return
+	}
+	for _, v := range rsp.MessageIdentifiers {
+		xlog.Debugf("message:%+v", v)
+	}
+	if err = client.ConfigureDefaultRetentionMessage(ctx, "Com.VoiceRecording.Telephone.103", "en-US", messageIdentifier); err != nil {
+		xlog.Errorf("client.ConfigureDefaultRetentionMessage(),err:%+v", err)
+		return
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/w6xian/gopay"
 )
@@ -29,3 +30,91 @@ func (c *Client) GetAllSubscriptionStatuses(ctx context.Context, transactionId s
 	}
 	return rsp, nil
 }
+
+// ExtendSubscriptionRenewalDate Extend a Subscription Renewal Date
+// bm：extendByDays、extendReasonCode、requestIdentifier
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/extend_a_subscription_renewal_date
+func (c *Client) ExtendSubscriptionRenewalDate(ctx context.Context, originalTransactionId string, bm gopay.BodyMap) (rsp *ExtendRenewalDateRsp, err error) {
+	path := fmt.Sprintf(extendSubscriptionRenewalDate, originalTransactionId)
+	res, bs, err := c.doRequestPut(ctx, path, bm)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &ExtendRenewalDateRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// MassExtendSubscriptionRenewalDate Extend Subscription Renewal Dates for All Active Subscribers
+// bm：extendByDays、extendReasonCode、requestIdentifier、productId、storefrontCountryCodes
+// 该接口异步处理，使用 GetMassExtendRenewalDateStatus() 或 WaitMassExtendRenewalDate() 查询处理结果
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/extend_subscription_renewal_dates_for_all_active_subscribers
+func (c *Client) MassExtendSubscriptionRenewalDate(ctx context.Context, bm gopay.BodyMap) (rsp *MassExtendRenewalDateRsp, err error) {
+	res, bs, err := c.doRequestPost(ctx, massExtendSubscriptionRenewalDate, bm)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &MassExtendRenewalDateRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// GetMassExtendRenewalDateStatus Get Status of Subscription Renewal Date Extensions
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/get_status_of_subscription_renewal_date_extensions
+func (c *Client) GetMassExtendRenewalDateStatus(ctx context.Context, productId, requestIdentifier string) (rsp *MassExtendRenewalDateStatusRsp, err error) {
+	path := fmt.Sprintf(getMassExtendRenewalDateStatus, productId, requestIdentifier)
+	res, bs, err := c.doRequestGet(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &MassExtendRenewalDateStatusRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
+
+// WaitMassExtendRenewalDate 轮询 GetMassExtendRenewalDateStatus()，直到批量延期处理完成（rsp.Complete 为 true）或 ctx 结束
+// interval：轮询间隔，小于等于 0 时默认 1 分钟
+func (c *Client) WaitMassExtendRenewalDate(ctx context.Context, productId, requestIdentifier string, interval time.Duration) (rsp *MassExtendRenewalDateStatusRsp, err error) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if rsp, err = c.GetMassExtendRenewalDateStatus(ctx, productId, requestIdentifier); err != nil {
+			return rsp, err
+		}
+		if rsp.Complete {
+			return rsp, nil
+		}
+		select {
+		case <-ctx.Done():
+			return rsp, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
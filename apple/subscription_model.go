@@ -2,6 +2,16 @@ package apple
 
 import "fmt"
 
+const (
+	// 自动续期订阅状态，status
+	// https://developer.apple.com/documentation/appstoreserverapi/status
+	SubscriptionStatusActive       = 1 // 有效
+	SubscriptionStatusExpired      = 2 // 已过期
+	SubscriptionStatusBillingRetry = 3 // 扣款重试期
+	SubscriptionStatusGracePeriod  = 4 // 宽限期
+	SubscriptionStatusRevoked      = 5 // 已撤销（退款或家庭共享撤销）
+)
+
 type AllSubscriptionStatusesRsp struct {
 	StatusCodeErr
 	AppAppleId  int                                `json:"appAppleId"`
@@ -17,7 +27,7 @@ type SubscriptionGroupIdentifierItem struct {
 
 type LastTransactionsItem struct {
 	OriginalTransactionId string `json:"originalTransactionId"`
-	Status                int    `json:"status"`
+	Status                int    `json:"status"` // 见 SubscriptionStatus* 常量
 	SignedRenewalInfo     string `json:"signedRenewalInfo"`
 	SignedTransactionInfo string `json:"signedTransactionInfo"`
 }
@@ -26,20 +36,49 @@ func (d *LastTransactionsItem) DecodeRenewalInfo() (ri *RenewalInfo, err error)
 	if d.SignedRenewalInfo == "" {
 		return nil, fmt.Errorf("SignedRenewalInfo is empty")
 	}
-	ri = &RenewalInfo{}
-	if err = ExtractClaims(d.SignedRenewalInfo, ri); err != nil {
-		return nil, err
-	}
-	return ri, nil
+	return DefaultSignedDataVerifier().VerifyAndDecodeRenewalInfo(d.SignedRenewalInfo)
 }
 
 func (d *LastTransactionsItem) DecodeTransactionInfo() (ti *TransactionInfo, err error) {
 	if d.SignedTransactionInfo == "" {
 		return nil, fmt.Errorf("signedTransactionInfo is empty")
 	}
-	ti = &TransactionInfo{}
-	if err = ExtractClaims(d.SignedTransactionInfo, ti); err != nil {
-		return nil, err
-	}
-	return ti, nil
+	return DefaultSignedDataVerifier().VerifyAndDecodeTransaction(d.SignedTransactionInfo)
+}
+
+const (
+	// 延期原因，extendReasonCode
+	// https://developer.apple.com/documentation/appstoreserverapi/extendreasoncode
+	ExtendReasonCodeUndeclared      = 0 // 未声明
+	ExtendReasonCodeCustomerSupport = 1 // 客户满意度
+	ExtendReasonCodeOther           = 2 // 其他
+	ExtendReasonCodeServiceIssue    = 3 // 服务问题或中断
+)
+
+// ExtendRenewalDateRsp
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/extendrenewaldateresponse
+type ExtendRenewalDateRsp struct {
+	StatusCodeErr
+	EffectiveDate         int64  `json:"effectiveDate"` // 延期后的到期时间，UNIX 毫秒时间戳
+	OriginalTransactionId string `json:"originalTransactionId"`
+	Success               bool   `json:"success"`
+	WebOrderLineItemId    string `json:"webOrderLineItemId"`
+}
+
+// MassExtendRenewalDateRsp
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/massextendrenewaldateresponse
+type MassExtendRenewalDateRsp struct {
+	StatusCodeErr
+	RequestIdentifier string `json:"requestIdentifier"`
+}
+
+// MassExtendRenewalDateStatusRsp
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/massextendrenewaldatestatusresponse
+type MassExtendRenewalDateStatusRsp struct {
+	StatusCodeErr
+	RequestIdentifier string `json:"requestIdentifier"`
+	Complete          bool   `json:"complete"`
+	CompleteDate      int64  `json:"completeDate"`
+	SucceededCount    int64  `json:"succeededCount"`
+	FailedCount       int64  `json:"failedCount"`
 }
@@ -2,8 +2,10 @@ package apple
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
 )
 
 func TestGetAllSubscriptionStatuses(t *testing.T) {
@@ -53,3 +55,49 @@ func TestGetAllSubscriptionStatuses(t *testing.T) {
 		}
 	}
 }
+
+func TestExtendSubscriptionRenewalDate(t *testing.T) {
+	originalTransactionId := "2000000184445477"
+	bm := make(gopay.BodyMap)
+	bm.Set("extendByDays", 7).
+		Set("extendReasonCode", ExtendReasonCodeServiceIssue).
+		Set("requestIdentifier", "a2d84a6e-0b67-4c39-a8b0-0ab5c1d7b3f1")
+
+	rsp, err := client.ExtendSubscriptionRenewalDate(ctx, originalTransactionId, bm)
+	if err != nil {
+		if statusErr, ok := IsStatusCodeError(err); ok {
+			xlog.Errorf("%+v", statusErr)
+			// do something
+			return
+		}
+		xlog.Errorf("client.ExtendSubscriptionRenewalDate(),err:%+v", err)
+		return
+	}
+	xlog.Debugf("rsp:%+v", rsp)
+}
+
+func TestMassExtendSubscriptionRenewalDate(t *testing.T) {
+	productId := "Com.VoiceRecording.Telephone.103"
+	requestIdentifier := "5f1b9a8c-3d2e-4b6f-9c7a-1e2d3f4a5b6c"
+	bm := make(gopay.BodyMap)
+	bm.Set("extendByDays", 7).
+		Set("extendReasonCode", ExtendReasonCodeServiceIssue).
+		Set("requestIdentifier", requestIdentifier).
+		Set("productId", productId).
+		Set("storefrontCountryCodes", []string{"USA", "CHN"})
+
+	rsp, err := client.MassExtendSubscriptionRenewalDate(ctx, bm)
+	if err != nil {
+		xlog.Errorf("client.MassExtendSubscriptionRenewalDate(),err:%+v", err)
+		return
+	}
+	xlog.Debugf("rsp:%+v", rsp)
+
+	// 轮询处理结果
+	status, err := client.WaitMassExtendRenewalDate(ctx, productId, rsp.RequestIdentifier, 30*time.Second)
+	if err != nil {
+		xlog.Errorf("client.WaitMassExtendRenewalDate(),err:%+v", err)
+		return
+	}
+	xlog.Debugf("status:%+v", status)
+}
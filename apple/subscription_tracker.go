@@ -0,0 +1,271 @@
+package apple
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+// 自动续期订阅的 transaction type
+const transactionTypeAutoRenewable = "Auto-Renewable Subscription"
+
+// Subscription 自动续期订阅的当前状态，以 originalTransactionId 为主键
+type Subscription struct {
+	OriginalTransactionId       string `json:"originalTransactionId"`
+	TransactionId               string `json:"transactionId"` // 最近一笔交易
+	ProductId                   string `json:"productId"`
+	SubscriptionGroupIdentifier string `json:"subscriptionGroupIdentifier"`
+	AppAccountToken             string `json:"appAccountToken"`
+	Environment                 string `json:"environment"`
+	Status                      int    `json:"status"`                 // 见 SubscriptionStatus* 常量
+	ExpiresDate                 int64  `json:"expiresDate"`            // 当前订阅周期到期时间，毫秒
+	GracePeriodExpiresDate      int64  `json:"gracePeriodExpiresDate"` // 宽限期到期时间，毫秒
+	RevocationDate              int64  `json:"revocationDate"`         // 撤销时间，毫秒
+	AutoRenewStatus             int64  `json:"autoRenewStatus"`        // 0：已关闭自动续期，1：开启
+	AutoRenewProductId          string `json:"autoRenewProductId"`
+	NotificationType            string `json:"notificationType"` // 最近一次更新来源的通知类型，来自 GetAllSubscriptionStatuses 时为空
+	Subtype                     string `json:"subtype"`
+	SignedDate                  int64  `json:"signedDate"` // 最近一次更新所依据数据的签名时间，毫秒，用于丢弃乱序到达的旧数据
+}
+
+// Entitled 订阅在 now 时是否有权益，及权益截止时间
+// 有效期内截止于 ExpiresDate，宽限期内截止于 GracePeriodExpiresDate，无权益时 until 为零值
+func (s *Subscription) Entitled(now time.Time) (entitled bool, until time.Time) {
+	switch s.Status {
+	case SubscriptionStatusActive:
+		until = time.UnixMilli(s.ExpiresDate)
+	case SubscriptionStatusGracePeriod:
+		until = time.UnixMilli(s.GracePeriodExpiresDate)
+	default:
+		return false, time.Time{}
+	}
+	if !now.Before(until) {
+		return false, time.Time{}
+	}
+	return true, until
+}
+
+// SubscriptionStore 订阅状态存储，由业务方实现，多副本部署时请使用共享存储
+type SubscriptionStore interface {
+	// Get 查询订阅，不存在时返回 nil, nil
+	Get(ctx context.Context, originalTransactionId string) (sub *Subscription, err error)
+	// Save 保存订阅，已存储记录的 SignedDate 不早于 sub.SignedDate 时不保存并返回 false
+	// 比较与写入需保证原子性（如数据库条件更新），避免并发处理通知时旧数据覆盖新数据
+	Save(ctx context.Context, sub *Subscription) (ok bool, err error)
+}
+
+// SubscriptionTracker 自动续期订阅权益跟踪
+// 消费 App Store Server Notifications V2 及 GetAllSubscriptionStatuses() 结果，按 signedDate 丢弃乱序到达的旧数据
+type SubscriptionTracker struct {
+	store    SubscriptionStore
+	verifier *SignedDataVerifier
+}
+
+// NewSubscriptionTracker 初始化订阅权益跟踪
+// verifier：签名数据校验器，为 nil 时使用 DefaultSignedDataVerifier()
+func NewSubscriptionTracker(store SubscriptionStore, verifier *SignedDataVerifier) *SubscriptionTracker {
+	return &SubscriptionTracker{store: store, verifier: verifier}
+}
+
+func (t *SubscriptionTracker) getVerifier() *SignedDataVerifier {
+	if t.verifier != nil {
+		return t.verifier
+	}
+	return DefaultSignedDataVerifier()
+}
+
+// HandleSignedPayload 校验并解析通知 signedPayload，更新订阅状态
+// 非自动续期订阅的通知（如 TEST、CONSUMPTION_REQUEST、一次性购买）返回 nil, nil
+func (t *SubscriptionTracker) HandleSignedPayload(ctx context.Context, signedPayload string) (sub *Subscription, err error) {
+	payload, err := t.getVerifier().VerifyAndDecodeNotification(signedPayload)
+	if err != nil {
+		return nil, err
+	}
+	return t.HandleNotification(ctx, payload)
+}
+
+// HandleNotification 根据已解析的通知更新订阅状态，返回更新后的订阅
+// 通知的 signedDate 不晚于已存储的状态时（乱序或重复投递），不更新并返回已存储的订阅
+// 非自动续期订阅的通知返回 nil, nil
+func (t *SubscriptionTracker) HandleNotification(ctx context.Context, payload *NotificationV2Payload) (sub *Subscription, err error) {
+	if payload.Data == nil || payload.Data.SignedTransactionInfo == "" {
+		return nil, nil
+	}
+	ti, err := t.getVerifier().VerifyAndDecodeTransaction(payload.Data.SignedTransactionInfo)
+	if err != nil {
+		return nil, err
+	}
+	if ti.Type != transactionTypeAutoRenewable {
+		return nil, nil
+	}
+	var ri *RenewalInfo
+	if payload.Data.SignedRenewalInfo != "" {
+		if ri, err = t.getVerifier().VerifyAndDecodeRenewalInfo(payload.Data.SignedRenewalInfo); err != nil {
+			return nil, err
+		}
+	}
+	status := payload.Data.Status
+	if status == 0 {
+		status = notificationStatus(payload, ti, ri)
+	}
+	return t.apply(ctx, payload.SignedDate, status, ti, ri, payload.NotificationType, payload.Subtype)
+}
+
+// HandleStatuses 根据 GetAllSubscriptionStatuses() 结果更新订阅状态，可用于启动时同步或定期对账
+func (t *SubscriptionTracker) HandleStatuses(ctx context.Context, rsp *AllSubscriptionStatusesRsp) (subs []*Subscription, err error) {
+	for _, group := range rsp.Data {
+		for _, item := range group.LastTransactions {
+			if item.SignedTransactionInfo == "" {
+				continue
+			}
+			ti, err := t.getVerifier().VerifyAndDecodeTransaction(item.SignedTransactionInfo)
+			if err != nil {
+				return subs, err
+			}
+			var ri *RenewalInfo
+			if item.SignedRenewalInfo != "" {
+				if ri, err = t.getVerifier().VerifyAndDecodeRenewalInfo(item.SignedRenewalInfo); err != nil {
+					return subs, err
+				}
+			}
+			signedDate := ti.SignedDate
+			if ri != nil && ri.SignedDate > signedDate {
+				signedDate = ri.SignedDate
+			}
+			sub, err := t.apply(ctx, signedDate, item.Status, ti, ri, "", "")
+			if err != nil {
+				return subs, err
+			}
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+// Entitlement 查询 originalTransactionId 在 now 时是否有权益，及权益截止时间
+// 订阅不存在时返回 false
+func (t *SubscriptionTracker) Entitlement(ctx context.Context, originalTransactionId string, now time.Time) (entitled bool, until time.Time, err error) {
+	if originalTransactionId == gopay.NULL {
+		return false, time.Time{}, fmt.Errorf("[%w], %v", gopay.MissParamErr, "originalTransactionId")
+	}
+	sub, err := t.store.Get(ctx, originalTransactionId)
+	if err != nil || sub == nil {
+		return false, time.Time{}, err
+	}
+	entitled, until = sub.Entitled(now)
+	return entitled, until, nil
+}
+
+func (t *SubscriptionTracker) apply(ctx context.Context, signedDate int64, status int, ti *TransactionInfo, ri *RenewalInfo, notificationType, subtype string) (sub *Subscription, err error) {
+	if ti.OriginalTransactionId == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "originalTransactionId")
+	}
+	old, err := t.store.Get(ctx, ti.OriginalTransactionId)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil && old.SignedDate >= signedDate {
+		return old, nil
+	}
+	sub = &Subscription{}
+	if old != nil {
+		*sub = *old
+	}
+	sub.OriginalTransactionId = ti.OriginalTransactionId
+	sub.TransactionId = ti.TransactionId
+	sub.ProductId = ti.ProductId
+	sub.SubscriptionGroupIdentifier = ti.SubscriptionGroupIdentifier
+	sub.AppAccountToken = ti.AppAccountToken
+	sub.Environment = ti.Environment
+	sub.ExpiresDate = ti.ExpiresDate
+	sub.RevocationDate = ti.RevocationDate
+	if ri != nil {
+		sub.GracePeriodExpiresDate = ri.GracePeriodExpiresDate
+		sub.AutoRenewStatus = ri.AutoRenewStatus
+		sub.AutoRenewProductId = ri.AutoRenewProductId
+	}
+	sub.Status = status
+	sub.NotificationType = notificationType
+	sub.Subtype = subtype
+	sub.SignedDate = signedDate
+
+	ok, err := t.store.Save(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// 并发处理时已有更新的数据写入
+		return t.store.Get(ctx, sub.OriginalTransactionId)
+	}
+	return sub, nil
+}
+
+// notificationStatus 通知未携带 data.status 时，根据通知类型及交易、续期信息推断订阅状态
+func notificationStatus(payload *NotificationV2Payload, ti *TransactionInfo, ri *RenewalInfo) int {
+	if ti.RevocationDate > 0 {
+		return SubscriptionStatusRevoked
+	}
+	switch payload.NotificationType {
+	case NotificationTypeV2Refund, NotificationTypeV2Revoke:
+		return SubscriptionStatusRevoked
+	case NotificationTypeV2Expired:
+		return SubscriptionStatusExpired
+	case NotificationTypeV2DidFailToRenew:
+		if payload.Subtype == SubTypeV2GracePeriod {
+			return SubscriptionStatusGracePeriod
+		}
+		return SubscriptionStatusBillingRetry
+	case NotificationTypeV2GracePeriodExpired:
+		if ri != nil && ri.IsInBillingRetryPeriod {
+			return SubscriptionStatusBillingRetry
+		}
+		return SubscriptionStatusExpired
+	}
+	if ri != nil && ri.IsInBillingRetryPeriod {
+		if ri.GracePeriodExpiresDate > payload.SignedDate {
+			return SubscriptionStatusGracePeriod
+		}
+		return SubscriptionStatusBillingRetry
+	}
+	if ti.ExpiresDate > payload.SignedDate {
+		return SubscriptionStatusActive
+	}
+	return SubscriptionStatusExpired
+}
+
+// =============================== 内存 ===============================
+
+// MemorySubscriptionStore 基于内存的订阅状态存储，仅适用于单实例部署及测试
+type MemorySubscriptionStore struct {
+	mu   sync.RWMutex
+	subs map[string]*Subscription
+}
+
+// NewMemorySubscriptionStore 初始化内存订阅状态存储
+func NewMemorySubscriptionStore() *MemorySubscriptionStore {
+	return &MemorySubscriptionStore{subs: make(map[string]*Subscription)}
+}
+
+func (m *MemorySubscriptionStore) Get(_ context.Context, originalTransactionId string) (sub *Subscription, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if s, ok := m.subs[originalTransactionId]; ok {
+		c := *s
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (m *MemorySubscriptionStore) Save(_ context.Context, sub *Subscription) (ok bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, exist := m.subs[sub.OriginalTransactionId]; exist && old.SignedDate >= sub.SignedDate {
+		return false, nil
+	}
+	c := *sub
+	m.subs[sub.OriginalTransactionId] = &c
+	return true, nil
+}
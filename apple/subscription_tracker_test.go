@@ -0,0 +1,130 @@
+package apple
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionTracker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	chain := newTestChain(t, now.Add(-time.Hour), now.Add(time.Hour), true)
+	verifier, err := NewSignedDataVerifier([][]byte{chain.rootDER}, "com.example.app", 1234, EnvironmentProduction, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := NewSubscriptionTracker(NewMemorySubscriptionStore(), verifier)
+
+	notify := func(typ, subtype string, signedAt time.Time, expires time.Time, gracePeriod time.Time) string {
+		ms := signedAt.UnixMilli()
+		return chain.sign(t, &NotificationV2Payload{
+			NotificationType: typ,
+			Subtype:          subtype,
+			SignedDate:       ms,
+			Data: &Data{
+				AppAppleID:  1234,
+				BundleID:    "com.example.app",
+				Environment: EnvironmentProduction,
+				SignedTransactionInfo: chain.sign(t, &TransactionInfo{
+					BundleId:              "com.example.app",
+					Environment:           EnvironmentProduction,
+					OriginalTransactionId: "2000000184445477",
+					TransactionId:         "2000000184445480",
+					ProductId:             "com.example.vip.month",
+					Type:                  transactionTypeAutoRenewable,
+					ExpiresDate:           expires.UnixMilli(),
+					SignedDate:            ms,
+				}),
+				SignedRenewalInfo: chain.sign(t, &RenewalInfo{
+					Environment:            EnvironmentProduction,
+					OriginalTransactionId:  "2000000184445477",
+					IsInBillingRetryPeriod: !gracePeriod.IsZero(),
+					GracePeriodExpiresDate: gracePeriod.UnixMilli(),
+					SignedDate:             ms,
+				}),
+			},
+		})
+	}
+	entitlement := func(at time.Time) (bool, time.Time) {
+		t.Helper()
+		entitled, until, err := tracker.Entitlement(ctx, "2000000184445477", at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entitled, until
+	}
+
+	if entitled, _ := entitlement(now); entitled {
+		t.Fatal("entitled before subscribed")
+	}
+	expires := now.Add(10 * time.Minute).Truncate(time.Millisecond)
+	if _, err = tracker.HandleSignedPayload(ctx, notify(NotificationTypeV2Subscribed, SubTypeV2InitialBuy, now.Add(-20*time.Minute), expires, time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+	if entitled, until := entitlement(now); !entitled || !until.Equal(expires) {
+		t.Fatalf("active: entitled = %v, until = %s", entitled, until)
+	}
+
+	// 扣款失败进入宽限期
+	grace := now.Add(30 * time.Minute).Truncate(time.Millisecond)
+	sub, err := tracker.HandleSignedPayload(ctx, notify(NotificationTypeV2DidFailToRenew, SubTypeV2GracePeriod, now.Add(-5*time.Minute), now.Add(-5*time.Minute), grace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Status != SubscriptionStatusGracePeriod {
+		t.Fatalf("status = %d", sub.Status)
+	}
+	if entitled, until := entitlement(now); !entitled || !until.Equal(grace) {
+		t.Fatalf("grace period: entitled = %v, until = %s", entitled, until)
+	}
+	if entitled, _ := entitlement(grace); entitled {
+		t.Fatal("entitled after grace period")
+	}
+
+	// 乱序到达的旧通知不覆盖新状态
+	sub, err = tracker.HandleSignedPayload(ctx, notify(NotificationTypeV2DidRenew, "", now.Add(-10*time.Minute), expires, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Status != SubscriptionStatusGracePeriod || sub.NotificationType != NotificationTypeV2DidFailToRenew {
+		t.Fatalf("out of order: status = %d, notificationType = %s", sub.Status, sub.NotificationType)
+	}
+
+	// 过期
+	if _, err = tracker.HandleSignedPayload(ctx, notify(NotificationTypeV2Expired, SubTypeV2Voluntary, now.Add(-time.Minute), now.Add(-5*time.Minute), time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+	if entitled, _ := entitlement(now); entitled {
+		t.Fatal("entitled after expired")
+	}
+
+	// GetAllSubscriptionStatuses 同步的最新状态
+	renewed := now.Add(time.Hour).Truncate(time.Millisecond)
+	subs, err := tracker.HandleStatuses(ctx, &AllSubscriptionStatusesRsp{
+		Data: []*SubscriptionGroupIdentifierItem{{
+			LastTransactions: []*LastTransactionsItem{{
+				OriginalTransactionId: "2000000184445477",
+				Status:                SubscriptionStatusActive,
+				SignedTransactionInfo: chain.sign(t, &TransactionInfo{
+					BundleId:              "com.example.app",
+					Environment:           EnvironmentProduction,
+					OriginalTransactionId: "2000000184445477",
+					TransactionId:         "2000000184445490",
+					Type:                  transactionTypeAutoRenewable,
+					ExpiresDate:           renewed.UnixMilli(),
+					SignedDate:            now.UnixMilli(),
+				}),
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].TransactionId != "2000000184445490" {
+		t.Fatalf("subs = %+v", subs)
+	}
+	if entitled, until := entitlement(now); !entitled || !until.Equal(renewed) {
+		t.Fatalf("statuses: entitled = %v, until = %s", entitled, until)
+	}
+}
@@ -71,3 +71,39 @@ func (c *Client) GetTransactionInfo(ctx context.Context, transactionId string) (
 	}
 	return rsp, nil
 }
+
+// SetAppAccountToken Set App Account Token
+// appAccountToken：UUID 格式，为空字符串时清除已设置的 appAccountToken
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/set-app-account-token
+func (c *Client) SetAppAccountToken(ctx context.Context, originalTransactionId, appAccountToken string) (err error) {
+	path := fmt.Sprintf(setAppAccountToken, originalTransactionId)
+	bm := make(gopay.BodyMap)
+	bm.Set("appAccountToken", appAccountToken)
+	res, bs, err := c.doRequestPut(ctx, path, bm)
+	if err != nil {
+		return err
+	}
+	return emptyRspErrCheck(res.StatusCode, bs)
+}
+
+// GetAppTransactionInfo Get App Transaction Info
+// rsp.SignedAppTransactionInfo use rsp.DecodeAppTransaction() to decode
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/get-app-transaction-info
+func (c *Client) GetAppTransactionInfo(ctx context.Context, transactionId string) (rsp *AppTransactionInfoRsp, err error) {
+	path := fmt.Sprintf(getAppTransactionInfo, transactionId)
+	res, bs, err := c.doRequestGet(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &AppTransactionInfoRsp{}
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if res.StatusCode == http.StatusOK {
+		return rsp, nil
+	}
+	if err = statusCodeErrCheck(rsp.StatusCodeErr); err != nil {
+		return rsp, err
+	}
+	return rsp, nil
+}
@@ -78,3 +78,36 @@ func (t *TransactionInfoRsp) DecodeSignedTransaction() (ti *TransactionsItem, er
 	}
 	return ti, nil
 }
+
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/appTransactionInfoResponse
+type AppTransactionInfoRsp struct {
+	StatusCodeErr
+	SignedAppTransactionInfo string `json:"signedAppTransactionInfo"`
+}
+
+// AppTransaction
+// Doc: https://developer.apple.com/documentation/appstoreserverapi/jwsapptransactiondecodedpayload
+type AppTransaction struct {
+	jwt.StandardClaims
+	AppAppleId                 int64  `json:"appAppleId"`
+	AppTransactionId           string `json:"appTransactionId"`
+	ApplicationVersion         string `json:"applicationVersion"`
+	BundleId                   string `json:"bundleId"`
+	DeviceVerification         string `json:"deviceVerification"`
+	DeviceVerificationNonce    string `json:"deviceVerificationNonce"`
+	OriginalApplicationVersion string `json:"originalApplicationVersion"`
+	OriginalPlatform           string `json:"originalPlatform"` // iOS、macOS、tvOS、visionOS
+	OriginalPurchaseDate       int64  `json:"originalPurchaseDate"`
+	PreorderDate               int64  `json:"preorderDate"`
+	ReceiptCreationDate        int64  `json:"receiptCreationDate"`
+	ReceiptType                string `json:"receiptType"` // Production、Sandbox、Xcode
+	SignedDate                 int64  `json:"signedDate"`
+	VersionExternalIdentifier  int64  `json:"versionExternalIdentifier"`
+}
+
+func (t *AppTransactionInfoRsp) DecodeAppTransaction() (at *AppTransaction, err error) {
+	if t.SignedAppTransactionInfo == "" {
+		return nil, fmt.Errorf("signedAppTransactionInfo is empty")
+	}
+	return DefaultSignedDataVerifier().VerifyAndDecodeAppTransaction(t.SignedAppTransactionInfo)
+}
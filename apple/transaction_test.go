@@ -80,3 +80,32 @@ func TestGetTransactionHistoryV2(t *testing.T) {
 		xlog.Debugf("transactions:%+v", transaction)
 	}
 }
+
+func TestSetAppAccountToken(t *testing.T) {
+	originalTransactionId := "2000000184445477"
+	err := client.SetAppAccountToken(ctx, originalTransactionId, "207262da-1ac8-4e0a-a399-5aa62a82800f")
+	if err != nil {
+		if statusErr, ok := IsStatusCodeError(err); ok {
+			xlog.Errorf("%+v", statusErr)
+			// do something
+			return
+		}
+		xlog.Errorf("client.SetAppAccountToken(),err:%+v", err)
+		return
+	}
+}
+
+func TestGetAppTransactionInfo(t *testing.T) {
+	transactionId := "2000000184445477"
+	rsp, err := client.GetAppTransactionInfo(ctx, transactionId)
+	if err != nil {
+		xlog.Errorf("client.GetAppTransactionInfo(),err:%+v", err)
+		return
+	}
+	appTransaction, err := rsp.DecodeAppTransaction()
+	if err != nil {
+		xlog.Error(err)
+		return
+	}
+	xlog.Debugf("appTransaction:%+v", appTransaction)
+}
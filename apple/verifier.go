@@ -0,0 +1,364 @@
+package apple
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/w6xian/gopay/pkg/jwt"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// 环境，对应签名数据中的 environment（App Transaction 为 receiptType）
+	EnvironmentProduction   = "Production"
+	EnvironmentSandbox      = "Sandbox"
+	EnvironmentXcode        = "Xcode"
+	EnvironmentLocalTesting = "LocalTesting"
+)
+
+var (
+	// 签名数据校验错误，可使用 errors.Is() 判断
+	InvalidChainErr         = errors.New("invalid certificate chain")
+	InvalidSignatureErr     = errors.New("invalid signature")
+	InvalidAppIdentifierErr = errors.New("invalid app identifier")
+	InvalidEnvironmentErr   = errors.New("invalid environment")
+	CertRevokedErr          = errors.New("certificate revoked")
+
+	// Apple 证书标记 OID
+	// https://images.apple.com/certificateauthority/pdf/Apple_WWDR_CPS_v1.31.pdf
+	oidLeafMarker         = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 11, 1}
+	oidIntermediateMarker = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 2, 1}
+
+	defaultVerifier atomic.Pointer[SignedDataVerifier]
+)
+
+// SignedDataVerifier App Store 签名数据（JWS）校验器，校验内容：
+//  1. x5c 证书链可追溯至信任的根证书，叶子证书、中间证书包含 Apple 标记 OID
+//  2. 证书在签名时间（signedDate）有效，开启在线校验时改为当前时间，并通过 OCSP 检查证书是否被吊销
+//  3. 使用叶子证书公钥校验 ES256 签名
+//  4. bundleId、appAppleId、environment 与配置一致（配置为空时不校验）
+//
+// environment 配置为 Xcode、LocalTesting 时，数据由 Xcode 本地签名，不校验证书链和签名，仅用于开发调试
+type SignedDataVerifier struct {
+	roots              *x509.CertPool
+	bundleId           string
+	appAppleId         int64
+	environment        string
+	enableOnlineChecks bool
+	hc                 *http.Client
+}
+
+// NewSignedDataVerifier 初始化签名数据校验器
+// rootCerts：信任的根证书，PEM 或 DER 格式，为空时使用内置的 Apple Root CA - G3
+// bundleId：App 的 bundle ID，为空时不校验
+// appAppleId：App 的 Apple ID，为空时不校验，Production 环境建议配置
+// environment：EnvironmentProduction、EnvironmentSandbox 等，为空时不校验
+// enableOnlineChecks：是否在线校验证书吊销状态（OCSP），开启后按当前时间校验证书有效期
+func NewSignedDataVerifier(rootCerts [][]byte, bundleId string, appAppleId int64, environment string, enableOnlineChecks bool) (v *SignedDataVerifier, err error) {
+	roots := x509.NewCertPool()
+	if len(rootCerts) == 0 {
+		rootCerts = [][]byte{[]byte(rootPEM)}
+	}
+	for _, bs := range rootCerts {
+		if block, _ := pem.Decode(bytes.TrimSpace(bs)); block != nil {
+			bs = block.Bytes
+		}
+		cert, err := x509.ParseCertificate(bs)
+		if err != nil {
+			return nil, fmt.Errorf("parse root certificate: %w", err)
+		}
+		roots.AddCert(cert)
+	}
+	v = &SignedDataVerifier{
+		roots:              roots,
+		bundleId:           bundleId,
+		appAppleId:         appAppleId,
+		environment:        environment,
+		enableOnlineChecks: enableOnlineChecks,
+		hc:                 &http.Client{Timeout: 10 * time.Second},
+	}
+	return v, nil
+}
+
+// SetDefaultSignedDataVerifier 设置 ExtractClaims()、DecodeSignedPayload() 及各 Decode 方法使用的校验器
+// 默认校验器仅信任 Apple Root CA - G3，不校验 bundleId、appAppleId、environment
+func SetDefaultSignedDataVerifier(v *SignedDataVerifier) {
+	if v != nil {
+		defaultVerifier.Store(v)
+	}
+}
+
+// DefaultSignedDataVerifier 获取默认校验器
+func DefaultSignedDataVerifier() *SignedDataVerifier {
+	if v := defaultVerifier.Load(); v != nil {
+		return v
+	}
+	v, _ := NewSignedDataVerifier(nil, "", 0, "", false)
+	defaultVerifier.CompareAndSwap(nil, v)
+	return defaultVerifier.Load()
+}
+
+// VerifyAndDecodeNotification 校验并解析 App Store Server Notifications V2 的 signedPayload
+func (v *SignedDataVerifier) VerifyAndDecodeNotification(signedPayload string) (payload *NotificationV2Payload, err error) {
+	if signedPayload == "" {
+		return nil, fmt.Errorf("signedPayload is empty")
+	}
+	payload = &NotificationV2Payload{}
+	if err = v.Verify(signedPayload, payload); err != nil {
+		return nil, err
+	}
+	var (
+		bundleId    string
+		appAppleId  int64
+		environment string
+	)
+	switch {
+	case payload.Data != nil:
+		bundleId, appAppleId, environment = payload.Data.BundleID, int64(payload.Data.AppAppleID), payload.Data.Environment
+	case payload.Summary != nil:
+		bundleId, appAppleId, environment = payload.Summary.BundleId, payload.Summary.AppAppleId, payload.Summary.Environment
+	}
+	if err = v.checkApp(bundleId, appAppleId, environment); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// VerifyAndDecodeTransaction 校验并解析 signedTransactionInfo
+func (v *SignedDataVerifier) VerifyAndDecodeTransaction(signedTransaction string) (ti *TransactionInfo, err error) {
+	if signedTransaction == "" {
+		return nil, fmt.Errorf("signedTransactionInfo is empty")
+	}
+	ti = &TransactionInfo{}
+	if err = v.Verify(signedTransaction, ti); err != nil {
+		return nil, err
+	}
+	if err = v.checkApp(ti.BundleId, 0, ti.Environment); err != nil {
+		return nil, err
+	}
+	return ti, nil
+}
+
+// VerifyAndDecodeRenewalInfo 校验并解析 signedRenewalInfo
+func (v *SignedDataVerifier) VerifyAndDecodeRenewalInfo(signedRenewalInfo string) (ri *RenewalInfo, err error) {
+	if signedRenewalInfo == "" {
+		return nil, fmt.Errorf("signedRenewalInfo is empty")
+	}
+	ri = &RenewalInfo{}
+	if err = v.Verify(signedRenewalInfo, ri); err != nil {
+		return nil, err
+	}
+	// signedRenewalInfo 不含 bundleId、appAppleId
+	if err = v.checkEnvironment(ri.Environment); err != nil {
+		return nil, err
+	}
+	return ri, nil
+}
+
+// VerifyAndDecodeAppTransaction 校验并解析 signedAppTransactionInfo
+func (v *SignedDataVerifier) VerifyAndDecodeAppTransaction(signedAppTransaction string) (at *AppTransaction, err error) {
+	if signedAppTransaction == "" {
+		return nil, fmt.Errorf("signedAppTransactionInfo is empty")
+	}
+	at = &AppTransaction{}
+	if err = v.Verify(signedAppTransaction, at); err != nil {
+		return nil, err
+	}
+	if err = v.checkApp(at.BundleId, at.AppAppleId, at.ReceiptType); err != nil {
+		return nil, err
+	}
+	return at, nil
+}
+
+// Verify 校验证书链和签名，并解析到 claims（指针类型的结构体），不校验 bundleId、appAppleId、environment
+func (v *SignedDataVerifier) Verify(signedData string, claims jwt.Claims) (err error) {
+	_, err = v.verifyToken(signedData, claims)
+	return err
+}
+
+func (v *SignedDataVerifier) verifyToken(signedData string, claims jwt.Claims) (tk *jwt.Token, err error) {
+	parts := strings.Split(signedData, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("[%w]: token contains an invalid number of segments", InvalidSignatureErr)
+	}
+	body, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidSignatureErr, err)
+	}
+	meta := &struct {
+		SignedDate int64 `json:"signedDate"`
+	}{}
+	if err = json.Unmarshal(body, meta); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidSignatureErr, err)
+	}
+	if v.environment == EnvironmentXcode || v.environment == EnvironmentLocalTesting {
+		// Xcode 本地签名，无 Apple 证书链，仅在校验器配置为对应环境时跳过校验，环境由 checkApp() 校验
+		tk, _, err = new(jwt.Parser).ParseUnverified(signedData, claims)
+		return tk, err
+	}
+
+	chain, err := extractX5c(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	effective := time.UnixMilli(meta.SignedDate)
+	if v.enableOnlineChecks || meta.SignedDate == 0 {
+		effective = time.Now()
+	}
+	leaf, err := v.verifyChain(chain, effective)
+	if err != nil {
+		return nil, err
+	}
+	parser := &jwt.Parser{ValidMethods: []string{jwt.SigningMethodES256.Alg()}}
+	tk, err = parser.ParseWithClaims(signedData, claims, func(token *jwt.Token) (any, error) {
+		pk, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, errors.New("appstore public key must be of type ecdsa.PublicKey")
+		}
+		return pk, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidSignatureErr, err)
+	}
+	return tk, nil
+}
+
+// verifyChain 校验 x5c 证书链，返回叶子证书
+func (v *SignedDataVerifier) verifyChain(chain []*x509.Certificate, effective time.Time) (leaf *x509.Certificate, err error) {
+	if len(chain) != 3 {
+		return nil, fmt.Errorf("[%w]: x5c must contain 3 certificates, got %d", InvalidChainErr, len(chain))
+	}
+	leaf, intermediate := chain[0], chain[1]
+	if !hasExtension(leaf, oidLeafMarker) {
+		return nil, fmt.Errorf("[%w]: leaf certificate missing marker %s", InvalidChainErr, oidLeafMarker)
+	}
+	if !hasExtension(intermediate, oidIntermediateMarker) {
+		return nil, fmt.Errorf("[%w]: intermediate certificate missing marker %s", InvalidChainErr, oidIntermediateMarker)
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate)
+	opts := x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   effective,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	chains, err := leaf.Verify(opts)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidChainErr, err)
+	}
+	if v.enableOnlineChecks {
+		verified := chains[0]
+		for i := 0; i < len(verified)-1; i++ {
+			if err = v.checkOCSP(verified[i], verified[i+1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return leaf, nil
+}
+
+// checkOCSP 在线检查证书吊销状态
+func (v *SignedDataVerifier) checkOCSP(cert, issuer *x509.Certificate) error {
+	if len(cert.OCSPServer) == 0 {
+		return fmt.Errorf("[%w]: certificate %s has no OCSP server", InvalidChainErr, cert.Subject.CommonName)
+	}
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", InvalidChainErr, err)
+	}
+	res, err := v.hc.Post(cert.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return fmt.Errorf("[%w]: ocsp request: %v", InvalidChainErr, err)
+	}
+	defer res.Body.Close()
+	bs, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("[%w]: ocsp response: %v", InvalidChainErr, err)
+	}
+	ocspRsp, err := ocsp.ParseResponseForCert(bs, cert, issuer)
+	if err != nil {
+		return fmt.Errorf("[%w]: ocsp response: %v", InvalidChainErr, err)
+	}
+	switch ocspRsp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("[%w]: %s", CertRevokedErr, cert.Subject.CommonName)
+	default:
+		return fmt.Errorf("[%w]: ocsp status unknown for %s", InvalidChainErr, cert.Subject.CommonName)
+	}
+}
+
+func (v *SignedDataVerifier) checkApp(bundleId string, appAppleId int64, environment string) error {
+	if v.bundleId != "" && bundleId != v.bundleId {
+		return fmt.Errorf("[%w]: bundleId want %s, got %s", InvalidAppIdentifierErr, v.bundleId, bundleId)
+	}
+	if v.appAppleId != 0 && appAppleId != 0 && appAppleId != v.appAppleId {
+		return fmt.Errorf("[%w]: appAppleId want %d, got %d", InvalidAppIdentifierErr, v.appAppleId, appAppleId)
+	}
+	return v.checkEnvironment(environment)
+}
+
+func (v *SignedDataVerifier) checkEnvironment(environment string) error {
+	if v.environment != "" && environment != v.environment {
+		return fmt.Errorf("[%w]: want %s, got %s", InvalidEnvironmentErr, v.environment, environment)
+	}
+	return nil
+}
+
+func extractX5c(headerSegment string) (chain []*x509.Certificate, err error) {
+	bs, err := decodeSegment(headerSegment)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidChainErr, err)
+	}
+	header := &struct {
+		Alg string   `json:"alg"`
+		X5c []string `json:"x5c"`
+	}{}
+	if err = json.Unmarshal(bs, header); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", InvalidChainErr, err)
+	}
+	for i, item := range header.X5c {
+		der, err := base64.StdEncoding.DecodeString(item)
+		if err != nil {
+			return nil, fmt.Errorf("[%w]: x5c[%d]: %v", InvalidChainErr, i, err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("[%w]: x5c[%d]: %v", InvalidChainErr, i, err)
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
+
+// decodeSegment JWS 分段为 base64url 编码，兼容标准 base64
+func decodeSegment(seg string) ([]byte, error) {
+	seg = strings.TrimRight(seg, "=")
+	if bs, err := base64.RawURLEncoding.DecodeString(seg); err == nil {
+		return bs, nil
+	}
+	return base64.RawStdEncoding.DecodeString(seg)
+}
+
+func hasExtension(cert *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package apple
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/w6xian/gopay/pkg/jwt"
+)
+
+type testChain struct {
+	root    *x509.Certificate
+	rootDER []byte
+	x5c     []string
+	key     *ecdsa.PrivateKey
+}
+
+// newTestChain 生成与 Apple 结构一致的证书链：root -> intermediate（标记 OID）-> leaf（标记 OID）
+func newTestChain(t *testing.T, notBefore, notAfter time.Time, markers bool) *testChain {
+	t.Helper()
+	newCert := func(serial int64, cn string, ca bool, marker []int, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, []byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             notBefore,
+			NotAfter:              notAfter,
+			BasicConstraintsValid: true,
+			IsCA:                  ca,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		if markers && marker != nil {
+			tpl.ExtraExtensions = []pkix.Extension{{Id: marker, Value: []byte{0x05, 0x00}}}
+		}
+		if parent == nil {
+			parent, parentKey = tpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, _ := x509.ParseCertificate(der)
+		return cert, der, key
+	}
+	root, rootDER, rootKey := newCert(1, "Test Root", true, nil, nil, nil)
+	inter, interDER, interKey := newCert(2, "Test Intermediate", true, oidIntermediateMarker, root, rootKey)
+	_, leafDER, leafKey := newCert(3, "Test Leaf", false, oidLeafMarker, inter, interKey)
+	enc := base64.StdEncoding.EncodeToString
+	return &testChain{
+		root:    root,
+		rootDER: rootDER,
+		x5c:     []string{enc(leafDER), enc(interDER), enc(rootDER)},
+		key:     leafKey,
+	}
+}
+
+func (c *testChain) sign(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["x5c"] = c.x5c
+	s, err := token.SignedString(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSignedDataVerifier(t *testing.T) {
+	now := time.Now()
+	chain := newTestChain(t, now.Add(-time.Hour), now.Add(time.Hour), true)
+	verifier, err := NewSignedDataVerifier([][]byte{chain.rootDER}, "com.example.app", 1234, EnvironmentProduction, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedTransaction := chain.sign(t, &TransactionInfo{
+		BundleId:      "com.example.app",
+		Environment:   EnvironmentProduction,
+		TransactionId: "2000000184445477",
+		SignedDate:    now.UnixMilli(),
+	})
+	ti, err := verifier.VerifyAndDecodeTransaction(signedTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.TransactionId != "2000000184445477" {
+		t.Fatalf("transactionId = %s", ti.TransactionId)
+	}
+
+	signedPayload := chain.sign(t, &NotificationV2Payload{
+		NotificationType: NotificationTypeV2DidRenew,
+		SignedDate:       now.UnixMilli(),
+		Data: &Data{
+			AppAppleID:            1234,
+			BundleID:              "com.example.app",
+			Environment:           EnvironmentProduction,
+			SignedTransactionInfo: signedTransaction,
+		},
+	})
+	payload, err := verifier.VerifyAndDecodeNotification(signedPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.NotificationType != NotificationTypeV2DidRenew {
+		t.Fatalf("notificationType = %s", payload.NotificationType)
+	}
+
+	tests := []struct {
+		name   string
+		verify func() error
+		want   error
+	}{
+		{"bundle id", func() error {
+			_, err := verifier.VerifyAndDecodeTransaction(chain.sign(t, &TransactionInfo{BundleId: "com.other.app", Environment: EnvironmentProduction, SignedDate: now.UnixMilli()}))
+			return err
+		}, InvalidAppIdentifierErr},
+		{"app apple id", func() error {
+			_, err := verifier.VerifyAndDecodeNotification(chain.sign(t, &NotificationV2Payload{SignedDate: now.UnixMilli(), Data: &Data{AppAppleID: 9, BundleID: "com.example.app", Environment: EnvironmentProduction}}))
+			return err
+		}, InvalidAppIdentifierErr},
+		{"environment", func() error {
+			_, err := verifier.VerifyAndDecodeRenewalInfo(chain.sign(t, &RenewalInfo{Environment: EnvironmentSandbox, SignedDate: now.UnixMilli()}))
+			return err
+		}, InvalidEnvironmentErr},
+		{"untrusted root", func() error {
+			other := newTestChain(t, now.Add(-time.Hour), now.Add(time.Hour), true)
+			_, err := verifier.VerifyAndDecodeTransaction(other.sign(t, &TransactionInfo{BundleId: "com.example.app", Environment: EnvironmentProduction, SignedDate: now.UnixMilli()}))
+			return err
+		}, InvalidChainErr},
+		{"missing marker oid", func() error {
+			noMarker := newTestChain(t, now.Add(-time.Hour), now.Add(time.Hour), false)
+			v, _ := NewSignedDataVerifier([][]byte{noMarker.rootDER}, "", 0, "", false)
+			return v.Verify(noMarker.sign(t, &TransactionInfo{SignedDate: now.UnixMilli()}), &TransactionInfo{})
+		}, InvalidChainErr},
+		{"signed after expiry", func() error {
+			_, err := verifier.VerifyAndDecodeTransaction(chain.sign(t, &TransactionInfo{BundleId: "com.example.app", Environment: EnvironmentProduction, SignedDate: now.Add(2 * time.Hour).UnixMilli()}))
+			return err
+		}, InvalidChainErr},
+		{"tampered", func() error {
+			_, err := verifier.VerifyAndDecodeTransaction(signedTransaction[:len(signedTransaction)-4] + "AAAA")
+			return err
+		}, InvalidSignatureErr},
+	}
+	for _, tt := range tests {
+		if err := tt.verify(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// 证书过期后，signedDate 在有效期内的历史数据仍可校验
+	expired := newTestChain(t, now.Add(-48*time.Hour), now.Add(-24*time.Hour), true)
+	v, _ := NewSignedDataVerifier([][]byte{expired.rootDER}, "", 0, "", false)
+	if err = v.Verify(expired.sign(t, &TransactionInfo{SignedDate: now.Add(-36 * time.Hour).UnixMilli()}), &TransactionInfo{}); err != nil {
+		t.Errorf("historical data: %v", err)
+	}
+}
+
+func TestSignedDataVerifierXcode(t *testing.T) {
+	now := time.Now()
+	chain := newTestChain(t, now.Add(-time.Hour), now.Add(time.Hour), true)
+	signed := chain.sign(t, &TransactionInfo{Environment: EnvironmentXcode, SignedDate: now.UnixMilli()})
+
+	// 未配置 Xcode 环境时，声明为 Xcode 的数据仍需校验证书链
+	if err := DefaultSignedDataVerifier().Verify(signed, &TransactionInfo{}); !errors.Is(err, InvalidChainErr) {
+		t.Fatalf("err = %v, want %v", err, InvalidChainErr)
+	}
+	v, _ := NewSignedDataVerifier(nil, "", 0, EnvironmentXcode, false)
+	if _, err := v.VerifyAndDecodeTransaction(signed); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -17,6 +17,9 @@ const (
 // url：取 UrlProd 或 UrlSandbox
 // pwd：苹果APP秘钥，https://help.apple.com/app-store-connect/#/devf341c0f01
 // 文档：https://developer.apple.com/documentation/appstorereceipts/verifyreceipt
+//
+// Deprecated: Apple 已废弃 verifyReceipt 接口，请使用 ReceiptDecoder 本地解析收据，
+// 或通过 ReceiptDecoder.ExtractTransactionId() 取得交易ID后使用 Client.GetTransactionHistoryV2()。
 func VerifyReceipt(ctx context.Context, url, pwd, receipt string) (rsp *VerifyResponse, err error) {
 	req := &VerifyRequest{Receipt: receipt, Password: pwd}
 	rsp = new(VerifyResponse)
@@ -53,6 +53,25 @@ func (bm BodyMap) SetFormFile(key string, file *File) BodyMap {
 	return bm
 }
 
+// 设置十进制金额字符串，如 "12.34"，适用于支付宝 total_amount、refund_amount 等
+func (bm BodyMap) SetDecimalAmount(key string, m Money) BodyMap {
+	return bm.Set(key, m.Decimal())
+}
+
+// 设置最小单位整数金额，如 1234，适用于微信 total_fee、amount.total，拉卡拉 price 等
+func (bm BodyMap) SetMinorAmount(key string, m Money) BodyMap {
+	return bm.Set(key, m.Minor())
+}
+
+// 设置金额对象 {"currency_code": "USD", "value": "12.34"}，按 ISO-4217 精度格式化
+// 注意：PayPal 的金额精度与 ISO-4217 不同（HUF、TWD 不支持小数），PayPal 金额请使用 paypal.NewAmount()
+func (bm BodyMap) SetCurrencyAmount(key string, m Money) BodyMap {
+	return bm.SetBodyMap(key, func(b BodyMap) {
+		b.Set("currency_code", m.Currency()).
+			Set("value", m.Decimal())
+	})
+}
+
 // 获取参数，同 GetString()
 func (bm BodyMap) Get(key string) string {
 	return bm.GetString(key)
@@ -7,7 +7,21 @@ const (
 	OK       = "OK"
 	DebugOff = 0
 	DebugOn  = 1
-	Version  = "v1.5.114"
+	Version  = "v1.5.115"
 )
 
 type DebugSwitch int8
+
+// 支付渠道，用于 xhttp.RequestInfo.Provider 及 unify 渠道名称
+const (
+	ProviderAlipay   = "alipay"
+	ProviderAlipayV3 = "alipay_v3"
+	ProviderWechat   = "wechat"
+	ProviderWechatV3 = "wechat_v3"
+	ProviderQQ       = "qq"
+	ProviderPayPal   = "paypal"
+	ProviderApple    = "apple"
+	ProviderLakala   = "lakala"
+	ProviderAllinpay = "allinpay"
+	ProviderSaobei   = "saobei"
+)
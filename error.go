@@ -3,17 +3,27 @@ package gopay
 import "errors"
 
 var (
-	MissWechatInitParamErr = errors.New("missing wechat init parameter")
-	MissAlipayInitParamErr = errors.New("missing alipay init parameter")
-	MissPayPalInitParamErr = errors.New("missing paypal init parameter")
-	MissAppleInitParamErr  = errors.New("missing apple init parameter")
-	MissLakalaInitParamErr = errors.New("missing lakala init parameter")
-	MissParamErr           = errors.New("missing required parameter")
-	MarshalErr             = errors.New("marshal error")
-	UnmarshalErr           = errors.New("unmarshal error")
-	SignatureErr           = errors.New("signature error")
-	VerifySignatureErr     = errors.New("verify signature error")
-	CertNotMatchErr        = errors.New("cert not match error")
-	GetSignDataErr         = errors.New("get signature data error")
-	BodyMapNilErr          = errors.New("body map is nil")
+	MissWechatInitParamErr   = errors.New("missing wechat init parameter")
+	MissAlipayInitParamErr   = errors.New("missing alipay init parameter")
+	MissPayPalInitParamErr   = errors.New("missing paypal init parameter")
+	MissAppleInitParamErr    = errors.New("missing apple init parameter")
+	MissLakalaInitParamErr   = errors.New("missing lakala init parameter")
+	MissParamErr             = errors.New("missing required parameter")
+	MarshalErr               = errors.New("marshal error")
+	UnmarshalErr             = errors.New("unmarshal error")
+	SignatureErr             = errors.New("signature error")
+	VerifySignatureErr       = errors.New("verify signature error")
+	CertNotMatchErr          = errors.New("cert not match error")
+	GetSignDataErr           = errors.New("get signature data error")
+	BodyMapNilErr            = errors.New("body map is nil")
+	TradeStatusTransitionErr = errors.New("illegal trade status transition")
+	NotifyDuplicateErr       = errors.New("duplicate notify")
+	NotifyExpiredErr         = errors.New("notify timestamp expired")
+	NotifyProcessingErr      = errors.New("notify is being processed")
+	BillFormatErr            = errors.New("bill format error")
+	BillHashErr              = errors.New("bill hash not match")
+	AmountFormatErr          = errors.New("invalid amount")
+	AmountOverflowErr        = errors.New("amount overflow")
+	CurrencyMismatchErr      = errors.New("currency mismatch")
+	ParamValidateErr         = errors.New("invalid parameter")
 )
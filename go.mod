@@ -9,5 +9,7 @@ require (
 	github.com/go-pay/util v0.0.4
 	github.com/go-pay/xlog v0.0.3
 	github.com/go-pay/xtime v0.0.2
+	github.com/smallstep/pkcs7 v0.2.1
 	golang.org/x/crypto v0.39.0
+	golang.org/x/text v0.26.0
 )
@@ -0,0 +1,67 @@
+// Package billcsv 账单 CSV 表头与带 bill tag 结构体字段的映射，供 alipay/bill、wechat/bill 共用
+//
+// bill tag 为表头名称，多个名称以 | 分隔，- 表示忽略；未识别的列写入结构体的 Extra map[string]string 字段
+package billcsv
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+// Column 表头列
+type Column struct {
+	Name  string
+	Field int // 结构体字段下标，-1 表示未识别
+}
+
+// Columns 按类型 t 的 bill tag 解析表头，clean 用于清理表头名称，known 为识别的列数
+func Columns(t reflect.Type, header []string, clean func(string) string) (columns []*Column, known int) {
+	index := tagIndex(t)
+	columns = make([]*Column, len(header))
+	for i, name := range header {
+		name = clean(name)
+		field, ok := index[name]
+		if ok {
+			known++
+		} else {
+			field = -1
+		}
+		columns[i] = &Column{Name: name, Field: field}
+	}
+	return columns, known
+}
+
+// Fill 将一行数据按表头列写入结构体 v，clean 用于清理字段值
+func Fill(v reflect.Value, columns []*Column, row []string, clean func(string) string) {
+	for i, raw := range row {
+		if i >= len(columns) {
+			break
+		}
+		val := clean(raw)
+		if c := columns[i]; c.Field >= 0 {
+			v.Field(c.Field).SetString(val)
+		} else if extra := v.FieldByName("Extra"); extra.IsValid() && val != gopay.NULL {
+			if extra.IsNil() {
+				extra.Set(reflect.MakeMap(extra.Type()))
+			}
+			extra.SetMapIndex(reflect.ValueOf(c.Name), reflect.ValueOf(val))
+		}
+	}
+}
+
+// tagIndex 表头名称 -> 字段下标
+func tagIndex(t reflect.Type) map[string]int {
+	index := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("bill")
+		if tag == gopay.NULL || tag == "-" {
+			continue
+		}
+		for _, name := range strings.Split(tag, "|") {
+			index[name] = i
+		}
+	}
+	return index
+}
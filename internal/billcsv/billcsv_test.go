@@ -0,0 +1,25 @@
+package billcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnsFill(t *testing.T) {
+	type record struct {
+		TradeNo string            `bill:"交易号|支付宝交易号"`
+		Amount  string            `bill:"金额"`
+		Ignored string            `bill:"-"`
+		Extra   map[string]string `bill:"-"`
+	}
+	columns, known := Columns(reflect.TypeFor[record](), []string{" 支付宝交易号 ", "金额", "备注", "-"}, strings.TrimSpace)
+	if known != 2 || len(columns) != 4 || columns[0].Field != 0 || columns[2].Field != -1 || columns[3].Field != -1 {
+		t.Fatalf("known = %d, columns = %+v", known, columns)
+	}
+	r := new(record)
+	Fill(reflect.ValueOf(r).Elem(), columns, []string{"2024010122001 ", " 0.01", "test", "", "overflow"}, strings.TrimSpace)
+	if r.TradeNo != "2024010122001" || r.Amount != "0.01" || r.Ignored != "" || len(r.Extra) != 1 || r.Extra["备注"] != "test" {
+		t.Fatalf("record = %+v", r)
+	}
+}
@@ -0,0 +1,69 @@
+package lakala
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 支付单、退款接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度），再传给对应的方法
+// order_id、refund_id 为路径参数，仍通过方法参数传入
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&lakala.OrderRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], price: must be > 0
+//	}
+//	rsp, err := client.CreateQRCodeOrder(ctx, orderId, bm)
+
+// OrderRequest 创建支付单请求参数
+// CreateQRCodeOrder()、CreateNativeQRCodeOrder()、CreateJSAPIOrder()、CreateNativeJSApiOrder()、CreateH5PayOrder()、
+// CreateMiniProgramOrder()、CreateSDKPaymentOrder()、CreateWebGatewayOrder() 使用
+// 文档：https://payjp.lakala.com/docs/cn/#api-QRCode-NewQRCode
+type OrderRequest struct {
+	Description string        `json:"description" validate:"required,maxlen=128"`
+	Price       int64         `json:"price" validate:"required,gt=0"` // 单位：币种最小单位
+	Currency    string        `json:"currency,omitempty" validate:"enum=JPY|CNY"`
+	Channel     string        `json:"channel" validate:"required,maxlen=32"`
+	NotifyUrl   string        `json:"notify_url,omitempty" validate:"maxlen=256"`
+	Operator    string        `json:"operator,omitempty" validate:"maxlen=64"`
+	Extra       gopay.BodyMap `json:"-"`
+}
+
+// RetailOrderRequest 创建线下支付单请求参数，CreateRetailOrder()、CreateRetailQRCodeOrder() 使用
+// 文档：https://payjp.lakala.com/docs/cn/#api-RetailPay-RetailMicroPay
+type RetailOrderRequest struct {
+	Description string        `json:"description" validate:"required,maxlen=128"`
+	Price       int64         `json:"price" validate:"required,gt=0"`
+	Currency    string        `json:"currency,omitempty" validate:"enum=JPY|CNY"`
+	NotifyUrl   string        `json:"notify_url,omitempty" validate:"maxlen=256"`
+	Operator    string        `json:"operator,omitempty" validate:"maxlen=64"`
+	Extra       gopay.BodyMap `json:"-"`
+}
+
+// RefundRequest 申请退款请求参数，ApplyRefund() 使用
+// 文档：https://payjp.lakala.com/docs/cn/#api-CommonApi-RefundOrder
+type RefundRequest struct {
+	Fee   int64         `json:"fee" validate:"required,gt=0"` // 退款金额，单位：币种最小单位
+	Extra gopay.BodyMap `json:"-"`
+}
+
+// BodyMap 校验并转换为创建支付单的请求参数
+func (r *OrderRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 CreateRetailOrder()、CreateRetailQRCodeOrder() 的请求参数
+func (r *RetailOrderRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 ApplyRefund() 的请求参数
+func (r *RefundRequest) BodyMap() (gopay.BodyMap, error) {
+	return buildBodyMap(r, r.Extra)
+}
+
+func buildBodyMap(req any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(req, extra)
+}
@@ -0,0 +1,43 @@
+package lakala
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ BodyMap() (gopay.BodyMap, error) }
+		body    string
+		wantErr string
+	}{
+		{"order", &OrderRequest{Description: "test", Price: 100, Currency: "JPY", Channel: "Wechat", Extra: gopay.BodyMap{"operator": "op01"}},
+			`{"channel":"Wechat","currency":"JPY","description":"test","operator":"op01","price":100}`, ""},
+		{"order without channel", &OrderRequest{Description: "test", Price: 100}, "", "[invalid parameter], channel: is required"},
+		{"order currency", &OrderRequest{Description: "test", Price: 100, Currency: "USD", Channel: "Alipay"},
+			"", "[invalid parameter], currency: must be one of JPY|CNY"},
+		{"retail order", &RetailOrderRequest{Description: "test", Price: 100}, `{"description":"test","price":100}`, ""},
+		{"retail order price", &RetailOrderRequest{Description: "test", Price: -1}, "", "[invalid parameter], price: must be > 0"},
+		{"refund", &RefundRequest{Fee: 1}, `{"fee":1}`, ""},
+		{"refund without fee", &RefundRequest{}, "", "[invalid parameter], fee: is required"},
+	}
+	for _, tt := range tests {
+		bm, err := tt.req.BodyMap()
+		if tt.wantErr != "" {
+			if !errors.Is(err, gopay.ParamValidateErr) || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if body := bm.JsonBody(); body != tt.body {
+			t.Errorf("%s: body = %s", tt.name, body)
+		}
+	}
+}
@@ -46,7 +46,7 @@ func NewClient(partnerCode, credentialCode string, isProd bool) (client *Client,
 		credentialCode: credentialCode,
 		IsProd:         isProd,
 		DebugSwitch:    gopay.DebugOff,
-		logger:         logger,
+		logger:         gopay.NewRedactLogger(logger, nil),
 		hc:             xhttp.NewClient(),
 		sha256Hash:     sha256.New(),
 	}
@@ -61,6 +61,8 @@ func (c *Client) SetBodySize(sizeMB int) {
 }
 
 // SetHttpClient 设置自定义的xhttp.Client
+// 本渠道未提供 SetRetryPolicy()：client 的重试策略 Classifier 为空时按 xhttp.DefaultRetryClassifier 判断，
+// 下单、退款等 POST 请求仅在连接未建立时重试，请勿设置 xhttp.IdempotentRetryClassifier，避免重复支付、退款
 func (c *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
 		c.hc = client
@@ -69,7 +71,7 @@ func (c *Client) SetHttpClient(client *xhttp.Client) {
 
 func (c *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		c.logger = logger
+		c.logger = gopay.NewRedactLogger(logger, nil)
 	}
 }
 
@@ -94,7 +96,7 @@ func VerifySign(notifyReq *NotifyRequest, partnerCode string, credentialCode str
 	h.Write([]byte(validStr))
 	validSign := strings.ToLower(hex.EncodeToString(h.Sum(nil)))
 	if notifyReq.Sign != validSign {
-		return fmt.Errorf("签名验证失败")
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, "签名验证失败")
 	}
 	return
 }
@@ -128,7 +130,7 @@ func (c *Client) doPut(ctx context.Context, path string, bm gopay.BodyMap) (bs [
 	if err != nil {
 		return nil, err
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderLakala, path)
 	req.Header.Add("Accept", "application/json")
 	uri := url + "?" + param
 	if c.DebugSwitch == gopay.DebugOn {
@@ -153,7 +155,7 @@ func (c *Client) doPost(ctx context.Context, path string, bm gopay.BodyMap) (bs
 	if err != nil {
 		return nil, err
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderLakala, path)
 	req.Header.Add("Accept", "application/json")
 	uri := url + "?" + param
 	if c.DebugSwitch == gopay.DebugOn {
@@ -181,7 +183,7 @@ func (c *Client) doGet(ctx context.Context, path, queryParams string) (bs []byte
 	if queryParams != "" {
 		param = param + "&" + queryParams
 	}
-	req := c.hc.Req()
+	req := c.hc.Req().SetApi(gopay.ProviderLakala, path)
 	req.Header.Add("Accept", "application/json")
 	uri := url + "?" + param
 	if c.DebugSwitch == gopay.DebugOn {
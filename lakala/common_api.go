@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/w6xian/gopay"
 )
@@ -234,3 +236,31 @@ func ParseNotify(req *http.Request) (notifyReq *NotifyRequest, err error) {
 	}
 	return
 }
+
+// CheckTimestamp 校验通知 time（UTC毫秒时间戳）是否在 window 时间窗口内，防止重放
+// 超出时间窗口返回 gopay.NotifyExpiredErr
+func (n *NotifyRequest) CheckTimestamp(window time.Duration) (err error) {
+	if window <= 0 {
+		return nil
+	}
+	ms, err := strconv.ParseInt(n.Time, 10, 64)
+	if err != nil {
+		return fmt.Errorf("[%w]: time=%s", gopay.NotifyExpiredErr, n.Time)
+	}
+	return gopay.CheckNotifyTimestamp(time.UnixMilli(ms), window)
+}
+
+// CheckDuplicate 对付款通知去重，已处理成功返回 gopay.NotifyDuplicateErr，正在处理返回 gopay.NotifyProcessingErr
+// 注意：需在验签通过后调用，业务处理成功后调用 deduper.Commit(ctx, notifyReq.DedupeKey())，失败时调用 deduper.Delete() 以便拉卡拉重试时可再次处理
+// 推荐直接使用 gopay.HandleNotifyOnce(ctx, deduper, notifyReq.DedupeKey(), fn)
+func (n *NotifyRequest) CheckDuplicate(ctx context.Context, deduper gopay.NotifyDeduper) (err error) {
+	if n.PartnerOrderId == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "partner_order_id")
+	}
+	return gopay.CheckNotifyDuplicate(ctx, deduper, n.DedupeKey())
+}
+
+// DedupeKey 通知去重使用的 key，拉卡拉通知无通知 Id，由商户订单号、渠道订单号、支付时间、实付金额组合标识同一笔付款事件
+func (n *NotifyRequest) DedupeKey() string {
+	return "lakala:" + n.PartnerOrderId + ":" + n.ChannelOrderId + ":" + n.PayTime + ":" + strconv.Itoa(n.RealFee)
+}
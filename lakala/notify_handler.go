@@ -0,0 +1,100 @@
+package lakala
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 拉卡拉付款通知处理器，实现 http.Handler
+// 依次完成：解析 -> 验签 -> OnPay -> 应答 {"return_code":"SUCCESS"}
+// 回调返回 error 时应答 HTTP 500 及 {"return_code":"FAIL"}，拉卡拉会按策略重试通知
+// 设置 NotifyDeduper 后，已处理成功的重复通知不再回调并直接应答成功，处理中的重复通知应答失败等待重试
+type NotifyHandler struct {
+	partnerCode    string
+	credentialCode string
+	window         time.Duration
+	deduper        gopay.NotifyDeduper
+	onPay          func(ctx context.Context, notifyReq *NotifyRequest) error
+	onError        func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化付款通知处理器
+// partnerCode：商户编码
+// credentialCode：系统为商户分配的开发校验码
+func NewNotifyHandler(partnerCode, credentialCode string) *NotifyHandler {
+	return &NotifyHandler{partnerCode: partnerCode, credentialCode: credentialCode}
+}
+
+// SetTimestampWindow 设置通知 time 有效窗口，默认不校验
+func (h *NotifyHandler) SetTimestampWindow(window time.Duration) *NotifyHandler {
+	h.window = window
+	return h
+}
+
+// SetDeduper 设置通知去重存储，多副本部署时请使用共享存储
+func (h *NotifyHandler) SetDeduper(deduper gopay.NotifyDeduper) *NotifyHandler {
+	h.deduper = deduper
+	return h
+}
+
+// OnPay 注册付款通知回调
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"return_code":"FAIL"}`))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(`{"return_code":"SUCCESS"}`))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	notifyReq, err := ParseNotify(req)
+	if err != nil {
+		return err
+	}
+	if err = VerifySign(notifyReq, h.partnerCode, h.credentialCode); err != nil {
+		return err
+	}
+	if err = notifyReq.CheckTimestamp(h.window); err != nil {
+		return err
+	}
+	ctx := req.Context()
+	if h.deduper == nil {
+		return h.dispatch(ctx, notifyReq)
+	}
+	if notifyReq.PartnerOrderId == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "partner_order_id")
+	}
+	return gopay.HandleNotifyOnce(ctx, h.deduper, notifyReq.DedupeKey(), func(ctx context.Context) error {
+		return h.dispatch(ctx, notifyReq)
+	})
+}
+
+func (h *NotifyHandler) dispatch(ctx context.Context, notifyReq *NotifyRequest) (err error) {
+	if h.onPay != nil {
+		return h.onPay(ctx, notifyReq)
+	}
+	return nil
+}
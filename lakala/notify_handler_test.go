@@ -0,0 +1,123 @@
+package lakala
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	newNotify := func(partnerOrderId string) *NotifyRequest {
+		n := &NotifyRequest{
+			Time:           strconv.FormatInt(time.Now().UnixMilli(), 10),
+			NonceStr:       "dyUNIkzFFWYYVuVbp2C4oPJkpfFm0lmf",
+			PartnerOrderId: partnerOrderId,
+			OrderId:        "PINE-20230308-0000012345",
+			ChannelOrderId: "4200001808202303088887776665",
+			TotalFee:       100,
+			RealFee:        100,
+			Currency:       "JPY",
+			Channel:        "Wechat",
+			PayTime:        "2023-03-08 10:00:00",
+		}
+		h := sha256.Sum256([]byte(partnerCode + "&" + n.Time + "&" + n.NonceStr + "&" + credentialCode))
+		n.Sign = hex.EncodeToString(h[:])
+		return n
+	}
+
+	var got []*NotifyRequest
+	h := NewNotifyHandler(partnerCode, credentialCode).
+		SetTimestampWindow(5 * time.Minute).
+		OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = append(got, notifyReq)
+			return nil
+		})
+
+	serve := func(n *NotifyRequest) (int, string) {
+		bs, _ := json.Marshal(n)
+		req := httptest.NewRequest(http.MethodPost, "/notify/lakala", strings.NewReader(string(bs)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+	const (
+		ackSuccess = `{"return_code":"SUCCESS"}`
+		ackFail    = `{"return_code":"FAIL"}`
+	)
+
+	// 验签通过，回调 OnPay 并应答成功
+	n := newNotify("GZ202303081000001")
+	if code, ack := serve(n); code != http.StatusOK || ack != ackSuccess {
+		t.Fatalf("code = %d, ack = %s", code, ack)
+	}
+	if len(got) != 1 || got[0].PartnerOrderId != n.PartnerOrderId || got[0].RealFee != 100 || got[0].Channel != "Wechat" {
+		t.Fatalf("OnPay got: %+v", got)
+	}
+
+	// 签名错误，应答失败且不回调
+	var errs []error
+	h.OnError(func(req *http.Request, err error) {
+		errs = append(errs, err)
+	})
+	bad := newNotify("GZ202303081000001")
+	bad.Sign = strings.Repeat("0", 64)
+	if code, ack := serve(bad); code != http.StatusInternalServerError || ack != ackFail || len(got) != 1 {
+		t.Fatalf("bad sign: code = %d, ack = %s, calls = %d", code, ack, len(got))
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], gopay.VerifySignatureErr) {
+		t.Fatalf("errs = %v", errs)
+	}
+
+	// 超出时间窗口，应答失败
+	expired := newNotify("GZ202303081000001")
+	expired.Time = strconv.FormatInt(time.Now().Add(-time.Hour).UnixMilli(), 10)
+	s := sha256.Sum256([]byte(partnerCode + "&" + expired.Time + "&" + expired.NonceStr + "&" + credentialCode))
+	expired.Sign = hex.EncodeToString(s[:])
+	if code, ack := serve(expired); code != http.StatusInternalServerError || ack != ackFail || !errors.Is(errs[len(errs)-1], gopay.NotifyExpiredErr) {
+		t.Fatalf("expired: code = %d, ack = %s, errs = %v", code, ack, errs)
+	}
+
+	// 设置去重后，已处理成功的重复通知不再回调并直接应答成功
+	got = nil
+	h.SetDeduper(gopay.NewMemoryNotifyDeduper(100, time.Hour))
+	n = newNotify("GZ202303081000002")
+	for i := 0; i < 2; i++ {
+		if code, ack := serve(n); code != http.StatusOK || ack != ackSuccess || len(got) != 1 {
+			t.Fatalf("dedupe #%d: code = %d, ack = %s, calls = %d", i, code, ack, len(got))
+		}
+	}
+
+	// 回调失败后应答失败，拉卡拉重试时可再次处理
+	fail := true
+	h.OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+		if fail {
+			return errors.New("db unavailable")
+		}
+		got = append(got, notifyReq)
+		return nil
+	})
+	n = newNotify("GZ202303081000003")
+	if code, ack := serve(n); code != http.StatusInternalServerError || ack != ackFail || len(got) != 1 {
+		t.Fatalf("callback err: code = %d, ack = %s, calls = %d", code, ack, len(got))
+	}
+	fail = false
+	if code, ack := serve(n); code != http.StatusOK || ack != ackSuccess || len(got) != 2 {
+		t.Fatalf("retry: code = %d, ack = %s, calls = %d", code, ack, len(got))
+	}
+
+	// 去重需要 partner_order_id
+	if code, ack := serve(newNotify("")); code != http.StatusInternalServerError || ack != ackFail || !errors.Is(errs[len(errs)-1], gopay.MissParamErr) {
+		t.Fatalf("no partner_order_id: code = %d, ack = %s, errs = %v", code, ack, errs)
+	}
+}
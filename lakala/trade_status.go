@@ -0,0 +1,35 @@
+package lakala
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 订单状态 result_code
+	ResultCodePaying        = "PAYING"         // 等待支付
+	ResultCodeCreateFail    = "CREATE_FAIL"    // 创建失败
+	ResultCodeClosed        = "CLOSED"         // 已关闭
+	ResultCodePayFail       = "PAY_FAIL"       // 支付失败
+	ResultCodePaySuccess    = "PAY_SUCCESS"    // 支付成功
+	ResultCodePartialRefund = "PARTIAL_REFUND" // 部分退款
+	ResultCodeFullRefund    = "FULL_REFUND"    // 全额退款
+)
+
+// NormalizeTradeStatus 拉卡拉订单 result_code 转统一订单状态
+func NormalizeTradeStatus(resultCode string) gopay.TradeStatus {
+	switch resultCode {
+	case ResultCodePaying:
+		return gopay.TradeStatusPending
+	case ResultCodePaySuccess:
+		return gopay.TradeStatusSucceeded
+	case ResultCodeClosed:
+		return gopay.TradeStatusClosed
+	case ResultCodePartialRefund:
+		return gopay.TradeStatusPartiallyRefunded
+	case ResultCodeFullRefund:
+		return gopay.TradeStatusRefunded
+	case ResultCodeCreateFail, ResultCodePayFail:
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
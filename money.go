@@ -0,0 +1,283 @@
+package gopay
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency 币种为空时使用的币种
+const DefaultCurrency = "CNY"
+
+// ISO-4217 中小数位数不为 2 的币种
+// 渠道的特殊要求（如 PayPal 的 HUF、TWD 不带小数）由渠道自行处理，见 paypal.FormatAmount()
+var currencyExponent = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyExponent 币种的小数位数，如 CNY 为 2，JPY 为 0，KWD 为 3，未知币种为 2
+func CurrencyExponent(currency string) int {
+	if e, ok := currencyExponent[normalizeCurrency(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+func normalizeCurrency(currency string) string {
+	if currency == NULL {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(currency)
+}
+
+// Money 金额，以币种最小单位（如 分）的整数保存，避免浮点误差
+// 各渠道格式：
+// 微信、QQ、拉卡拉、通联、扫呗：最小单位整数，使用 Minor()、NewMoney()
+// 支付宝：十进制字符串，使用 Decimal()、ParseMoney()
+// PayPal：十进制字符串，HUF、JPY、TWD 不带小数，使用 paypal.FormatAmount()、paypal.NewAmount()
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// NewMoney 以最小单位创建金额，如 NewMoney(1234, "CNY") 为 12.34 元；currency 为空时默认 CNY
+func NewMoney(minor int64, currency string) Money {
+	return Money{amount: minor, currency: normalizeCurrency(currency)}
+}
+
+// ParseMoney 解析十进制金额字符串，如 ParseMoney("12.34", "CNY")
+// 小数位数超过币种精度（末尾的 0 除外）时返回 AmountFormatErr，不做四舍五入
+func ParseMoney(value, currency string) (m Money, err error) {
+	currency = normalizeCurrency(currency)
+	s := strings.TrimSpace(value)
+	if s == NULL {
+		return Money{}, fmt.Errorf("[%w]: empty", AmountFormatErr)
+	}
+	neg := false
+	switch s[0] {
+	case '-':
+		neg, s = true, s[1:]
+	case '+':
+		s = s[1:]
+	}
+	exp := CurrencyExponent(currency)
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == NULL && fracPart == NULL || hasDot && fracPart == NULL || !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("[%w]: %s", AmountFormatErr, value)
+	}
+	if len(fracPart) > exp {
+		if strings.Trim(fracPart[exp:], "0") != NULL {
+			return Money{}, fmt.Errorf("[%w]: %s has more than %d decimal places for %s", AmountFormatErr, value, exp, currency)
+		}
+		fracPart = fracPart[:exp]
+	}
+	fracPart += strings.Repeat("0", exp-len(fracPart))
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	if digits == NULL {
+		return Money{currency: currency}, nil
+	}
+	if neg {
+		digits = "-" + digits
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("[%w]: %s", AmountOverflowErr, value)
+	}
+	return Money{amount: n, currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor 最小单位金额，如 12.34 元返回 1234
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency 币种，大写
+func (m Money) Currency() string {
+	return normalizeCurrency(m.currency)
+}
+
+// Decimal 十进制金额字符串，按币种精度补齐小数位，如 CNY "12.30"，JPY "1234"
+func (m Money) Decimal() string {
+	exp := CurrencyExponent(m.currency)
+	u, sign := uint64(m.amount), ""
+	if m.amount < 0 {
+		u, sign = absUint64(m.amount), "-"
+	}
+	s := strconv.FormatUint(u, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+// String 如 "12.34 CNY"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency()
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+// Equal 金额与币种均相同
+func (m Money) Equal(o Money) bool {
+	return m.amount == o.amount && m.Currency() == o.Currency()
+}
+
+// Cmp 比较金额，m < o 返回 -1，相等返回 0，m > o 返回 1；币种不同时返回 CurrencyMismatchErr
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add 加法，币种不同返回 CurrencyMismatchErr，溢出返回 AmountOverflowErr
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.amount + o.amount
+	if (o.amount > 0 && sum < m.amount) || (o.amount < 0 && sum > m.amount) {
+		return Money{}, fmt.Errorf("[%w]: %s + %s", AmountOverflowErr, m, o)
+	}
+	return Money{amount: sum, currency: m.Currency()}, nil
+}
+
+// Sub 减法，币种不同返回 CurrencyMismatchErr，溢出返回 AmountOverflowErr
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	diff := m.amount - o.amount
+	if (o.amount > 0 && diff > m.amount) || (o.amount < 0 && diff < m.amount) {
+		return Money{}, fmt.Errorf("[%w]: %s - %s", AmountOverflowErr, m, o)
+	}
+	return Money{amount: diff, currency: m.Currency()}, nil
+}
+
+// Mul 乘以整数，如数量，溢出返回 AmountOverflowErr
+func (m Money) Mul(n int64) (Money, error) {
+	if m.amount == 0 || n == 0 {
+		return Money{currency: m.Currency()}, nil
+	}
+	p := m.amount * n
+	if p/n != m.amount || (m.amount == -1 && n == math.MinInt64) || (n == -1 && m.amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("[%w]: %s * %d", AmountOverflowErr, m, n)
+	}
+	return Money{amount: p, currency: m.Currency()}, nil
+}
+
+// Neg 相反数，溢出返回 AmountOverflowErr
+func (m Money) Neg() (Money, error) {
+	if m.amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("[%w]: -(%s)", AmountOverflowErr, m)
+	}
+	return Money{amount: -m.amount, currency: m.Currency()}, nil
+}
+
+// Split 平均拆分为 n 份，不能整除的最小单位依次分配给前面的份额，各份之和等于原金额
+// 如 0.10 元拆分 3 份为 0.04、0.03、0.03
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("[%w]: split into %d parts", AmountFormatErr, n)
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Allocate 按比例拆分，用于分账、按商品分摊退款等，各份之和等于原金额
+// 比例不能为负数且之和大于 0，按比例向下取整后剩余的最小单位依次分配给比例不为 0 的前面的份额
+// 如 1.00 元按 70:30 分配为 0.70、0.30；0.05 元按 1:1 分配为 0.03、0.02
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total uint64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("[%w]: negative ratio %d", AmountFormatErr, r)
+		}
+		sum, carry := bits.Add64(total, uint64(r), 0)
+		if carry != 0 {
+			return nil, fmt.Errorf("[%w]: ratios sum", AmountOverflowErr)
+		}
+		total = sum
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("[%w]: ratios sum is zero", AmountFormatErr)
+	}
+	neg := m.amount < 0
+	abs := absUint64(m.amount)
+	parts := make([]uint64, len(ratios))
+	remain := abs
+	for i, r := range ratios {
+		// abs * r / total，r <= total 故商不会溢出
+		hi, lo := bits.Mul64(abs, uint64(r))
+		parts[i], _ = bits.Div64(hi, lo, total)
+		remain -= parts[i]
+	}
+	for i := 0; remain > 0; i = (i + 1) % len(parts) {
+		if ratios[i] > 0 {
+			parts[i]++
+			remain--
+		}
+	}
+	currency := m.Currency()
+	result := make([]Money, len(parts))
+	for i, p := range parts {
+		amount := int64(p)
+		if neg {
+			// p <= 2^63，p = 2^63 时 int64(p) 取反仍为 math.MinInt64
+			amount = -amount
+		}
+		result[i] = Money{amount: amount, currency: currency}
+	}
+	return result, nil
+}
+
+// absUint64 绝对值，math.MinInt64 取反溢出，返回 2^63
+func absUint64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency() != o.Currency() {
+		return fmt.Errorf("[%w]: %s != %s", CurrencyMismatchErr, m.Currency(), o.Currency())
+	}
+	return nil
+}
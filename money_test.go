@@ -0,0 +1,132 @@
+package gopay
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		value    string
+		currency string
+		minor    int64
+		decimal  string
+	}{
+		{"0.01", "CNY", 1, "0.01"},
+		{"12.3", "", 1230, "12.30"},
+		{"12.340", "cny", 1234, "12.34"},
+		{"-0.5", "USD", -50, "-0.50"},
+		{"+8", "CNY", 800, "8.00"},
+		{".5", "CNY", 50, "0.50"},
+		{"1234", "JPY", 1234, "1234"},
+		{"1.000", "JPY", 1, "1"},
+		{"1.234", "KWD", 1234, "1.234"},
+		{"123", "HUF", 12300, "123.00"},
+		{"1.5", "TWD", 150, "1.50"},
+		{"92233720368547758.07", "CNY", math.MaxInt64, "92233720368547758.07"},
+		{"-92233720368547758.08", "CNY", math.MinInt64, "-92233720368547758.08"},
+	}
+	for _, v := range cases {
+		m, err := ParseMoney(v.value, v.currency)
+		if err != nil {
+			t.Errorf("ParseMoney(%s, %s), err: %v", v.value, v.currency, err)
+			continue
+		}
+		if m.Minor() != v.minor || m.Decimal() != v.decimal {
+			t.Errorf("ParseMoney(%s, %s) = %d %s, want %d %s", v.value, v.currency, m.Minor(), m.Decimal(), v.minor, v.decimal)
+		}
+	}
+	for _, v := range []string{"", "0.001", "1.5.0", "1e3", "12,00", "-", "1.", "1.5"} {
+		currency := "CNY"
+		if v == "1.5" {
+			currency = "JPY"
+		}
+		if _, err := ParseMoney(v, currency); !errors.Is(err, AmountFormatErr) {
+			t.Errorf("ParseMoney(%q, %s) want AmountFormatErr, got: %v", v, currency, err)
+		}
+	}
+	if _, err := ParseMoney("92233720368547758.08", "CNY"); !errors.Is(err, AmountOverflowErr) {
+		t.Errorf("want AmountOverflowErr, got: %v", err)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := NewMoney(1000, "CNY"), NewMoney(1, "")
+	if sum, err := a.Add(b); err != nil || sum.Minor() != 1001 || sum.String() != "10.01 CNY" {
+		t.Errorf("Add = %v, %v", sum, err)
+	}
+	if diff, err := b.Sub(a); err != nil || diff.Minor() != -999 {
+		t.Errorf("Sub = %v, %v", diff, err)
+	}
+	if p, err := a.Mul(3); err != nil || p.Minor() != 3000 {
+		t.Errorf("Mul = %v, %v", p, err)
+	}
+	if c, err := a.Cmp(b); err != nil || c != 1 {
+		t.Errorf("Cmp = %d, %v", c, err)
+	}
+	if _, err := a.Add(NewMoney(1, "USD")); !errors.Is(err, CurrencyMismatchErr) {
+		t.Errorf("want CurrencyMismatchErr, got: %v", err)
+	}
+	max, min := NewMoney(math.MaxInt64, "CNY"), NewMoney(math.MinInt64, "CNY")
+	overflows := []func() (Money, error){
+		func() (Money, error) { return max.Add(b) },
+		func() (Money, error) { return min.Sub(b) },
+		func() (Money, error) { return max.Mul(2) },
+		func() (Money, error) { return min.Mul(-1) },
+		func() (Money, error) { return min.Neg() },
+	}
+	for i, fn := range overflows {
+		if _, err := fn(); !errors.Is(err, AmountOverflowErr) {
+			t.Errorf("overflows[%d] want AmountOverflowErr, got: %v", i, err)
+		}
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	cases := []struct {
+		amount int64
+		ratios []int64
+		want   []int64
+	}{
+		{100, []int64{70, 30}, []int64{70, 30}},
+		{5, []int64{1, 1}, []int64{3, 2}},
+		{10, []int64{1, 1, 1}, []int64{4, 3, 3}},
+		{-10, []int64{1, 1, 1}, []int64{-4, -3, -3}},
+		{2, []int64{0, 1, 1, 1}, []int64{0, 1, 1, 0}},
+		{math.MaxInt64, []int64{math.MaxInt64, 1}, []int64{math.MaxInt64, 0}},
+	}
+	for _, v := range cases {
+		parts, err := NewMoney(v.amount, "CNY").Allocate(v.ratios...)
+		if err != nil {
+			t.Errorf("Allocate(%d, %v), err: %v", v.amount, v.ratios, err)
+			continue
+		}
+		for i, p := range parts {
+			if p.Minor() != v.want[i] {
+				t.Errorf("Allocate(%d, %v) = %v, want %v", v.amount, v.ratios, parts, v.want)
+				break
+			}
+		}
+	}
+	if parts, err := NewMoney(10, "CNY").Split(3); err != nil || len(parts) != 3 || parts[0].Decimal() != "0.04" {
+		t.Errorf("Split = %v, %v", parts, err)
+	}
+	for _, ratios := range [][]int64{{}, {0, 0}, {1, -1}} {
+		if _, err := NewMoney(10, "CNY").Allocate(ratios...); !errors.Is(err, AmountFormatErr) {
+			t.Errorf("Allocate(%v) want AmountFormatErr, got: %v", ratios, err)
+		}
+	}
+}
+
+func TestBodyMapSetMoney(t *testing.T) {
+	m := NewMoney(1234, "usd")
+	bm := make(BodyMap)
+	bm.SetDecimalAmount("total_amount", m).
+		SetMinorAmount("total_fee", m).
+		SetCurrencyAmount("amount", m)
+	if bm.GetString("total_amount") != "12.34" || bm.GetString("total_fee") != "1234" ||
+		bm.JsonBody() != `{"amount":{"currency_code":"USD","value":"12.34"},"total_amount":"12.34","total_fee":1234}` {
+		t.Errorf("bm = %s", bm.JsonBody())
+	}
+}
@@ -0,0 +1,365 @@
+package gopay
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// NotifyState 通知处理状态
+type NotifyState int8
+
+const (
+	NotifyStateNew        NotifyState = iota // 首次处理，已记录为处理中
+	NotifyStateProcessing                    // 其他请求正在处理（如渠道在处理完成前重复投递）
+	NotifyStateDone                          // 已处理成功
+)
+
+// DefaultNotifyLease 处理中记录的默认租约时长，超过后视为处理进程已崩溃，重复投递的通知可再次处理
+const DefaultNotifyLease = 5 * time.Minute
+
+// NotifyDeduper 异步通知去重存储，记录 处理中、已处理 两种状态
+// 渠道在未收到成功应答前会重复投递通知，多副本部署时请使用共享存储实现（如 Redis SET NX PX + Lua 校验状态）
+type NotifyDeduper interface {
+	// Begin 开始处理通知 id：未记录、或处理中记录已超过租约时，记录为处理中并返回 NotifyStateNew
+	// 否则返回当前状态 NotifyStateProcessing 或 NotifyStateDone
+	Begin(ctx context.Context, id string) (state NotifyState, err error)
+	// Commit 业务处理成功，记录为已处理
+	Commit(ctx context.Context, id string) error
+	// Delete 删除通知 id，业务处理失败时调用，以便渠道重试时可再次处理
+	Delete(ctx context.Context, id string) error
+}
+
+// CheckNotifyDuplicate 通过 deduper 开始处理通知：已处理成功时返回 NotifyDuplicateErr，其他请求正在处理时返回 NotifyProcessingErr
+// 返回 nil 时，业务处理成功后需调用 deduper.Commit()，失败时调用 deduper.Delete()，推荐直接使用 HandleNotifyOnce()
+func CheckNotifyDuplicate(ctx context.Context, deduper NotifyDeduper, id string) error {
+	if id == NULL {
+		return fmt.Errorf("[%w], %v", MissParamErr, "notify id")
+	}
+	state, err := deduper.Begin(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch state {
+	case NotifyStateDone:
+		return fmt.Errorf("[%w]: %s", NotifyDuplicateErr, id)
+	case NotifyStateProcessing:
+		return fmt.Errorf("[%w]: %s", NotifyProcessingErr, id)
+	}
+	return nil
+}
+
+// HandleNotifyOnce 通过 deduper 保证同一通知只处理成功一次：
+// 首次处理时调用 fn，成功后记录为已处理，失败时删除记录以便渠道重试时可再次处理；
+// 已处理成功的重复通知不再调用 fn 并返回 nil，应答成功即可；
+// 其他请求正在处理时返回 NotifyProcessingErr，请应答失败，渠道会稍后重试
+// 注意：Commit 失败或处理进程崩溃时，租约过期后通知会被再次处理，fn 仍需保证幂等
+func HandleNotifyOnce(ctx context.Context, deduper NotifyDeduper, id string, fn func(ctx context.Context) error) error {
+	if err := CheckNotifyDuplicate(ctx, deduper, id); err != nil {
+		if errors.Is(err, NotifyDuplicateErr) {
+			return nil
+		}
+		return err
+	}
+	if err := fn(ctx); err != nil {
+		_ = deduper.Delete(ctx, id)
+		return err
+	}
+	return deduper.Commit(ctx, id)
+}
+
+// CheckNotifyTimestamp 校验通知时间戳是否在 window 时间窗口内，超出时返回 NotifyExpiredErr
+// window <= 0 时不校验
+func CheckNotifyTimestamp(ts time.Time, window time.Duration) error {
+	if window <= 0 {
+		return nil
+	}
+	if d := time.Since(ts); d > window || d < -window {
+		return fmt.Errorf("[%w]: %s, window: %s", NotifyExpiredErr, ts.Format(time.RFC3339), window)
+	}
+	return nil
+}
+
+// =============================== 内存 LRU ===============================
+
+type memoryNotifyItem struct {
+	id       string
+	state    NotifyState
+	expireAt time.Time // 零值表示永不过期
+}
+
+// MemoryNotifyDeduper 基于内存 LRU 的通知去重，仅适用于单实例部署
+type MemoryNotifyDeduper struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	lease    time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewMemoryNotifyDeduper 初始化内存 LRU 通知去重
+// capacity：最多保存的通知 id 数，超出后淘汰最久未使用的 id
+// ttl：已处理通知 id 的保存时长，需大于渠道重试通知的总时长（如微信 24h4m，支付宝 25h），<= 0 表示永久保存
+func NewMemoryNotifyDeduper(capacity int, ttl time.Duration) *MemoryNotifyDeduper {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryNotifyDeduper{
+		capacity: capacity,
+		ttl:      ttl,
+		lease:    DefaultNotifyLease,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}
+}
+
+// SetLease 设置处理中记录的租约时长，需大于业务处理的最长耗时，默认 DefaultNotifyLease
+func (m *MemoryNotifyDeduper) SetLease(lease time.Duration) *MemoryNotifyDeduper {
+	if lease > 0 {
+		m.lease = lease
+	}
+	return m
+}
+
+func (m *MemoryNotifyDeduper) Begin(_ context.Context, id string) (state NotifyState, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if e, exist := m.items[id]; exist {
+		item := e.Value.(*memoryNotifyItem)
+		m.ll.MoveToFront(e)
+		if item.expireAt.IsZero() || now.Before(item.expireAt) {
+			return item.state, nil
+		}
+		item.state, item.expireAt = NotifyStateProcessing, now.Add(m.lease)
+		return NotifyStateNew, nil
+	}
+	m.set(id, NotifyStateProcessing, now.Add(m.lease))
+	return NotifyStateNew, nil
+}
+
+func (m *MemoryNotifyDeduper) Commit(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expireAt time.Time
+	if m.ttl > 0 {
+		expireAt = time.Now().Add(m.ttl)
+	}
+	if e, exist := m.items[id]; exist {
+		item := e.Value.(*memoryNotifyItem)
+		item.state, item.expireAt = NotifyStateDone, expireAt
+		m.ll.MoveToFront(e)
+		return nil
+	}
+	m.set(id, NotifyStateDone, expireAt)
+	return nil
+}
+
+func (m *MemoryNotifyDeduper) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, exist := m.items[id]; exist {
+		m.ll.Remove(e)
+		delete(m.items, id)
+	}
+	return nil
+}
+
+// set 新增记录，超出容量时淘汰最久未使用的 id，调用方需持有 m.mu
+// 租约内的处理中记录不淘汰，否则重复投递的通知会被并发处理；全部为处理中记录时暂时超出容量
+func (m *MemoryNotifyDeduper) set(id string, state NotifyState, expireAt time.Time) {
+	m.items[id] = m.ll.PushFront(&memoryNotifyItem{id: id, state: state, expireAt: expireAt})
+	if m.ll.Len() <= m.capacity {
+		return
+	}
+	now := time.Now()
+	for e := m.ll.Back(); e != nil; e = e.Prev() {
+		item := e.Value.(*memoryNotifyItem)
+		if item.state == NotifyStateProcessing && now.Before(item.expireAt) {
+			continue
+		}
+		m.ll.Remove(e)
+		delete(m.items, item.id)
+		return
+	}
+}
+
+// =============================== 文件 ===============================
+
+// FileNotifyDeduper 基于文件的通知去重，每个通知 id 对应目录下一个文件，文件首字节记录状态（P：处理中，D：已处理）
+// 通过 O_CREATE|O_EXCL 创建处理中记录，rename 提交已处理记录；
+// 接管已过期记录及 Cleanup 删除过期记录时，持有目录下 .lock 文件的 flock 排它锁后再次检查并替换或删除，避免多个副本同时接管
+// 同一主机的多个进程或挂载同一共享目录（需支持 flock）的多个副本可共用；不支持 flock 的平台仅同一进程内互斥
+type FileNotifyDeduper struct {
+	dir   string
+	ttl   time.Duration
+	lease time.Duration
+}
+
+const (
+	fileNotifyProcessing = 'P'
+	fileNotifyDone       = 'D'
+	fileNotifyLock       = ".lock"
+)
+
+// NewFileNotifyDeduper 初始化文件通知去重
+// dir：存储目录，不存在时自动创建
+// ttl：已处理通知 id 的保存时长，<= 0 表示永久保存
+func NewFileNotifyDeduper(dir string, ttl time.Duration) (*FileNotifyDeduper, error) {
+	if dir == NULL {
+		return nil, fmt.Errorf("[%w], %v", MissParamErr, "dir")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileNotifyDeduper{dir: dir, ttl: ttl, lease: DefaultNotifyLease}, nil
+}
+
+// SetLease 设置处理中记录的租约时长，需大于业务处理的最长耗时，默认 DefaultNotifyLease
+func (f *FileNotifyDeduper) SetLease(lease time.Duration) *FileNotifyDeduper {
+	if lease > 0 {
+		f.lease = lease
+	}
+	return f
+}
+
+func (f *FileNotifyDeduper) Begin(_ context.Context, id string) (state NotifyState, err error) {
+	name := f.path(id)
+	for i := 0; i < 2; i++ {
+		file, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = file.WriteString(string(fileNotifyProcessing) + id)
+			if cErr := file.Close(); err == nil {
+				err = cErr
+			}
+			return NotifyStateNew, err
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return NotifyStateNew, err
+		}
+		state, expired, err := f.stat(name)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return NotifyStateNew, err
+		}
+		if !expired {
+			return state, nil
+		}
+		// 已过期（或处理中记录租约已过），加锁后再次检查，仍过期时以新的处理中记录原子替换
+		err = f.withLock(func() error {
+			if state, expired, err = f.stat(name); err != nil || !expired {
+				return err
+			}
+			if err = f.replace(name, fileNotifyProcessing, id); err == nil {
+				state = NotifyStateNew
+			}
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return NotifyStateNew, err
+		}
+		return state, nil
+	}
+	return NotifyStateProcessing, nil
+}
+
+func (f *FileNotifyDeduper) Commit(_ context.Context, id string) error {
+	return f.replace(f.path(id), fileNotifyDone, id)
+}
+
+func (f *FileNotifyDeduper) Delete(_ context.Context, id string) error {
+	if err := os.Remove(f.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Cleanup 删除已过期的通知 id 文件，可定时调用
+func (f *FileNotifyDeduper) Cleanup() error {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	return f.withLock(func() error {
+		for _, entry := range entries {
+			if entry.IsDir() || entry.Name() == fileNotifyLock {
+				continue
+			}
+			name := filepath.Join(f.dir, entry.Name())
+			if _, expired, err := f.stat(name); err == nil && expired {
+				_ = os.Remove(name)
+			}
+		}
+		return nil
+	})
+}
+
+// replace 写入临时文件后 rename 原子替换 name，读取方不会看到写入一半的记录
+func (f *FileNotifyDeduper) replace(name string, state byte, id string) error {
+	tmp, err := os.CreateTemp(f.dir, ".commit-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(string(state) + id)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// withLock 持有目录 .lock 文件的排它锁执行 fn
+func (f *FileNotifyDeduper) withLock(fn func() error) error {
+	lock, err := os.OpenFile(filepath.Join(f.dir, fileNotifyLock), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err = lockFile(lock); err != nil {
+		return err
+	}
+	defer unlockFile(lock)
+	return fn()
+}
+
+// stat 读取记录状态，处理中记录超过租约、已处理记录超过 ttl 时 expired 为 true
+func (f *FileNotifyDeduper) stat(name string) (state NotifyState, expired bool, err error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return NotifyStateNew, false, err
+	}
+	bs, err := os.ReadFile(name)
+	if err != nil {
+		return NotifyStateNew, false, err
+	}
+	age := time.Since(info.ModTime())
+	if len(bs) > 0 && bs[0] == fileNotifyDone {
+		return NotifyStateDone, f.ttl > 0 && age >= f.ttl, nil
+	}
+	// 处理中，或创建后尚未写入内容
+	return NotifyStateProcessing, age >= f.lease, nil
+}
+
+// 通知 id 可能包含路径字符，取 sha256 作为文件名
+func (f *FileNotifyDeduper) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,21 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package gopay
+
+import (
+	"os"
+	"sync"
+)
+
+// 不支持 flock 的平台，仅同一进程内互斥
+var fileNotifyMu sync.Mutex
+
+func lockFile(*os.File) error {
+	fileNotifyMu.Lock()
+	return nil
+}
+
+func unlockFile(*os.File) error {
+	fileNotifyMu.Unlock()
+	return nil
+}
@@ -0,0 +1,214 @@
+package gopay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-pay/xlog"
+)
+
+func TestMemoryNotifyDeduper(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	ctx := context.Background()
+	d := NewMemoryNotifyDeduper(2, time.Hour)
+	if err := CheckNotifyDuplicate(ctx, d, "a"); err != nil {
+		t.Fatalf("first a: %v", err)
+	}
+	// 首次处理尚未完成，重复投递不能确认成功
+	if err := CheckNotifyDuplicate(ctx, d, "a"); !errors.Is(err, NotifyProcessingErr) {
+		t.Fatalf("in-flight a want NotifyProcessingErr, got: %v", err)
+	}
+	_ = d.Commit(ctx, "a")
+	if err := CheckNotifyDuplicate(ctx, d, "a"); !errors.Is(err, NotifyDuplicateErr) {
+		t.Fatalf("committed a want NotifyDuplicateErr, got: %v", err)
+	}
+	// 容量为 2，写入 b、c 后 a 被淘汰
+	_, _ = d.Begin(ctx, "b")
+	_, _ = d.Begin(ctx, "c")
+	if state, _ := d.Begin(ctx, "a"); state != NotifyStateNew {
+		t.Error("a should be evicted")
+	}
+	_ = d.Delete(ctx, "c")
+	if state, _ := d.Begin(ctx, "c"); state != NotifyStateNew {
+		t.Error("c should be deleted")
+	}
+
+	expired := NewMemoryNotifyDeduper(10, time.Millisecond).SetLease(time.Millisecond)
+	_, _ = expired.Begin(ctx, "x")
+	_, _ = expired.Begin(ctx, "y")
+	_ = expired.Commit(ctx, "y")
+	time.Sleep(5 * time.Millisecond)
+	if state, _ := expired.Begin(ctx, "x"); state != NotifyStateNew {
+		t.Error("x lease should be expired")
+	}
+	if state, _ := expired.Begin(ctx, "y"); state != NotifyStateNew {
+		t.Error("y should be expired")
+	}
+}
+
+func TestMemoryNotifyDeduperEvictProcessing(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryNotifyDeduper(2, time.Hour)
+	_, _ = d.Begin(ctx, "a")
+	_, _ = d.Begin(ctx, "b")
+	_ = d.Commit(ctx, "b")
+	// a 处理中且租约未过，写入 c 时淘汰已处理的 b
+	_, _ = d.Begin(ctx, "c")
+	if state, _ := d.Begin(ctx, "a"); state != NotifyStateProcessing {
+		t.Errorf("in-flight a should not be evicted, got: %v", state)
+	}
+	if state, _ := d.Begin(ctx, "b"); state != NotifyStateNew {
+		t.Errorf("b should be evicted, got: %v", state)
+	}
+	// 全部为处理中记录时暂时超出容量
+	for _, id := range []string{"a", "b", "c"} {
+		if state, _ := d.Begin(ctx, id); state != NotifyStateProcessing {
+			t.Errorf("in-flight %s should not be evicted, got: %v", id, state)
+		}
+	}
+}
+
+func TestHandleNotifyOnce(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	ctx := context.Background()
+	d := NewMemoryNotifyDeduper(10, time.Hour)
+	var calls int
+	fail := errors.New("db down")
+	handle := func(err error) error {
+		return HandleNotifyOnce(ctx, d, "n1", func(context.Context) error {
+			calls++
+			return err
+		})
+	}
+	// 处理失败：删除记录，重试时再次处理
+	if err := handle(fail); !errors.Is(err, fail) {
+		t.Fatalf("want fail, got: %v", err)
+	}
+	if err := handle(nil); err != nil || calls != 2 {
+		t.Fatalf("retry: %v, calls: %d", err, calls)
+	}
+	// 已处理成功：不再调用
+	if err := handle(fail); err != nil || calls != 2 {
+		t.Fatalf("duplicate: %v, calls: %d", err, calls)
+	}
+
+	// 处理过程中的重复投递返回 NotifyProcessingErr
+	err := HandleNotifyOnce(ctx, d, "n2", func(ctx context.Context) error {
+		if err := HandleNotifyOnce(ctx, d, "n2", func(context.Context) error { return nil }); !errors.Is(err, NotifyProcessingErr) {
+			t.Errorf("in-flight want NotifyProcessingErr, got: %v", err)
+		}
+		return fail
+	})
+	if !errors.Is(err, fail) {
+		t.Fatalf("want fail, got: %v", err)
+	}
+	if state, _ := d.Begin(ctx, "n2"); state != NotifyStateNew {
+		t.Error("n2 should be retried after failure")
+	}
+}
+
+func TestFileNotifyDeduper(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	ctx := context.Background()
+	dir := t.TempDir()
+	d, err := NewFileNotifyDeduper(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := "wechat:EV-2018022511223320873/../"
+	if state, err := d.Begin(ctx, id); state != NotifyStateNew || err != nil {
+		t.Fatalf("first Begin: %v, %v", state, err)
+	}
+	// 另一个实例共享同一目录
+	other, _ := NewFileNotifyDeduper(dir, time.Hour)
+	if state, err := other.Begin(ctx, id); state != NotifyStateProcessing || err != nil {
+		t.Fatalf("in-flight Begin: %v, %v", state, err)
+	}
+	if err = d.Commit(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := other.Begin(ctx, id); state != NotifyStateDone || err != nil {
+		t.Fatalf("committed Begin: %v, %v", state, err)
+	}
+	if err = other.Delete(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := d.Begin(ctx, id); state != NotifyStateNew {
+		t.Error("id should be deleted")
+	}
+
+	// 处理中记录租约过期后可再次处理
+	d.SetLease(time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if state, _ := other.SetLease(time.Millisecond).Begin(ctx, id); state != NotifyStateNew {
+		t.Error("lease should be expired")
+	}
+	if err = d.Cleanup(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckNotifyTimestamp(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	if err := CheckNotifyTimestamp(time.Now().Add(-time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("want ok, got: %v", err)
+	}
+	if err := CheckNotifyTimestamp(time.Now().Add(-10*time.Minute), 5*time.Minute); !errors.Is(err, NotifyExpiredErr) {
+		t.Errorf("want NotifyExpiredErr, got: %v", err)
+	}
+	if err := CheckNotifyTimestamp(time.Now().Add(-10*time.Minute), 0); err != nil {
+		t.Errorf("window 0 want ok, got: %v", err)
+	}
+}
+
+func TestFileNotifyDeduperTakeover(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	d, err := NewFileNotifyDeduper(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := "alipay:2024010122001"
+	lease := 200 * time.Millisecond
+	_, _ = d.SetLease(lease).Begin(ctx, id)
+	time.Sleep(lease + 50*time.Millisecond)
+	// 多个副本同时接管同一过期记录，只有一个返回 NotifyStateNew
+	var (
+		wg  sync.WaitGroup
+		won atomic.Int32
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replica, _ := NewFileNotifyDeduper(dir, time.Hour)
+			state, err := replica.SetLease(lease).Begin(ctx, id)
+			if err != nil {
+				t.Error(err)
+			}
+			if state == NotifyStateNew {
+				won.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := won.Load(); n != 1 {
+		t.Fatalf("takeover winners: %d", n)
+	}
+	// Cleanup 不删除锁文件及未过期记录
+	if err = d.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, fileNotifyLock)); err != nil {
+		t.Errorf("lock file: %v", err)
+	}
+	if state, _ := d.Begin(ctx, id); state != NotifyStateProcessing {
+		t.Errorf("taken over record should be processing, got: %v", state)
+	}
+}
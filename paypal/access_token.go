@@ -51,7 +51,7 @@ func (c *Client) GetAccessToken() (token *AccessToken, err error) {
 	url = baseUrl + getAccessToken
 	// Authorization
 	authHeader := AuthorizationPrefixBasic + base64.StdEncoding.EncodeToString([]byte(c.Clientid+":"+c.Secret))
-	req := c.hc.Req(xhttp.TypeFormData)
+	req := c.hc.Req(xhttp.TypeFormData).SetApi(gopay.ProviderPayPal, getAccessToken)
 	req.Header.Add(HeaderAuthorization, authHeader)
 	req.Header.Add("Accept", "*/*")
 	// Body
@@ -0,0 +1,192 @@
+package paypal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+// 订单核心接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度、币种精度），再传给对应的方法
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&paypal.CreateOrderRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], purchase_units[0].amount.value: must be a positive amount with at most 0 decimal places for JPY
+//	}
+//	ppRsp, err := client.CreateOrder(ctx, bm)
+
+// CreateOrderRequest 创建订单请求参数，CreateOrder() 使用
+// 文档：https://developer.paypal.com/docs/api/orders/v2/#orders_create
+type CreateOrderRequest struct {
+	Intent             string                   `json:"intent" validate:"required,enum=CAPTURE|AUTHORIZE"`
+	PurchaseUnits      []*PurchaseUnitRequest   `json:"purchase_units" validate:"required,maxlen=10"`
+	ApplicationContext *ApplicationContextParam `json:"application_context,omitempty"`
+	Extra              gopay.BodyMap            `json:"-"`
+}
+
+type PurchaseUnitRequest struct {
+	ReferenceId    string       `json:"reference_id,omitempty" validate:"maxlen=256"`
+	Amount         *AmountParam `json:"amount" validate:"required"`
+	Payee          *Payee       `json:"payee,omitempty"`
+	Description    string       `json:"description,omitempty" validate:"maxlen=127"`
+	CustomId       string       `json:"custom_id,omitempty" validate:"maxlen=127"`
+	InvoiceId      string       `json:"invoice_id,omitempty" validate:"maxlen=127"`
+	SoftDescriptor string       `json:"soft_descriptor,omitempty" validate:"maxlen=22"`
+	Items          []*ItemParam `json:"items,omitempty"`
+	Shipping       *Shipping    `json:"shipping,omitempty"`
+}
+
+// AmountParam 金额，value 的小数位数需符合币种精度，如 USD "10.50"、JPY "1050"
+type AmountParam struct {
+	CurrencyCode string                 `json:"currency_code" validate:"required,len=3"`
+	Value        string                 `json:"value" validate:"required,maxlen=32"`
+	Breakdown    *PurchaseUnitBreakdown `json:"breakdown,omitempty"`
+}
+
+type ItemParam struct {
+	Name        string       `json:"name" validate:"required,maxlen=127"`
+	UnitAmount  *AmountParam `json:"unit_amount" validate:"required"`
+	Quantity    string       `json:"quantity" validate:"required,maxlen=10"`
+	Description string       `json:"description,omitempty" validate:"maxlen=127"`
+	Sku         string       `json:"sku,omitempty" validate:"maxlen=127"`
+	Category    string       `json:"category,omitempty" validate:"enum=DIGITAL_GOODS|PHYSICAL_GOODS|DONATION"`
+}
+
+type ApplicationContextParam struct {
+	BrandName          string `json:"brand_name,omitempty" validate:"maxlen=127"`
+	Locale             string `json:"locale,omitempty" validate:"maxlen=10"`
+	LandingPage        string `json:"landing_page,omitempty" validate:"enum=LOGIN|BILLING|NO_PREFERENCE"`
+	ShippingPreference string `json:"shipping_preference,omitempty" validate:"enum=GET_FROM_FILE|NO_SHIPPING|SET_PROVIDED_ADDRESS"`
+	UserAction         string `json:"user_action,omitempty" validate:"enum=CONTINUE|PAY_NOW"`
+	ReturnUrl          string `json:"return_url,omitempty" validate:"maxlen=2048"`
+	CancelUrl          string `json:"cancel_url,omitempty" validate:"maxlen=2048"`
+}
+
+// CaptureRefundRequest 支付捕获退款请求参数，PaymentCaptureRefund() 使用，amount 为空时全额退款
+// 文档：https://developer.paypal.com/docs/api/payments/v2/#captures_refund
+type CaptureRefundRequest struct {
+	Amount      *AmountParam  `json:"amount,omitempty"`
+	CustomId    string        `json:"custom_id,omitempty" validate:"maxlen=127"`
+	InvoiceId   string        `json:"invoice_id,omitempty" validate:"maxlen=127"`
+	NoteToPayer string        `json:"note_to_payer,omitempty" validate:"maxlen=255"`
+	Extra       gopay.BodyMap `json:"-"`
+}
+
+// OrderCaptureRequest 订单支付捕获请求参数，OrderCapture() 使用，通常无需参数
+// 文档：https://developer.paypal.com/docs/api/orders/v2/#orders_capture
+type OrderCaptureRequest struct {
+	PaymentSource *CapturePaymentSource `json:"payment_source,omitempty"`
+	Extra         gopay.BodyMap         `json:"-"`
+}
+
+type CapturePaymentSource struct {
+	Token *CaptureToken `json:"token,omitempty"`
+}
+
+type CaptureToken struct {
+	Id   string `json:"id" validate:"required,maxlen=255"`
+	Type string `json:"type" validate:"required,enum=BILLING_AGREEMENT"`
+}
+
+// OrderDetailRequest 订单详情查询参数，OrderDetail() 使用
+// 文档：https://developer.paypal.com/docs/api/orders/v2/#orders_get
+type OrderDetailRequest struct {
+	Fields string        `json:"fields,omitempty" validate:"maxlen=256"` // 返回的额外字段，如 payment_source
+	Extra  gopay.BodyMap `json:"-"`
+}
+
+// AuthorizeCaptureRequest 支付授权捕获请求参数，PaymentAuthorizeCapture() 使用，amount 为空时捕获全部授权金额
+// 文档：https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture
+type AuthorizeCaptureRequest struct {
+	Amount         *AmountParam  `json:"amount,omitempty"`
+	InvoiceId      string        `json:"invoice_id,omitempty" validate:"maxlen=127"`
+	FinalCapture   bool          `json:"final_capture,omitempty"`
+	NoteToPayer    string        `json:"note_to_payer,omitempty" validate:"maxlen=255"`
+	SoftDescriptor string        `json:"soft_descriptor,omitempty" validate:"maxlen=22"`
+	Extra          gopay.BodyMap `json:"-"`
+}
+
+// Validate 本地校验请求参数
+func (r *CreateOrderRequest) Validate() error {
+	errs := gopay.CheckStruct(r)
+	for i, unit := range r.PurchaseUnits {
+		if unit == nil {
+			continue
+		}
+		checkAmount(&errs, fmt.Sprintf("purchase_units[%d].amount", i), unit.Amount)
+		for j, item := range unit.Items {
+			if item != nil {
+				checkAmount(&errs, fmt.Sprintf("purchase_units[%d].items[%d].unit_amount", i, j), item.UnitAmount)
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// BodyMap 校验并转换为 CreateOrder() 的请求参数
+func (r *CreateOrderRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(r, r.Extra)
+}
+
+// Validate 本地校验请求参数
+func (r *CaptureRefundRequest) Validate() error {
+	errs := gopay.CheckStruct(r)
+	checkAmount(&errs, "amount", r.Amount)
+	return errs.Err()
+}
+
+// BodyMap 校验并转换为 PaymentCaptureRefund() 的请求参数
+func (r *CaptureRefundRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 OrderCapture() 的请求参数
+func (r *OrderCaptureRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(r); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(r, r.Extra)
+}
+
+// BodyMap 校验并转换为 OrderDetail() 的查询参数
+func (r *OrderDetailRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(r); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(r, r.Extra)
+}
+
+// Validate 本地校验请求参数
+func (r *AuthorizeCaptureRequest) Validate() error {
+	errs := gopay.CheckStruct(r)
+	checkAmount(&errs, "amount", r.Amount)
+	return errs.Err()
+}
+
+// BodyMap 校验并转换为 PaymentAuthorizeCapture() 的请求参数
+func (r *AuthorizeCaptureRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(r, r.Extra)
+}
+
+// checkAmount 按 PayPal 币种精度校验金额，HUF、JPY、TWD 不能带小数
+func checkAmount(errs *gopay.ValidationError, field string, amount *AmountParam) {
+	if amount == nil || amount.Value == gopay.NULL || len(amount.CurrencyCode) != 3 {
+		return
+	}
+	exp := CurrencyExponent(amount.CurrencyCode)
+	m, err := gopay.ParseMoney(amount.Value, amount.CurrencyCode)
+	if err != nil || !m.IsPositive() || exp == 0 && strings.Contains(amount.Value, ".") {
+		errs.Add(field+".value", fmt.Sprintf("must be a positive amount with at most %d decimal places for %s",
+			exp, strings.ToUpper(amount.CurrencyCode)))
+	}
+}
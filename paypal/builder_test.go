@@ -0,0 +1,69 @@
+package paypal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ BodyMap() (gopay.BodyMap, error) }
+		body    string
+		wantErr string
+	}{
+		{"create order", &CreateOrderRequest{
+			Intent:        "CAPTURE",
+			PurchaseUnits: []*PurchaseUnitRequest{{ReferenceId: "r01", Amount: &AmountParam{CurrencyCode: "USD", Value: "8.00"}}},
+			Extra:         gopay.BodyMap{"processing_instruction": "NO_INSTRUCTION"},
+		}, `{"intent":"CAPTURE","processing_instruction":"NO_INSTRUCTION","purchase_units":[{"amount":{"currency_code":"USD","value":"8.00"},"reference_id":"r01"}]}`, ""},
+		{"create order JPY decimals", &CreateOrderRequest{
+			Intent:        "CAPTURE",
+			PurchaseUnits: []*PurchaseUnitRequest{{Amount: &AmountParam{CurrencyCode: "JPY", Value: "100.5"}}},
+		}, "", "[invalid parameter], purchase_units[0].amount.value: must be a positive amount with at most 0 decimal places for JPY"},
+		{"create order HUF item", &CreateOrderRequest{
+			Intent: "AUTHORIZE",
+			PurchaseUnits: []*PurchaseUnitRequest{{
+				Amount: &AmountParam{CurrencyCode: "HUF", Value: "100"},
+				Items:  []*ItemParam{{Name: "a", Quantity: "1", UnitAmount: &AmountParam{CurrencyCode: "HUF", Value: "100.00"}}},
+			}},
+		}, "", "[invalid parameter], purchase_units[0].items[0].unit_amount.value: must be a positive amount with at most 0 decimal places for HUF"},
+		{"create order intent", &CreateOrderRequest{Intent: "SALE"}, "", "[invalid parameter], intent: must be one of CAPTURE|AUTHORIZE; purchase_units: is required"},
+		{"order capture", &OrderCaptureRequest{}, `{}`, ""},
+		{"order capture payment source", &OrderCaptureRequest{PaymentSource: &CapturePaymentSource{Token: &CaptureToken{Id: "BA-1", Type: "BILLING_AGREEMENT"}}},
+			`{"payment_source":{"token":{"id":"BA-1","type":"BILLING_AGREEMENT"}}}`, ""},
+		{"order capture token type", &OrderCaptureRequest{PaymentSource: &CapturePaymentSource{Token: &CaptureToken{Id: "BA-1", Type: "CARD"}}},
+			"", "[invalid parameter], payment_source.token.type: must be one of BILLING_AGREEMENT"},
+		{"order detail", &OrderDetailRequest{Fields: "payment_source"}, `{"fields":"payment_source"}`, ""},
+		{"authorize capture", &AuthorizeCaptureRequest{Amount: &AmountParam{CurrencyCode: "USD", Value: "1.00"}, FinalCapture: true},
+			`{"amount":{"currency_code":"USD","value":"1.00"},"final_capture":true}`, ""},
+		{"authorize capture TWD decimals", &AuthorizeCaptureRequest{Amount: &AmountParam{CurrencyCode: "TWD", Value: "1.50"}},
+			"", "[invalid parameter], amount.value: must be a positive amount with at most 0 decimal places for TWD"},
+		{"capture refund", &CaptureRefundRequest{NoteToPayer: "refund"}, `{"note_to_payer":"refund"}`, ""},
+		{"capture refund zero", &CaptureRefundRequest{Amount: &AmountParam{CurrencyCode: "USD", Value: "0"}},
+			"", "[invalid parameter], amount.value: must be a positive amount with at most 2 decimal places for USD"},
+	}
+	for _, tt := range tests {
+		bm, err := tt.req.BodyMap()
+		if tt.wantErr != "" {
+			if !errors.Is(err, gopay.ParamValidateErr) || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if body := bm.JsonBody(); body != tt.body {
+			t.Errorf("%s: body = %s", tt.name, body)
+		}
+	}
+	// 查询参数
+	bm, _ := (&OrderDetailRequest{Fields: "payment_source"}).BodyMap()
+	if p := bm.EncodeURLParams(); p != "fields=payment_source" {
+		t.Errorf("params = %s", p)
+	}
+}
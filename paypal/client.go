@@ -41,7 +41,7 @@ func NewClient(clientid, secret string, isProd bool, options ...Option) (client
 		IsProd:           isProd,
 		ctx:              context.Background(),
 		DebugSwitch:      gopay.DebugOff,
-		logger:           logger,
+		logger:           gopay.NewRedactLogger(logger, nil),
 		hc:               xhttp.NewClient(),
 		baseUrlProd:      baseUrlProd,
 		baseUrlSandbox:   baseUrlSandbox,
@@ -91,6 +91,13 @@ func (c *Client) SetBodySize(sizeMB int) {
 	}
 }
 
+// SetRetryPolicy 设置请求重试策略，policy.Classifier 为空时使用 RetryClassifier
+// 设置后，未自定义 PayPal-Request-Id 的 POST 请求自动生成幂等键，重试时保持不变；已自定义的值不做修改
+// 之后调用 SetHttpClient 需重新设置
+func (c *Client) SetRetryPolicy(policy *xhttp.RetryPolicy) {
+	c.hc.SetRetryPolicy(withClassifier(policy))
+}
+
 // SetHttpClient 设置自定义的xhttp.Client
 func (c *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
@@ -100,7 +107,7 @@ func (c *Client) SetHttpClient(client *xhttp.Client) {
 
 func (c *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		c.logger = logger
+		c.logger = gopay.NewRedactLogger(logger, nil)
 	}
 }
 
@@ -3,7 +3,8 @@ package paypal
 const (
 	Success = 0
 
-	HeaderAuthorization       = "Authorization" // 请求头Auth
+	HeaderAuthorization       = "Authorization"     // 请求头Auth
+	HeaderPayPalRequestId     = "PayPal-Request-Id" // 请求头幂等键
 	AuthorizationPrefixBasic  = "Basic "
 	AuthorizationPrefixBearer = "Bearer "
 
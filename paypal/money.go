@@ -0,0 +1,43 @@
+package paypal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+// PayPal 不支持小数的币种，ISO-4217 中 HUF、TWD 为 2 位小数，PayPal 要求金额为整数
+// 文档：https://developer.paypal.com/reference/currency-codes/
+var zeroDecimalCurrency = map[string]bool{"HUF": true, "JPY": true, "TWD": true}
+
+// CurrencyExponent PayPal 金额的小数位数：HUF、JPY、TWD 为 0，其他同 gopay.CurrencyExponent()
+func CurrencyExponent(currency string) int {
+	if zeroDecimalCurrency[strings.ToUpper(currency)] {
+		return 0
+	}
+	return gopay.CurrencyExponent(currency)
+}
+
+// FormatAmount 按 PayPal 要求格式化金额，如 gopay.NewMoney(1234, "USD") 为 "12.34"，gopay.NewMoney(12300, "HUF") 为 "123"
+// HUF、TWD 的金额不是整数时返回 gopay.AmountFormatErr
+func FormatAmount(m gopay.Money) (string, error) {
+	value := m.Decimal()
+	if !zeroDecimalCurrency[m.Currency()] {
+		return value, nil
+	}
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if strings.Trim(fracPart, "0") != gopay.NULL {
+		return gopay.NULL, fmt.Errorf("[%w]: %s, PayPal does not support decimals for %s", gopay.AmountFormatErr, value, m.Currency())
+	}
+	return intPart, nil
+}
+
+// NewAmount 按 PayPal 要求创建金额参数，如 {"currency_code": "USD", "value": "12.34"}
+func NewAmount(m gopay.Money) (*AmountParam, error) {
+	value, err := FormatAmount(m)
+	if err != nil {
+		return nil, err
+	}
+	return &AmountParam{CurrencyCode: m.Currency(), Value: value}, nil
+}
@@ -0,0 +1,39 @@
+package paypal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		m    gopay.Money
+		want string
+	}{
+		{gopay.NewMoney(1234, "usd"), "12.34"},
+		{gopay.NewMoney(1234, "JPY"), "1234"},
+		{gopay.NewMoney(12300, "HUF"), "123"},
+		{gopay.NewMoney(-500, "TWD"), "-5"},
+	}
+	for _, v := range cases {
+		if got, err := FormatAmount(v.m); err != nil || got != v.want {
+			t.Errorf("FormatAmount(%s) = %s, err: %v, want %s", v.m, got, err, v.want)
+		}
+	}
+	if _, err := FormatAmount(gopay.NewMoney(12345, "HUF")); !errors.Is(err, gopay.AmountFormatErr) {
+		t.Errorf("HUF with decimals want AmountFormatErr, got: %v", err)
+	}
+	amount, err := NewAmount(gopay.NewMoney(100, "TWD"))
+	if err != nil || amount.CurrencyCode != "TWD" || amount.Value != "1" {
+		t.Errorf("NewAmount = %+v, err: %v", amount, err)
+	}
+
+	var errs gopay.ValidationError
+	checkAmount(&errs, "amount", &AmountParam{CurrencyCode: "HUF", Value: "123.00"})
+	checkAmount(&errs, "amount", &AmountParam{CurrencyCode: "HUF", Value: "123"})
+	if len(errs) != 1 {
+		t.Errorf("checkAmount: %v", errs.Err())
+	}
+}
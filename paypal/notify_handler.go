@@ -0,0 +1,146 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+const (
+	EventTypeCheckoutOrderApproved  = "CHECKOUT.ORDER.APPROVED"  // 买家已授权订单
+	EventTypeCheckoutOrderCompleted = "CHECKOUT.ORDER.COMPLETED" // 订单已完成
+	EventTypeCaptureCompleted       = "PAYMENT.CAPTURE.COMPLETED"
+	EventTypeCaptureDenied          = "PAYMENT.CAPTURE.DENIED"
+	EventTypeCapturePending         = "PAYMENT.CAPTURE.PENDING"
+	EventTypeCaptureRefunded        = "PAYMENT.CAPTURE.REFUNDED"
+	EventTypeCaptureReversed        = "PAYMENT.CAPTURE.REVERSED"
+
+	// Webhook 签名相关请求头
+	HeaderAuthAlgo         = "PAYPAL-AUTH-ALGO"
+	HeaderCertUrl          = "PAYPAL-CERT-URL"
+	HeaderTransmissionId   = "PAYPAL-TRANSMISSION-ID"
+	HeaderTransmissionSig  = "PAYPAL-TRANSMISSION-SIG"
+	HeaderTransmissionTime = "PAYPAL-TRANSMISSION-TIME"
+
+	webhookVerifySuccess = "SUCCESS"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler PayPal Webhook 通知处理器，实现 http.Handler
+// 依次完成：解析 -> 调用 VerifyWebhookSignature 验签 -> 按 event_type 分发到回调 -> 应答
+// 成功应答 HTTP 200，失败应答 HTTP 500，PayPal 会按策略重试通知
+// 未注册对应回调的通知直接应答 HTTP 200
+type NotifyHandler struct {
+	client    *Client
+	webhookId string
+	onOrder   func(ctx context.Context, event *WebhookEvent, order *OrderDetail) error
+	onCapture func(ctx context.Context, event *WebhookEvent, capture *Capture) error
+	onRefund  func(ctx context.Context, event *WebhookEvent, refund *Refund) error
+	onNotify  func(ctx context.Context, event *WebhookEvent) error
+	onError   func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化 Webhook 通知处理器
+// webhookId：创建 Webhook 时返回的 id，用于验签
+func NewNotifyHandler(client *Client, webhookId string) *NotifyHandler {
+	return &NotifyHandler{client: client, webhookId: webhookId}
+}
+
+// OnOrder 注册订单事件（CHECKOUT.ORDER.*）回调
+func (h *NotifyHandler) OnOrder(fn func(ctx context.Context, event *WebhookEvent, order *OrderDetail) error) *NotifyHandler {
+	h.onOrder = fn
+	return h
+}
+
+// OnCapture 注册支付捕获事件（PAYMENT.CAPTURE.COMPLETED、DENIED、PENDING 等）回调
+func (h *NotifyHandler) OnCapture(fn func(ctx context.Context, event *WebhookEvent, capture *Capture) error) *NotifyHandler {
+	h.onCapture = fn
+	return h
+}
+
+// OnRefund 注册退款事件（PAYMENT.CAPTURE.REFUNDED、PAYMENT.CAPTURE.REVERSED）回调
+func (h *NotifyHandler) OnRefund(fn func(ctx context.Context, event *WebhookEvent, refund *Refund) error) *NotifyHandler {
+	h.onRefund = fn
+	return h
+}
+
+// OnNotify 注册其他类型事件回调，event.Resource 为原始 JSON
+func (h *NotifyHandler) OnNotify(fn func(ctx context.Context, event *WebhookEvent) error) *NotifyHandler {
+	h.onNotify = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	bs, err := io.ReadAll(io.LimitReader(req.Body, int64(5<<20)))
+	defer req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+	event := new(WebhookEvent)
+	if err = json.Unmarshal(bs, event); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	ctx := req.Context()
+	bm := make(gopay.BodyMap)
+	bm.Set("auth_algo", req.Header.Get(HeaderAuthAlgo)).
+		Set("cert_url", req.Header.Get(HeaderCertUrl)).
+		Set("transmission_id", req.Header.Get(HeaderTransmissionId)).
+		Set("transmission_sig", req.Header.Get(HeaderTransmissionSig)).
+		Set("transmission_time", req.Header.Get(HeaderTransmissionTime)).
+		Set("webhook_id", h.webhookId).
+		Set("webhook_event", json.RawMessage(bs))
+	verifyRes, err := h.client.VerifyWebhookSignature(ctx, bm)
+	if err != nil {
+		return err
+	}
+	if verifyRes.VerificationStatus != webhookVerifySuccess {
+		return fmt.Errorf("[%w]: verification_status: %s", gopay.VerifySignatureErr, verifyRes.VerificationStatus)
+	}
+	switch {
+	case strings.HasPrefix(event.EventType, "CHECKOUT.ORDER.") && h.onOrder != nil:
+		order := new(OrderDetail)
+		if err = json.Unmarshal(event.Resource, order); err != nil {
+			return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(event.Resource))
+		}
+		return h.onOrder(ctx, event, order)
+	case (event.EventType == EventTypeCaptureRefunded || event.EventType == EventTypeCaptureReversed) && h.onRefund != nil:
+		refund := new(Refund)
+		if err = json.Unmarshal(event.Resource, refund); err != nil {
+			return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(event.Resource))
+		}
+		return h.onRefund(ctx, event, refund)
+	case strings.HasPrefix(event.EventType, "PAYMENT.CAPTURE.") && event.EventType != EventTypeCaptureRefunded &&
+		event.EventType != EventTypeCaptureReversed && h.onCapture != nil:
+		capture := new(Capture)
+		if err = json.Unmarshal(event.Resource, capture); err != nil {
+			return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(event.Resource))
+		}
+		return h.onCapture(ctx, event, capture)
+	case h.onNotify != nil:
+		return h.onNotify(ctx, event)
+	}
+	return nil
+}
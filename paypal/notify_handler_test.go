@@ -0,0 +1,113 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case getAccessToken:
+			_, _ = w.Write([]byte(`{"access_token":"A21AAFEpH4PsADK7qSS7pSRsgzfENtu-Q1ysgEDVDESseMHBYXVJYE8ovjj68elIDy8nF26AwPhfXTIeWAZHSLIsQkSYz9ifg","token_type":"Bearer","expires_in":32400}`))
+		case verifyWebhookSignature:
+			var body struct {
+				TransmissionSig string `json:"transmission_sig"`
+				WebhookId       string `json:"webhook_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			status := "FAILURE"
+			if body.TransmissionSig == "valid-sig" && body.WebhookId == "WH-1" {
+				status = webhookVerifySuccess
+			}
+			_, _ = w.Write([]byte(`{"verification_status":"` + status + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient("clientid", "secret", false, WithProxyUrl(ts.URL, ts.URL), WithoutAutoRefreshToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	h := NewNotifyHandler(c, "WH-1").
+		OnOrder(func(ctx context.Context, event *WebhookEvent, order *OrderDetail) error {
+			got = "order:" + order.Id
+			return nil
+		}).
+		OnCapture(func(ctx context.Context, event *WebhookEvent, capture *Capture) error {
+			got = "capture:" + capture.Id
+			return nil
+		}).
+		OnRefund(func(ctx context.Context, event *WebhookEvent, refund *Refund) error {
+			got = "refund:" + refund.Id
+			return nil
+		}).
+		OnNotify(func(ctx context.Context, event *WebhookEvent) error {
+			got = "notify:" + event.EventType
+			return nil
+		})
+
+	serve := func(eventType, sig string) int {
+		body := `{"id":"WH-EVT-1","event_type":"` + eventType + `","resource":{"id":"R-1","status":"COMPLETED"}}`
+		req := httptest.NewRequest(http.MethodPost, "/notify/paypal", strings.NewReader(body))
+		req.Header.Set(HeaderAuthAlgo, "SHA256withRSA")
+		req.Header.Set(HeaderCertUrl, "https://api.sandbox.paypal.com/v1/notifications/certs/CERT-360caa42-fca2a594-1d93a270")
+		req.Header.Set(HeaderTransmissionId, "69cd13f0-d67a-11e5-baa3-778b53f4ae55")
+		req.Header.Set(HeaderTransmissionSig, sig)
+		req.Header.Set(HeaderTransmissionTime, "2016-02-18T20:01:35Z")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 按 event_type 分发
+	tests := []struct {
+		eventType string
+		want      string
+	}{
+		{EventTypeCheckoutOrderApproved, "order:R-1"},
+		{EventTypeCheckoutOrderCompleted, "order:R-1"},
+		{EventTypeCaptureCompleted, "capture:R-1"},
+		{EventTypeCapturePending, "capture:R-1"},
+		{EventTypeCaptureRefunded, "refund:R-1"},
+		{EventTypeCaptureReversed, "refund:R-1"},
+		{"BILLING.SUBSCRIPTION.CREATED", "notify:BILLING.SUBSCRIPTION.CREATED"},
+	}
+	for _, tt := range tests {
+		got = ""
+		if code := serve(tt.eventType, "valid-sig"); code != http.StatusOK || got != tt.want {
+			t.Errorf("%s: code = %d, got = %s, want %s", tt.eventType, code, got, tt.want)
+		}
+	}
+
+	// 验签失败，应答 500 且不回调
+	got = ""
+	if code := serve(EventTypeCaptureCompleted, "forged-sig"); code != http.StatusInternalServerError || got != "" {
+		t.Errorf("forged: code = %d, got = %s", code, got)
+	}
+
+	// 回调返回错误，应答 500
+	var errs []error
+	h.OnCapture(func(ctx context.Context, event *WebhookEvent, capture *Capture) error {
+		return errors.New("db unavailable")
+	}).OnError(func(req *http.Request, err error) {
+		errs = append(errs, err)
+	})
+	if code := serve(EventTypeCaptureCompleted, "valid-sig"); code != http.StatusInternalServerError || len(errs) != 1 {
+		t.Errorf("callback err: code = %d, errs = %v", code, errs)
+	}
+
+	// 未注册对应回调，直接应答 200
+	h = NewNotifyHandler(c, "WH-1")
+	if code := serve(EventTypeCaptureDenied, "valid-sig"); code != http.StatusOK {
+		t.Errorf("no callback: code = %d", code)
+	}
+}
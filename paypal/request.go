@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/go-pay/util"
 	"github.com/w6xian/gopay"
 	"github.com/w6xian/gopay/pkg/xhttp"
 )
@@ -16,7 +17,7 @@ func (c *Client) doPayPalGet(ctx context.Context, uri string) (res *http.Respons
 	if !c.IsProd {
 		url = c.baseUrlSandbox + uri
 	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderPayPal, uri) // default json
 	c.setPaypalHeader(ctx, req)
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("PayPal_Url: %s", url)
@@ -42,8 +43,12 @@ func (c *Client) doPayPalPost(ctx context.Context, bm gopay.BodyMap, path string
 	if !c.IsProd {
 		url = c.baseUrlSandbox + path
 	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderPayPal, path) // default json
 	c.setPaypalHeader(ctx, req)
+	// 幂等键：仅在设置重试策略且未自定义时自动生成，重试时保持不变
+	if c.hc.RetryPolicy() != nil && req.Header.Get(HeaderPayPalRequestId) == "" {
+		req.Header.Set(HeaderPayPalRequestId, util.RandomString(32))
+	}
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("PayPal_Url: %s", url)
 		c.logger.Debugf("PayPal_Req_Body: %s", bm.JsonBody())
@@ -65,7 +70,7 @@ func (c *Client) doPayPalPut(ctx context.Context, bm gopay.BodyMap, path string)
 	if !c.IsProd {
 		url = c.baseUrlSandbox + path
 	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderPayPal, path) // default json
 	c.setPaypalHeader(ctx, req)
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("PayPal_Url: %s", url)
@@ -88,7 +93,7 @@ func (c *Client) doPayPalPatch(ctx context.Context, patchs []*Patch, path string
 	if !c.IsProd {
 		url = c.baseUrlSandbox + path
 	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderPayPal, path) // default json
 	c.setPaypalHeader(ctx, req)
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("PayPal_Url: %s", url)
@@ -112,7 +117,7 @@ func (c *Client) doPayPalDelete(ctx context.Context, path string) (res *http.Res
 	if !c.IsProd {
 		url = c.baseUrlSandbox + path
 	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderPayPal, path) // default json
 	c.setPaypalHeader(ctx, req)
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("PayPal_Url: %s", url)
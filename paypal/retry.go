@@ -0,0 +1,28 @@
+package paypal
+
+import (
+	"net/http"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// RetryClassifier PayPal 重试判断
+// POST、PATCH 携带 PayPal-Request-Id 时按 xhttp.IdempotentRetryClassifier 重试，其他请求按 xhttp.DefaultRetryClassifier 判断，避免重复创建资源
+func RetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch:
+		if req.Header.Get(HeaderPayPalRequestId) != "" {
+			return xhttp.IdempotentRetryClassifier(req, res, body, err)
+		}
+	}
+	return xhttp.DefaultRetryClassifier(req, res, body, err)
+}
+
+func withClassifier(policy *xhttp.RetryPolicy) *xhttp.RetryPolicy {
+	if policy == nil || policy.Classifier != nil {
+		return policy
+	}
+	p := *policy
+	p.Classifier = RetryClassifier
+	return &p
+}
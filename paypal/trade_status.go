@@ -0,0 +1,76 @@
+package paypal
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 订单状态
+	OrderStatusCreated             = "CREATED"
+	OrderStatusSaved               = "SAVED"
+	OrderStatusApproved            = "APPROVED"
+	OrderStatusVoided              = "VOIDED"
+	OrderStatusCompleted           = "COMPLETED"
+	OrderStatusPayerActionRequired = "PAYER_ACTION_REQUIRED"
+
+	// Capture 状态
+	CaptureStatusCompleted         = "COMPLETED"
+	CaptureStatusDeclined          = "DECLINED"
+	CaptureStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	CaptureStatusPending           = "PENDING"
+	CaptureStatusRefunded          = "REFUNDED"
+	CaptureStatusFailed            = "FAILED"
+)
+
+// NormalizeTradeStatus PayPal 订单状态转统一订单状态
+// 已完成订单的退款情况需结合 Capture 状态判断，请使用 NormalizeOrderStatus()
+func NormalizeTradeStatus(orderStatus string) gopay.TradeStatus {
+	switch orderStatus {
+	case OrderStatusCreated, OrderStatusSaved:
+		return gopay.TradeStatusPending
+	case OrderStatusApproved, OrderStatusPayerActionRequired:
+		return gopay.TradeStatusPaying
+	case OrderStatusVoided:
+		return gopay.TradeStatusClosed
+	case OrderStatusCompleted:
+		return gopay.TradeStatusSucceeded
+	}
+	return gopay.TradeStatusUnknown
+}
+
+// NormalizeCaptureStatus PayPal Capture 状态转统一订单状态
+func NormalizeCaptureStatus(captureStatus string) gopay.TradeStatus {
+	switch captureStatus {
+	case CaptureStatusPending:
+		return gopay.TradeStatusPaying
+	case CaptureStatusCompleted:
+		return gopay.TradeStatusSucceeded
+	case CaptureStatusPartiallyRefunded:
+		return gopay.TradeStatusPartiallyRefunded
+	case CaptureStatusRefunded:
+		return gopay.TradeStatusRefunded
+	case CaptureStatusDeclined, CaptureStatusFailed:
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
+
+// NormalizeOrderStatus PayPal 订单详情转统一订单状态，已完成订单以首个 Capture 状态为准
+func NormalizeOrderStatus(order *OrderDetail) gopay.TradeStatus {
+	if order == nil {
+		return gopay.TradeStatusUnknown
+	}
+	status := NormalizeTradeStatus(order.Status)
+	if order.Status != OrderStatusCompleted {
+		return status
+	}
+	for _, unit := range order.PurchaseUnits {
+		if unit.Payments != nil && len(unit.Payments.Captures) > 0 {
+			if s := NormalizeCaptureStatus(unit.Payments.Captures[0].Status); s != gopay.TradeStatusUnknown {
+				return s
+			}
+			break
+		}
+	}
+	return status
+}
@@ -4,16 +4,22 @@ import (
 	"crypto/tls"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type Client struct {
-	HttpClient *http.Client
-	bodySize   int // body size limit(MB), default is 10MB
+	HttpClient   *http.Client
+	bodySize     int // body size limit(MB), default is 10MB
+	retry        *RetryPolicy
+	interceptors []*Interceptor
+	pinMu        sync.RWMutex
+	pins         map[string]map[string]bool // host -> 公钥指纹
+	transport    *http.Transport            // Client 独占的 Transport，TLS 相关设置只修改它
 }
 
 func defaultClient() *Client {
-	return &Client{
+	c := &Client{
 		HttpClient: &http.Client{
 			Timeout: 60 * time.Second,
 			Transport: &http.Transport{
@@ -22,37 +28,55 @@ func defaultClient() *Client {
 					Timeout:   30 * time.Second,
 					KeepAlive: 30 * time.Second,
 				}),
-				TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-				MaxIdleConnsPerHost:   1000,
-				MaxConnsPerHost:       3000,
+				TLSClientConfig:       NewTLSConfig(),
+				MaxIdleConns:          100,
+				MaxIdleConnsPerHost:   20,
+				MaxConnsPerHost:       200,
 				IdleConnTimeout:       90 * time.Second,
 				TLSHandshakeTimeout:   10 * time.Second,
 				ExpectContinueTimeout: 1 * time.Second,
-				DisableKeepAlives:     true,
 				ForceAttemptHTTP2:     true,
 			},
 		},
 		bodySize: 10, // default is 10MB
 	}
+	c.transport = c.HttpClient.Transport.(*http.Transport)
+	c.tlsConfig()
+	return c
 }
 
-// NewClient , default tls.Config{InsecureSkipVerify: true}
+// NewClient , default verify server certificates with the system root CAs and reuse connections
 func NewClient() (client *Client) {
 	return defaultClient()
 }
 
+// NewInsecureClient 不校验服务端证书（tls.Config{InsecureSkipVerify: true}），仅用于测试环境或受信任的内网代理
+// 已设置的公钥固定仍然生效
+func NewInsecureClient() (client *Client) {
+	return defaultClient().SetInsecureSkipVerify(true)
+}
+
 func (c *Client) SetTransport(transport http.RoundTripper) (client *Client) {
 	c.HttpClient.Transport = transport
 	return c
 }
 
+// SetHttpTransport 使用 transport 的副本（含 TLSClientConfig），不修改调用方的 transport
 func (c *Client) SetHttpTransport(transport *http.Transport) (client *Client) {
 	c.HttpClient.Transport = transport
+	c.tlsConfig()
 	return c
 }
 
+// SetHttpTLSConfig 使用 tlsCfg 的副本替换 TLS 配置，tlsCfg.VerifyConnection 为空时，已设置的公钥固定继续生效
 func (c *Client) SetHttpTLSConfig(tlsCfg *tls.Config) (client *Client) {
-	if ht, ok := c.HttpClient.Transport.(*http.Transport); ok {
+	if ht := c.httpTransport(); ht != nil {
+		if tlsCfg != nil {
+			tlsCfg = tlsCfg.Clone()
+			if tlsCfg.VerifyConnection == nil {
+				tlsCfg.VerifyConnection = c.verifyPins
+			}
+		}
 		ht.TLSClientConfig = tlsCfg
 	}
 	return c
@@ -69,6 +93,17 @@ func (c *Client) SetBodySize(sizeMB int) (client *Client) {
 	return c
 }
 
+// SetRetryPolicy 设置请求重试策略，nil 表示不重试（默认）
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) (client *Client) {
+	c.retry = policy
+	return c
+}
+
+// RetryPolicy 获取请求重试策略，未设置时返回 nil
+func (c *Client) RetryPolicy() *RetryPolicy {
+	return c.retry
+}
+
 // typeStr is request type and response type
 // default is TypeJSON
 // first param is request type
@@ -0,0 +1,97 @@
+package xhttp
+
+import (
+	"net/http"
+)
+
+// RequestInfo 请求信息，同一次调用的多次重试共用
+type RequestInfo struct {
+	Provider string // 支付渠道，如 gopay.ProviderAlipay，未设置时为空
+	Api      string // 接口名，支付宝为 method（如 alipay.trade.pay），其他渠道为请求路径
+	Route    string // 接口路由模板，路径中的订单号等参数替换为占位符，如 /v3/pay/transactions/out-trade-no/{out_trade_no}，适用于指标等需低基数的场景
+	Method   string // HTTP 方法
+	Url      string // 请求地址
+	Body     []byte // 已签名的请求体，GET 请求为 nil
+	Attempt  int    // 第几次请求，从 1 开始
+}
+
+// Interceptor 请求拦截器，字段为空表示不处理
+// 每次 HTTP 请求（含重试）依次调用各拦截器的 BeforeSend，收到应答或出错后逆序调用 AfterReceive、OnError
+type Interceptor struct {
+	// BeforeSend 发送前调用，可修改请求头或通过 req.WithContext 传递数据，返回的 req 用于后续发送
+	// 返回 error 时不再发送请求（如熔断），该 error 作为请求结果返回
+	BeforeSend func(req *http.Request, info *RequestInfo) (*http.Request, error)
+	// AfterReceive 收到应答后调用，返回 error 时该 error 作为请求结果返回
+	AfterReceive func(req *http.Request, info *RequestInfo, res *http.Response, body []byte) error
+	// OnError 请求失败时调用，包括网络错误及 BeforeSend、AfterReceive 返回的 error
+	OnError func(req *http.Request, info *RequestInfo, err error)
+}
+
+// AddInterceptor 添加请求拦截器
+func (c *Client) AddInterceptor(interceptors ...*Interceptor) (client *Client) {
+	for _, v := range interceptors {
+		if v != nil {
+			c.interceptors = append(c.interceptors, v)
+		}
+	}
+	return c
+}
+
+// Interceptors 获取已添加的请求拦截器
+func (c *Client) Interceptors() []*Interceptor {
+	return c.interceptors
+}
+
+// SetApi 设置请求所属的支付渠道与接口名，供拦截器使用，路由模板由 RouteTemplate(api) 生成
+func (r *Request) SetApi(provider, api string) *Request {
+	r.provider = provider
+	r.api = api
+	r.route = RouteTemplate(api)
+	return r
+}
+
+// SetRoute 自定义接口路由模板，需在 SetApi 之后调用
+func (r *Request) SetRoute(route string) *Request {
+	r.route = route
+	return r
+}
+
+// send 经拦截器发送一次请求
+func (r *Request) send(req *http.Request, info *RequestInfo) (res *http.Response, bs []byte, err error) {
+	interceptors := r.client.interceptors
+	n := 0
+	for ; n < len(interceptors); n++ {
+		if h := interceptors[n].BeforeSend; h != nil {
+			newReq, e := h(req, info)
+			if e != nil {
+				err = e
+				break
+			}
+			if newReq != nil {
+				req = newReq
+			}
+		}
+	}
+	if err == nil {
+		res, bs, err = r.roundTrip(req)
+	}
+	// 仅回调已执行 BeforeSend 的拦截器
+	if n == len(interceptors) {
+		n--
+	}
+	for i := n; i >= 0 && err == nil; i-- {
+		if h := interceptors[i].AfterReceive; h != nil {
+			err = h(req, info, res, bs)
+		}
+	}
+	if err != nil {
+		r.closeStream(res)
+		for i := n; i >= 0; i-- {
+			if h := interceptors[i].OnError; h != nil {
+				h(req, info, err)
+			}
+		}
+		return nil, nil, err
+	}
+	return res, bs, nil
+}
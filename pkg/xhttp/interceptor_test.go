@@ -0,0 +1,101 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Trace-Id")))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	trace := &Interceptor{
+		BeforeSend: func(req *http.Request, info *RequestInfo) (*http.Request, error) {
+			calls = append(calls, "trace.before")
+			if info.Provider != "alipay" || info.Api != "alipay.trade.query" || string(info.Body) != "a=1" || info.Attempt != 1 {
+				t.Errorf("info: %+v", info)
+			}
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return req, nil
+		},
+		AfterReceive: func(req *http.Request, info *RequestInfo, res *http.Response, body []byte) error {
+			calls = append(calls, "trace.after")
+			return nil
+		},
+		OnError: func(req *http.Request, info *RequestInfo, err error) {
+			calls = append(calls, "trace.error")
+		},
+	}
+	breakerErr := errors.New("circuit open")
+	open := false
+	breaker := &Interceptor{
+		BeforeSend: func(req *http.Request, info *RequestInfo) (*http.Request, error) {
+			calls = append(calls, "breaker.before")
+			if open {
+				return nil, breakerErr
+			}
+			return req, nil
+		},
+		AfterReceive: func(req *http.Request, info *RequestInfo, res *http.Response, body []byte) error {
+			calls = append(calls, "breaker.after")
+			return nil
+		},
+	}
+	client := NewClient().AddInterceptor(trace, breaker)
+
+	_, bs, err := client.Req(TypeFormData).SetApi("alipay", "alipay.trade.query").Post(srv.URL).SendString("a=1").EndBytes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "trace-1" {
+		t.Fatalf("header not mutated: %s", bs)
+	}
+	if got := strings.Join(calls, ","); got != "trace.before,breaker.before,breaker.after,trace.after" {
+		t.Fatalf("calls: %s", got)
+	}
+
+	// 熔断：不发送请求，返回 BeforeSend 的 error
+	calls, open = nil, true
+	_, _, err = client.Req(TypeFormData).SetApi("alipay", "alipay.trade.query").Post(srv.URL).SendString("a=1").EndBytes(context.Background())
+	if !errors.Is(err, breakerErr) {
+		t.Fatalf("err: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "trace.before,breaker.before,trace.error" {
+		t.Fatalf("calls: %s", got)
+	}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		api, want string
+	}{
+		{"alipay.trade.query", "alipay.trade.query"},
+		{"/v3/pay/transactions/jsapi", "/v3/pay/transactions/jsapi"},
+		{"/v3/pay/transactions/out-trade-no/1217752501201407033?mchid=1230000109", "/v3/pay/transactions/out-trade-no/{out_trade_no}"},
+		{"/v3/pay/transactions/out-trade-no/order/close", "/v3/pay/transactions/out-trade-no/{out_trade_no}/close"},
+		{"/v3/pay/transactions/id/4200000985202103031441826014", "/v3/pay/transactions/id/{id}"},
+		{"/v3/transfer/batches/out-batch-no/plfk2020042013/details/out-detail-no/x23zy545Bd5436", "/v3/transfer/batches/out-batch-no/{out_batch_no}/details/out-detail-no/{out_detail_no}"},
+		{"/v3/refund/domestic/refunds/1217752501201407033", "/v3/refund/domestic/refunds/{id}"},
+		{"/v3/merchant-service/complaints-v2/200201820200101080076610000/response", "/v3/merchant-service/complaints-v2/{id}/response"},
+		{"/v2/checkout/orders/5O190127TN364715T/capture", "/v2/checkout/orders/{id}/capture"},
+		{"/v1/oauth2/token", "/v1/oauth2/token"},
+		{"/inApps/v1/history/2000000123", "/inApps/v1/history/{id}"},
+		{"/api/v1.0/gateway/partners/PINE/orders/20240101153012/refunds/R20240101", "/api/v1.0/gateway/partners/{id}/orders/{id}/refunds/{id}"},
+		{"https://api.qpay.qq.com/cgi-bin/pay/qpay_unified_order.cgi", "/cgi-bin/pay/qpay_unified_order.cgi"},
+	}
+	for _, tt := range tests {
+		if got := RouteTemplate(tt.api); got != tt.want {
+			t.Errorf("RouteTemplate(%s) = %s, want %s", tt.api, got, tt.want)
+		}
+	}
+	if info := new(Request).SetApi("wechat_v3", "/v3/pay/transactions/id/1").SetRoute("/v3/pay/transactions/id/{transaction_id}"); info.route != "/v3/pay/transactions/id/{transaction_id}" {
+		t.Errorf("SetRoute: %s", info.route)
+	}
+}
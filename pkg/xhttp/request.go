@@ -33,9 +33,23 @@ type Request struct {
 	requestType      string
 	responseType     string
 	multipartBodyMap map[string]any
+	provider         string
+	api              string
+	route            string
+	failover         FailoverFunc
+	stream           bool // 应答 HTTP 200 时不读取 body，见 EndStream()
 	err              error
 }
 
+// FailoverFunc 每次请求后调用，url 为本次请求地址，返回非空时下一次请求改用返回的地址
+type FailoverFunc func(url string, res *http.Response, err error) (next string)
+
+// SetFailover 设置请求失败时切换地址，切换后的请求计入 RetryPolicy 的最大请求次数（未设置重试时最多请求 2 次）
+func (r *Request) SetFailover(fn FailoverFunc) *Request {
+	r.failover = fn
+	return r
+}
+
 func (r *Request) Get(url string) *Request {
 	r.method = GET
 	r.url = url
@@ -221,22 +235,7 @@ func (r *Request) EndBytesForAlipayV3(ctx context.Context) (res *http.Response,
 		return nil, nil, errors.New("Only support GET and POST and PUT and DELETE ")
 	}
 
-	// request
-	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
-	if err != nil {
-		return nil, nil, err
-	}
-	req.Header = r.Header
-	res, err = r.client.HttpClient.Do(req)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer res.Body.Close()
-	bs, err = io.ReadAll(io.LimitReader(res.Body, int64(r.client.bodySize<<20))) // default 10MB change the size you want
-	if err != nil {
-		return nil, nil, err
-	}
-	return res, bs, nil
+	return r.do(ctx, body)
 }
 
 func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte, err error) {
@@ -298,16 +297,74 @@ func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte,
 		return nil, nil, errors.New("Only support GET and POST and PUT and DELETE ")
 	}
 
-	// request
-	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+	return r.do(ctx, body)
+}
+
+// EndStream 发送请求，应答 HTTP 200 时不读取 body，由调用方流式读取 res.Body 并关闭，不受 SetBodySize 限制，适用于下载账单等大文件
+// 其他状态码与 EndBytes 相同，读取 body 至 bs 并关闭；应答 HTTP 200 时拦截器 AfterReceive 收到的 body 为 nil
+func (r *Request) EndStream(ctx context.Context) (res *http.Response, bs []byte, err error) {
+	r.stream = true
+	return r.EndBytes(ctx)
+}
+
+// do 经拦截器发送请求，按 Client 的 RetryPolicy 重试，每次重试发送完全相同的请求头与请求体，设置 failover 时可切换请求地址
+func (r *Request) do(ctx context.Context, body io.Reader) (res *http.Response, bs []byte, err error) {
+	var payload []byte
+	if body != nil {
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, nil, err
+		}
+	}
+	info := &RequestInfo{Provider: r.provider, Api: r.api, Route: r.route, Method: r.method, Body: payload}
+	policy := r.client.retry
+	attempts := policy.attempts()
+	// 切换地址时至少再请求一次
+	limit := attempts
+	if r.failover != nil && limit < 2 {
+		limit = 2
+	}
+	for i := 1; ; i++ {
+		info.Attempt, info.Url = i, r.url
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, e := http.NewRequestWithContext(ctx, r.method, r.url, reqBody)
+		if e != nil {
+			return nil, nil, e
+		}
+		req.Header = r.Header
+		res, bs, err = r.send(req, info)
+		if r.failover != nil {
+			// 切换地址后立即请求，不等待
+			if next := r.failover(r.url, res, err); next != "" && i < limit && ctx.Err() == nil {
+				r.closeStream(res)
+				r.url = next
+				continue
+			}
+		}
+		if i >= attempts || !policy.shouldRetry(req, res, bs, err) {
+			break
+		}
+		r.closeStream(res)
+		if e = sleepContext(ctx, policy.backoff(i, res)); e != nil {
+			break
+		}
+	}
 	if err != nil {
 		return nil, nil, err
 	}
-	req.Header = r.Header
+	return res, bs, nil
+}
+
+func (r *Request) roundTrip(req *http.Request) (res *http.Response, bs []byte, err error) {
 	res, err = r.client.HttpClient.Do(req)
 	if err != nil {
 		return nil, nil, err
 	}
+	if r.stream && res.StatusCode == http.StatusOK {
+		return res, nil, nil
+	}
 	defer res.Body.Close()
 	bs, err = io.ReadAll(io.LimitReader(res.Body, int64(r.client.bodySize<<20))) // default 10MB change the size you want
 	if err != nil {
@@ -316,6 +373,13 @@ func (r *Request) EndBytes(ctx context.Context) (res *http.Response, bs []byte,
 	return res, bs, nil
 }
 
+// closeStream 关闭 EndStream 未读取的应答 body，用于放弃本次应答重新请求
+func (r *Request) closeStream(res *http.Response) {
+	if r.stream && res != nil && res.StatusCode == http.StatusOK {
+		_ = res.Body.Close()
+	}
+}
+
 func (r *Request) EndStruct(ctx context.Context, v any) (res *http.Response, err error) {
 	res, bs, err := r.EndBytes(ctx)
 	if err != nil {
@@ -0,0 +1,164 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 2 * time.Second
+)
+
+// RetryClassifier 判断一次请求结果是否可以重试
+// 网络错误时 res、body 为 nil；收到应答时 err 为 nil
+type RetryClassifier func(req *http.Request, res *http.Response, body []byte, err error) bool
+
+// RetryPolicy 请求重试策略
+// 每次重试复用首次请求的 url、请求头与请求体（含签名、out_trade_no、PayPal-Request-Id 等幂等键），不会重新签名
+type RetryPolicy struct {
+	MaxAttempts int             // 最大请求次数（含首次），<= 1 不重试
+	BaseDelay   time.Duration   // 首次重试前的等待时间，之后每次翻倍，默认 100ms
+	MaxDelay    time.Duration   // 最大等待时间，默认 2s
+	Methods     []string        // 允许重试的 HTTP 方法，为空表示由 Classifier 判断
+	Classifier  RetryClassifier // 为空时使用 DefaultRetryClassifier
+}
+
+// DefaultRetryClassifier 默认重试判断
+// 任意方法：仅重试连接未建立的错误（拨号失败、连接被拒绝），此时请求未发送到服务端
+// 幂等方法（GET、HEAD、OPTIONS、PUT、DELETE）：另外重试连接被重置、超时等网络错误，以及 HTTP 429、500、502、503、504
+// POST 等非幂等请求如接口以 out_trade_no 等业务单号保证幂等，请使用 IdempotentRetryClassifier
+// context 取消或超时不重试
+func DefaultRetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if IsDialErr(err) {
+		return true
+	}
+	if !IsIdempotentMethod(req.Method) {
+		return false
+	}
+	return IdempotentRetryClassifier(req, res, body, err)
+}
+
+// IdempotentRetryClassifier 按幂等请求重试判断，不区分 HTTP 方法：网络临时错误，以及 HTTP 429、500、502、503、504
+// 仅用于以业务单号、幂等键保证重复请求不会重复扣款、退款的接口
+func IdempotentRetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if err != nil {
+		return IsTemporaryErr(err)
+	}
+	if res == nil {
+		return false
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsIdempotentMethod 判断 HTTP 方法是否幂等
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// IsDialErr 判断是否为连接未建立的错误（拨号失败、连接被拒绝），此时请求未发送到服务端，任意方法均可重试
+func IsDialErr(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// IsTemporaryErr 判断网络错误是否为可重试的临时错误：连接未建立、连接被重置、应答读取中断、超时
+// 请求可能已被服务端处理，仅适用于幂等请求
+func IsTemporaryErr(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsDialErr(err) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) allowMethod(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if !p.allowMethod(req.Method) {
+		return false
+	}
+	classifier := p.Classifier
+	if classifier == nil {
+		classifier = DefaultRetryClassifier
+	}
+	return classifier(req, res, body, err)
+}
+
+// backoff 第 n 次重试（从 1 开始）前的等待时间：指数退避 + 随机抖动，并参考应答头 Retry-After
+func (p *RetryPolicy) backoff(n int, res *http.Response) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+	d := base << (n - 1)
+	if d <= 0 || d > max {
+		d = max
+	}
+	// 在 [d/2, d] 之间随机，避免多个客户端同时重试
+	d = d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	if res != nil {
+		if sec, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && sec > 0 {
+			if ra := time.Duration(sec) * time.Second; ra > d {
+				d = min(ra, max)
+			}
+		}
+	}
+	return d
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
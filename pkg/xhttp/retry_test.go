@@ -0,0 +1,194 @@
+package xhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"out_trade_no":"GZ201907301420334577"}` || r.Header.Get("Idempotency-Key") != "abc" {
+			t.Errorf("retry request changed: %s, %v", body, r.Header)
+		}
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	// 默认不重试 POST 的 5xx 应答，请求可能已被处理
+	client := NewClient().SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	req := client.Req()
+	req.Header.Set("Idempotency-Key", "abc")
+	res, _, err := req.Post(srv.URL).SendString(`{"out_trade_no":"GZ201907301420334577"}`).EndBytes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusServiceUnavailable || count != 1 {
+		t.Fatalf("default POST got %d after %d attempts", res.StatusCode, count)
+	}
+
+	// 以业务单号保证幂等的接口
+	atomic.StoreInt32(&count, 0)
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Classifier: IdempotentRetryClassifier})
+	req = client.Req()
+	req.Header.Set("Idempotency-Key", "abc")
+	res, bs, err := req.Post(srv.URL).SendString(`{"out_trade_no":"GZ201907301420334577"}`).EndBytes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || string(bs) != "ok" || count != 3 {
+		t.Fatalf("got %d > %s after %d attempts", res.StatusCode, bs, count)
+	}
+
+	// 超过最大次数返回最后一次应答
+	atomic.StoreInt32(&count, -10)
+	req = client.Req()
+	req.Header.Set("Idempotency-Key", "abc")
+	if res, _, err = req.Post(srv.URL).SendString(`{"out_trade_no":"GZ201907301420334577"}`).EndBytes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusServiceUnavailable || count != -7 {
+		t.Fatalf("got %d after max attempts", res.StatusCode)
+	}
+
+	// 不允许重试的方法
+	atomic.StoreInt32(&count, 0)
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Methods: []string{GET}, Classifier: IdempotentRetryClassifier})
+	req = client.Req()
+	req.Header.Set("Idempotency-Key", "abc")
+	if res, _, err = req.Post(srv.URL).SendString(`{"out_trade_no":"GZ201907301420334577"}`).EndBytes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusServiceUnavailable || count != 1 {
+		t.Fatalf("got %d after %d attempts", res.StatusCode, count)
+	}
+}
+
+func TestDefaultRetryClassifier(t *testing.T) {
+	post, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	get, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+		err  error
+		want bool
+	}{
+		{"post dial", post, 0, dial, true},
+		{"post refused", post, 0, syscall.ECONNREFUSED, true},
+		{"post reset", post, 0, reset, false},
+		{"post eof", post, 0, io.EOF, false},
+		{"post 503", post, http.StatusServiceUnavailable, nil, false},
+		{"get reset", get, 0, reset, true},
+		{"get eof", get, 0, io.EOF, false},
+		{"get op error", get, 0, &net.OpError{Op: "write", Err: errors.New("broken")}, false},
+		{"get 503", get, http.StatusServiceUnavailable, nil, true},
+		{"get 400", get, http.StatusBadRequest, nil, false},
+		{"canceled", get, 0, context.Canceled, false},
+	}
+	for _, tt := range tests {
+		var res *http.Response
+		if tt.code != 0 {
+			res = &http.Response{StatusCode: tt.code}
+		}
+		if got := DefaultRetryClassifier(tt.req, res, nil, tt.err); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRequestFailover(t *testing.T) {
+	var primaryHits, backupHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&backupHits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer backup.Close()
+	failover := func(url string, res *http.Response, err error) string {
+		if url == primary.URL {
+			return backup.URL
+		}
+		return ""
+	}
+
+	tests := []struct {
+		policy          *RetryPolicy
+		primary, backup int32
+	}{
+		{nil, 1, 1},
+		{&RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}, 1, 2},
+	}
+	for _, tt := range tests {
+		atomic.StoreInt32(&primaryHits, 0)
+		atomic.StoreInt32(&backupHits, 0)
+		client := NewClient().SetRetryPolicy(tt.policy)
+		res, _, err := client.Req().SetFailover(failover).Get(primary.URL).EndBytes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != http.StatusBadGateway || primaryHits != tt.primary || backupHits != tt.backup {
+			t.Errorf("policy %+v: primary = %d, backup = %d", tt.policy, primaryHits, backupHits)
+		}
+	}
+}
+
+func TestEndStream(t *testing.T) {
+	var count int32
+	large := bytes.Repeat([]byte("a"), 2<<20)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("busy"))
+			return
+		}
+		_, _ = w.Write(large)
+	}))
+	defer srv.Close()
+
+	var received []byte
+	client := NewClient().SetBodySize(1).SetRetryPolicy(&RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	client.AddInterceptor(&Interceptor{AfterReceive: func(req *http.Request, info *RequestInfo, res *http.Response, body []byte) error {
+		received = body
+		return nil
+	}})
+	res, bs, err := client.Req().Get(srv.URL).EndStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	// HTTP 200 时不读取 body，不受 SetBodySize 限制
+	if res.StatusCode != http.StatusOK || bs != nil || received != nil || count != 2 {
+		t.Fatalf("status = %d, bs = %d bytes, received = %d bytes, count = %d", res.StatusCode, len(bs), len(received), count)
+	}
+	if body, err := io.ReadAll(res.Body); err != nil || !bytes.Equal(body, large) {
+		t.Fatalf("body = %d bytes, err = %v", len(body), err)
+	}
+
+	// 其他状态码读取 body
+	atomic.StoreInt32(&count, 0)
+	res, bs, err = NewClient().Req().Get(srv.URL).EndStream(context.Background())
+	if err != nil || res.StatusCode != http.StatusServiceUnavailable || string(bs) != "busy" {
+		t.Fatalf("status = %d, bs = %s, err = %v", res.StatusCode, bs, err)
+	}
+}
@@ -0,0 +1,56 @@
+package xhttp
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// 固定的路径片段：小写（或驼峰）单词，以 . _ - 连接，单词末尾最多 2 位数字，如 v3、oauth2、inApps、complaints-v2、v1.0、alipay.trade.pay
+var staticSegment = regexp.MustCompile(`^[a-z]+([A-Z][a-z]+)*\d{0,2}([._-]([a-z]+([A-Z][a-z]+)*\d{0,2}|\d{1,2}))*$`)
+
+// RouteTemplate 将接口路径转换为路由模板：去掉 scheme、host 及查询参数，路径参数替换为占位符
+// 参数名片段（id、openid 及 -id、-no、-code、-type 结尾，如 out-trade-no）之后的片段替换为同名占位符，如 {out_trade_no}；
+// 其他非固定片段（含连续大写字母或较长数字，如 PayPal 订单 Id）替换为 {id}
+// 注意：纯小写字母的参数值无法与固定片段区分，会保留原值
+// 如 /v3/pay/transactions/out-trade-no/1217752501201407033?mchid=1230000109 转换为 /v3/pay/transactions/out-trade-no/{out_trade_no}
+func RouteTemplate(api string) string {
+	if strings.Contains(api, "://") {
+		if u, err := url.Parse(api); err == nil {
+			api = u.Path
+		}
+	}
+	if i := strings.IndexAny(api, "?#"); i >= 0 {
+		api = api[:i]
+	}
+	if !strings.Contains(api, "/") {
+		return api
+	}
+	segments := strings.Split(api, "/")
+	for i := 1; i < len(segments); i++ {
+		seg, prev := segments[i], segments[i-1]
+		if seg == "" {
+			continue
+		}
+		switch {
+		case isParamName(prev):
+			segments[i] = "{" + strings.ReplaceAll(prev, "-", "_") + "}"
+		case !staticSegment.MatchString(seg):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isParamName 路径中表示参数名的片段，其后的片段为参数值
+func isParamName(seg string) bool {
+	if seg == "id" || seg == "openid" {
+		return true
+	}
+	for _, suffix := range []string{"-id", "-no", "-code", "-type"} {
+		if strings.HasSuffix(seg, suffix) {
+			return true
+		}
+	}
+	return false
+}
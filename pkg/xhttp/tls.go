@@ -0,0 +1,125 @@
+package xhttp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrPinMismatch 服务端证书链中没有与固定公钥匹配的证书
+var ErrPinMismatch = errors.New("xhttp: server certificate public key pin mismatch")
+
+// NewTLSConfig 安全的 TLS 配置：使用系统根证书校验服务端证书，最低 TLS 1.2
+// certs：双向 TLS 的客户端证书，可为空
+func NewTLSConfig(certs ...tls.Certificate) *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: certs,
+	}
+}
+
+// PublicKeyPin 证书公钥指纹：base64(sha256(SubjectPublicKeyInfo))，与 HPKP pin-sha256 格式一致
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SetInsecureSkipVerify 设置是否跳过服务端证书校验，默认 false
+func (c *Client) SetInsecureSkipVerify(skip bool) (client *Client) {
+	if cfg := c.tlsConfig(); cfg != nil {
+		cfg.InsecureSkipVerify = skip
+	}
+	return c
+}
+
+// SetRootCAs 设置校验服务端证书的根证书，nil 表示使用系统根证书
+func (c *Client) SetRootCAs(pool *x509.CertPool) (client *Client) {
+	if cfg := c.tlsConfig(); cfg != nil {
+		cfg.RootCAs = pool
+	}
+	return c
+}
+
+// SetCertificates 设置双向 TLS 的客户端证书，保留当前的证书校验与公钥固定配置
+func (c *Client) SetCertificates(certs ...tls.Certificate) (client *Client) {
+	if cfg := c.tlsConfig(); cfg != nil {
+		cfg.Certificates = certs
+	}
+	return c
+}
+
+// SetPinnedPublicKeys 固定 host 的服务端证书公钥，证书链中至少一个证书的 PublicKeyPin 与 pins 匹配时才建立连接
+// host：不含端口的域名，如 api.mch.weixin.qq.com；"*" 表示未单独设置的所有 host（含通过 IP 访问的 host）
+// pins：PublicKeyPin() 格式的公钥指纹，建议同时固定备用公钥，为空时取消该 host 的固定
+// 仅在 Transport 为 *http.Transport 时生效
+func (c *Client) SetPinnedPublicKeys(host string, pins ...string) (client *Client) {
+	c.tlsConfig()
+	c.pinMu.Lock()
+	defer c.pinMu.Unlock()
+	host = strings.ToLower(host)
+	if len(pins) == 0 {
+		delete(c.pins, host)
+		return c
+	}
+	if c.pins == nil {
+		c.pins = make(map[string]map[string]bool)
+	}
+	set := make(map[string]bool, len(pins))
+	for _, v := range pins {
+		set[v] = true
+	}
+	c.pins[host] = set
+	return c
+}
+
+func (c *Client) verifyPins(cs tls.ConnectionState) error {
+	c.pinMu.RLock()
+	pins, ok := c.pins[strings.ToLower(cs.ServerName)]
+	if !ok {
+		pins = c.pins["*"]
+	}
+	c.pinMu.RUnlock()
+	if len(pins) == 0 {
+		return nil
+	}
+	for _, cert := range cs.PeerCertificates {
+		if pins[PublicKeyPin(cert)] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrPinMismatch, cs.ServerName)
+}
+
+// httpTransport Client 独占的 *http.Transport，自定义 RoundTripper 时返回 nil
+// Transport 由外部传入（SetTransport、SetHttpTransport）时先复制（含 TLSClientConfig），避免修改调用方共享的配置
+func (c *Client) httpTransport() *http.Transport {
+	ht, ok := c.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	if ht != c.transport {
+		ht = ht.Clone()
+		c.HttpClient.Transport, c.transport = ht, ht
+	}
+	return ht
+}
+
+// tlsConfig 当前 *http.Transport 的 TLS 配置，自定义 RoundTripper 时返回 nil
+func (c *Client) tlsConfig() *tls.Config {
+	ht := c.httpTransport()
+	if ht == nil {
+		return nil
+	}
+	if ht.TLSClientConfig == nil {
+		ht.TLSClientConfig = NewTLSConfig()
+	}
+	if ht.TLSClientConfig.VerifyConnection == nil {
+		ht.TLSClientConfig.VerifyConnection = c.verifyPins
+	}
+	return ht.TLSClientConfig
+}
@@ -0,0 +1,81 @@
+package xhttp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // 忽略握手失败日志
+	srv.StartTLS()
+	defer srv.Close()
+	ctx := context.Background()
+
+	// 默认校验服务端证书
+	var certErr x509.UnknownAuthorityError
+	if _, _, err := NewClient().Req().Get(srv.URL).EndBytes(ctx); !errors.As(err, &certErr) {
+		t.Fatalf("self-signed cert accepted: %v", err)
+	}
+	if _, _, err := NewInsecureClient().Req().Get(srv.URL).EndBytes(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	client := NewClient().SetRootCAs(pool)
+	if _, bs, err := client.Req().Get(srv.URL).EndBytes(ctx); err != nil || string(bs) != "ok" {
+		t.Fatalf("trusted root: %s, %v", bs, err)
+	}
+
+	// 公钥固定
+	client = NewClient().SetRootCAs(pool).SetPinnedPublicKeys("*", PublicKeyPin(srv.Certificate()))
+	if _, _, err := client.Req().Get(srv.URL).EndBytes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client = NewInsecureClient().SetPinnedPublicKeys("*", "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	if _, _, err := client.Req().Get(srv.URL).EndBytes(ctx); !errors.Is(err, ErrPinMismatch) {
+		t.Fatalf("pin mismatch accepted: %v", err)
+	}
+}
+
+func TestTLSSharedTransport(t *testing.T) {
+	shared := &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12}}
+	pool := x509.NewCertPool()
+
+	// 多个 Client 共用调用方的 Transport
+	a := NewClient().SetHttpTransport(shared).SetInsecureSkipVerify(true).SetPinnedPublicKeys("*", "pin")
+	b := NewClient().SetTransport(shared).SetRootCAs(pool)
+	if shared.TLSClientConfig.VerifyConnection != nil || shared.TLSClientConfig.InsecureSkipVerify || shared.TLSClientConfig.RootCAs != nil {
+		t.Fatal("shared transport modified")
+	}
+	ta, tb := a.HttpClient.Transport.(*http.Transport), b.HttpClient.Transport.(*http.Transport)
+	if ta == shared || tb == shared || ta.TLSClientConfig == tb.TLSClientConfig {
+		t.Fatal("transport not cloned")
+	}
+	if !ta.TLSClientConfig.InsecureSkipVerify || tb.TLSClientConfig.InsecureSkipVerify || tb.TLSClientConfig.RootCAs != pool {
+		t.Fatalf("a = %+v, b = %+v", ta.TLSClientConfig, tb.TLSClientConfig)
+	}
+	// 后续设置修改 Client 的副本
+	if a.SetRootCAs(pool); a.HttpClient.Transport != ta || shared.TLSClientConfig.RootCAs != nil {
+		t.Fatal("transport cloned again")
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS13}
+	c := NewClient().SetHttpTLSConfig(cfg).SetInsecureSkipVerify(true)
+	if cfg.VerifyConnection != nil || cfg.InsecureSkipVerify {
+		t.Fatal("tls config modified")
+	}
+	if got := c.HttpClient.Transport.(*http.Transport).TLSClientConfig; got.MinVersion != tls.VersionTLS13 || got.VerifyConnection == nil {
+		t.Fatalf("tls config = %+v", got)
+	}
+}
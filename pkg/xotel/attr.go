@@ -0,0 +1,150 @@
+package xotel
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// OutTradeNo 从请求中解析商户订单号 out_trade_no，解析不到时返回空
+// 支持 JSON、XML、表单（含支付宝 biz_content）请求体，以及微信 V3 路径 /out-trade-no/{out_trade_no} 和 URL 参数
+func OutTradeNo(info *xhttp.RequestInfo) string {
+	body := bytes.TrimSpace(info.Body)
+	switch {
+	case len(body) == 0:
+	case body[0] == '{':
+		return jsonString(body, "out_trade_no")
+	case body[0] == '<':
+		return xmlString(body, "out_trade_no")
+	default:
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			if no := form.Get("out_trade_no"); no != gopay.NULL {
+				return no
+			}
+			if biz := form.Get("biz_content"); biz != gopay.NULL {
+				return jsonString([]byte(biz), "out_trade_no")
+			}
+		}
+	}
+	u, err := url.Parse(info.Url)
+	if err != nil {
+		return gopay.NULL
+	}
+	if no := u.Query().Get("out_trade_no"); no != gopay.NULL {
+		return no
+	}
+	if _, after, ok := strings.Cut(u.Path, "/out-trade-no/"); ok {
+		no, _, _ := strings.Cut(after, "/")
+		return no
+	}
+	if form, err := url.ParseQuery(u.RawQuery); err == nil {
+		if biz := form.Get("biz_content"); biz != gopay.NULL {
+			return jsonString([]byte(biz), "out_trade_no")
+		}
+	}
+	return gopay.NULL
+}
+
+// ErrCode 从应答中解析渠道业务错误码，成功或无法解析时返回空
+// 支付宝：code 不为 10000 时返回 sub_code（为空时返回 code）
+// 微信 V2、QQ：return_code 为 FAIL 时返回 FAIL，result_code 为 FAIL 时返回 err_code
+// 微信 V3、支付宝 V3：HTTP 状态码 >= 400 时返回 code
+// PayPal：HTTP 状态码 >= 400 时返回 name
+// Apple：HTTP 状态码 >= 400 时返回 errorCode
+func ErrCode(provider string, status int, body []byte) string {
+	if len(body) == 0 {
+		return gopay.NULL
+	}
+	switch provider {
+	case gopay.ProviderAlipay:
+		rsp := make(map[string]json.RawMessage)
+		if json.Unmarshal(body, &rsp) != nil {
+			return gopay.NULL
+		}
+		for k, v := range rsp {
+			if !strings.HasSuffix(k, "_response") {
+				continue
+			}
+			errRsp := new(struct {
+				Code    string `json:"code"`
+				SubCode string `json:"sub_code"`
+			})
+			if json.Unmarshal(v, errRsp) != nil || errRsp.Code == gopay.NULL || errRsp.Code == "10000" {
+				return gopay.NULL
+			}
+			if errRsp.SubCode != gopay.NULL {
+				return errRsp.SubCode
+			}
+			return errRsp.Code
+		}
+	case gopay.ProviderWechat, gopay.ProviderQQ:
+		rsp := new(struct {
+			ReturnCode string `xml:"return_code"`
+			ResultCode string `xml:"result_code"`
+			ErrCode    string `xml:"err_code"`
+		})
+		if xml.Unmarshal(body, rsp) != nil {
+			return gopay.NULL
+		}
+		if rsp.ReturnCode == gopay.FAIL {
+			return gopay.FAIL
+		}
+		if rsp.ResultCode == gopay.FAIL {
+			if rsp.ErrCode != gopay.NULL {
+				return rsp.ErrCode
+			}
+			return gopay.FAIL
+		}
+	case gopay.ProviderWechatV3, gopay.ProviderAlipayV3:
+		if status >= http.StatusBadRequest {
+			return jsonString(body, "code")
+		}
+	case gopay.ProviderPayPal:
+		if status >= http.StatusBadRequest {
+			return jsonString(body, "name")
+		}
+	case gopay.ProviderApple:
+		if status >= http.StatusBadRequest {
+			rsp := new(struct {
+				ErrorCode int `json:"errorCode"`
+			})
+			if json.Unmarshal(body, rsp) == nil && rsp.ErrorCode != 0 {
+				return strconv.Itoa(rsp.ErrorCode)
+			}
+		}
+	}
+	return gopay.NULL
+}
+
+func jsonString(body []byte, key string) string {
+	m := make(map[string]any)
+	if json.Unmarshal(body, &m) != nil {
+		return gopay.NULL
+	}
+	v, _ := m[key].(string)
+	return v
+}
+
+func xmlString(body []byte, key string) string {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return gopay.NULL
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == key {
+			var v string
+			if d.DecodeElement(&v, &se) != nil {
+				return gopay.NULL
+			}
+			return v
+		}
+	}
+}
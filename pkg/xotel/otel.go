@@ -0,0 +1,205 @@
+// Package xotel 为 gopay 请求提供 OpenTelemetry 链路追踪与指标
+//
+// 基于 xhttp.Interceptor 实现，每次 HTTP 请求（含重试）创建一个 Client Span，
+// Span 的父节点取自调用接口时传入的 context.Context
+package xotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/w6xian/gopay/pkg/xotel"
+
+	ProviderKey   = attribute.Key("gopay.provider")     // 支付渠道
+	ApiKey        = attribute.Key("gopay.api")          // 接口路由模板，见 xhttp.RequestInfo.Route
+	OutTradeNoKey = attribute.Key("gopay.out_trade_no") // 商户订单号
+	ErrCodeKey    = attribute.Key("gopay.error_code")   // 渠道业务错误码
+	AttemptKey    = attribute.Key("gopay.attempt")      // 第几次请求
+)
+
+type config struct {
+	tp trace.TracerProvider
+	mp metric.MeterProvider
+}
+
+type Option func(*config)
+
+// WithTracerProvider 设置 TracerProvider，默认 otel.GetTracerProvider()
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		if tp != nil {
+			c.tp = tp
+		}
+	}
+}
+
+// WithMeterProvider 设置 MeterProvider，默认 otel.GetMeterProvider()
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		if mp != nil {
+			c.mp = mp
+		}
+	}
+}
+
+type instrument struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+}
+
+type stateKey struct{}
+
+// state 单次请求的状态，BeforeSend 写入 context
+type state struct {
+	start time.Time
+	ended bool // 其他拦截器的 AfterReceive 返回 error 时，OnError 会在 AfterReceive 之后调用
+}
+
+// NewInterceptor 创建 OpenTelemetry 拦截器
+// 指标：
+// gopay.client.request.duration：请求耗时直方图（秒）
+// gopay.client.request.errors：失败请求计数，包括网络错误、HTTP 4xx/5xx 及渠道业务错误码
+func NewInterceptor(opts ...Option) (*xhttp.Interceptor, error) {
+	c := &config{tp: otel.GetTracerProvider(), mp: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	meter := c.mp.Meter(instrumentationName)
+	duration, err := meter.Float64Histogram("gopay.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of gopay provider API requests."))
+	if err != nil {
+		return nil, err
+	}
+	errCounter, err := meter.Int64Counter("gopay.client.request.errors",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of failed gopay provider API requests."))
+	if err != nil {
+		return nil, err
+	}
+	i := &instrument{
+		tracer:   c.tp.Tracer(instrumentationName),
+		duration: duration,
+		errors:   errCounter,
+	}
+	return &xhttp.Interceptor{
+		BeforeSend:   i.beforeSend,
+		AfterReceive: i.afterReceive,
+		OnError:      i.onError,
+	}, nil
+}
+
+// Instrument 为 xhttp.Client 添加 OpenTelemetry 拦截器
+func Instrument(client *xhttp.Client, opts ...Option) error {
+	interceptor, err := NewInterceptor(opts...)
+	if err != nil {
+		return err
+	}
+	client.AddInterceptor(interceptor)
+	return nil
+}
+
+func (i *instrument) beforeSend(req *http.Request, info *xhttp.RequestInfo) (*http.Request, error) {
+	// 原始路径（含订单号等参数）仅用于 Span，不作为指标属性
+	attrs := []attribute.KeyValue{
+		ProviderKey.String(info.Provider),
+		ApiKey.String(info.Route),
+		AttemptKey.Int(info.Attempt),
+		semconv.HTTPRequestMethodKey.String(info.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+		semconv.URLPath(req.URL.Path),
+	}
+	if no := OutTradeNo(info); no != "" {
+		attrs = append(attrs, OutTradeNoKey.String(no))
+	}
+	ctx, _ := i.tracer.Start(req.Context(), spanName(info),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	ctx = context.WithValue(ctx, stateKey{}, &state{start: time.Now()})
+	return req.WithContext(ctx), nil
+}
+
+func (i *instrument) afterReceive(req *http.Request, info *xhttp.RequestInfo, res *http.Response, body []byte) error {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+	var errType string
+	if code := ErrCode(info.Provider, res.StatusCode, body); code != "" {
+		span.SetAttributes(ErrCodeKey.String(code))
+		errType = code
+	} else if res.StatusCode >= http.StatusBadRequest {
+		errType = strconv.Itoa(res.StatusCode)
+	}
+	if errType != "" {
+		span.SetStatus(codes.Error, errType)
+	}
+	i.end(ctx, span, info, res.StatusCode, errType)
+	return nil
+}
+
+func (i *instrument) onError(req *http.Request, info *xhttp.RequestInfo, err error) {
+	ctx := req.Context()
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	i.end(ctx, span, info, 0, errType(err))
+}
+
+func errType(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case xhttp.IsTemporaryErr(err):
+		return "network"
+	}
+	return semconv.ErrorTypeOther.Value.AsString()
+}
+
+func (i *instrument) end(ctx context.Context, span trace.Span, info *xhttp.RequestInfo, status int, errType string) {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	if !ok || st.ended {
+		return
+	}
+	st.ended = true
+	attrs := []attribute.KeyValue{
+		ProviderKey.String(info.Provider),
+		ApiKey.String(info.Route),
+		semconv.HTTPRequestMethodKey.String(info.Method),
+	}
+	if status > 0 {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(status))
+	}
+	if errType != "" {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(errType))
+		i.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+	i.duration.Record(ctx, time.Since(st.start).Seconds(), metric.WithAttributes(attrs...))
+	span.End()
+}
+
+// spanName Span 名称使用路由模板，避免订单号等参数导致名称无限增长
+func spanName(info *xhttp.RequestInfo) string {
+	if info.Provider == "" {
+		return "gopay " + info.Method
+	}
+	if info.Route == "" {
+		return info.Provider + " " + info.Method
+	}
+	return info.Provider + " " + info.Route
+}
@@ -0,0 +1,152 @@
+package xotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/pkg/xhttp"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInterceptor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"alipay_trade_query_response":{"code":"40004","msg":"Business Failed","sub_code":"ACQ.TRADE_NOT_EXIST","sub_msg":"交易不存在"},"sign":"xxx"}`))
+	}))
+	defer srv.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := xhttp.NewClient()
+	if err := Instrument(client, WithTracerProvider(tp), WithMeterProvider(mp)); err != nil {
+		t.Fatal(err)
+	}
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	bm := make(gopay.BodyMap)
+	bm.Set("method", "alipay.trade.query").Set("biz_content", `{"out_trade_no":"GZ201907301420334577"}`)
+	_, _, err := client.Req(xhttp.TypeFormData).SetApi(gopay.ProviderAlipay, "alipay.trade.query").
+		Post(srv.URL + "/gateway.do").SendString(bm.EncodeURLParams()).EndBytes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("got %d spans", len(ended))
+	}
+	span := ended[0]
+	if span.Name() != "alipay alipay.trade.query" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("span: %s, parent: %s", span.Name(), span.Parent().SpanID())
+	}
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["gopay.out_trade_no"] != "GZ201907301420334577" || attrs["gopay.error_code"] != "ACQ.TRADE_NOT_EXIST" || attrs["http.response.status_code"] != "200" {
+		t.Fatalf("attrs: %v", attrs)
+	}
+	if span.Status().Code != codes.Error {
+		t.Fatalf("status: %+v", span.Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err = reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = true
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && (len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1) {
+				t.Fatalf("%s: %+v", m.Name, sum.DataPoints)
+			}
+		}
+	}
+	if !got["gopay.client.request.duration"] || !got["gopay.client.request.errors"] {
+		t.Fatalf("metrics: %v", got)
+	}
+}
+
+func TestInterceptorRoute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"ORDER_NOT_EXIST","message":"订单不存在"}`))
+	}))
+	defer srv.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	client := xhttp.NewClient()
+	if err := Instrument(client, WithTracerProvider(tp), WithMeterProvider(mp)); err != nil {
+		t.Fatal(err)
+	}
+	nos := []string{"1217752501201407033", "1217752501201407034"}
+	for _, no := range nos {
+		path := "/v3/pay/transactions/out-trade-no/" + no + "?mchid=1900000001"
+		_, _, _ = client.Req().SetApi(gopay.ProviderWechatV3, path).Get(srv.URL + path).EndBytes(context.Background())
+	}
+
+	route := "/v3/pay/transactions/out-trade-no/{out_trade_no}"
+	for i, span := range spans.Ended() {
+		attrs := make(map[string]string)
+		for _, kv := range span.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		if span.Name() != gopay.ProviderWechatV3+" "+route || attrs["gopay.api"] != route || attrs["url.path"] != "/v3/pay/transactions/out-trade-no/"+nos[i] {
+			t.Fatalf("span: %s, attrs: %v", span.Name(), attrs)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				continue
+			}
+			// 不同订单号共用同一时间序列
+			if len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 2 {
+				t.Fatalf("%s: %+v", m.Name, hist.DataPoints)
+			}
+			if v, _ := hist.DataPoints[0].Attributes.Value("gopay.api"); v.AsString() != route {
+				t.Fatalf("gopay.api: %s", v.AsString())
+			}
+			if hist.DataPoints[0].Attributes.HasValue("url.path") {
+				t.Fatal("url.path in metric attributes")
+			}
+		}
+	}
+}
+
+func TestOutTradeNo(t *testing.T) {
+	tests := []struct {
+		info *xhttp.RequestInfo
+		want string
+	}{
+		{&xhttp.RequestInfo{Body: []byte(`{"out_trade_no":"A1","amount":{"total":1}}`)}, "A1"},
+		{&xhttp.RequestInfo{Body: []byte(`<xml><appid>wx</appid><out_trade_no><![CDATA[A2]]></out_trade_no></xml>`)}, "A2"},
+		{&xhttp.RequestInfo{Body: []byte(`out_trade_no=A3&total_fee=1`)}, "A3"},
+		{&xhttp.RequestInfo{Url: "https://api.mch.weixin.qq.com/v3/pay/transactions/out-trade-no/A4?mchid=1900000001"}, "A4"},
+		{&xhttp.RequestInfo{Url: "https://api.mch.weixin.qq.com/v3/pay/transactions/id/4200000001"}, ""},
+	}
+	for _, tt := range tests {
+		if got := OutTradeNo(tt.info); got != tt.want {
+			t.Errorf("OutTradeNo(%+v) = %s, want %s", tt.info, got, tt.want)
+		}
+	}
+}
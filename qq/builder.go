@@ -0,0 +1,129 @@
+package qq
+
+import (
+	"github.com/go-pay/util"
+	"github.com/w6xian/gopay"
+)
+
+// 核心支付接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度），再传给对应的方法
+// mch_id、fee_type、sign 由 Client 自动填充；nonce_str 为空时生成 32 位随机字符串，不修改请求结构体
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&qq.UnifiedOrderRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], trade_type: must be one of JSAPI|NATIVE|APP|MINIAPP
+//	}
+//	qqRsp, err := client.UnifiedOrder(ctx, bm)
+
+// UnifiedOrderRequest 统一下单请求参数，UnifiedOrder() 使用，trade_type 取值见 TradeType_JsApi 等
+// 文档：https://qpay.qq.com/buss/wiki/38/1203
+type UnifiedOrderRequest struct {
+	NonceStr       string        `json:"nonce_str" validate:"maxlen=32"`
+	Appid          string        `json:"appid,omitempty" validate:"maxlen=32"`
+	Body           string        `json:"body" validate:"required,maxlen=128"`
+	Attach         string        `json:"attach,omitempty" validate:"maxlen=127"`
+	OutTradeNo     string        `json:"out_trade_no" validate:"required,maxlen=32"`
+	TotalFee       int64         `json:"total_fee" validate:"required,gt=0"` // 单位：分
+	SpbillCreateIp string        `json:"spbill_create_ip" validate:"required,maxlen=45"`
+	TimeStart      string        `json:"time_start,omitempty" validate:"len=14"`  // yyyyMMddHHmmss
+	TimeExpire     string        `json:"time_expire,omitempty" validate:"len=14"` // yyyyMMddHHmmss
+	LimitPay       string        `json:"limit_pay,omitempty" validate:"enum=no_balance|no_anyone|no_credit"`
+	TradeType      string        `json:"trade_type" validate:"required,enum=JSAPI|NATIVE|APP|MINIAPP"`
+	NotifyUrl      string        `json:"notify_url" validate:"required,maxlen=255"`
+	DeviceInfo     string        `json:"device_info,omitempty" validate:"maxlen=32"`
+	Extra          gopay.BodyMap `json:"-"`
+}
+
+// MicroPayRequest 提交付款码支付请求参数，MicroPay() 使用
+// 文档：https://qpay.qq.com/buss/wiki/1/1122
+type MicroPayRequest struct {
+	NonceStr       string        `json:"nonce_str" validate:"maxlen=32"`
+	Body           string        `json:"body" validate:"required,maxlen=128"`
+	Attach         string        `json:"attach,omitempty" validate:"maxlen=127"`
+	OutTradeNo     string        `json:"out_trade_no" validate:"required,maxlen=32"`
+	TotalFee       int64         `json:"total_fee" validate:"required,gt=0"`
+	SpbillCreateIp string        `json:"spbill_create_ip" validate:"required,maxlen=45"`
+	DeviceInfo     string        `json:"device_info" validate:"required,maxlen=32"`
+	AuthCode       string        `json:"auth_code" validate:"required,maxlen=128"`
+	LimitPay       string        `json:"limit_pay,omitempty" validate:"enum=no_balance|no_anyone|no_credit"`
+	Extra          gopay.BodyMap `json:"-"`
+}
+
+// OrderQueryRequest 订单查询请求参数，OrderQuery() 使用，out_trade_no 与 transaction_id 必填其一
+// 文档：https://qpay.qq.com/buss/wiki/38/1205
+type OrderQueryRequest struct {
+	NonceStr      string        `json:"nonce_str" validate:"maxlen=32"`
+	OutTradeNo    string        `json:"out_trade_no,omitempty" validate:"required_without=transaction_id,maxlen=32"`
+	TransactionId string        `json:"transaction_id,omitempty" validate:"maxlen=32"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// CloseOrderRequest 关闭订单请求参数，CloseOrder() 使用
+// 文档：https://qpay.qq.com/buss/wiki/38/1206
+type CloseOrderRequest struct {
+	NonceStr   string        `json:"nonce_str" validate:"maxlen=32"`
+	OutTradeNo string        `json:"out_trade_no" validate:"required,maxlen=32"`
+	Extra      gopay.BodyMap `json:"-"`
+}
+
+// RefundRequest 申请退款请求参数，Refund() 使用，out_trade_no 与 transaction_id 必填其一
+// 文档：https://qpay.qq.com/buss/wiki/38/1207
+type RefundRequest struct {
+	NonceStr      string        `json:"nonce_str" validate:"maxlen=32"`
+	OutTradeNo    string        `json:"out_trade_no,omitempty" validate:"required_without=transaction_id,maxlen=32"`
+	TransactionId string        `json:"transaction_id,omitempty" validate:"maxlen=32"`
+	OutRefundNo   string        `json:"out_refund_no" validate:"required,maxlen=32"`
+	RefundFee     int64         `json:"refund_fee" validate:"required,gt=0"` // 单位：分
+	OpUserId      string        `json:"op_user_id" validate:"required,maxlen=32"`
+	OpUserPasswd  string        `json:"op_user_passwd" validate:"required,maxlen=32"` // 操作员密码的 MD5 值
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// BodyMap 校验并转换为 UnifiedOrder() 的请求参数
+func (r *UnifiedOrderRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.NonceStr = nonceStr(r.NonceStr)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 MicroPay() 的请求参数
+func (r *MicroPayRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.NonceStr = nonceStr(r.NonceStr)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 OrderQuery() 的请求参数
+func (r *OrderQueryRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.NonceStr = nonceStr(r.NonceStr)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 CloseOrder() 的请求参数
+func (r *CloseOrderRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.NonceStr = nonceStr(r.NonceStr)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 Refund() 的请求参数
+func (r *RefundRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.NonceStr = nonceStr(r.NonceStr)
+	return buildBodyMap(&req, r.Extra)
+}
+
+func nonceStr(s string) string {
+	if s == gopay.NULL {
+		return util.RandomString(32)
+	}
+	return s
+}
+
+func buildBodyMap(req any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(req, extra)
+}
@@ -0,0 +1,60 @@
+package qq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ BodyMap() (gopay.BodyMap, error) }
+		body    string // 不含 nonce_str
+		wantErr string
+	}{
+		{"unified order", &UnifiedOrderRequest{Body: "test", OutTradeNo: "larry01", TotalFee: 1, SpbillCreateIp: "127.0.0.1", TradeType: TradeType_Native,
+			NotifyUrl: "https://www.fmm.ink", Extra: gopay.BodyMap{"promotion_tag": "level_tag"}},
+			`{"body":"test","notify_url":"https://www.fmm.ink","out_trade_no":"larry01","promotion_tag":"level_tag","spbill_create_ip":"127.0.0.1","total_fee":1,"trade_type":"NATIVE"}`, ""},
+		{"unified order trade type", &UnifiedOrderRequest{Body: "test", OutTradeNo: "larry01", TotalFee: 1, SpbillCreateIp: "127.0.0.1", TradeType: TradeType_MicroPay, NotifyUrl: "https://www.fmm.ink"},
+			"", "[invalid parameter], trade_type: must be one of JSAPI|NATIVE|APP|MINIAPP"},
+		{"micro pay", &MicroPayRequest{Body: "test", OutTradeNo: "larry02", TotalFee: 1, SpbillCreateIp: "127.0.0.1", DeviceInfo: "d01", AuthCode: "910000000000000000"},
+			`{"auth_code":"910000000000000000","body":"test","device_info":"d01","out_trade_no":"larry02","spbill_create_ip":"127.0.0.1","total_fee":1}`, ""},
+		{"micro pay without auth code", &MicroPayRequest{Body: "test", OutTradeNo: "larry02", TotalFee: 1, SpbillCreateIp: "127.0.0.1", DeviceInfo: "d01"},
+			"", "[invalid parameter], auth_code: is required"},
+		{"order query", &OrderQueryRequest{TransactionId: "1000000000"}, `{"transaction_id":"1000000000"}`, ""},
+		{"order query without no", &OrderQueryRequest{}, "", "[invalid parameter], out_trade_no: is required when transaction_id is empty"},
+		{"close order", &CloseOrderRequest{OutTradeNo: "larry01"}, `{"out_trade_no":"larry01"}`, ""},
+		{"refund", &RefundRequest{OutTradeNo: "larry01", OutRefundNo: "r01", RefundFee: 1, OpUserId: "op01", OpUserPasswd: "e10adc3949ba59abbe56e057f20f883e"},
+			`{"op_user_id":"op01","op_user_passwd":"e10adc3949ba59abbe56e057f20f883e","out_refund_no":"r01","out_trade_no":"larry01","refund_fee":1}`, ""},
+		{"refund fee", &RefundRequest{OutTradeNo: "larry01", OutRefundNo: "r01", OpUserId: "op01", OpUserPasswd: "p"}, "", "[invalid parameter], refund_fee: is required"},
+	}
+	for _, tt := range tests {
+		bm, err := tt.req.BodyMap()
+		if tt.wantErr != "" {
+			if !errors.Is(err, gopay.ParamValidateErr) || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		// nonce_str 为空时自动生成
+		if len(bm.GetString("nonce_str")) != 32 {
+			t.Errorf("%s: nonce_str = %s", tt.name, bm.GetString("nonce_str"))
+		}
+		bm.Remove("nonce_str")
+		if body := bm.JsonBody(); body != tt.body {
+			t.Errorf("%s: body = %s", tt.name, body)
+		}
+	}
+
+	// 不修改请求结构体
+	req := &CloseOrderRequest{OutTradeNo: "larry01"}
+	if _, err := req.BodyMap(); err != nil || req.NonceStr != "" {
+		t.Errorf("nonce_str = %s, err = %v", req.NonceStr, err)
+	}
+}
@@ -41,7 +41,7 @@ func NewClient(mchId, apiKey string) (client *Client) {
 		MchId:       mchId,
 		ApiKey:      apiKey,
 		DebugSwitch: gopay.DebugOff,
-		logger:      logger,
+		logger:      gopay.NewRedactLogger(logger, nil),
 		sha256Hash:  hmac.New(sha256.New, []byte(apiKey)),
 		md5Hash:     md5.New(),
 		hc:          xhttp.NewClient(),
@@ -56,7 +56,16 @@ func (q *Client) SetBodySize(sizeMB int) {
 	}
 }
 
+// AddInterceptor 添加请求拦截器，同时作用于普通请求与证书请求
+// 之后调用 SetHttpClient、SetTLSHttpClient 需重新添加
+func (q *Client) AddInterceptor(interceptors ...*xhttp.Interceptor) {
+	q.hc.AddInterceptor(interceptors...)
+	q.tlsHc.AddInterceptor(interceptors...)
+}
+
 // SetHttpClient 设置自定义的xhttp.Client
+// 本渠道未提供 SetRetryPolicy()：client 的重试策略 Classifier 为空时按 xhttp.DefaultRetryClassifier 判断，
+// 下单、退款等 POST 请求仅在连接未建立时重试，请勿设置 xhttp.IdempotentRetryClassifier，避免重复支付、退款
 func (q *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
 		q.hc = client
@@ -72,7 +81,7 @@ func (q *Client) SetTLSHttpClient(client *xhttp.Client) {
 
 func (q *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		q.logger = logger
+		q.logger = gopay.NewRedactLogger(logger, nil)
 	}
 }
 
@@ -95,11 +104,11 @@ func (q *Client) PostQQAPISelf(ctx context.Context, bm gopay.BodyMap, url string
 	if q.DebugSwitch == gopay.DebugOn {
 		q.logger.Debugf("QQ_Request: %s", req)
 	}
-	httpClient := xhttp.NewClient()
+	httpClient := xhttp.NewClient().AddInterceptor(q.hc.Interceptors()...)
 	if q.IsProd && tlsConfig != nil {
 		httpClient.SetHttpTLSConfig(tlsConfig)
 	}
-	res, bs, err := httpClient.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := httpClient.Req(xhttp.TypeXML).SetApi(gopay.ProviderQQ, url).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -307,7 +316,7 @@ func (q *Client) doQQPost(ctx context.Context, bm gopay.BodyMap, url string) (bs
 	if q.DebugSwitch == gopay.DebugOn {
 		q.logger.Debugf("QQ_Request: %s", req)
 	}
-	res, bs, err := q.hc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := q.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderQQ, url).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -339,7 +348,7 @@ func (q *Client) doQQPostTLS(ctx context.Context, bm gopay.BodyMap, url string)
 	if q.DebugSwitch == gopay.DebugOn {
 		q.logger.Debugf("QQ_Request: %s", req)
 	}
-	res, bs, err := q.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := q.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderQQ, url).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -368,7 +377,7 @@ func (q *Client) doQQGet(ctx context.Context, bm gopay.BodyMap, url, signType st
 	}
 	param := bm.EncodeURLParams()
 	uri := url + "?" + param
-	res, bs, err := q.hc.Req(xhttp.TypeXML).Get(uri).EndBytes(ctx)
+	res, bs, err := q.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderQQ, url).Get(uri).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -396,7 +405,7 @@ func (q *Client) doQQRed(ctx context.Context, bm gopay.BodyMap, url string) (bs
 	if q.DebugSwitch == gopay.DebugOn {
 		q.logger.Debugf("QQ_Request: %s", req)
 	}
-	res, bs, err := q.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := q.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderQQ, url).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
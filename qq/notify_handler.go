@@ -0,0 +1,85 @@
+package qq
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/w6xian/gopay"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler QQ钱包支付异步通知处理器，实现 http.Handler
+// 依次完成：解析 -> 验签 -> OnPay -> 应答 NotifyResponse XML
+// 回调返回 error 时应答 FAIL，QQ钱包会按策略重试通知
+type NotifyHandler struct {
+	apiKey   string
+	signType string
+	onPay    func(ctx context.Context, notifyReq *NotifyRequest) error
+	onError  func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化异步通知处理器
+// apiKey：API秘钥值
+// signType：下单时使用的签名类型，为空默认 SignType_MD5
+func NewNotifyHandler(apiKey, signType string) *NotifyHandler {
+	if signType == gopay.NULL {
+		signType = SignType_MD5
+	}
+	return &NotifyHandler{apiKey: apiKey, signType: signType}
+}
+
+// OnPay 注册支付结果通知回调
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rsp := &NotifyResponse{ReturnCode: gopay.SUCCESS}
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		rsp = &NotifyResponse{ReturnCode: gopay.FAIL, ReturnMsg: gopay.FAIL}
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(rsp.ToXmlString()))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	bs, err := io.ReadAll(io.LimitReader(req.Body, int64(3<<20)))
+	defer req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+	bm := make(gopay.BodyMap)
+	if err = xml.Unmarshal(bs, &bm); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	ok, err := VerifySign(h.apiKey, h.signType, bm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, "notify sign verify failed")
+	}
+	notifyReq := new(NotifyRequest)
+	if err = xml.Unmarshal(bs, notifyReq); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if h.onPay != nil {
+		return h.onPay(req.Context(), notifyReq)
+	}
+	return nil
+}
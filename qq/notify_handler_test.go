@@ -0,0 +1,58 @@
+package qq
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	key := "ziR0QKsTUfMOuochC9RfCdmfHECorQAP"
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "1104606937").
+		Set("mch_id", "1900000109").
+		Set("nonce_str", "5d2b6c2a8db53831f7eda20af46e531c").
+		Set("trade_state", TradeState_Success).
+		Set("out_trade_no", "GZ202001101420131999").
+		Set("transaction_id", "1900000109471701140217300052").
+		Set("total_fee", "1")
+	bm.Set("sign", GetReleaseSign(key, SignType_MD5, bm))
+	body, err := xml.Marshal(bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *NotifyRequest
+	h := NewNotifyHandler(key, SignType_MD5).
+		OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Debugf("notify err: %v", err)
+		})
+	serve := func(body []byte) string {
+		req := httptest.NewRequest(http.MethodPost, "/notify/qq", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if ack := serve(body); ack != "<xml><return_code>SUCCESS</return_code></xml>" {
+		t.Fatalf("ack = %s, want SUCCESS", ack)
+	}
+	if got == nil || got.OutTradeNo != "GZ202001101420131999" {
+		t.Fatalf("OnPay got: %+v", got)
+	}
+
+	// 篡改订单号，验签失败
+	tampered := bytes.Replace(body, []byte("GZ202001101420131999"), []byte("GZ202001101420132000"), 1)
+	if ack := serve(tampered); ack != "<xml><return_code>FAIL</return_code><return_msg>FAIL</return_msg></xml>" {
+		t.Errorf("tampered ack = %s, want FAIL", ack)
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/go-pay/xlog"
 	"github.com/w6xian/gopay"
 	"golang.org/x/crypto/pkcs12"
 )
@@ -28,11 +27,12 @@ func (q *Client) AddCertFilePath(certFilePath, keyFilePath, pkcs12FilePath any)
 	if err = checkCertFilePathOrContent(certFilePath, keyFilePath, pkcs12FilePath); err != nil {
 		return err
 	}
-	config, err := q.addCertConfig(certFilePath, keyFilePath, pkcs12FilePath)
+	certificate, err := q.addCertConfig(certFilePath, keyFilePath, pkcs12FilePath)
 	if err != nil {
 		return
 	}
-	q.tlsHc.SetHttpTLSConfig(config)
+	// 服务端证书校验及公钥固定沿用 tlsHc 的配置
+	q.tlsHc.SetCertificates(certificate)
 	return nil
 }
 
@@ -109,7 +109,7 @@ func GetReleaseSign(apiKey string, signType string, bm gopay.BodyMap) (sign stri
 func (q *Client) getReleaseSign(apiKey string, signType string, bm gopay.BodyMap) (sign string) {
 	signParams := bm.EncodeWeChatSignParams(apiKey)
 	if q.DebugSwitch == gopay.DebugOn {
-		xlog.Debugf("QQ_Request_SignStr: %s", signParams)
+		q.logger.Debugf("QQ_Request_SignStr: %s", signParams)
 	}
 	var h hash.Hash
 	if signType == SignType_HMAC_SHA256 {
@@ -126,15 +126,13 @@ func (q *Client) getReleaseSign(apiKey string, signType string, bm gopay.BodyMap
 	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
 }
 
-func (q *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tls.Config, err error) {
+// addCertConfig 解析商户 API 证书，用于双向 TLS
+func (q *Client) addCertConfig(certFile, keyFile, pkcs12File any) (certificate tls.Certificate, err error) {
 	if certFile == nil && keyFile == nil && pkcs12File == nil {
-		return nil, errors.New("cert parse failed")
+		return certificate, errors.New("cert parse failed")
 	}
 
-	var (
-		certPem, keyPem []byte
-		certificate     tls.Certificate
-	)
+	var certPem, keyPem []byte
 	if certFile != nil && keyFile != nil {
 		if _, ok := certFile.([]byte); ok {
 			certPem = certFile.([]byte)
@@ -147,7 +145,7 @@ func (q *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 			keyPem, err = os.ReadFile(keyFile.(string))
 		}
 		if err != nil {
-			return nil, fmt.Errorf("os.ReadFile: %w", err)
+			return certificate, fmt.Errorf("os.ReadFile: %w", err)
 		}
 	} else if pkcs12File != nil {
 		var pfxData []byte
@@ -155,12 +153,12 @@ func (q *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 			pfxData = pkcs12File.([]byte)
 		} else {
 			if pfxData, err = os.ReadFile(pkcs12File.(string)); err != nil {
-				return nil, fmt.Errorf("os.ReadFile: %w", err)
+				return certificate, fmt.Errorf("os.ReadFile: %w", err)
 			}
 		}
 		blocks, err := pkcs12.ToPEM(pfxData, q.MchId)
 		if err != nil {
-			return nil, fmt.Errorf("pkcs12.ToPEM: %w", err)
+			return certificate, fmt.Errorf("pkcs12.ToPEM: %w", err)
 		}
 		for _, b := range blocks {
 			keyPem = append(keyPem, pem.EncodeToMemory(b)...)
@@ -169,13 +167,9 @@ func (q *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 	}
 	if certPem != nil && keyPem != nil {
 		if certificate, err = tls.X509KeyPair(certPem, keyPem); err != nil {
-			return nil, fmt.Errorf("tls.LoadX509KeyPair: %w", err)
+			return certificate, fmt.Errorf("tls.LoadX509KeyPair: %w", err)
 		}
-		tlsConfig = &tls.Config{
-			Certificates:       []tls.Certificate{certificate},
-			InsecureSkipVerify: true,
-		}
-		return tlsConfig, nil
+		return certificate, nil
 	}
-	return nil, errors.New("cert files must all nil or all not nil")
+	return certificate, errors.New("cert files must all nil or all not nil")
 }
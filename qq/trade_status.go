@@ -0,0 +1,35 @@
+package qq
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 交易状态 trade_state
+	TradeState_Success    = "SUCCESS"    // 支付成功
+	TradeState_Refund     = "REFUND"     // 转入退款
+	TradeState_NotPay     = "NOTPAY"     // 未支付
+	TradeState_Closed     = "CLOSED"     // 已关闭
+	TradeState_Revoked    = "REVOKED"    // 已冲正
+	TradeState_UserPaying = "USERPAYING" // 用户支付中
+	TradeState_PayError   = "PAYERROR"   // 支付失败
+)
+
+// NormalizeTradeStatus QQ trade_state 转统一订单状态
+func NormalizeTradeStatus(tradeState string) gopay.TradeStatus {
+	switch tradeState {
+	case TradeState_NotPay:
+		return gopay.TradeStatusPending
+	case TradeState_UserPaying:
+		return gopay.TradeStatusPaying
+	case TradeState_Success:
+		return gopay.TradeStatusSucceeded
+	case TradeState_Closed, TradeState_Revoked:
+		return gopay.TradeStatusClosed
+	case TradeState_Refund:
+		return gopay.TradeStatusRefunded
+	case TradeState_PayError:
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
@@ -0,0 +1,48 @@
+package reconcile
+
+import (
+	"iter"
+	"time"
+
+	"github.com/w6xian/gopay"
+	alibill "github.com/w6xian/gopay/alipay/bill"
+)
+
+const alipayBizRefund = "退款"
+
+// AlipayTradeEntries 支付宝业务明细（bill_type = trade）转记录流
+// reader：file.TradeRecords() 返回
+func AlipayTradeEntries(reader *alibill.Reader[alibill.TradeRecord]) iter.Seq2[*Entry, error] {
+	return convert(reader.All(), AlipayTradeEntry)
+}
+
+// AlipayTradeEntry 支付宝业务明细转 Entry
+// 业务类型为 退款 的行为退款记录，商户退款单号取 退款批次号/请求号；账单中均为已成功的记录
+func AlipayTradeEntry(record *alibill.TradeRecord) (entry *Entry, err error) {
+	entry = &Entry{
+		Provider:   gopay.ProviderAlipay,
+		Kind:       KindPayment,
+		OutTradeNo: record.OutTradeNo,
+		TradeNo:    record.TradeNo,
+		Currency:   defaultCurrency,
+		Status:     gopay.TradeStatusSucceeded,
+		Raw:        record,
+	}
+	if record.BizType == alipayBizRefund {
+		entry.Kind = KindRefund
+		entry.OutRefundNo = record.OutRequestNo
+	}
+	if entry.Amount, err = parseAmount(record.TotalAmount, entry.Currency); err != nil {
+		return nil, err
+	}
+	if entry.Fee, err = parseAmount(record.ServiceFee, entry.Currency); err != nil {
+		return nil, err
+	}
+	if entry.Kind == KindRefund {
+		entry.Fee = -entry.Fee
+	}
+	if entry.Time, err = parseTime(time.DateTime, firstOf(record.FinishTime, record.CreateTime), chinaLocation); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
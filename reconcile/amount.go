@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+const defaultCurrency = gopay.DefaultCurrency
+
+var (
+	chinaLocation = time.FixedZone("CST", 8*3600) // 微信、支付宝账单时间
+	japanLocation = time.FixedZone("JST", 9*3600) // 拉卡拉账单时间
+)
+
+// parseAmount 账单十进制金额 转 最小单位金额的绝对值，如 CNY "-12.34" => 1234，值为空时返回 0
+func parseAmount(value, currency string) (int64, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	if value == gopay.NULL {
+		return 0, nil
+	}
+	m, err := gopay.ParseMoney(value, currency)
+	if err != nil {
+		return 0, fmt.Errorf("[%w]: %v", gopay.BillFormatErr, err)
+	}
+	if m.IsNegative() {
+		if m, err = m.Neg(); err != nil {
+			return 0, fmt.Errorf("[%w]: %v", gopay.BillFormatErr, err)
+		}
+	}
+	return m.Minor(), nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// parseTime 解析账单时间，值为空时返回零值
+func parseTime(layout, value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == gopay.NULL {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("[%w]: time %s", gopay.BillFormatErr, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,52 @@
+package reconcile
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/lakala"
+)
+
+const (
+	lakalaTimeLayout = "20060102150405"
+	lakalaTypeRefund = "REFUND"
+)
+
+// LakalaEntries 拉卡拉账单流水转记录流
+// transactions：client.TransactionList() 或 client.Settlements() 返回的 Transactions
+func LakalaEntries(transactions []*lakala.Transaction) iter.Seq2[*Entry, error] {
+	return func(yield func(*Entry, error) bool) {
+		for _, v := range transactions {
+			entry, err := LakalaEntry(v)
+			if !yield(entry, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// LakalaEntry 拉卡拉账单流水转 Entry，流水类型为 REFUND 时为退款记录，金额取订单总金额，手续费取 surcharge
+func LakalaEntry(transaction *lakala.Transaction) (entry *Entry, err error) {
+	entry = &Entry{
+		Provider:   gopay.ProviderLakala,
+		Kind:       KindPayment,
+		OutTradeNo: transaction.PartnerOrderId,
+		TradeNo:    transaction.OrderId,
+		Amount:     abs(int64(transaction.TotalAmount)),
+		Fee:        abs(int64(transaction.Surcharge)),
+		Currency:   strings.ToUpper(transaction.Currency),
+		Status:     gopay.TradeStatusSucceeded,
+		Raw:        transaction,
+	}
+	if strings.EqualFold(transaction.Type, lakalaTypeRefund) {
+		entry.Kind = KindRefund
+		entry.OutRefundNo = transaction.PartnerRefundId
+		entry.RefundNo = transaction.RefundId
+		entry.Fee = -entry.Fee
+	}
+	if entry.Time, err = parseTime(lakalaTimeLayout, transaction.TransactionTime, japanLocation); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
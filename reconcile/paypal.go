@@ -0,0 +1,81 @@
+package reconcile
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/paypal"
+)
+
+// PayPalCaptureEntry PayPal Capture 详情（client.PaymentCaptureDetail() 返回的 Response）转 Entry
+// 商户订单号取 invoice_id，手续费取 seller_receivable_breakdown.paypal_fee
+func PayPalCaptureEntry(capture *paypal.PaymentAuthorizeCapture) (entry *Entry, err error) {
+	if capture == nil || capture.Amount == nil {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "capture.amount")
+	}
+	entry = &Entry{
+		Provider:   gopay.ProviderPayPal,
+		Kind:       KindPayment,
+		OutTradeNo: capture.InvoiceId,
+		TradeNo:    capture.Id,
+		Currency:   capture.Amount.CurrencyCode,
+		Status:     paypal.NormalizeCaptureStatus(capture.Status),
+		Raw:        capture,
+	}
+	if entry.Amount, err = parseAmount(capture.Amount.Value, entry.Currency); err != nil {
+		return nil, err
+	}
+	if b := capture.SellerReceivableBreakdown; b != nil && b.PaypalFee != nil {
+		if entry.Fee, err = parseAmount(b.PaypalFee.Value, b.PaypalFee.CurrencyCode); err != nil {
+			return nil, err
+		}
+	}
+	if entry.Time, err = parseTime(time.RFC3339, firstOf(capture.UpdateTime, capture.CreateTime), time.UTC); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// PayPalRefundEntry PayPal 退款详情（client.PaymentRefundDetail()、client.PaymentCaptureRefund() 返回的 Response）转 Entry
+// 商户退款单号取 invoice_id，退回的手续费取 seller_payable_breakdown.paypal_fee
+func PayPalRefundEntry(refund *paypal.PaymentCaptureRefund) (entry *Entry, err error) {
+	if refund == nil || refund.Amount == nil {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "refund.amount")
+	}
+	entry = &Entry{
+		Provider:    gopay.ProviderPayPal,
+		Kind:        KindRefund,
+		OutRefundNo: refund.InvoiceId,
+		RefundNo:    refund.Id,
+		Currency:    refund.Amount.CurrencyCode,
+		Status:      paypalRefundStatus(refund.Status),
+		Raw:         refund,
+	}
+	if entry.Amount, err = parseAmount(refund.Amount.Value, entry.Currency); err != nil {
+		return nil, err
+	}
+	if b := refund.SellerPayableBreakdown; b != nil && b.PaypalFee != nil {
+		if entry.Fee, err = parseAmount(b.PaypalFee.Value, b.PaypalFee.CurrencyCode); err != nil {
+			return nil, err
+		}
+		entry.Fee = -entry.Fee
+	}
+	if entry.Time, err = parseTime(time.RFC3339, firstOf(refund.UpdateTime, refund.CreateTime), time.UTC); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// 退款状态：CANCELLED、FAILED、PENDING、COMPLETED
+func paypalRefundStatus(status string) gopay.TradeStatus {
+	switch status {
+	case "COMPLETED":
+		return gopay.TradeStatusSucceeded
+	case "PENDING":
+		return gopay.TradeStatusPending
+	case "CANCELLED", "FAILED":
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
@@ -0,0 +1,327 @@
+// Package reconcile 渠道账单与商户账本对账
+// 渠道账单（微信、支付宝、拉卡拉、PayPal）转为统一的 Entry 流，按 商户订单号/商户退款单号 与商户账本逐条核对，
+// 输出 长款、短款、金额不符、状态不符 差异明细，以及按日汇总的交易、退款、手续费金额。
+// 渠道账单逐条处理，内存中仅保留已核对的单号，适用于较大的月账单。
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sort"
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+// Kind 账单记录类型
+type Kind string
+
+const (
+	KindPayment Kind = "PAYMENT" // 支付
+	KindRefund  Kind = "REFUND"  // 退款
+)
+
+// Entry 统一的账单记录，渠道账单与商户账本均使用该结构
+type Entry struct {
+	Provider    string    // 渠道，如 gopay.ProviderWechatV3
+	Kind        Kind      // 支付 或 退款
+	OutTradeNo  string    // 商户订单号
+	OutRefundNo string    // 商户退款单号，退款记录必填
+	TradeNo     string    // 渠道订单号
+	RefundNo    string    // 渠道退款单号
+	Amount      int64     // 支付或退款金额，币种最小单位（如 分），均为正数
+	Fee         int64     // 手续费，币种最小单位，退款退回的手续费为负数
+	Currency    string    // ISO-4217 币种，为空默认 CNY
+	Time        time.Time // 支付完成时间或退款时间，用于按日汇总
+	// 支付记录：订单状态；退款记录：gopay.TradeStatusSucceeded 退款成功，gopay.TradeStatusPending 退款处理中，gopay.TradeStatusFailed 退款失败
+	Status gopay.TradeStatus
+	Raw    any // 渠道账单原始记录
+}
+
+// No 对账单号，支付记录为商户订单号，退款记录为商户退款单号
+func (e *Entry) No() string {
+	if e.Kind == KindRefund {
+		return e.OutRefundNo
+	}
+	return e.OutTradeNo
+}
+
+// settled 资金是否已发生变动：支付已付款，或退款已成功
+// 状态为空或 TradeStatusUnknown 时视为已发生
+func (e *Entry) settled() bool {
+	switch e.Status {
+	case "", gopay.TradeStatusUnknown:
+		return true
+	}
+	if e.Kind == KindRefund {
+		return e.Status == gopay.TradeStatusSucceeded || e.Status == gopay.TradeStatusRefunded
+	}
+	return e.Status.IsPaid()
+}
+
+func (e *Entry) currency() string {
+	if e.Currency == gopay.NULL {
+		return defaultCurrency
+	}
+	return e.Currency
+}
+
+// Ledger 商户账本，由业务方实现
+type Ledger interface {
+	// Find 按记录类型及对账单号（商户订单号 或 商户退款单号）查询商户记录，不存在时返回 nil, nil
+	Find(ctx context.Context, kind Kind, no string) (entry *Entry, err error)
+	// Range 遍历 [start, end) 内该渠道的全部商户记录（含支付与退款），用于发现短款
+	Range(ctx context.Context, provider string, start, end time.Time) iter.Seq2[*Entry, error]
+}
+
+// DiffType 差异类型
+type DiffType string
+
+const (
+	DiffLong      DiffType = "LONG"            // 长款：渠道有，商户无
+	DiffShort     DiffType = "SHORT"           // 短款：商户有，渠道无
+	DiffAmount    DiffType = "AMOUNT_MISMATCH" // 金额或币种不符
+	DiffStatus    DiffType = "STATUS_MISMATCH" // 状态不符，如渠道已支付而商户未支付
+	DiffDuplicate DiffType = "DUPLICATE"       // 渠道账单中重复的记录
+)
+
+// Diff 差异明细
+type Diff struct {
+	Type   DiffType
+	Kind   Kind
+	No     string // 对账单号
+	Bill   *Entry // 渠道记录，短款时为 nil
+	Ledger *Entry // 商户记录，长款时为 nil
+}
+
+// Summary 汇总，金额为币种最小单位，仅统计渠道账单中资金已发生变动的记录
+type Summary struct {
+	Date          string // 日期 2006-01-02，合计时为空
+	Currency      string
+	PaymentCount  int
+	PaymentAmount int64
+	RefundCount   int
+	RefundAmount  int64
+	Fee           int64 // 手续费（已扣除退回的手续费）
+	NetAmount     int64 // 净额 = PaymentAmount - RefundAmount - Fee
+	Matched       int   // 核对一致的记录数
+	Diffs         int   // 差异记录数
+}
+
+func (s *Summary) add(e *Entry) {
+	if e.Kind == KindRefund {
+		s.RefundCount++
+		s.RefundAmount += e.Amount
+	} else {
+		s.PaymentCount++
+		s.PaymentAmount += e.Amount
+	}
+	s.Fee += e.Fee
+	s.NetAmount = s.PaymentAmount - s.RefundAmount - s.Fee
+}
+
+func (s *Summary) merge(o *Summary) {
+	s.PaymentCount += o.PaymentCount
+	s.PaymentAmount += o.PaymentAmount
+	s.RefundCount += o.RefundCount
+	s.RefundAmount += o.RefundAmount
+	s.Fee += o.Fee
+	s.NetAmount += o.NetAmount
+	s.Matched += o.Matched
+	s.Diffs += o.Diffs
+}
+
+// Report 对账结果
+type Report struct {
+	Provider string
+	Start    time.Time
+	End      time.Time
+	Matched  int        // 核对一致的记录数
+	Diffs    []*Diff    // 差异明细，按发现顺序：先渠道账单中的差异，后短款
+	Days     []*Summary // 按日、币种汇总，按日期、币种排序
+	Totals   []*Summary // 按币种合计
+}
+
+// Reconciler 对账器
+type Reconciler struct {
+	ledger   Ledger
+	location *time.Location
+	onDiff   func(diff *Diff)
+}
+
+type Option func(*Reconciler)
+
+// WithLocation 按日汇总使用的时区，默认 Asia/Shanghai（UTC+8）
+func WithLocation(loc *time.Location) Option {
+	return func(r *Reconciler) {
+		if loc != nil {
+			r.location = loc
+		}
+	}
+}
+
+// WithDiffHandler 发现差异时回调，可用于实时告警或落库
+func WithDiffHandler(fn func(diff *Diff)) Option {
+	return func(r *Reconciler) {
+		r.onDiff = fn
+	}
+}
+
+// New 创建对账器
+func New(ledger Ledger, opts ...Option) *Reconciler {
+	r := &Reconciler{ledger: ledger, location: chinaLocation}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type entryKey struct {
+	kind Kind
+	no   string
+}
+
+type dayKey struct {
+	date     string
+	currency string
+}
+
+type reconciliation struct {
+	*Reconciler
+	report *Report
+	days   map[dayKey]*Summary
+}
+
+// Reconcile 核对 provider 渠道 [start, end) 的账单
+// bills：渠道账单记录流，如 WechatTradeEntries()、AlipayTradeEntries()，读取出错时返回 error
+func (r *Reconciler) Reconcile(ctx context.Context, provider string, start, end time.Time, bills iter.Seq2[*Entry, error]) (report *Report, err error) {
+	if r.ledger == nil {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "ledger")
+	}
+	rc := &reconciliation{
+		Reconciler: r,
+		report:     &Report{Provider: provider, Start: start, End: end},
+		days:       make(map[dayKey]*Summary),
+	}
+	seen := make(map[entryKey]struct{})
+	for bill, err := range bills {
+		if err != nil {
+			return nil, err
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		key := entryKey{kind: bill.Kind, no: bill.No()}
+		if key.no == gopay.NULL {
+			return nil, fmt.Errorf("[%w]: %s entry without merchant no: %+v", gopay.BillFormatErr, bill.Kind, bill)
+		}
+		if _, ok := seen[key]; ok {
+			rc.diff(&Diff{Type: DiffDuplicate, Kind: bill.Kind, No: key.no, Bill: bill}, bill.Time, bill.currency())
+			continue
+		}
+		seen[key] = struct{}{}
+		if bill.settled() {
+			rc.day(bill.Time, bill.currency()).add(bill)
+		}
+		ledger, err := r.ledger.Find(ctx, bill.Kind, key.no)
+		if err != nil {
+			return nil, err
+		}
+		if diff := compare(bill, ledger); diff != nil {
+			rc.diff(diff, bill.Time, bill.currency())
+			continue
+		}
+		rc.match(bill.Time, bill.currency())
+	}
+	for ledger, err := range r.ledger.Range(ctx, provider, start, end) {
+		if err != nil {
+			return nil, err
+		}
+		key := entryKey{kind: ledger.Kind, no: ledger.No()}
+		if _, ok := seen[key]; ok || !ledger.settled() {
+			continue
+		}
+		rc.diff(&Diff{Type: DiffShort, Kind: ledger.Kind, No: key.no, Ledger: ledger}, ledger.Time, ledger.currency())
+	}
+	rc.summarize()
+	return rc.report, nil
+}
+
+// compare 比较渠道记录与商户记录，一致时返回 nil
+func compare(bill, ledger *Entry) *Diff {
+	diff := &Diff{Kind: bill.Kind, No: bill.No(), Bill: bill, Ledger: ledger}
+	switch {
+	case ledger == nil:
+		// 渠道侧未成功（如已撤销）的记录，商户无记录时不视为差异
+		if !bill.settled() {
+			return nil
+		}
+		diff.Type = DiffLong
+	case bill.settled() != ledger.settled():
+		diff.Type = DiffStatus
+	case bill.Amount != ledger.Amount || bill.currency() != ledger.currency():
+		diff.Type = DiffAmount
+	default:
+		return nil
+	}
+	return diff
+}
+
+func (rc *reconciliation) day(t time.Time, currency string) *Summary {
+	key := dayKey{date: t.In(rc.location).Format(time.DateOnly), currency: currency}
+	s, ok := rc.days[key]
+	if !ok {
+		s = &Summary{Date: key.date, Currency: currency}
+		rc.days[key] = s
+	}
+	return s
+}
+
+func (rc *reconciliation) match(t time.Time, currency string) {
+	rc.report.Matched++
+	rc.day(t, currency).Matched++
+}
+
+func (rc *reconciliation) diff(diff *Diff, t time.Time, currency string) {
+	rc.report.Diffs = append(rc.report.Diffs, diff)
+	rc.day(t, currency).Diffs++
+	if rc.onDiff != nil {
+		rc.onDiff(diff)
+	}
+}
+
+func (rc *reconciliation) summarize() {
+	totals := make(map[string]*Summary)
+	for _, s := range rc.days {
+		rc.report.Days = append(rc.report.Days, s)
+		total, ok := totals[s.Currency]
+		if !ok {
+			total = &Summary{Currency: s.Currency}
+			totals[s.Currency] = total
+			rc.report.Totals = append(rc.report.Totals, total)
+		}
+		total.merge(s)
+	}
+	sort.Slice(rc.report.Days, func(i, j int) bool {
+		a, b := rc.report.Days[i], rc.report.Days[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.Currency < b.Currency
+	})
+	sort.Slice(rc.report.Totals, func(i, j int) bool {
+		return rc.report.Totals[i].Currency < rc.report.Totals[j].Currency
+	})
+}
+
+// Entries 将已加载的记录转为记录流，适用于 PayPal 等通过接口逐笔查询的渠道
+func Entries(entries ...*Entry) iter.Seq2[*Entry, error] {
+	return func(yield func(*Entry, error) bool) {
+		for _, e := range entries {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/w6xian/gopay"
+	alibill "github.com/w6xian/gopay/alipay/bill"
+	"github.com/w6xian/gopay/lakala"
+	"github.com/w6xian/gopay/paypal"
+	wxbill "github.com/w6xian/gopay/wechat/bill"
+)
+
+const wechatBill = "交易时间,公众账号ID,商户号,特约商户号,设备号,微信订单号,商户订单号,用户标识,交易类型,交易状态,付款银行,货币种类,应结订单金额,代金券金额,微信退款单号,商户退款单号,退款金额,充值券退款金额,退款类型,退款状态,商品名称,商户数据包,手续费,费率,订单金额,申请退款金额,费率备注\n" +
+	"`2024-01-01 10:00:00,`wx01,`1900000001,`0,`,`4200000001,`A,`o1,`NATIVE,`SUCCESS,`OTHERS,`CNY,`1.00,`0.00,`0,`0,`0.00,`0.00,`,`,`商品,`,`0.01,`0.60%,`1.00,`0.00,`\n" +
+	"`2024-01-01 11:00:00,`wx01,`1900000001,`0,`,`4200000002,`B,`o1,`NATIVE,`SUCCESS,`OTHERS,`CNY,`2.00,`0.00,`0,`0,`0.00,`0.00,`,`,`商品,`,`0.01,`0.60%,`2.00,`0.00,`\n" +
+	"`2024-01-01 12:00:00,`wx01,`1900000001,`0,`,`4200000003,`C,`o1,`NATIVE,`SUCCESS,`OTHERS,`CNY,`3.00,`0.00,`0,`0,`0.00,`0.00,`,`,`商品,`,`0.01,`0.60%,`3.00,`0.00,`\n" +
+	"`2024-01-01 13:00:00,`wx01,`1900000001,`0,`,`4200000004,`D,`o1,`NATIVE,`SUCCESS,`OTHERS,`CNY,`4.00,`0.00,`0,`0,`0.00,`0.00,`,`,`商品,`,`0.01,`0.60%,`4.00,`0.00,`\n" +
+	"`2024-01-02 09:00:00,`wx01,`1900000001,`0,`,`4200000001,`A,`o1,`NATIVE,`REFUND,`OTHERS,`CNY,`1.00,`0.00,`5000000001,`RA,`0.50,`0.00,`ORIGINAL,`SUCCESS,`商品,`,`-0.01,`0.60%,`1.00,`0.50,`\n" +
+	"总交易单数,应结订单总金额,退款总金额,充值券退款总金额,手续费总金额,订单总金额,申请退款总金额\n" +
+	"`5,`10.00,`0.50,`0.00,`0.03,`10.00,`0.50\n"
+
+// memLedger 内存商户账本
+type memLedger struct {
+	entries []*Entry
+}
+
+func (l *memLedger) Find(_ context.Context, kind Kind, no string) (*Entry, error) {
+	for _, e := range l.entries {
+		if e.Kind == kind && e.No() == no {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *memLedger) Range(_ context.Context, provider string, start, end time.Time) iter.Seq2[*Entry, error] {
+	return func(yield func(*Entry, error) bool) {
+		for _, e := range l.entries {
+			if e.Provider != provider || e.Time.Before(start) || !e.Time.Before(end) {
+				continue
+			}
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestReconcileWechat(t *testing.T) {
+	day := time.Date(2024, 1, 1, 10, 0, 0, 0, chinaLocation)
+	pay := func(no string, amount int64, status gopay.TradeStatus) *Entry {
+		return &Entry{Provider: gopay.ProviderWechat, Kind: KindPayment, OutTradeNo: no, Amount: amount, Status: status, Time: day}
+	}
+	ledger := &memLedger{entries: []*Entry{
+		pay("A", 100, gopay.TradeStatusPartiallyRefunded),
+		pay("B", 150, gopay.TradeStatusSucceeded),
+		pay("D", 400, gopay.TradeStatusPending),
+		pay("E", 500, gopay.TradeStatusSucceeded),
+		pay("F", 600, gopay.TradeStatusClosed),
+		{Provider: gopay.ProviderWechat, Kind: KindRefund, OutTradeNo: "A", OutRefundNo: "RA", Amount: 50, Status: gopay.TradeStatusSucceeded, Time: day.AddDate(0, 0, 1)},
+	}}
+	reader, err := wxbill.NewTradeReader(strings.NewReader(wechatBill))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handled int
+	r := New(ledger, WithDiffHandler(func(*Diff) { handled++ }))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, chinaLocation)
+	report, err := r.Reconcile(context.Background(), gopay.ProviderWechat, start, start.AddDate(0, 0, 2), WechatTradeEntries(reader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Matched != 2 {
+		t.Errorf("matched = %d, want 2", report.Matched)
+	}
+	want := []struct {
+		typ DiffType
+		no  string
+	}{{DiffAmount, "B"}, {DiffLong, "C"}, {DiffStatus, "D"}, {DiffShort, "E"}}
+	if len(report.Diffs) != len(want) || handled != len(want) {
+		t.Fatalf("diffs = %d, handled = %d, want %d", len(report.Diffs), handled, len(want))
+	}
+	for i, w := range want {
+		if d := report.Diffs[i]; d.Type != w.typ || d.No != w.no {
+			t.Errorf("diffs[%d] = %s %s, want %s %s", i, d.Type, d.No, w.typ, w.no)
+		}
+	}
+	if len(report.Days) != 2 || len(report.Totals) != 1 {
+		t.Fatalf("days = %d, totals = %d", len(report.Days), len(report.Totals))
+	}
+	d1, d2, total := report.Days[0], report.Days[1], report.Totals[0]
+	if d1.Date != "2024-01-01" || d1.PaymentCount != 4 || d1.PaymentAmount != 1000 || d1.Fee != 4 || d1.NetAmount != 996 || d1.Matched != 1 || d1.Diffs != 4 {
+		t.Errorf("day1 = %+v", d1)
+	}
+	if d2.Date != "2024-01-02" || d2.RefundCount != 1 || d2.RefundAmount != 50 || d2.Fee != -1 || d2.NetAmount != -49 || d2.Matched != 1 {
+		t.Errorf("day2 = %+v", d2)
+	}
+	if total.Currency != "CNY" || total.PaymentAmount != 1000 || total.RefundAmount != 50 || total.Fee != 3 || total.NetAmount != 947 {
+		t.Errorf("total = %+v", total)
+	}
+}
+
+func TestReconcileDuplicate(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bill := &Entry{Kind: KindPayment, OutTradeNo: "A", Amount: 1, Time: ts}
+	ledger := &memLedger{entries: []*Entry{{Kind: KindPayment, OutTradeNo: "A", Amount: 1, Time: ts}}}
+	report, err := New(ledger).Reconcile(context.Background(), "", ts, ts.Add(time.Hour), Entries(bill, bill))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Matched != 1 || len(report.Diffs) != 1 || report.Diffs[0].Type != DiffDuplicate {
+		t.Fatalf("report = %+v", report)
+	}
+
+	billErr := errors.New("read bill")
+	bills := func(yield func(*Entry, error) bool) { yield(nil, billErr) }
+	if _, err = New(ledger).Reconcile(context.Background(), "", ts, ts, bills); !errors.Is(err, billErr) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProviderEntries(t *testing.T) {
+	ali, err := AlipayTradeEntry(&alibill.TradeRecord{
+		TradeNo: "2024010122001", OutTradeNo: "A", BizType: "退款", FinishTime: "2024-01-01 10:00:05",
+		TotalAmount: "-0.01", ServiceFee: "0.00", OutRequestNo: "RA",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ali.Kind != KindRefund || ali.No() != "RA" || ali.Amount != 1 || ali.Time.Day() != 1 {
+		t.Errorf("alipay = %+v", ali)
+	}
+
+	lkl, err := LakalaEntry(&lakala.Transaction{
+		TransactionTime: "20240101090000", PartnerOrderId: "A", PartnerRefundId: "RA", Type: "REFUND",
+		Currency: "jpy", TotalAmount: -1000, Surcharge: 30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lkl.Kind != KindRefund || lkl.No() != "RA" || lkl.Amount != 1000 || lkl.Fee != -30 || lkl.Currency != "JPY" ||
+		lkl.Time.In(chinaLocation).Format(time.DateTime) != "2024-01-01 08:00:00" {
+		t.Errorf("lakala = %+v", lkl)
+	}
+
+	pp, err := PayPalCaptureEntry(&paypal.PaymentAuthorizeCapture{
+		Id: "CAP1", Status: paypal.CaptureStatusCompleted, InvoiceId: "A",
+		Amount:                    &paypal.Amount{CurrencyCode: "USD", Value: "10.50"},
+		SellerReceivableBreakdown: &paypal.SellerReceivableBreakdown{PaypalFee: &paypal.Amount{CurrencyCode: "USD", Value: "0.61"}},
+		UpdateTime:                "2024-01-01T02:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.No() != "A" || pp.Amount != 1050 || pp.Fee != 61 || pp.Status != gopay.TradeStatusSucceeded {
+		t.Errorf("paypal = %+v", pp)
+	}
+	if _, err = PayPalRefundEntry(&paypal.PaymentCaptureRefund{Amount: &paypal.Amount{CurrencyCode: "CNY", Value: "0.001"}}); !errors.Is(err, gopay.BillFormatErr) {
+		t.Errorf("err = %v", err)
+	}
+}
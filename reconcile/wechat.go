@@ -0,0 +1,94 @@
+package reconcile
+
+import (
+	"iter"
+	"time"
+
+	"github.com/w6xian/gopay"
+	wxbill "github.com/w6xian/gopay/wechat/bill"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+// WechatTradeEntries 微信交易账单（bill_type = ALL）转记录流
+// reader：wxbill.NewTradeReader() 或 client.V3BillTradeBillReader() 返回
+func WechatTradeEntries(reader *wxbill.TradeReader) iter.Seq2[*Entry, error] {
+	return convert(reader.All(), WechatTradeEntry)
+}
+
+// WechatTradeEntry 微信交易账单明细转 Entry
+// 交易状态为 REFUND 的行为退款记录，金额取 申请退款金额（无该列时取 退款金额），时间取 退款成功时间（无则取 退款申请时间）
+// 其余行为支付记录，金额取 订单金额（无该列时取 应结订单金额）
+func WechatTradeEntry(record *wxbill.TradeRecord) (entry *Entry, err error) {
+	entry = &Entry{
+		Provider:   gopay.ProviderWechat,
+		Kind:       KindPayment,
+		OutTradeNo: record.OutTradeNo,
+		TradeNo:    record.TransactionId,
+		Currency:   record.FeeType,
+		Status:     wechat.NormalizeTradeStatus(record.TradeState),
+		Raw:        record,
+	}
+	if entry.Currency == gopay.NULL {
+		entry.Currency = defaultCurrency
+	}
+	amount, ts := firstOf(record.TotalFee, record.SettlementTotalFee), record.TradeTime
+	if record.TradeState == wechat.TradeStateRefund {
+		entry.Kind = KindRefund
+		entry.OutRefundNo = record.OutRefundNo
+		entry.RefundNo = record.RefundId
+		entry.Status = wechatRefundStatus(record.RefundStatus)
+		amount = firstOf(record.RefundFee, record.SettlementRefundFee)
+		ts = firstOf(record.RefundSuccessTime, record.RefundApplyTime, record.TradeTime)
+	}
+	if entry.Amount, err = parseAmount(amount, entry.Currency); err != nil {
+		return nil, err
+	}
+	if entry.Fee, err = parseAmount(record.ServiceFee, entry.Currency); err != nil {
+		return nil, err
+	}
+	if entry.Kind == KindRefund {
+		entry.Fee = -entry.Fee
+	}
+	if entry.Time, err = parseTime(time.DateTime, ts, chinaLocation); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// 账单中的退款状态：SUCCESS、PROCESSING、ABNORMAL、CLOSED（V2 为 REFUNDCLOSE、CHANGE）
+func wechatRefundStatus(status string) gopay.TradeStatus {
+	switch status {
+	case gopay.NULL:
+		return gopay.TradeStatusUnknown
+	case "SUCCESS":
+		return gopay.TradeStatusSucceeded
+	case "PROCESSING":
+		return gopay.TradeStatusPending
+	}
+	return gopay.TradeStatusFailed
+}
+
+// convert 渠道账单记录流转 Entry 记录流
+func convert[R any](seq iter.Seq2[*R, error], fn func(*R) (*Entry, error)) iter.Seq2[*Entry, error] {
+	return func(yield func(*Entry, error) bool) {
+		for record, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			entry, err := fn(record)
+			if !yield(entry, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != gopay.NULL {
+			return v
+		}
+	}
+	return gopay.NULL
+}
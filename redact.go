@@ -0,0 +1,301 @@
+package gopay
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-pay/xlog"
+)
+
+// RedactRule 日志脱敏规则，输入一行日志，返回脱敏后的日志
+type RedactRule interface {
+	Redact(s string) string
+}
+
+// RedactFunc 函数形式的脱敏规则
+type RedactFunc func(s string) string
+
+func (f RedactFunc) Redact(s string) string {
+	return f(s)
+}
+
+// RedactPattern 正则脱敏规则，匹配到的内容替换为 mask(match)
+// pattern 非法时 panic，与 regexp.MustCompile 一致
+func RedactPattern(pattern string, mask func(match string) string) RedactRule {
+	re := regexp.MustCompile(pattern)
+	return RedactFunc(func(s string) string {
+		return re.ReplaceAllStringFunc(s, mask)
+	})
+}
+
+// RedactFields 字段脱敏规则，按字段名（不区分大小写）脱敏 JSON、表单（key=value）及 XML 中的字段值
+func RedactFields(mask func(value string) string, keys ...string) RedactRule {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = regexp.QuoteMeta(k)
+	}
+	names := strings.Join(quoted, "|")
+	var (
+		// "key":"value"，兼容嵌套在字符串中的转义 JSON（如 biz_content）
+		jsonRe = regexp.MustCompile(`(?i)(\\?"(?:` + names + `)\\?"\s*:\s*\\?")((?:[^"\\]|\\[^"])*)(\\?")`)
+		formRe = regexp.MustCompile(`(?i)((?:^|[?&\s])(?:` + names + `)=)([^&\s"]*)`)
+		xmlRe  = regexp.MustCompile(`(?i)(<(?:` + names + `)>(?:<!\[CDATA\[)?)([^<\]]*)`)
+	)
+	replace := func(re *regexp.Regexp, s string) string {
+		return re.ReplaceAllStringFunc(s, func(match string) string {
+			sub := re.FindStringSubmatch(match)
+			if sub[2] == NULL {
+				return match
+			}
+			return sub[1] + mask(sub[2]) + strings.Join(sub[3:], NULL)
+		})
+	}
+	return RedactFunc(func(s string) string {
+		return replace(xmlRe, replace(formRe, replace(jsonRe, s)))
+	})
+}
+
+// MaskMiddle 保留前 head 位、后 tail 位，中间替换为 *
+// 长度不足 head+tail 时全部替换为 ***
+func MaskMiddle(s string, head, tail int) string {
+	r := []rune(s)
+	if len(r) <= head+tail {
+		return "***"
+	}
+	return string(r[:head]) + strings.Repeat("*", len(r)-head-tail) + string(r[len(r)-tail:])
+}
+
+// Redactor 日志脱敏器，按添加顺序依次应用规则，并发安全
+type Redactor struct {
+	mu      sync.RWMutex
+	rules   []RedactRule
+	headers map[string]struct{}
+}
+
+// NewRedactor 初始化脱敏器，不包含默认规则
+func NewRedactor(rules ...RedactRule) *Redactor {
+	return &Redactor{rules: rules, headers: make(map[string]struct{})}
+}
+
+// AddRule 追加脱敏规则
+func (r *Redactor) AddRule(rules ...RedactRule) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, rules...)
+	return r
+}
+
+// AddHeader 追加需脱敏的 http 请求头/响应头
+func (r *Redactor) AddHeader(names ...string) *Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.headers[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	return r
+}
+
+// Redact 对字符串依次应用脱敏规则
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, rule := range r.rules {
+		s = rule.Redact(s)
+	}
+	return s
+}
+
+// RedactHeader 返回脱敏后的 http.Header 副本，认证类请求头保留认证类型（如 WECHATPAY2-SHA256-RSA2048）
+func (r *Redactor) RedactHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := h.Clone()
+	for k, vs := range clone {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; !ok {
+			continue
+		}
+		for i, v := range vs {
+			if scheme, _, found := strings.Cut(v, " "); found {
+				vs[i] = scheme + " ***"
+				continue
+			}
+			vs[i] = "***"
+		}
+	}
+	return clone
+}
+
+// DefaultRedactor 默认日志脱敏器，各支付渠道客户端的 logger 默认使用
+// 可通过 gopay.DefaultRedactor.AddRule()、AddHeader() 扩展规则
+var DefaultRedactor = NewDefaultRedactor()
+
+var (
+	defaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "Wechatpay-Signature", "Alipay-Signature"}
+	// 密钥、令牌类字段，全部替换
+	defaultSecretFields = []string{"key", "access_token", "refresh_token", "id_token", "client_secret", "app_secret", "password", "token"}
+	// 姓名、证件类字段，保留首尾
+	defaultPersonalFields = []string{"real_name", "true_name", "cert_name", "user_name", "payee_real_name", "id_card_name", "cert_no", "id_card_number", "id_card_no", "identity_no"}
+	// 卡号类字段，银行卡号（校验位有效）保留前 6 后 4，其他保留首尾
+	defaultCardFields = []string{"card", "card_no", "bank_card", "bank_card_no", "bank_account", "account_number"}
+	// 手机号类字段，手机号保留前 3 后 4，其他保留首尾
+	defaultPhoneFields = []string{"mobile", "phone", "mobile_no", "phone_no", "mobile_phone", "contact_mobile", "contact_phone", "phone_number"}
+
+	cardNoRe = regexp.MustCompile(`^\d{13,19}$`)
+	phoneRe  = regexp.MustCompile(`^1[3-9]\d{9}$`)
+)
+
+// NewDefaultRedactor 初始化包含默认规则的脱敏器：
+// 1. Authorization、Wechatpay-Signature 等请求头
+// 2. access_token 等令牌、密钥字段及 Bearer 令牌
+// 3. 姓名、证件号、卡号、手机号等字段，银行卡号、手机号仅在卡号、手机号字段中识别，避免误伤订单号、金额等数字
+// 4. 长 base64 密文（敏感信息加密字段、签名）
+// 5. 身份证号（校验位有效）
+func NewDefaultRedactor() *Redactor {
+	return NewRedactor(
+		RedactFields(func(string) string { return "***" }, defaultSecretFields...),
+		RedactFields(func(v string) string { return MaskMiddle(v, 1, 1) }, defaultPersonalFields...),
+		RedactFields(func(v string) string {
+			if cardNoRe.MatchString(v) && luhn(v) {
+				return MaskMiddle(v, 6, 4)
+			}
+			return MaskMiddle(v, 1, 1)
+		}, defaultCardFields...),
+		RedactFields(func(v string) string {
+			if phoneRe.MatchString(v) {
+				return MaskMiddle(v, 3, 4)
+			}
+			return MaskMiddle(v, 1, 1)
+		}, defaultPhoneFields...),
+		RedactPattern(`(?i)\b(?:Bearer|Basic) [A-Za-z0-9\-._~+/]+=*`, func(m string) string {
+			scheme, _, _ := strings.Cut(m, " ")
+			return scheme + " ***"
+		}),
+		RedactPattern(`[A-Za-z0-9+/]{100,}={0,2}`, func(m string) string {
+			return m[:6] + "***(" + strconv.Itoa(len(m)) + ")"
+		}),
+		RedactPattern(`\b\d{17}[\dXx]\b`, func(m string) string {
+			if !validIdNumber(m) {
+				return m
+			}
+			return MaskMiddle(m, 3, 4)
+		}),
+	).AddHeader(defaultRedactHeaders...)
+}
+
+// validIdNumber 18 位居民身份证号校验码（ISO 7064 MOD 11-2）
+func validIdNumber(s string) bool {
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(s[i]-'0') * w
+	}
+	check := s[17]
+	if check == 'x' {
+		check = 'X'
+	}
+	return "10X98765432"[sum%11] == check
+}
+
+// luhn 银行卡号 Luhn 校验
+func luhn(s string) bool {
+	sum := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if (len(s)-i)%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// =============================== logger ===============================
+
+// RedactLogger 对日志内容脱敏后再输出的 xlog.XLogger
+type RedactLogger struct {
+	logger   xlog.XLogger
+	redactor *Redactor
+}
+
+// NewRedactLogger 包装 logger，输出前使用 redactor 脱敏，redactor 为 nil 时使用 DefaultRedactor
+// logger 已是 *RedactLogger 时直接返回
+func NewRedactLogger(logger xlog.XLogger, redactor *Redactor) xlog.XLogger {
+	if l, ok := logger.(*RedactLogger); ok {
+		return l
+	}
+	return &RedactLogger{logger: logger, redactor: redactor}
+}
+
+func (l *RedactLogger) Debug(args ...any) {
+	l.logger.Debug(l.sprint(args...))
+}
+
+func (l *RedactLogger) Info(args ...any) {
+	l.logger.Info(l.sprint(args...))
+}
+
+func (l *RedactLogger) Warn(args ...any) {
+	l.logger.Warn(l.sprint(args...))
+}
+
+func (l *RedactLogger) Error(args ...any) {
+	l.logger.Error(l.sprint(args...))
+}
+
+func (l *RedactLogger) Debugf(format string, args ...any) {
+	l.logger.Debug(l.sprintf(format, args...))
+}
+
+func (l *RedactLogger) Infof(format string, args ...any) {
+	l.logger.Info(l.sprintf(format, args...))
+}
+
+func (l *RedactLogger) Warnf(format string, args ...any) {
+	l.logger.Warn(l.sprintf(format, args...))
+}
+
+func (l *RedactLogger) Errorf(format string, args ...any) {
+	l.logger.Error(l.sprintf(format, args...))
+}
+
+func (l *RedactLogger) getRedactor() *Redactor {
+	if l.redactor != nil {
+		return l.redactor
+	}
+	return DefaultRedactor
+}
+
+func (l *RedactLogger) sprint(args ...any) string {
+	r := l.getRedactor()
+	return r.Redact(strings.TrimSuffix(fmt.Sprintln(redactArgs(r, args)...), "\n"))
+}
+
+func (l *RedactLogger) sprintf(format string, args ...any) string {
+	r := l.getRedactor()
+	return r.Redact(fmt.Sprintf(format, redactArgs(r, args)...))
+}
+
+// redactArgs 请求头按 Redactor.RedactHeader 脱敏，其余参数格式化后按规则脱敏
+func redactArgs(r *Redactor, args []any) []any {
+	out := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case http.Header:
+			out[i] = r.RedactHeader(v)
+		case map[string][]string:
+			out[i] = map[string][]string(r.RedactHeader(v))
+		default:
+			out[i] = arg
+		}
+	}
+	return out
+}
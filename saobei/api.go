@@ -11,4 +11,6 @@ const (
 	refundPath = "/pay/open/refund"
 	// queryRefundPath 退款订单查询
 	queryRefundPath = "/pay/open/queryrefund"
+	// closePath 关闭订单
+	closePath = "/pay/open/close"
 )
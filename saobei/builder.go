@@ -0,0 +1,145 @@
+package saobei
+
+import (
+	"time"
+
+	"github.com/w6xian/gopay"
+)
+
+// 支付2.0核心接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度），再传给对应的方法
+// pay_ver、service_id、merchant_no、terminal_id 由 Client 自动填充；terminal_time 为空时使用当前时间，不修改请求结构体
+// pay_type 取值见 PayTypeWX、PayTypeAli、PayTypeQQ、PayTypeYi、PayTypeYL
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&saobei.MiniPayRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], pay_type: must be one of 010|020|060|100|110
+//	}
+//	rsp, err := client.MiniPay(ctx, bm)
+
+// MiniPayRequest 小程序支付请求参数，MiniPay() 使用
+type MiniPayRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalIp    string        `json:"terminal_ip" validate:"required,maxlen=32"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"`
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`    // yyyyMMddHHmmss
+	TotalFee      int64         `json:"total_fee" validate:"required,gt=0"` // 单位：分
+	SubAppid      string        `json:"sub_appid" validate:"required,maxlen=32"`
+	OpenId        string        `json:"open_id" validate:"required,maxlen=128"`
+	OrderBody     string        `json:"order_body,omitempty" validate:"maxlen=128"`
+	NotifyUrl     string        `json:"notify_url,omitempty" validate:"maxlen=128"`
+	Attach        string        `json:"attach,omitempty" validate:"maxlen=128"`
+	GoodsTag      string        `json:"goods_tag,omitempty" validate:"maxlen=32"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// BarcodePayRequest 付款码支付请求参数，BarcodePay() 使用
+type BarcodePayRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalIp    string        `json:"terminal_ip" validate:"required,maxlen=32"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"`
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`
+	TotalFee      int64         `json:"total_fee" validate:"required,gt=0"`
+	AuthNo        string        `json:"auth_no" validate:"required,maxlen=128"`
+	OrderBody     string        `json:"order_body,omitempty" validate:"maxlen=128"`
+	Attach        string        `json:"attach,omitempty" validate:"maxlen=128"`
+	GoodsTag      string        `json:"goods_tag,omitempty" validate:"maxlen=32"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// QueryRequest 支付查询请求参数，Query() 使用，out_trade_no 与 pay_trace 必填其一
+type QueryRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"`
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`
+	OutTradeNo    string        `json:"out_trade_no,omitempty" validate:"required_without=pay_trace,maxlen=64"`
+	PayTrace      string        `json:"pay_trace,omitempty" validate:"maxlen=32"` // 当前支付终端流水号
+	PayTime       string        `json:"pay_time,omitempty" validate:"len=14"`     // 当前支付终端交易时间
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// RefundRequest 退款申请请求参数，Refund() 使用
+type RefundRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"` // 退款单号
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`
+	RefundFee     int64         `json:"refund_fee" validate:"required,gt=0"`
+	OutTradeNo    string        `json:"out_trade_no" validate:"required,maxlen=64"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// QueryRefundRequest 退款订单查询请求参数，QueryRefund() 使用，out_refund_no 与 pay_trace 必填其一
+type QueryRefundRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"`
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`
+	OutRefundNo   string        `json:"out_refund_no,omitempty" validate:"required_without=pay_trace,maxlen=64"`
+	PayTrace      string        `json:"pay_trace,omitempty" validate:"maxlen=32"` // 退款终端流水号
+	PayTime       string        `json:"pay_time,omitempty" validate:"len=14"`     // 退款终端交易时间
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// CloseRequest 关闭订单请求参数，Close() 使用
+type CloseRequest struct {
+	PayType       string        `json:"pay_type" validate:"required,enum=010|020|060|100|110"`
+	TerminalTrace string        `json:"terminal_trace" validate:"required,maxlen=32"`
+	TerminalTime  string        `json:"terminal_time" validate:"len=14"`
+	OutTradeNo    string        `json:"out_trade_no" validate:"required,maxlen=64"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// BodyMap 校验并转换为 MiniPay() 的请求参数
+func (r *MiniPayRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 BarcodePay() 的请求参数
+func (r *BarcodePayRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 Query() 的请求参数
+func (r *QueryRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 Refund() 的请求参数
+func (r *RefundRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 QueryRefund() 的请求参数
+func (r *QueryRefundRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+// BodyMap 校验并转换为 Close() 的请求参数
+func (r *CloseRequest) BodyMap() (gopay.BodyMap, error) {
+	req := *r
+	req.TerminalTime = terminalTime(r.TerminalTime)
+	return buildBodyMap(&req, r.Extra)
+}
+
+func terminalTime(t string) string {
+	if t == gopay.NULL {
+		return time.Now().Format("20060102150405")
+	}
+	return t
+}
+
+func buildBodyMap(req any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	if err := gopay.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return gopay.StructToBodyMap(req, extra)
+}
@@ -0,0 +1,66 @@
+package saobei
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     interface{ BodyMap() (gopay.BodyMap, error) }
+		params  string // 不含 terminal_time
+		wantErr string
+	}{
+		{"mini pay", &MiniPayRequest{PayType: PayTypeWX, TerminalIp: "127.0.0.1", TerminalTrace: "larry01", TotalFee: 1, SubAppid: "wx01", OpenId: "openid",
+			Extra: gopay.BodyMap{"attach": "a"}},
+			`{"attach":"a","open_id":"openid","pay_type":"010","sub_appid":"wx01","terminal_ip":"127.0.0.1","terminal_trace":"larry01","total_fee":1}`, ""},
+		{"barcode pay", &BarcodePayRequest{PayType: PayTypeAli, TerminalIp: "127.0.0.1", TerminalTrace: "larry02", TotalFee: 100, AuthNo: "132038911197761804"},
+			`{"auth_no":"132038911197761804","pay_type":"020","terminal_ip":"127.0.0.1","terminal_trace":"larry02","total_fee":100}`, ""},
+		{"query", &QueryRequest{PayType: PayTypeWX, TerminalTrace: "q01", PayTrace: "larry02", PayTime: "20231008133303"},
+			`{"pay_time":"20231008133303","pay_trace":"larry02","pay_type":"010","terminal_trace":"q01"}`, ""},
+		{"refund", &RefundRequest{PayType: PayTypeWX, TerminalTrace: "r01", RefundFee: 1, OutTradeNo: "443505910021123100813330300001"},
+			`{"out_trade_no":"443505910021123100813330300001","pay_type":"010","refund_fee":1,"terminal_trace":"r01"}`, ""},
+		{"query refund", &QueryRefundRequest{PayType: PayTypeWX, TerminalTrace: "qr01", OutRefundNo: "1111111"},
+			`{"out_refund_no":"1111111","pay_type":"010","terminal_trace":"qr01"}`, ""},
+		{"close", &CloseRequest{PayType: PayTypeWX, TerminalTrace: "c01", OutTradeNo: "443505910021123100813330300001"},
+			`{"out_trade_no":"443505910021123100813330300001","pay_type":"010","terminal_trace":"c01"}`, ""},
+		{"pay type", &MiniPayRequest{PayType: "030", TerminalIp: "127.0.0.1", TerminalTrace: "larry01", TotalFee: 1, SubAppid: "wx01", OpenId: "openid"},
+			"", "[invalid parameter], pay_type: must be one of 010|020|060|100|110"},
+		{"terminal time", &BarcodePayRequest{PayType: PayTypeWX, TerminalIp: "127.0.0.1", TerminalTrace: "larry02", TerminalTime: "2023", TotalFee: 1, AuthNo: "1"},
+			"", "[invalid parameter], terminal_time: length must be 14"},
+		{"query without trade no", &QueryRequest{PayType: PayTypeWX, TerminalTrace: "q01"},
+			"", "[invalid parameter], out_trade_no: is required when pay_trace is empty"},
+		{"close without trade no", &CloseRequest{PayType: PayTypeWX, TerminalTrace: "c01"},
+			"", "[invalid parameter], out_trade_no: is required"},
+	}
+	for _, tt := range tests {
+		bm, err := tt.req.BodyMap()
+		if tt.wantErr != "" {
+			if !errors.Is(err, gopay.ParamValidateErr) || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		// terminal_time 为空时使用当前时间
+		if len(bm.GetString("terminal_time")) != 14 {
+			t.Errorf("%s: terminal_time = %s", tt.name, bm.GetString("terminal_time"))
+		}
+		bm.Remove("terminal_time")
+		if p := bm.JsonBody(); p != tt.params {
+			t.Errorf("%s: params = %s", tt.name, p)
+		}
+	}
+
+	// 不修改请求结构体
+	req := &RefundRequest{PayType: PayTypeWX, TerminalTrace: "r01", RefundFee: 1, OutTradeNo: "1"}
+	if _, err := req.BodyMap(); err != nil || req.TerminalTime != "" {
+		t.Errorf("terminal_time = %s, err = %v", req.TerminalTime, err)
+	}
+}
@@ -48,6 +48,15 @@ func NewClient(instNo, key, merchantNo, terminalId, accessToken string, isProd b
 	}, nil
 }
 
+// SetHttpClient 设置自定义的xhttp.Client
+// 本渠道未提供 SetRetryPolicy()：client 的重试策略 Classifier 为空时按 xhttp.DefaultRetryClassifier 判断，
+// 下单、退款等 POST 请求仅在连接未建立时重试，请勿设置 xhttp.IdempotentRetryClassifier，避免重复支付、退款
+func (c *Client) SetHttpClient(client *xhttp.Client) {
+	if client != nil {
+		c.hc = client
+	}
+}
+
 // pubParamsHandle 公共参数处理
 func (c *Client) pubParamsHandle(bm gopay.BodyMap) gopay.BodyMap {
 	if ver := bm.GetString("pay_ver"); ver == gopay.NULL {
@@ -70,12 +79,12 @@ func (c *Client) pubParamsHandle(bm gopay.BodyMap) gopay.BodyMap {
 // doPost 发起请求
 func (c *Client) doPost(ctx context.Context, path string, bm gopay.BodyMap) (bs []byte, err error) {
 	param := c.pubParamsHandle(bm)
-	xlog.Debugf("saobeiParam:%+v", param.JsonBody())
+	xlog.Debugf("saobeiParam:%s", gopay.DefaultRedactor.Redact(param.JsonBody()))
 	url := baseUrl
 	if !c.isProd {
 		url = sandboxBaseUrl
 	}
-	res, bs, err := c.hc.Req(xhttp.TypeJSON).Post(url + path).SendBodyMap(param).EndBytes(ctx)
+	res, bs, err := c.hc.Req(xhttp.TypeJSON).SetApi(gopay.ProviderSaobei, path).Post(url + path).SendBodyMap(param).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -138,3 +138,33 @@ type QueryRefundRsp struct {
 	PayTrace                  string `json:"pay_trace"`                    //退款终端流水号
 	PayTime                   string `json:"pay_time"`                     //退款终端交易时间
 }
+
+// NotifyRequest 支付结果通知
+type NotifyRequest struct {
+	RspBase
+	PayType        string `json:"pay_type"`         //支付方式，010微信，020支付宝
+	UserId         string `json:"user_id"`          //付款方用户id，“微信openid”、“支付宝账户”
+	MerchantName   string `json:"merchant_name"`    //商户名称
+	MerchantNo     string `json:"merchant_no"`      //商户号
+	TerminalId     string `json:"terminal_id"`      //终端号
+	TerminalTrace  string `json:"terminal_trace"`   //终端流水号，商户系统的订单号，系统原样返回
+	TerminalTime   string `json:"terminal_time"`    //终端交易时间，yyyyMMddHHmmss，全局统一时间格式，系统原样返回
+	TotalFee       string `json:"total_fee"`        //金额，单位分
+	EndTime        string `json:"end_time"`         //支付完成时间，yyyyMMddHHmmss，全局统一时间格式
+	OutTradeNo     string `json:"out_trade_no"`     //平台唯一订单号
+	ChannelTradeNo string `json:"channel_trade_no"` //通道订单号，微信订单号、支付宝订单号等
+	Attach         string `json:"attach"`           //附加数据,原样返回
+	ReceiptFee     string `json:"receipt_fee"`      //商家应结算金额,单位分
+}
+
+// CloseRsp 关闭订单
+type CloseRsp struct {
+	RspBase
+	PayType       string `json:"pay_type"`       //支付方式，010微信，020支付宝
+	MerchantName  string `json:"merchant_name"`  //商户名称
+	MerchantNo    string `json:"merchant_no"`    //商户号
+	TerminalId    string `json:"terminal_id"`    //终端号
+	TerminalTrace string `json:"terminal_trace"` //终端流水号，商户系统的订单号，系统原样返回
+	TerminalTime  string `json:"terminal_time"`  //终端交易时间，yyyyMMddHHmmss，全局统一时间格式，系统原样返回
+	OutTradeNo    string `json:"out_trade_no"`   //平台唯一订单号
+}
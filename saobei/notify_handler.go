@@ -0,0 +1,71 @@
+package saobei
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/w6xian/gopay"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 扫呗支付结果通知处理器，实现 http.Handler
+// 依次完成：解析 -> 验签 -> OnPay -> 应答 {"return_code":"01","return_msg":"success"}
+// 回调返回 error 时应答 return_code=02，扫呗会按策略重试通知
+type NotifyHandler struct {
+	client  *Client
+	onPay   func(ctx context.Context, notifyReq *NotifyRequest) error
+	onError func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化支付结果通知处理器，验签使用 client 的 access_token
+func NewNotifyHandler(client *Client) *NotifyHandler {
+	return &NotifyHandler{client: client}
+}
+
+// OnPay 注册支付结果通知回调
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		_, _ = w.Write([]byte(`{"return_code":"02","return_msg":"fail"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"return_code":"01","return_msg":"success"}`))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	bs, err := io.ReadAll(io.LimitReader(req.Body, int64(3<<20)))
+	defer req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if err = h.client.verifySign(bs); err != nil {
+		return err
+	}
+	notifyReq := new(NotifyRequest)
+	if err = json.Unmarshal(bs, notifyReq); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if h.onPay != nil {
+		return h.onPay(req.Context(), notifyReq)
+	}
+	return nil
+}
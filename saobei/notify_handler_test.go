@@ -0,0 +1,59 @@
+package saobei
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	bm := make(gopay.BodyMap)
+	bm.Set("return_code", ResultCodeSuccess).
+		Set("return_msg", "支付成功").
+		Set("result_code", ResultCodeSuccess).
+		Set("pay_type", PayTypeWX).
+		Set("merchant_no", client.merchantNo).
+		Set("terminal_id", client.terminalId).
+		Set("terminal_trace", "GZ202001101420131999").
+		Set("total_fee", "1").
+		Set("out_trade_no", "300520200110142013000012345")
+	bm.Set("key_sign", client.getRsaSign(bm))
+	body, err := json.Marshal(bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *NotifyRequest
+	h := NewNotifyHandler(client).
+		OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Debugf("notify err: %v", err)
+		})
+	serve := func(body []byte) string {
+		req := httptest.NewRequest(http.MethodPost, "/notify/saobei", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if ack := serve(body); ack != `{"return_code":"01","return_msg":"success"}` {
+		t.Fatalf("ack = %s, want success", ack)
+	}
+	if got == nil || got.TerminalTrace != "GZ202001101420131999" {
+		t.Fatalf("OnPay got: %+v", got)
+	}
+
+	// 篡改订单号，验签失败
+	tampered := bytes.Replace(body, []byte("GZ202001101420131999"), []byte("GZ202001101420132000"), 1)
+	if ack := serve(tampered); ack != `{"return_code":"02","return_msg":"fail"}` {
+		t.Errorf("tampered ack = %s, want fail", ack)
+	}
+}
@@ -109,3 +109,23 @@ func (c *Client) QueryRefund(ctx context.Context, bm gopay.BodyMap) (rsp *QueryR
 	}
 	return rsp, c.verifySign(bs)
 }
+
+// Close 关闭订单 https://help.lcsw.cn/xrmpic/tisnldchblgxohfl/rinsc3
+func (c *Client) Close(ctx context.Context, bm gopay.BodyMap) (rsp *CloseRsp, err error) {
+	err = bm.CheckEmptyError("pay_type", "terminal_trace", "terminal_time", "out_trade_no")
+	if err != nil {
+		return nil, err
+	}
+	var bs []byte
+	if bs, err = c.doPost(ctx, closePath, bm); err != nil {
+		return nil, err
+	}
+	rsp = new(CloseRsp)
+	if err = json.Unmarshal(bs, rsp); err != nil {
+		return nil, fmt.Errorf("[%w], bytes: %s", gopay.UnmarshalErr, string(bs))
+	}
+	if err := bizErrCheck(rsp.RspBase); err != nil {
+		return nil, err
+	}
+	return rsp, c.verifySign(bs)
+}
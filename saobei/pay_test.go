@@ -113,3 +113,20 @@ func TestClient_QueryRefund(t *testing.T) {
 		return
 	}
 }
+
+// 关闭订单
+func TestClient_Close(t *testing.T) {
+	// 请求参数
+	bm := make(gopay.BodyMap)
+	bm.Set("pay_type", "010").
+		Set("terminal_trace", "larry02456").
+		Set("terminal_time", "20231008133303").
+		Set("out_trade_no", "443505910021123100813330300001")
+
+	resp, err := client.Close(ctx, bm)
+	xlog.Debugf("saobeiRsp:%+v", resp)
+	if err != nil {
+		xlog.Errorf("%+v", err)
+		return
+	}
+}
@@ -0,0 +1,24 @@
+package saobei
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// NormalizeTradeStatus 扫呗 trade_state 转统一订单状态
+func NormalizeTradeStatus(tradeState string) gopay.TradeStatus {
+	switch tradeState {
+	case TradeStatusNotPay:
+		return gopay.TradeStatusPending
+	case TradeStatusUserPaying:
+		return gopay.TradeStatusPaying
+	case TradeStatusSuccess:
+		return gopay.TradeStatusSucceeded
+	case TradeStatusClosed, TradeStatusRevoked, TradeStatusNoPay:
+		return gopay.TradeStatusClosed
+	case TradeStatusRefund:
+		return gopay.TradeStatusRefunded
+	case TradeStatusPayError:
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
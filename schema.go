@@ -0,0 +1,458 @@
+package gopay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValueType BodyMap 字段值类型
+type ValueType uint8
+
+const (
+	TypeAny     ValueType = iota // 不校验类型
+	TypeString                   // 字符串
+	TypeInt                      // 整数，如微信 amount.total
+	TypeNumber                   // 数字
+	TypeDecimal                  // 十进制金额，字符串或数字，最多 2 位小数，如支付宝 total_amount
+	TypeBool                     // 布尔
+	TypeObject                   // 对象，BodyMap、SetBodyMap() 设置的值、map 或结构体
+	TypeArray                    // 数组，切片
+)
+
+// Field BodyMap 字段规则，通过 String()、Int()、Object()、Array() 等创建，链式设置规则
+type Field struct {
+	name            string
+	typ             ValueType
+	required        bool
+	requiredWithout []string
+	minLen, maxLen  int
+	length          int
+	gt, min, max    *float64
+	pattern         *regexp.Regexp
+	enum            []string
+	fields          []*Field // TypeObject 的子字段
+	items           *Field   // TypeArray 的元素规则
+}
+
+func newField(name string, typ ValueType) *Field {
+	return &Field{name: name, typ: typ}
+}
+
+func Any(name string) *Field     { return newField(name, TypeAny) }
+func String(name string) *Field  { return newField(name, TypeString) }
+func Int(name string) *Field     { return newField(name, TypeInt) }
+func Number(name string) *Field  { return newField(name, TypeNumber) }
+func Decimal(name string) *Field { return newField(name, TypeDecimal) }
+func Bool(name string) *Field    { return newField(name, TypeBool) }
+
+// Object 对象字段，fields 为子字段规则
+func Object(name string, fields ...*Field) *Field {
+	f := newField(name, TypeObject)
+	f.fields = fields
+	return f
+}
+
+// Array 数组字段，items 为元素规则（名称忽略），为 nil 时不校验元素
+func Array(name string, items *Field) *Field {
+	f := newField(name, TypeArray)
+	f.items = items
+	return f
+}
+
+// Name 字段名
+func (f *Field) Name() string {
+	return f.name
+}
+
+// Required 必填，值不能为 nil、空字符串、空数组或空对象
+func (f *Field) Required() *Field {
+	f.required = true
+	return f
+}
+
+// RequiredWithout 同级字段 names 均为空时必填，用于 out_trade_no 与 trade_no 必填其一
+func (f *Field) RequiredWithout(names ...string) *Field {
+	f.requiredWithout = names
+	return f
+}
+
+// MinLen 字符串最少字符数，或数组最少元素个数
+func (f *Field) MinLen(n int) *Field {
+	f.minLen = n
+	return f
+}
+
+// MaxLen 字符串最多字符数（非字节数），或数组最多元素个数
+func (f *Field) MaxLen(n int) *Field {
+	f.maxLen = n
+	return f
+}
+
+// Len 字符串字符数或数组元素个数必须为 n
+func (f *Field) Len(n int) *Field {
+	f.length = n
+	return f
+}
+
+// Gt 数字或金额必须大于 n
+func (f *Field) Gt(n float64) *Field {
+	f.gt = &n
+	return f
+}
+
+// Min 数字或金额必须大于等于 n
+func (f *Field) Min(n float64) *Field {
+	f.min = &n
+	return f
+}
+
+// Max 数字或金额必须小于等于 n
+func (f *Field) Max(n float64) *Field {
+	f.max = &n
+	return f
+}
+
+// Pattern 字符串必须匹配正则表达式，expr 不合法时 panic
+func (f *Field) Pattern(expr string) *Field {
+	f.pattern = regexp.MustCompile(expr)
+	return f
+}
+
+// Enum 枚举值，数字按十进制字符串比较
+func (f *Field) Enum(values ...string) *Field {
+	f.enum = values
+	return f
+}
+
+// Schema BodyMap 的声明式校验规则，未声明的字段不校验
+//
+//	var schema = gopay.NewSchema(
+//		gopay.String("out_trade_no").Required().MaxLen(32),
+//		gopay.Object("amount",
+//			gopay.Int("total").Required().Gt(0),
+//			gopay.String("currency").Enum("CNY"),
+//		).Required(),
+//	)
+//	err := bm.Validate(schema) // [invalid parameter], amount.total: must be > 0
+type Schema struct {
+	fields []*Field
+}
+
+// NewSchema 创建 Schema
+func NewSchema(fields ...*Field) *Schema {
+	return &Schema{fields: fields}
+}
+
+// Extend 复制并追加字段规则，与已有字段同名时替换，原 Schema 不变
+func (s *Schema) Extend(fields ...*Field) *Schema {
+	ns := &Schema{fields: make([]*Field, 0, len(s.fields)+len(fields))}
+	ns.fields = append(ns.fields, s.fields...)
+	for _, f := range fields {
+		replaced := false
+		for i, old := range ns.fields {
+			if old.name == f.name {
+				ns.fields[i], replaced = f, true
+				break
+			}
+		}
+		if !replaced {
+			ns.fields = append(ns.fields, f)
+		}
+	}
+	return ns
+}
+
+// Omit 复制并移除字段规则，原 Schema 不变
+func (s *Schema) Omit(names ...string) *Schema {
+	ns := &Schema{fields: make([]*Field, 0, len(s.fields))}
+	for _, f := range s.fields {
+		if !slices.Contains(names, f.name) {
+			ns.fields = append(ns.fields, f)
+		}
+	}
+	return ns
+}
+
+// Require 复制并将 paths 对应的字段设为必填，嵌套字段以 . 分隔，如 scene_info.h5_info，原 Schema 不变
+// 字段不存在时 panic
+func (s *Schema) Require(paths ...string) *Schema {
+	ns := &Schema{fields: slices.Clone(s.fields)}
+	for _, path := range paths {
+		fields := ns.fields
+		names := strings.Split(path, ".")
+		for j, name := range names {
+			i := slices.IndexFunc(fields, func(f *Field) bool { return f.name == name })
+			if i < 0 {
+				panic("gopay: schema field " + path + " not found")
+			}
+			f := *fields[i]
+			f.fields = slices.Clone(f.fields)
+			f.required = f.required || j == len(names)-1
+			fields[i] = &f
+			fields = f.fields
+		}
+	}
+	return ns
+}
+
+// Check 校验 BodyMap，返回所有字段错误，字段路径如 amount.total、detail.goods_detail[0].quantity
+func (s *Schema) Check(bm BodyMap) (errs ValidationError) {
+	errs.checkObject("", bm, s.fields)
+	return errs
+}
+
+// Validate 同 Check()，没有字段错误时返回 nil
+func (s *Schema) Validate(bm BodyMap) error {
+	return s.Check(bm).Err()
+}
+
+// Validate 按 Schema 校验参数，没有字段错误时返回 nil
+func (bm BodyMap) Validate(s *Schema) error {
+	return s.Validate(bm)
+}
+
+func (e *ValidationError) checkObject(path string, obj map[string]any, fields []*Field) {
+	for _, f := range fields {
+		field := f.name
+		if path != NULL {
+			field = path + "." + f.name
+		}
+		e.checkValue(field, obj[f.name], f, obj)
+	}
+}
+
+func (e *ValidationError) checkValue(field string, v any, f *Field, siblings map[string]any) {
+	if isEmpty(v) {
+		if f.required {
+			e.AddMissing(field, "is required")
+		} else if len(f.requiredWithout) > 0 {
+			for _, name := range f.requiredWithout {
+				if !isEmpty(siblings[name]) {
+					return
+				}
+			}
+			e.AddMissing(field, fmt.Sprintf("is required when %s is empty", strings.Join(f.requiredWithout, ", ")))
+		} else if obj, ok := toObject(v); ok && f.typ == TypeObject {
+			// 传入了空对象，如数组中的 {}，仍校验子字段
+			e.checkObject(field, obj, f.fields)
+		}
+		return
+	}
+	switch f.typ {
+	case TypeString:
+		s, ok := v.(string)
+		if !ok {
+			e.Add(field, "must be a string")
+			return
+		}
+		e.checkLen(field, utf8.RuneCountInString(s), f)
+		if f.pattern != nil && !f.pattern.MatchString(s) {
+			e.Add(field, "must match "+f.pattern.String())
+		}
+		e.checkEnum(field, s, f)
+	case TypeInt, TypeNumber:
+		n, isInt, ok := toNumber(v)
+		if !ok || f.typ == TypeInt && !isInt {
+			if f.typ == TypeInt {
+				e.Add(field, "must be an integer")
+			} else {
+				e.Add(field, "must be a number")
+			}
+			return
+		}
+		e.checkRange(field, n, f)
+		e.checkEnum(field, strconv.FormatFloat(n, 'f', -1, 64), f)
+	case TypeDecimal:
+		var s string
+		switch val := v.(type) {
+		case string:
+			s = val
+		case json.Number:
+			s = val.String()
+		default:
+			n, _, ok := toNumber(v)
+			if !ok {
+				e.Add(field, "must be a decimal amount")
+				return
+			}
+			s = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		m, err := ParseMoney(s, DefaultCurrency)
+		if err != nil {
+			e.Add(field, "must be a decimal amount with at most 2 decimal places")
+			return
+		}
+		n, _ := strconv.ParseFloat(m.Decimal(), 64)
+		e.checkRange(field, n, f)
+	case TypeBool:
+		if _, ok := v.(bool); !ok {
+			e.Add(field, "must be a boolean")
+		}
+	case TypeObject:
+		obj, ok := toObject(v)
+		if !ok {
+			e.Add(field, "must be an object")
+			return
+		}
+		e.checkObject(field, obj, f.fields)
+	case TypeArray:
+		items, ok := toArray(v)
+		if !ok {
+			e.Add(field, "must be an array")
+			return
+		}
+		e.checkLen(field, len(items), f)
+		if f.items != nil {
+			for i, item := range items {
+				e.checkValue(fmt.Sprintf("%s[%d]", field, i), item, f.items, nil)
+			}
+		}
+	default:
+		e.checkEnum(field, fmt.Sprint(v), f)
+	}
+}
+
+func (e *ValidationError) checkLen(field string, l int, f *Field) {
+	if f.length > 0 && l != f.length {
+		e.Add(field, fmt.Sprintf("length must be %d", f.length))
+	}
+	if f.minLen > 0 && l < f.minLen {
+		e.Add(field, fmt.Sprintf("length must be >= %d", f.minLen))
+	}
+	if f.maxLen > 0 && l > f.maxLen {
+		e.Add(field, fmt.Sprintf("length must be <= %d", f.maxLen))
+	}
+}
+
+func (e *ValidationError) checkRange(field string, n float64, f *Field) {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	if f.gt != nil && n <= *f.gt {
+		e.Add(field, "must be > "+format(*f.gt))
+	}
+	if f.min != nil && n < *f.min {
+		e.Add(field, "must be >= "+format(*f.min))
+	}
+	if f.max != nil && n > *f.max {
+		e.Add(field, "must be <= "+format(*f.max))
+	}
+}
+
+func (e *ValidationError) checkEnum(field, v string, f *Field) {
+	if len(f.enum) == 0 {
+		return
+	}
+	for _, item := range f.enum {
+		if v == item {
+			return
+		}
+	}
+	e.Add(field, "must be one of "+strings.Join(f.enum, "|"))
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch val := v.(type) {
+	case string:
+		return val == NULL
+	case BodyMap:
+		return len(val) == 0
+	case structObject:
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
+
+// toNumber 转换数字，isInt 表示是否为整数
+func toNumber(v any) (n float64, isInt, ok bool) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return float64(i), true, true
+		}
+		f, err := val.Float64()
+		return f, false, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true, true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		return f, f == math.Trunc(f), true
+	}
+	return 0, false, false
+}
+
+// toObject 转换对象，非 map 的值（如结构体）经 json 转换
+func toObject(v any) (map[string]any, bool) {
+	switch val := v.(type) {
+	case BodyMap:
+		return val, true
+	case map[string]any:
+		return val, true
+	case structObject:
+		return val, true
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		obj := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			obj[iter.Key().String()] = iter.Value().Interface()
+		}
+		return obj, true
+	case reflect.Struct:
+		var obj map[string]any
+		if bs, err := json.Marshal(v); err == nil && decodeJSON(bs, &obj) == nil {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+func toArray(v any) ([]any, bool) {
+	if val, ok := v.([]any); ok {
+		return val, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+func decodeJSON(bs []byte, ptr any) error {
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	return dec.Decode(ptr)
+}
@@ -0,0 +1,86 @@
+package gopay
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var testSchema = NewSchema(
+	String("out_trade_no").Required().MinLen(6).MaxLen(32).Pattern(`^[0-9A-Za-z_\-|*]+$`),
+	String("transaction_id").RequiredWithout("out_trade_no"),
+	Decimal("total_amount").Gt(0),
+	Bool("support_fapiao"),
+	Object("amount",
+		Int("total").Required().Gt(0),
+		String("currency").Enum("CNY"),
+	).Required(),
+	Object("detail",
+		Array("goods_detail", Object("",
+			String("merchant_goods_id").Required(),
+			Int("quantity").Required().Gt(0),
+		)).MaxLen(2),
+	),
+	Array("query_options", String("").MaxLen(16)),
+)
+
+func TestSchemaValidate(t *testing.T) {
+	bm := make(BodyMap)
+	bm.Set("out_trade_no", "GZ2023-0001").
+		Set("total_amount", "0.01").
+		Set("support_fapiao", true).
+		SetBodyMap("amount", func(b BodyMap) {
+			b.Set("total", 1).
+				Set("currency", "CNY")
+		}).
+		SetBodyMap("detail", func(b BodyMap) {
+			b.Set("goods_detail", []BodyMap{{"merchant_goods_id": "g1", "quantity": json.Number("2")}})
+		}).
+		Set("query_options", []string{"fund_bill_list"}).
+		Set("unknown", 1)
+	if err := bm.Validate(testSchema); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	bm = make(BodyMap)
+	bm.Set("out_trade_no", "GZ#01").
+		Set("total_amount", 0.001).
+		Set("support_fapiao", "true").
+		SetBodyMap("amount", func(b BodyMap) {
+			b.Set("total", 0).
+				Set("currency", "USD")
+		}).
+		Set("detail", map[string]any{
+			"goods_detail": []map[string]any{{"quantity": 1.5}, {"merchant_goods_id": "g2", "quantity": 1}, {}},
+		}).
+		Set("query_options", []any{"fund_bill_list_detail", 1})
+	err := testSchema.Validate(bm)
+	if !errors.Is(err, ParamValidateErr) || !errors.Is(err, MissParamErr) {
+		t.Fatalf("err = %v", err)
+	}
+	want := "[invalid parameter], out_trade_no: length must be >= 6; out_trade_no: must match ^[0-9A-Za-z_\\-|*]+$; " +
+		"total_amount: must be a decimal amount with at most 2 decimal places; support_fapiao: must be a boolean; " +
+		"amount.total: must be > 0; amount.currency: must be one of CNY; detail.goods_detail: length must be <= 2; " +
+		"detail.goods_detail[0].merchant_goods_id: is required; detail.goods_detail[0].quantity: must be an integer; " +
+		"detail.goods_detail[2].merchant_goods_id: is required; detail.goods_detail[2].quantity: is required; query_options[0]: length must be <= 16; query_options[1]: must be a string"
+	if err.Error() != want {
+		t.Errorf("err = %s\nwant  %s", err, want)
+	}
+
+	err = testSchema.Validate(BodyMap{"amount": "1"})
+	if err == nil || err.Error() != "[invalid parameter], out_trade_no: is required; transaction_id: is required when out_trade_no is empty; amount: must be an object" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSchemaExtend(t *testing.T) {
+	base := NewSchema(String("a").Required(), String("b"))
+	ext := base.Extend(String("b").Required(), Int("c").Max(10))
+	bm := BodyMap{"a": "x", "c": 11}
+	if err := base.Validate(bm); err != nil {
+		t.Errorf("base err = %v", err)
+	}
+	if err := ext.Validate(bm); err == nil || err.Error() != "[invalid parameter], b: is required; c: must be <= 10" {
+		t.Errorf("ext err = %v", err)
+	}
+}
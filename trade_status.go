@@ -0,0 +1,69 @@
+package gopay
+
+import (
+	"fmt"
+)
+
+// TradeStatus 跨渠道统一的订单状态
+// 各渠道包提供 NormalizeTradeStatus() 将渠道原始状态转为 TradeStatus
+type TradeStatus string
+
+const (
+	TradeStatusUnknown           TradeStatus = "UNKNOWN"            // 无法识别的渠道状态
+	TradeStatusPending           TradeStatus = "PENDING"            // 已下单，待支付
+	TradeStatusPaying            TradeStatus = "PAYING"             // 用户支付中
+	TradeStatusSucceeded         TradeStatus = "SUCCEEDED"          // 支付成功
+	TradeStatusClosed            TradeStatus = "CLOSED"             // 已关闭/已撤销
+	TradeStatusRefunded          TradeStatus = "REFUNDED"           // 已全额退款（或渠道无法区分的转入退款）
+	TradeStatusPartiallyRefunded TradeStatus = "PARTIALLY_REFUNDED" // 已部分退款
+	TradeStatusFailed            TradeStatus = "FAILED"             // 支付失败
+)
+
+// 合法的状态流转，key 为当前状态，value 为可流转到的下一状态
+var tradeStatusTransitions = map[TradeStatus][]TradeStatus{
+	TradeStatusPending: {TradeStatusPaying, TradeStatusSucceeded, TradeStatusClosed, TradeStatusFailed},
+	TradeStatusPaying:  {TradeStatusSucceeded, TradeStatusClosed, TradeStatusFailed},
+	// 支付宝全额退款后交易状态为 TRADE_CLOSED
+	TradeStatusSucceeded:         {TradeStatusRefunded, TradeStatusPartiallyRefunded, TradeStatusClosed},
+	TradeStatusPartiallyRefunded: {TradeStatusPartiallyRefunded, TradeStatusRefunded, TradeStatusClosed},
+	// 付款码支付失败后，渠道会撤销订单
+	TradeStatusFailed:   {TradeStatusClosed},
+	TradeStatusClosed:   {},
+	TradeStatusRefunded: {},
+}
+
+// IsFinal 是否为终态，终态不会再发生变化
+func (s TradeStatus) IsFinal() bool {
+	return s == TradeStatusClosed || s == TradeStatusRefunded
+}
+
+// IsPaid 买家是否已付款（含付款后退款）
+func (s TradeStatus) IsPaid() bool {
+	return s == TradeStatusSucceeded || s == TradeStatusRefunded || s == TradeStatusPartiallyRefunded
+}
+
+// CanTransitionTo 判断能否从当前状态流转到 next
+// 状态不变视为合法；当前状态为 TradeStatusUnknown 时，任意已知状态均合法
+func (s TradeStatus) CanTransitionTo(next TradeStatus) bool {
+	if next == TradeStatusUnknown {
+		return s == TradeStatusUnknown
+	}
+	if s == next || s == TradeStatusUnknown {
+		return true
+	}
+	for _, v := range tradeStatusTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTradeStatusTransition 校验订单状态流转，非法流转返回 TradeStatusTransitionErr
+// 常用于查询结果、异步通知乱序到达时，判断是否应更新本地订单状态
+func ValidateTradeStatusTransition(from, to TradeStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("[%w]: %s -> %s", TradeStatusTransitionErr, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gopay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-pay/xlog"
+)
+
+func TestTradeStatusTransition(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	cases := []struct {
+		from TradeStatus
+		to   TradeStatus
+		ok   bool
+	}{
+		{TradeStatusPending, TradeStatusPending, true},
+		{TradeStatusPending, TradeStatusPaying, true},
+		{TradeStatusPending, TradeStatusSucceeded, true},
+		{TradeStatusPaying, TradeStatusFailed, true},
+		{TradeStatusSucceeded, TradeStatusPartiallyRefunded, true},
+		{TradeStatusPartiallyRefunded, TradeStatusRefunded, true},
+		{TradeStatusSucceeded, TradeStatusClosed, true},
+		{TradeStatusUnknown, TradeStatusRefunded, true},
+		{TradeStatusSucceeded, TradeStatusPending, false},
+		{TradeStatusClosed, TradeStatusSucceeded, false},
+		{TradeStatusRefunded, TradeStatusPartiallyRefunded, false},
+		{TradeStatusPending, TradeStatusRefunded, false},
+		{TradeStatusSucceeded, TradeStatusUnknown, false},
+	}
+	for _, v := range cases {
+		err := ValidateTradeStatusTransition(v.from, v.to)
+		if v.ok && err != nil {
+			t.Errorf("%s -> %s want ok, got: %v", v.from, v.to, err)
+		}
+		if !v.ok && !errors.Is(err, TradeStatusTransitionErr) {
+			t.Errorf("%s -> %s want TradeStatusTransitionErr, got: %v", v.from, v.to, err)
+		}
+	}
+	if !TradeStatusRefunded.IsFinal() || TradeStatusSucceeded.IsFinal() {
+		t.Error("IsFinal mismatch")
+	}
+	if !TradeStatusPartiallyRefunded.IsPaid() || TradeStatusClosed.IsPaid() {
+		t.Error("IsPaid mismatch")
+	}
+}
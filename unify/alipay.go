@@ -0,0 +1,187 @@
+package unify
+
+import (
+	"context"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay"
+)
+
+var _ Provider = (*Alipay)(nil)
+
+// Alipay 支付宝 适配器
+// 异步通知地址、同步跳转地址请通过 client.SetNotifyUrl()、client.SetReturnUrl() 设置
+type Alipay struct {
+	client *alipay.Client
+}
+
+// NewAlipay 初始化支付宝 适配器
+func NewAlipay(client *alipay.Client) *Alipay {
+	return &Alipay{client: client}
+}
+
+func (a *Alipay) Name() string {
+	return ProviderAlipay
+}
+
+// Pay 支持 SceneNative、SceneBarcode、SceneApp、SceneH5、ScenePage、SceneJSAPI、SceneMini
+func (a *Alipay) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	currency := currencyOrDefault(req.Currency)
+	if currency != defaultCurrency {
+		return nil, currencyErr(a.Name(), currency)
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("out_trade_no", req.OutTradeNo).
+		Set("total_amount", formatAmount(req.Amount, currency)).
+		Set("subject", subjectOf(req))
+	if req.Description != gopay.NULL {
+		bm.Set("body", req.Description)
+	}
+	rsp = &PayResponse{Provider: a.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	switch req.Scene {
+	case SceneNative:
+		aliRsp, err := a.client.TradePrecreate(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		rsp.CodeUrl, rsp.Raw = aliRsp.Response.QrCode, aliRsp
+	case SceneBarcode:
+		bm.Set("scene", "bar_code").
+			Set("auth_code", req.AuthCode)
+		aliRsp, err := a.client.TradePay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		rsp.TradeNo, rsp.Raw = aliRsp.Response.TradeNo, aliRsp
+		rsp.Status = gopay.TradeStatusSucceeded
+		// 10003：等待用户付款
+		if aliRsp.Response.Code == "10003" {
+			rsp.Status = gopay.TradeStatusPaying
+		}
+	case SceneApp:
+		orderStr, err := a.client.TradeAppPay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		rsp.PayParams, rsp.Raw = orderStr, orderStr
+	case SceneH5:
+		payUrl, err := a.client.TradeWapPay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		rsp.PayUrl, rsp.Raw = payUrl, payUrl
+	case ScenePage:
+		payUrl, err := a.client.TradePagePay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		rsp.PayUrl, rsp.Raw = payUrl, payUrl
+	case SceneJSAPI, SceneMini:
+		if req.OpenId != gopay.NULL {
+			bm.Set("buyer_id", req.OpenId)
+		}
+		aliRsp, err := a.client.TradeCreate(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, alipayErr(err)
+		}
+		// 前端使用 trade_no 调起支付
+		rsp.TradeNo, rsp.PayParams, rsp.Raw = aliRsp.Response.TradeNo, aliRsp.Response.TradeNo, aliRsp
+	default:
+		return nil, sceneErr(a.Name(), req.Scene)
+	}
+	return rsp, nil
+}
+
+func (a *Alipay) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	setTradeNo(bm, "out_trade_no", req.OutTradeNo, "trade_no", req.TradeNo)
+	aliRsp, err := a.client.TradeQuery(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, alipayErr(err)
+	}
+	order := aliRsp.Response
+	currency := defaultCurrency
+	if order.TransCurrency != gopay.NULL {
+		currency = order.TransCurrency
+	}
+	amount, err := parseAmount(order.TotalAmount, currency)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &OrderResponse{
+		Provider:    a.Name(),
+		OutTradeNo:  order.OutTradeNo,
+		TradeNo:     order.TradeNo,
+		Status:      alipay.NormalizeTradeStatus(order.TradeStatus),
+		RawStatus:   order.TradeStatus,
+		Amount:      amount,
+		Currency:    currency,
+		SuccessTime: order.SendPayDate,
+		Raw:         aliRsp,
+	}
+	return rsp, nil
+}
+
+func (a *Alipay) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	currency := currencyOrDefault(req.Currency)
+	bm := make(gopay.BodyMap)
+	setTradeNo(bm, "out_trade_no", req.OutTradeNo, "trade_no", req.TradeNo)
+	bm.Set("refund_amount", formatAmount(req.Amount, currency)).
+		Set("out_request_no", req.OutRefundNo)
+	if req.Reason != gopay.NULL {
+		bm.Set("refund_reason", req.Reason)
+	}
+	aliRsp, err := a.client.TradeRefund(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, alipayErr(err)
+	}
+	refund := aliRsp.Response
+	amount, err := parseAmount(refund.RefundFee, currency)
+	if err != nil {
+		return nil, err
+	}
+	rsp = &RefundResponse{
+		Provider:    a.Name(),
+		OutTradeNo:  refund.OutTradeNo,
+		TradeNo:     refund.TradeNo,
+		OutRefundNo: req.OutRefundNo,
+		Amount:      amount,
+		Currency:    currency,
+		RawStatus:   refund.FundChange,
+		Raw:         aliRsp,
+	}
+	return rsp, nil
+}
+
+func (a *Alipay) CloseOrder(ctx context.Context, req *CloseRequest) (err error) {
+	if err = req.check(); err != nil {
+		return err
+	}
+	bm := make(gopay.BodyMap)
+	setTradeNo(bm, "out_trade_no", req.OutTradeNo, "trade_no", req.TradeNo)
+	if _, err = a.client.TradeClose(ctx, mergeExtra(bm, req.Extra)); err != nil {
+		return alipayErr(err)
+	}
+	return nil
+}
+
+// 支付宝业务错误转为统一 BizErr，优先使用 sub_code
+func alipayErr(err error) error {
+	bizErr, ok := alipay.IsBizError(err)
+	if !ok {
+		return err
+	}
+	if bizErr.SubCode != gopay.NULL {
+		return &BizErr{Provider: ProviderAlipay, Code: bizErr.SubCode, Msg: bizErr.SubMsg}
+	}
+	return &BizErr{Provider: ProviderAlipay, Code: bizErr.Code, Msg: bizErr.Msg}
+}
@@ -0,0 +1,206 @@
+package unify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/allinpay"
+)
+
+var _ Provider = (*Allinpay)(nil)
+
+// Allinpay 通联收银宝 适配器
+// 商户订单号对应 reqsn，渠道订单号对应 trxid
+type Allinpay struct {
+	client *allinpay.Client
+}
+
+// NewAllinpay 初始化通联 适配器
+func NewAllinpay(client *allinpay.Client) *Allinpay {
+	return &Allinpay{client: client}
+}
+
+func (a *Allinpay) Name() string {
+	return ProviderAllinpay
+}
+
+// Pay 根据 Wallet、Scene 选择 paytype；SceneBarcode 走统一扫码接口，需在 Extra 中传入 terminfo
+func (a *Allinpay) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if currency := currencyOrDefault(req.Currency); currency != defaultCurrency {
+		return nil, currencyErr(a.Name(), currency)
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("reqsn", req.OutTradeNo).
+		Set("trxamt", strconv.FormatInt(req.Amount, 10))
+	if subject := subjectOf(req); subject != gopay.NULL {
+		bm.Set("body", subject)
+	}
+	rsp = &PayResponse{Provider: a.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	if req.Scene == SceneBarcode {
+		bm.Set("authcode", req.AuthCode)
+		apRsp, err := a.client.ScanPay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, allinpayErr(err)
+		}
+		rsp.TradeNo, rsp.Status, rsp.Raw = apRsp.Trxid, allinpay.NormalizeTradeStatus(apRsp.TrxStatus), apRsp
+		if rsp.Status == gopay.TradeStatusFailed {
+			return nil, &BizErr{Provider: a.Name(), Code: apRsp.TrxStatus, Msg: apRsp.ErrMsg}
+		}
+		return rsp, nil
+	}
+	payType, err := allinpayPayType(req.Wallet, req.Scene)
+	if err != nil {
+		return nil, err
+	}
+	bm.Set("paytype", payType)
+	if req.NotifyUrl != gopay.NULL {
+		bm.Set("notify_url", req.NotifyUrl)
+	}
+	if req.OpenId != gopay.NULL {
+		bm.Set("acct", req.OpenId)
+	}
+	apRsp, err := a.client.Pay(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, allinpayErr(err)
+	}
+	rsp.TradeNo, rsp.Raw = apRsp.Trxid, apRsp
+	if strings.HasPrefix(apRsp.TrxStatus, allinpay.TrxStatusFailPrefix) {
+		return nil, &BizErr{Provider: a.Name(), Code: apRsp.TrxStatus, Msg: apRsp.ErrMsg}
+	}
+	// 扫码类 payinfo 为二维码链接，其余为拉起支付的参数
+	if req.Scene == SceneNative {
+		rsp.CodeUrl = apRsp.PayInfo
+	} else {
+		rsp.PayParams = apRsp.PayInfo
+	}
+	return rsp, nil
+}
+
+func (a *Allinpay) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	orderType, no := allinpay.OrderTypeReqSN, req.OutTradeNo
+	if req.TradeNo != gopay.NULL {
+		orderType, no = allinpay.OrderTypeTrxId, req.TradeNo
+	}
+	apRsp, err := a.client.Query(ctx, orderType, no)
+	if err != nil {
+		return nil, allinpayErr(err)
+	}
+	rsp = &OrderResponse{
+		Provider:    a.Name(),
+		OutTradeNo:  apRsp.Reqsn,
+		TradeNo:     apRsp.Trxid,
+		Status:      allinpay.NormalizeTradeStatus(apRsp.TrxStatus),
+		RawStatus:   apRsp.TrxStatus,
+		Amount:      parseMinorAmount(apRsp.TrxAmt),
+		Currency:    defaultCurrency,
+		SuccessTime: apRsp.FinTime,
+		Raw:         apRsp,
+	}
+	return rsp, nil
+}
+
+func (a *Allinpay) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("reqsn", req.OutRefundNo).
+		Set("trxamt", strconv.FormatInt(req.Amount, 10))
+	setTradeNo(bm, "oldreqsn", req.OutTradeNo, "oldtrxid", req.TradeNo)
+	if req.Reason != gopay.NULL {
+		bm.Set("remark", req.Reason)
+	}
+	apRsp, err := a.client.Refund(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, allinpayErr(err)
+	}
+	if strings.HasPrefix(apRsp.TrxStatus, allinpay.TrxStatusFailPrefix) {
+		return nil, &BizErr{Provider: a.Name(), Code: apRsp.TrxStatus, Msg: apRsp.ErrMsg}
+	}
+	rsp = &RefundResponse{
+		Provider:    a.Name(),
+		OutTradeNo:  req.OutTradeNo,
+		TradeNo:     req.TradeNo,
+		OutRefundNo: apRsp.Reqsn,
+		RefundNo:    apRsp.Trxid,
+		Amount:      req.Amount,
+		Currency:    defaultCurrency,
+		RawStatus:   apRsp.TrxStatus,
+		Raw:         apRsp,
+	}
+	return rsp, nil
+}
+
+func (a *Allinpay) CloseOrder(ctx context.Context, req *CloseRequest) (err error) {
+	if err = req.check(); err != nil {
+		return err
+	}
+	bm := make(gopay.BodyMap)
+	setTradeNo(bm, "oldreqsn", req.OutTradeNo, "oldtrxid", req.TradeNo)
+	apRsp, err := a.client.Close(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return allinpayErr(err)
+	}
+	if apRsp.TrxStatus != allinpay.TrxStatusSuccess {
+		return &BizErr{Provider: a.Name(), Code: apRsp.TrxStatus, Msg: apRsp.RetMsg}
+	}
+	return nil
+}
+
+func allinpayPayType(wallet Wallet, scene Scene) (string, error) {
+	switch wallet {
+	case WalletWechat:
+		switch scene {
+		case SceneNative:
+			return allinpay.PayTypeWXScan, nil
+		case SceneJSAPI:
+			return allinpay.PayTypeWXJS, nil
+		case SceneMini:
+			return allinpay.PayTypeWXMini, nil
+		}
+	case WalletAlipay:
+		switch scene {
+		case SceneNative:
+			return allinpay.PayTypeAliScan, nil
+		case SceneJSAPI, SceneMini:
+			return allinpay.PayTypeAliJS, nil
+		case SceneApp:
+			return allinpay.PayTypeAliApp, nil
+		}
+	case WalletQQ:
+		switch scene {
+		case SceneNative:
+			return allinpay.PayTypeQQScan, nil
+		case SceneJSAPI:
+			return allinpay.PayTypeQQJS, nil
+		}
+	case WalletUnionPay:
+		switch scene {
+		case SceneNative:
+			return allinpay.PayTypeUniPay, nil
+		case SceneJSAPI:
+			return allinpay.PayTypeUniJS, nil
+		}
+	default:
+		return gopay.NULL, fmt.Errorf("[%w], %v", gopay.MissParamErr, "wallet")
+	}
+	return gopay.NULL, sceneErr(ProviderAllinpay, scene)
+}
+
+func allinpayErr(err error) error {
+	var bizErr *allinpay.BizErr
+	if errors.As(err, &bizErr) {
+		return &BizErr{Provider: ProviderAllinpay, Code: bizErr.Code, Msg: bizErr.Msg}
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package unify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+const defaultCurrency = gopay.DefaultCurrency
+
+func currencyOrDefault(currency string) string {
+	if currency == gopay.NULL {
+		return defaultCurrency
+	}
+	return strings.ToUpper(currency)
+}
+
+// formatAmount 最小单位金额 转 十进制字符串，如 CNY 1234 => "12.34"，JPY 1234 => "1234"
+func formatAmount(amount int64, currency string) string {
+	return gopay.NewMoney(amount, currency).Decimal()
+}
+
+// parseAmount 十进制字符串 转 最小单位金额，如 CNY "12.34" => 1234
+func parseAmount(value, currency string) (int64, error) {
+	if strings.TrimSpace(value) == gopay.NULL {
+		return 0, nil
+	}
+	m, err := gopay.ParseMoney(value, currency)
+	if err != nil {
+		return 0, fmt.Errorf("[%w]: %v", AmountErr, err)
+	}
+	return m.Minor(), nil
+}
+
+// 解析以最小单位表示的整数字符串金额，如 "1234"
+func parseMinorAmount(value string) int64 {
+	n, _ := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	return n
+}
@@ -0,0 +1,44 @@
+package unify
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	NotSupportedErr = errors.New("operation not supported by provider")
+	SceneErr        = errors.New("scene not supported by provider")
+	CurrencyErr     = errors.New("currency not supported by provider")
+	AmountErr       = errors.New("invalid amount")
+)
+
+// BizErr 渠道返回的业务错误（通信成功，但业务处理失败）
+type BizErr struct {
+	Provider string `json:"provider"`
+	Code     string `json:"code"`
+	Msg      string `json:"msg"`
+}
+
+func (e *BizErr) Error() string {
+	return fmt.Sprintf(`{"provider":"%s","code":"%s","msg":"%s"}`, e.Provider, e.Code, e.Msg)
+}
+
+func IsBizError(err error) (*BizErr, bool) {
+	var bizErr *BizErr
+	if errors.As(err, &bizErr) {
+		return bizErr, true
+	}
+	return nil, false
+}
+
+func sceneErr(provider string, scene Scene) error {
+	return fmt.Errorf("[%w]: %s %s", SceneErr, provider, scene)
+}
+
+func currencyErr(provider, currency string) error {
+	return fmt.Errorf("[%w]: %s %s", CurrencyErr, provider, currency)
+}
+
+func notSupportedErr(provider, op string) error {
+	return fmt.Errorf("[%w]: %s %s", NotSupportedErr, provider, op)
+}
@@ -0,0 +1,181 @@
+package unify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/lakala"
+)
+
+var _ Provider = (*Lakala)(nil)
+
+// Lakala 拉卡拉 适配器
+// 商户订单号对应 partner_order_id，渠道订单号对应拉卡拉 order_id，所有接口均以商户订单号为准
+type Lakala struct {
+	client *lakala.Client
+}
+
+// NewLakala 初始化拉卡拉 适配器
+func NewLakala(client *lakala.Client) *Lakala {
+	return &Lakala{client: client}
+}
+
+func (l *Lakala) Name() string {
+	return ProviderLakala
+}
+
+// Pay 支持 SceneNative、SceneJSAPI、SceneH5、SceneMini、SceneApp、SceneBarcode
+func (l *Lakala) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("description", subjectOf(req)).
+		Set("price", req.Amount).
+		Set("currency", currencyOrDefault(req.Currency))
+	if req.NotifyUrl != gopay.NULL {
+		bm.Set("notify_url", req.NotifyUrl)
+	}
+	rsp = &PayResponse{Provider: l.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	if req.Scene == SceneBarcode {
+		bm.Set("auth_code", req.AuthCode)
+		llRsp, err := l.client.CreateRetailOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if err = lakalaBizErr(llRsp.ErrorCode); err != nil {
+			return nil, err
+		}
+		rsp.TradeNo, rsp.Status, rsp.Raw = llRsp.OrderId, lakala.NormalizeTradeStatus(llRsp.ResultCode), llRsp
+		return rsp, nil
+	}
+	channel, err := lakalaChannel(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	bm.Set("channel", channel)
+	var llRsp *lakala.PaymentRsp
+	switch req.Scene {
+	case SceneNative:
+		llRsp, err = l.client.CreateQRCodeOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+	case SceneJSAPI:
+		llRsp, err = l.client.CreateJSAPIOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+	case SceneH5:
+		llRsp, err = l.client.CreateH5PayOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+	case SceneMini:
+		if req.OpenId != gopay.NULL {
+			bm.Set("customer_id", req.OpenId)
+		}
+		llRsp, err = l.client.CreateMiniProgramOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+	case SceneApp:
+		llRsp, err = l.client.CreateSDKPaymentOrder(ctx, req.OutTradeNo, mergeExtra(bm, req.Extra))
+	default:
+		return nil, sceneErr(l.Name(), req.Scene)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = lakalaBizErr(llRsp.ErrorCode); err != nil {
+		return nil, err
+	}
+	rsp.TradeNo, rsp.CodeUrl, rsp.PayUrl, rsp.PayParams, rsp.Raw = llRsp.OrderId, llRsp.CodeUrl, llRsp.PayUrl, llRsp.SdkParams, llRsp
+	return rsp, nil
+}
+
+// QueryOrder 仅支持按商户订单号查询
+func (l *Lakala) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.OutTradeNo == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no")
+	}
+	llRsp, err := l.client.OrderStatus(ctx, req.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if err = lakalaBizErr(llRsp.ErrorCode); err != nil {
+		return nil, err
+	}
+	rsp = &OrderResponse{
+		Provider:    l.Name(),
+		OutTradeNo:  llRsp.PartnerOrderId,
+		TradeNo:     llRsp.OrderId,
+		Status:      lakala.NormalizeTradeStatus(llRsp.ResultCode),
+		RawStatus:   llRsp.ResultCode,
+		Amount:      int64(llRsp.TotalFee),
+		Currency:    llRsp.Currency,
+		SuccessTime: llRsp.PayTime,
+		Raw:         llRsp,
+	}
+	return rsp, nil
+}
+
+// Refund 仅支持按商户订单号退款
+func (l *Lakala) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.OutTradeNo == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no")
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("fee", req.Amount)
+	llRsp, err := l.client.ApplyRefund(ctx, req.OutTradeNo, req.OutRefundNo, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if err = lakalaBizErr(llRsp.ErrorCode); err != nil {
+		return nil, err
+	}
+	rsp = &RefundResponse{
+		Provider:    l.Name(),
+		OutTradeNo:  req.OutTradeNo,
+		TradeNo:     req.TradeNo,
+		OutRefundNo: llRsp.PartnerRefundId,
+		RefundNo:    llRsp.RefundId,
+		Amount:      int64(llRsp.Amount),
+		Currency:    llRsp.Currency,
+		RawStatus:   llRsp.ResultCode,
+		Raw:         llRsp,
+	}
+	return rsp, nil
+}
+
+// CloseOrder 仅支持按商户订单号关单
+func (l *Lakala) CloseOrder(ctx context.Context, req *CloseRequest) (err error) {
+	if err = req.check(); err != nil {
+		return err
+	}
+	if req.OutTradeNo == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no")
+	}
+	llRsp, err := l.client.CloseOrder(ctx, req.OutTradeNo)
+	if err != nil {
+		return err
+	}
+	return lakalaBizErr(*llRsp)
+}
+
+func lakalaChannel(wallet Wallet) (string, error) {
+	switch wallet {
+	case WalletWechat:
+		return "Wechat", nil
+	case WalletAlipay:
+		return "Alipay", nil
+	case WalletUnionPay:
+		return "UnionPay", nil
+	case WalletQQ:
+		return gopay.NULL, notSupportedErr(ProviderLakala, string(wallet))
+	}
+	return gopay.NULL, fmt.Errorf("[%w], %v", gopay.MissParamErr, "wallet")
+}
+
+// 拉卡拉 return_code 非 SUCCESS 时返回 BizErr
+func lakalaBizErr(ec lakala.ErrorCode) error {
+	if ec.ReturnCode != gopay.NULL && ec.ReturnCode != "SUCCESS" {
+		return &BizErr{Provider: ProviderLakala, Code: ec.ReturnCode, Msg: ec.ReturnMsg}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package unify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+// Scene 支付场景
+type Scene string
+
+const (
+	SceneNative  Scene = "NATIVE"  // 扫码支付（商户展示二维码）
+	SceneJSAPI   Scene = "JSAPI"   // 公众号/服务窗支付
+	SceneMini    Scene = "MINI"    // 小程序支付
+	SceneApp     Scene = "APP"     // APP支付
+	SceneH5      Scene = "H5"      // 手机网页支付
+	ScenePage    Scene = "PAGE"    // 电脑网页支付
+	SceneBarcode Scene = "BARCODE" // 付款码支付（商户扫用户）
+)
+
+// Wallet 聚合渠道（扫呗、通联、拉卡拉）下用户使用的钱包
+type Wallet string
+
+const (
+	WalletWechat   Wallet = "WECHAT"
+	WalletAlipay   Wallet = "ALIPAY"
+	WalletQQ       Wallet = "QQ"
+	WalletUnionPay Wallet = "UNIONPAY"
+)
+
+// PayRequest 统一下单请求
+type PayRequest struct {
+	OutTradeNo  string        // 商户订单号，必填
+	Amount      int64         // 订单金额，币种最小单位（如 分），必填
+	Currency    string        // ISO-4217 币种，为空默认 CNY
+	Subject     string        // 订单标题
+	Description string        // 订单描述
+	Scene       Scene         // 支付场景，必填
+	Wallet      Wallet        // 聚合渠道必填，直连渠道忽略
+	NotifyUrl   string        // 异步通知地址
+	ReturnUrl   string        // 同步跳转地址
+	ClientIp    string        // 用户终端IP
+	OpenId      string        // JSAPI、小程序场景用户标识
+	AuthCode    string        // 付款码支付场景的付款码
+	Extra       gopay.BodyMap // 渠道特有参数，原样合并到渠道请求中
+}
+
+// PayResponse 统一下单返回
+type PayResponse struct {
+	Provider   string
+	OutTradeNo string            // 商户订单号
+	TradeNo    string            // 渠道订单号
+	Status     gopay.TradeStatus // 下单后的订单状态，付款码支付可能直接为 gopay.TradeStatusSucceeded
+	CodeUrl    string            // 二维码链接
+	PayUrl     string            // 跳转支付链接
+	PrepayId   string            // 预支付交易会话标识
+	PayParams  string            // 客户端拉起支付所需参数（APP orderStr、JSAPI/小程序签名参数 Json 等）
+	Raw        any               // 渠道原始返回
+}
+
+// QueryRequest 统一查询请求，OutTradeNo、TradeNo 二选一
+type QueryRequest struct {
+	OutTradeNo string
+	TradeNo    string
+	Wallet     Wallet // 部分聚合渠道（扫呗）查询时需要
+	Extra      gopay.BodyMap
+}
+
+// OrderResponse 统一查询返回
+type OrderResponse struct {
+	Provider    string
+	OutTradeNo  string
+	TradeNo     string
+	Status      gopay.TradeStatus
+	RawStatus   string // 渠道原始状态
+	Amount      int64  // 订单金额，币种最小单位
+	Currency    string
+	SuccessTime string // 支付完成时间，渠道原始格式
+	Raw         any
+}
+
+// RefundRequest 统一退款请求
+type RefundRequest struct {
+	OutTradeNo  string // 商户订单号，与 TradeNo 二选一
+	TradeNo     string // 渠道订单号
+	OutRefundNo string // 商户退款单号，必填
+	Amount      int64  // 退款金额，币种最小单位，必填
+	TotalAmount int64  // 原订单金额，部分渠道（微信、QQ）必填
+	Currency    string
+	Reason      string
+	NotifyUrl   string
+	Wallet      Wallet // 部分聚合渠道（扫呗）退款时需要
+	Extra       gopay.BodyMap
+}
+
+// RefundResponse 统一退款返回
+type RefundResponse struct {
+	Provider    string
+	OutTradeNo  string
+	TradeNo     string
+	OutRefundNo string
+	RefundNo    string // 渠道退款单号
+	Amount      int64
+	Currency    string
+	RawStatus   string
+	Raw         any
+}
+
+// CloseRequest 统一关单请求，OutTradeNo、TradeNo 二选一
+type CloseRequest struct {
+	OutTradeNo string
+	TradeNo    string
+	Extra      gopay.BodyMap
+}
+
+func (r *PayRequest) check() error {
+	if r == nil {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "request is nil")
+	}
+	var missing []string
+	if r.OutTradeNo == gopay.NULL {
+		missing = append(missing, "out_trade_no")
+	}
+	if r.Amount <= 0 {
+		missing = append(missing, "amount")
+	}
+	if r.Scene == "" {
+		missing = append(missing, "scene")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (r *QueryRequest) check() error {
+	if r == nil || (r.OutTradeNo == gopay.NULL && r.TradeNo == gopay.NULL) {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no, trade_no")
+	}
+	return nil
+}
+
+func (r *RefundRequest) check() error {
+	if r == nil {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "request is nil")
+	}
+	var missing []string
+	if r.OutTradeNo == gopay.NULL && r.TradeNo == gopay.NULL {
+		missing = append(missing, "out_trade_no|trade_no")
+	}
+	if r.OutRefundNo == gopay.NULL {
+		missing = append(missing, "out_refund_no")
+	}
+	if r.Amount <= 0 {
+		missing = append(missing, "amount")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (r *CloseRequest) check() error {
+	if r == nil || (r.OutTradeNo == gopay.NULL && r.TradeNo == gopay.NULL) {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no, trade_no")
+	}
+	return nil
+}
+
+// 合并渠道特有参数，已存在的 key 以 extra 为准
+func mergeExtra(bm, extra gopay.BodyMap) gopay.BodyMap {
+	for k, v := range extra {
+		bm[k] = v
+	}
+	return bm
+}
+
+// 订单标题，为空时使用订单描述
+func subjectOf(req *PayRequest) string {
+	if req.Subject != gopay.NULL {
+		return req.Subject
+	}
+	return req.Description
+}
+
+// 两个订单号二选一，优先使用渠道订单号
+func setTradeNo(bm gopay.BodyMap, outKey, outTradeNo, key, tradeNo string) {
+	if tradeNo != gopay.NULL {
+		bm.Set(key, tradeNo)
+		return
+	}
+	bm.Set(outKey, outTradeNo)
+}
@@ -0,0 +1,189 @@
+package unify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/paypal"
+)
+
+var _ Provider = (*PayPal)(nil)
+
+// PayPal PayPal 适配器
+// 商户订单号保存在 purchase_units[0].invoice_id 中，渠道订单号为 PayPal Order Id
+type PayPal struct {
+	client *paypal.Client
+}
+
+// NewPayPal 初始化PayPal 适配器
+func NewPayPal(client *paypal.Client) *PayPal {
+	return &PayPal{client: client}
+}
+
+func (p *PayPal) Name() string {
+	return ProviderPayPal
+}
+
+// Pay 创建 intent=CAPTURE 订单，返回买家授权链接 PayUrl，不区分场景
+func (p *PayPal) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	currency := currencyOrDefault(req.Currency)
+	unit := make(gopay.BodyMap)
+	unit.Set("invoice_id", req.OutTradeNo).
+		Set("description", subjectOf(req)).
+		SetBodyMap("amount", func(b gopay.BodyMap) {
+			b.Set("currency_code", currency).
+				Set("value", formatAmount(req.Amount, currency))
+		})
+	bm := make(gopay.BodyMap)
+	bm.Set("intent", "CAPTURE").
+		Set("purchase_units", []gopay.BodyMap{unit})
+	if req.ReturnUrl != gopay.NULL {
+		bm.SetBodyMap("application_context", func(b gopay.BodyMap) {
+			b.Set("return_url", req.ReturnUrl)
+		})
+	}
+	ppRsp, err := p.client.CreateOrder(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if ppRsp.Code != paypal.Success {
+		return nil, paypalBizErr(ppRsp.Code, ppRsp.ErrorResponse, ppRsp.Error)
+	}
+	order := ppRsp.Response
+	rsp = &PayResponse{
+		Provider:   p.Name(),
+		OutTradeNo: req.OutTradeNo,
+		TradeNo:    order.Id,
+		Status:     paypal.NormalizeOrderStatus(order),
+		Raw:        ppRsp,
+	}
+	for _, link := range order.Links {
+		if link.Rel == "approve" || link.Rel == "payer-action" {
+			rsp.PayUrl = link.Href
+			break
+		}
+	}
+	return rsp, nil
+}
+
+// QueryOrder 仅支持按 PayPal Order Id（TradeNo）查询
+func (p *PayPal) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.TradeNo == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "trade_no")
+	}
+	ppRsp, err := p.client.OrderDetail(ctx, req.TradeNo, req.Extra)
+	if err != nil {
+		return nil, err
+	}
+	if ppRsp.Code != paypal.Success {
+		return nil, paypalBizErr(ppRsp.Code, ppRsp.ErrorResponse, ppRsp.Error)
+	}
+	order := ppRsp.Response
+	rsp = &OrderResponse{
+		Provider:  p.Name(),
+		TradeNo:   order.Id,
+		Status:    paypal.NormalizeOrderStatus(order),
+		RawStatus: order.Status,
+		Raw:       ppRsp,
+	}
+	if len(order.PurchaseUnits) > 0 {
+		unit := order.PurchaseUnits[0]
+		rsp.OutTradeNo = unit.InvoiceId
+		if unit.Amount != nil {
+			rsp.Currency = unit.Amount.CurrencyCode
+			if rsp.Amount, err = parseAmount(unit.Amount.Value, rsp.Currency); err != nil {
+				return nil, err
+			}
+		}
+		if capture := firstCapture(order); capture != nil {
+			rsp.SuccessTime = capture.CreateTime
+		}
+	}
+	return rsp, nil
+}
+
+// Refund 按 PayPal Order Id（TradeNo）查出 Capture 后退款
+func (p *PayPal) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.TradeNo == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "trade_no")
+	}
+	detail, err := p.client.OrderDetail(ctx, req.TradeNo, nil)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Code != paypal.Success {
+		return nil, paypalBizErr(detail.Code, detail.ErrorResponse, detail.Error)
+	}
+	capture := firstCapture(detail.Response)
+	if capture == nil {
+		return nil, &BizErr{Provider: p.Name(), Code: "CAPTURE_NOT_FOUND", Msg: "order " + req.TradeNo + " has no capture"}
+	}
+	currency := currencyOrDefault(req.Currency)
+	if req.Currency == gopay.NULL && capture.Amount != nil {
+		currency = capture.Amount.CurrencyCode
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("invoice_id", req.OutRefundNo).
+		SetBodyMap("amount", func(b gopay.BodyMap) {
+			b.Set("currency_code", currency).
+				Set("value", formatAmount(req.Amount, currency))
+		})
+	if req.Reason != gopay.NULL {
+		bm.Set("note_to_payer", req.Reason)
+	}
+	ppRsp, err := p.client.PaymentCaptureRefund(ctx, capture.Id, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if ppRsp.Code != paypal.Success {
+		return nil, paypalBizErr(ppRsp.Code, ppRsp.ErrorResponse, ppRsp.Error)
+	}
+	refund := ppRsp.Response
+	rsp = &RefundResponse{
+		Provider:    p.Name(),
+		OutTradeNo:  req.OutTradeNo,
+		TradeNo:     req.TradeNo,
+		OutRefundNo: req.OutRefundNo,
+		RefundNo:    refund.Id,
+		Amount:      req.Amount,
+		Currency:    currency,
+		RawStatus:   refund.Status,
+		Raw:         ppRsp,
+	}
+	return rsp, nil
+}
+
+// CloseOrder PayPal 订单未授权时会自动过期，不支持主动关单
+func (p *PayPal) CloseOrder(_ context.Context, _ *CloseRequest) (err error) {
+	return notSupportedErr(p.Name(), "close order")
+}
+
+func paypalBizErr(code int, errRsp *paypal.ErrorResponse, errStr string) error {
+	if errRsp == nil || errRsp.Name == gopay.NULL {
+		return &BizErr{Provider: ProviderPayPal, Code: strconv.Itoa(code), Msg: errStr}
+	}
+	return &BizErr{Provider: ProviderPayPal, Code: errRsp.Name, Msg: errRsp.Message}
+}
+
+func firstCapture(order *paypal.OrderDetail) *paypal.Capture {
+	if order == nil {
+		return nil
+	}
+	for _, unit := range order.PurchaseUnits {
+		if unit.Payments != nil && len(unit.Payments.Captures) > 0 {
+			return unit.Payments.Captures[0]
+		}
+	}
+	return nil
+}
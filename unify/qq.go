@@ -0,0 +1,190 @@
+package unify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-pay/util"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/qq"
+)
+
+var _ Provider = (*QQ)(nil)
+
+// QQ QQ钱包 适配器
+// 退款需要先通过 client.AddCertFilePath() 或 client.AddCertFileContent() 添加商户证书
+type QQ struct {
+	client       *qq.Client
+	appid        string
+	opUserId     string
+	opUserPasswd string
+}
+
+// NewQQ 初始化QQ钱包 适配器
+// appid：下单使用的 appid，可为空
+// opUserId、opUserPasswd：退款操作员帐号及密码（MD5后）
+func NewQQ(client *qq.Client, appid, opUserId, opUserPasswd string) *QQ {
+	return &QQ{client: client, appid: appid, opUserId: opUserId, opUserPasswd: opUserPasswd}
+}
+
+func (q *QQ) Name() string {
+	return ProviderQQ
+}
+
+// Pay 支持 SceneNative、SceneJSAPI、SceneApp、SceneMini、SceneBarcode
+func (q *QQ) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if currency := currencyOrDefault(req.Currency); currency != defaultCurrency {
+		return nil, currencyErr(q.Name(), currency)
+	}
+	bm := q.newBodyMap()
+	bm.Set("body", subjectOf(req)).
+		Set("out_trade_no", req.OutTradeNo).
+		Set("total_fee", strconv.FormatInt(req.Amount, 10)).
+		Set("spbill_create_ip", req.ClientIp)
+	rsp = &PayResponse{Provider: q.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	if req.Scene == SceneBarcode {
+		bm.Set("auth_code", req.AuthCode)
+		qqRsp, err := q.client.MicroPay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if err = qqBizErr(qqRsp.ReturnCode, qqRsp.ReturnMsg, qqRsp.ResultCode, qqRsp.ErrCode, qqRsp.ErrCodeDes); err != nil {
+			return nil, err
+		}
+		rsp.TradeNo, rsp.Status, rsp.Raw = qqRsp.TransactionId, qq.NormalizeTradeStatus(qqRsp.TradeState), qqRsp
+		if rsp.Status == gopay.TradeStatusUnknown {
+			rsp.Status = gopay.TradeStatusSucceeded
+		}
+		return rsp, nil
+	}
+	var tradeType string
+	switch req.Scene {
+	case SceneNative:
+		tradeType = qq.TradeType_Native
+	case SceneJSAPI:
+		tradeType = qq.TradeType_JsApi
+	case SceneApp:
+		tradeType = qq.TradeType_App
+	case SceneMini:
+		tradeType = qq.TradeType_Mini
+	default:
+		return nil, sceneErr(q.Name(), req.Scene)
+	}
+	bm.Set("trade_type", tradeType).
+		Set("notify_url", req.NotifyUrl)
+	qqRsp, err := q.client.UnifiedOrder(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if err = qqBizErr(qqRsp.ReturnCode, qqRsp.ReturnMsg, qqRsp.ResultCode, qqRsp.ErrCode, qqRsp.ErrCodeDes); err != nil {
+		return nil, err
+	}
+	rsp.PrepayId, rsp.CodeUrl, rsp.Raw = qqRsp.PrepayId, qqRsp.CodeUrl, qqRsp
+	return rsp, nil
+}
+
+func (q *QQ) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	bm := q.newBodyMap()
+	setTradeNo(bm, "out_trade_no", req.OutTradeNo, "transaction_id", req.TradeNo)
+	qqRsp, err := q.client.OrderQuery(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if err = qqBizErr(qqRsp.ReturnCode, qqRsp.ReturnMsg, qqRsp.ResultCode, qqRsp.ErrCode, qqRsp.ErrCodeDes); err != nil {
+		return nil, err
+	}
+	rsp = &OrderResponse{
+		Provider:    q.Name(),
+		OutTradeNo:  qqRsp.OutTradeNo,
+		TradeNo:     qqRsp.TransactionId,
+		Status:      qq.NormalizeTradeStatus(qqRsp.TradeState),
+		RawStatus:   qqRsp.TradeState,
+		Amount:      parseMinorAmount(qqRsp.TotalFee),
+		Currency:    defaultCurrency,
+		SuccessTime: qqRsp.TimeEnd,
+		Raw:         qqRsp,
+	}
+	if qqRsp.FeeType != gopay.NULL {
+		rsp.Currency = qqRsp.FeeType
+	}
+	return rsp, nil
+}
+
+func (q *QQ) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	bm := q.newBodyMap()
+	setTradeNo(bm, "out_trade_no", req.OutTradeNo, "transaction_id", req.TradeNo)
+	bm.Set("out_refund_no", req.OutRefundNo).
+		Set("refund_fee", strconv.FormatInt(req.Amount, 10)).
+		Set("op_user_id", q.opUserId).
+		Set("op_user_passwd", q.opUserPasswd)
+	if req.TotalAmount > 0 {
+		bm.Set("total_fee", strconv.FormatInt(req.TotalAmount, 10))
+	}
+	// 证书已通过 client.AddCertFilePath() 添加
+	qqRsp, err := q.client.Refund(ctx, mergeExtra(bm, req.Extra), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = qqBizErr(qqRsp.ReturnCode, qqRsp.ReturnMsg, qqRsp.ResultCode, qqRsp.ErrCode, qqRsp.ErrCodeDes); err != nil {
+		return nil, err
+	}
+	rsp = &RefundResponse{
+		Provider:    q.Name(),
+		OutTradeNo:  qqRsp.OutTradeNo,
+		TradeNo:     qqRsp.TransactionId,
+		OutRefundNo: qqRsp.OutRefundNo,
+		RefundNo:    qqRsp.RefundId,
+		Amount:      parseMinorAmount(qqRsp.RefundFee),
+		Currency:    defaultCurrency,
+		RawStatus:   qqRsp.ResultCode,
+		Raw:         qqRsp,
+	}
+	return rsp, nil
+}
+
+// CloseOrder 仅支持按商户订单号关单
+func (q *QQ) CloseOrder(ctx context.Context, req *CloseRequest) (err error) {
+	if err = req.check(); err != nil {
+		return err
+	}
+	if req.OutTradeNo == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no")
+	}
+	bm := q.newBodyMap()
+	bm.Set("out_trade_no", req.OutTradeNo)
+	qqRsp, err := q.client.CloseOrder(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return err
+	}
+	return qqBizErr(qqRsp.ReturnCode, qqRsp.ReturnMsg, qqRsp.ResultCode, qqRsp.ErrCode, qqRsp.ErrCodeDes)
+}
+
+func (q *QQ) newBodyMap() gopay.BodyMap {
+	bm := make(gopay.BodyMap)
+	bm.Set("nonce_str", util.RandomString(32))
+	if q.appid != gopay.NULL {
+		bm.Set("appid", q.appid)
+	}
+	return bm
+}
+
+// QQ 通信结果或业务结果非 SUCCESS 时返回 BizErr
+func qqBizErr(returnCode, returnMsg, resultCode, errCode, errCodeDes string) error {
+	if returnCode != "SUCCESS" {
+		return &BizErr{Provider: ProviderQQ, Code: returnCode, Msg: returnMsg}
+	}
+	if resultCode != "SUCCESS" {
+		return &BizErr{Provider: ProviderQQ, Code: errCode, Msg: errCodeDes}
+	}
+	return nil
+}
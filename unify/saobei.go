@@ -0,0 +1,217 @@
+package unify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/saobei"
+)
+
+var _ Provider = (*Saobei)(nil)
+
+// Saobei 扫呗 适配器
+// 商户订单号对应 terminal_trace，渠道订单号对应扫呗平台订单号 out_trade_no
+type Saobei struct {
+	client   *saobei.Client
+	subAppid string
+}
+
+// NewSaobei 初始化扫呗 适配器
+// subAppid：小程序支付使用的 sub_appid
+func NewSaobei(client *saobei.Client, subAppid string) *Saobei {
+	return &Saobei{client: client, subAppid: subAppid}
+}
+
+func (s *Saobei) Name() string {
+	return ProviderSaobei
+}
+
+// Pay 支持 SceneMini、SceneJSAPI、SceneBarcode
+func (s *Saobei) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if currency := currencyOrDefault(req.Currency); currency != defaultCurrency {
+		return nil, currencyErr(s.Name(), currency)
+	}
+	payType, err := saobeiPayType(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("pay_type", payType).
+		Set("terminal_ip", req.ClientIp).
+		Set("terminal_trace", req.OutTradeNo).
+		Set("terminal_time", time.Now().Format("20060102150405")).
+		Set("total_fee", strconv.FormatInt(req.Amount, 10))
+	if subject := subjectOf(req); subject != gopay.NULL {
+		bm.Set("order_body", subject)
+	}
+	if req.NotifyUrl != gopay.NULL {
+		bm.Set("notify_url", req.NotifyUrl)
+	}
+	rsp = &PayResponse{Provider: s.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	switch req.Scene {
+	case SceneMini, SceneJSAPI:
+		bm.Set("sub_appid", s.subAppid).
+			Set("open_id", req.OpenId)
+		sbRsp, err := s.client.MiniPay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, saobeiErr(err)
+		}
+		rsp.TradeNo, rsp.Raw = sbRsp.OutTradeNo, sbRsp
+		if sbRsp.AliTradeNo != gopay.NULL {
+			rsp.PayParams = sbRsp.AliTradeNo
+		} else {
+			bs, _ := json.Marshal(map[string]string{
+				"appId":     sbRsp.AppId,
+				"timeStamp": sbRsp.TimeStamp,
+				"nonceStr":  sbRsp.NonceStr,
+				"package":   sbRsp.PackageStr,
+				"signType":  sbRsp.SignType,
+				"paySign":   sbRsp.PaySign,
+			})
+			rsp.PayParams = string(bs)
+		}
+	case SceneBarcode:
+		bm.Set("auth_no", req.AuthCode)
+		sbRsp, err := s.client.BarcodePay(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, saobeiErr(err)
+		}
+		rsp.TradeNo, rsp.Raw = sbRsp.OutTradeNo, sbRsp
+		switch sbRsp.ResultCode {
+		case saobei.ResultCodeSuccess:
+			rsp.Status = gopay.TradeStatusSucceeded
+		case saobei.ResultCodePaying:
+			rsp.Status = gopay.TradeStatusPaying
+		default:
+			return nil, &BizErr{Provider: s.Name(), Code: sbRsp.ResultCode, Msg: sbRsp.ReturnMsg}
+		}
+	default:
+		return nil, sceneErr(s.Name(), req.Scene)
+	}
+	return rsp, nil
+}
+
+// QueryOrder 优先使用扫呗平台订单号（TradeNo）查询；
+// 仅有商户订单号时，需在 Extra 中传入下单时的 pay_time（即 terminal_time）
+func (s *Saobei) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	payType, err := saobeiPayType(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("pay_type", payType).
+		Set("terminal_trace", saobeiTrace()).
+		Set("terminal_time", time.Now().Format("20060102150405"))
+	if req.TradeNo != gopay.NULL {
+		bm.Set("out_trade_no", req.TradeNo)
+	} else {
+		bm.Set("pay_trace", req.OutTradeNo)
+	}
+	mergeExtra(bm, req.Extra)
+	if req.TradeNo == gopay.NULL {
+		if err = bm.CheckEmptyError("pay_time"); err != nil {
+			return nil, err
+		}
+	}
+	sbRsp, err := s.client.Query(ctx, bm)
+	if err != nil {
+		return nil, saobeiErr(err)
+	}
+	rsp = &OrderResponse{
+		Provider:    s.Name(),
+		OutTradeNo:  sbRsp.PayTrace,
+		TradeNo:     sbRsp.OutTradeNo,
+		Status:      saobei.NormalizeTradeStatus(sbRsp.TradeState),
+		RawStatus:   sbRsp.TradeState,
+		Amount:      parseMinorAmount(sbRsp.TotalFee),
+		Currency:    defaultCurrency,
+		SuccessTime: sbRsp.EndTime,
+		Raw:         sbRsp,
+	}
+	if rsp.OutTradeNo == gopay.NULL {
+		rsp.OutTradeNo = req.OutTradeNo
+	}
+	return rsp, nil
+}
+
+// Refund 扫呗退款需使用扫呗平台订单号（TradeNo）
+func (s *Saobei) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.TradeNo == gopay.NULL {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "trade_no")
+	}
+	payType, err := saobeiPayType(req.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("pay_type", payType).
+		Set("terminal_trace", req.OutRefundNo).
+		Set("terminal_time", time.Now().Format("20060102150405")).
+		Set("refund_fee", strconv.FormatInt(req.Amount, 10)).
+		Set("out_trade_no", req.TradeNo)
+	sbRsp, err := s.client.Refund(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, saobeiErr(err)
+	}
+	if sbRsp.ResultCode != saobei.ResultCodeSuccess {
+		return nil, &BizErr{Provider: s.Name(), Code: sbRsp.ResultCode, Msg: sbRsp.ReturnMsg}
+	}
+	rsp = &RefundResponse{
+		Provider:    s.Name(),
+		OutTradeNo:  req.OutTradeNo,
+		TradeNo:     sbRsp.OutTradeNo,
+		OutRefundNo: req.OutRefundNo,
+		RefundNo:    sbRsp.OutRefundNo,
+		Amount:      parseMinorAmount(sbRsp.RefundFee),
+		Currency:    defaultCurrency,
+		RawStatus:   sbRsp.ResultCode,
+		Raw:         sbRsp,
+	}
+	return rsp, nil
+}
+
+// CloseOrder 扫呗未提供关单接口
+func (s *Saobei) CloseOrder(_ context.Context, _ *CloseRequest) (err error) {
+	return notSupportedErr(s.Name(), "close order")
+}
+
+func saobeiPayType(wallet Wallet) (string, error) {
+	switch wallet {
+	case WalletWechat:
+		return saobei.PayTypeWX, nil
+	case WalletAlipay:
+		return saobei.PayTypeAli, nil
+	case WalletQQ:
+		return saobei.PayTypeQQ, nil
+	case WalletUnionPay:
+		return saobei.PayTypeYL, nil
+	}
+	return gopay.NULL, fmt.Errorf("[%w], %v", gopay.MissParamErr, "wallet")
+}
+
+// 查询请求使用的终端流水号
+func saobeiTrace() string {
+	return "Q" + strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+func saobeiErr(err error) error {
+	var bizErr *saobei.BizErr
+	if errors.As(err, &bizErr) {
+		return &BizErr{Provider: ProviderSaobei, Code: bizErr.Code, Msg: bizErr.Msg}
+	}
+	return err
+}
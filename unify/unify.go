@@ -0,0 +1,52 @@
+// Package unify 与支付渠道无关的统一支付门面
+// 通过适配器把 支付宝、微信V3、PayPal、扫呗、通联、拉卡拉、QQ 的 Client 统一为相同的 下单、查询、退款、关单 接口，
+// 业务代码只依赖本包的接口与模型，切换渠道时无需修改调用方。
+package unify
+
+import (
+	"context"
+
+	"github.com/w6xian/gopay"
+)
+
+// 渠道名称，与 gopay.Provider* 一致
+const (
+	ProviderAlipay   = gopay.ProviderAlipay
+	ProviderWechat   = gopay.ProviderWechat
+	ProviderPayPal   = gopay.ProviderPayPal
+	ProviderSaobei   = gopay.ProviderSaobei
+	ProviderAllinpay = gopay.ProviderAllinpay
+	ProviderLakala   = gopay.ProviderLakala
+	ProviderQQ       = gopay.ProviderQQ
+)
+
+// Payer 统一下单
+type Payer interface {
+	Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error)
+}
+
+// OrderQuerier 统一订单查询
+type OrderQuerier interface {
+	QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error)
+}
+
+// Refunder 统一退款
+type Refunder interface {
+	Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error)
+}
+
+// Closer 统一关闭订单
+// 渠道不支持关单时返回 NotSupportedErr
+type Closer interface {
+	CloseOrder(ctx context.Context, req *CloseRequest) (err error)
+}
+
+// Provider 渠道适配器，本包内所有适配器均实现该接口
+type Provider interface {
+	// Name 渠道名称，如 ProviderAlipay
+	Name() string
+	Payer
+	OrderQuerier
+	Refunder
+	Closer
+}
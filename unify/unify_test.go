@@ -0,0 +1,118 @@
+package unify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/alipay"
+	"github.com/w6xian/gopay/allinpay"
+	"github.com/w6xian/gopay/lakala"
+	"github.com/w6xian/gopay/paypal"
+	"github.com/w6xian/gopay/qq"
+	"github.com/w6xian/gopay/saobei"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+func TestFormatAmount(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	cases := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{1, "CNY", "0.01"},
+		{1234, "", "12.34"},
+		{100, "usd", "1.00"},
+		{1234, "JPY", "1234"},
+		{1234, "KWD", "1.234"},
+		{-5, "CNY", "-0.05"},
+	}
+	for _, v := range cases {
+		if got := formatAmount(v.amount, v.currency); got != v.want {
+			t.Errorf("formatAmount(%d, %s) = %s, want %s", v.amount, v.currency, got, v.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	cases := []struct {
+		value    string
+		currency string
+		want     int64
+	}{
+		{"0.01", "CNY", 1},
+		{"12.3", "CNY", 1230},
+		{"12.340", "CNY", 1234},
+		{"88", "CNY", 8800},
+		{"1234", "JPY", 1234},
+		{"", "CNY", 0},
+	}
+	for _, v := range cases {
+		got, err := parseAmount(v.value, v.currency)
+		if err != nil {
+			t.Errorf("parseAmount(%s, %s), err: %v", v.value, v.currency, err)
+			continue
+		}
+		if got != v.want {
+			t.Errorf("parseAmount(%s, %s) = %d, want %d", v.value, v.currency, got, v.want)
+		}
+	}
+	if _, err := parseAmount("0.001", "CNY"); !errors.Is(err, AmountErr) {
+		t.Errorf("parseAmount(0.001, CNY) want AmountErr, got: %v", err)
+	}
+}
+
+func TestNormalizeTradeStatus(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	cases := []struct {
+		provider string
+		got      gopay.TradeStatus
+		want     gopay.TradeStatus
+	}{
+		{ProviderWechat, wechat.NormalizeTradeStatus(wechat.TradeStateSuccess), gopay.TradeStatusSucceeded},
+		{ProviderWechat, wechat.NormalizeTradeStatus(wechat.TradeStateRefund), gopay.TradeStatusRefunded},
+		{ProviderAlipay, alipay.NormalizeTradeStatus(alipay.TradeStatusWaitBuyerPay), gopay.TradeStatusPending},
+		{ProviderAlipay, alipay.NormalizeTradeStatus(alipay.TradeStatusFinished), gopay.TradeStatusSucceeded},
+		{ProviderPayPal, paypal.NormalizeTradeStatus(paypal.OrderStatusCompleted), gopay.TradeStatusSucceeded},
+		{ProviderSaobei, saobei.NormalizeTradeStatus("REVOKED"), gopay.TradeStatusClosed},
+		{ProviderAllinpay, allinpay.NormalizeTradeStatus(allinpay.TrxStatusPaying), gopay.TradeStatusPaying},
+		{ProviderAllinpay, allinpay.NormalizeTradeStatus("3045"), gopay.TradeStatusFailed},
+		{ProviderLakala, lakala.NormalizeTradeStatus(lakala.ResultCodePartialRefund), gopay.TradeStatusPartiallyRefunded},
+		{ProviderQQ, qq.NormalizeTradeStatus(qq.TradeState_UserPaying), gopay.TradeStatusPaying},
+		{ProviderQQ, qq.NormalizeTradeStatus("UNKNOWN_STATE"), gopay.TradeStatusUnknown},
+	}
+	for _, v := range cases {
+		if v.got != v.want {
+			t.Errorf("%s status = %s, want %s", v.provider, v.got, v.want)
+		}
+	}
+}
+
+func TestRequestCheck(t *testing.T) {
+	xlog.SetLevel(xlog.DebugLevel)
+	ctx := context.Background()
+	providers := []Provider{NewWechat(nil, ""), NewAlipay(nil), NewPayPal(nil), NewSaobei(nil, ""), NewAllinpay(nil), NewLakala(nil), NewQQ(nil, "", "", "")}
+	for _, p := range providers {
+		// 参数校验先于网络请求，nil client 不会被调用
+		if _, err := p.Pay(ctx, &PayRequest{Scene: SceneNative}); !errors.Is(err, gopay.MissParamErr) {
+			t.Errorf("%s Pay want MissParamErr, got: %v", p.Name(), err)
+		}
+		if _, err := p.QueryOrder(ctx, &QueryRequest{}); !errors.Is(err, gopay.MissParamErr) {
+			t.Errorf("%s QueryOrder want MissParamErr, got: %v", p.Name(), err)
+		}
+		if _, err := p.Refund(ctx, &RefundRequest{OutTradeNo: "GZ202301010001"}); !errors.Is(err, gopay.MissParamErr) {
+			t.Errorf("%s Refund want MissParamErr, got: %v", p.Name(), err)
+		}
+		xlog.Debugf("%s check ok", p.Name())
+	}
+	if _, err := NewWechat(nil, "").Pay(ctx, &PayRequest{OutTradeNo: "GZ202301010001", Amount: 1, Currency: "USD", Scene: SceneNative}); !errors.Is(err, CurrencyErr) {
+		t.Errorf("wechat Pay want CurrencyErr, got: %v", err)
+	}
+	if err := NewSaobei(nil, "").CloseOrder(ctx, &CloseRequest{OutTradeNo: "GZ202301010001"}); !errors.Is(err, NotSupportedErr) {
+		t.Errorf("saobei CloseOrder want NotSupportedErr, got: %v", err)
+	}
+}
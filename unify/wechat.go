@@ -0,0 +1,219 @@
+package unify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/wechat/v3"
+)
+
+var _ Provider = (*Wechat)(nil)
+
+// Wechat 微信支付V3 适配器
+type Wechat struct {
+	client *wechat.ClientV3
+	appid  string
+}
+
+// NewWechat 初始化微信支付V3 适配器
+// appid：下单使用的 公众号/小程序/APP 的 appid
+func NewWechat(client *wechat.ClientV3, appid string) *Wechat {
+	return &Wechat{client: client, appid: appid}
+}
+
+func (w *Wechat) Name() string {
+	return ProviderWechat
+}
+
+// Pay 支持 SceneNative、SceneJSAPI、SceneMini、SceneApp、SceneH5
+func (w *Wechat) Pay(ctx context.Context, req *PayRequest) (rsp *PayResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	currency := currencyOrDefault(req.Currency)
+	if currency != defaultCurrency {
+		return nil, currencyErr(w.Name(), currency)
+	}
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", w.appid).
+		Set("description", subjectOf(req)).
+		Set("out_trade_no", req.OutTradeNo).
+		Set("notify_url", req.NotifyUrl).
+		SetBodyMap("amount", func(b gopay.BodyMap) {
+			b.Set("total", req.Amount).
+				Set("currency", currency)
+		})
+	rsp = &PayResponse{Provider: w.Name(), OutTradeNo: req.OutTradeNo, Status: gopay.TradeStatusPending}
+	switch req.Scene {
+	case SceneJSAPI, SceneMini:
+		bm.SetBodyMap("payer", func(b gopay.BodyMap) {
+			b.Set("openid", req.OpenId)
+		})
+		wxRsp, err := w.client.V3TransactionJsapi(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if wxRsp.Code != wechat.Success {
+			return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+		}
+		params, err := w.client.PaySignOfJSAPI(w.appid, wxRsp.Response.PrepayId)
+		if err != nil {
+			return nil, err
+		}
+		bs, _ := json.Marshal(params)
+		rsp.PrepayId, rsp.PayParams, rsp.Raw = wxRsp.Response.PrepayId, string(bs), wxRsp
+	case SceneApp:
+		wxRsp, err := w.client.V3TransactionApp(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if wxRsp.Code != wechat.Success {
+			return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+		}
+		params, err := w.client.PaySignOfApp(w.appid, wxRsp.Response.PrepayId)
+		if err != nil {
+			return nil, err
+		}
+		bs, _ := json.Marshal(params)
+		rsp.PrepayId, rsp.PayParams, rsp.Raw = wxRsp.Response.PrepayId, string(bs), wxRsp
+	case SceneNative:
+		wxRsp, err := w.client.V3TransactionNative(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if wxRsp.Code != wechat.Success {
+			return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+		}
+		rsp.CodeUrl, rsp.Raw = wxRsp.Response.CodeUrl, wxRsp
+	case SceneH5:
+		bm.SetBodyMap("scene_info", func(b gopay.BodyMap) {
+			b.Set("payer_client_ip", req.ClientIp).
+				SetBodyMap("h5_info", func(b gopay.BodyMap) {
+					b.Set("type", "Wap")
+				})
+		})
+		wxRsp, err := w.client.V3TransactionH5(ctx, mergeExtra(bm, req.Extra))
+		if err != nil {
+			return nil, err
+		}
+		if wxRsp.Code != wechat.Success {
+			return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+		}
+		rsp.PayUrl, rsp.Raw = wxRsp.Response.H5Url, wxRsp
+	default:
+		return nil, sceneErr(w.Name(), req.Scene)
+	}
+	return rsp, nil
+}
+
+func (w *Wechat) QueryOrder(ctx context.Context, req *QueryRequest) (rsp *OrderResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	orderNoType, orderNo := wechat.OutTradeNo, req.OutTradeNo
+	if req.TradeNo != gopay.NULL {
+		orderNoType, orderNo = wechat.TransactionId, req.TradeNo
+	}
+	wxRsp, err := w.client.V3TransactionQueryOrder(ctx, orderNoType, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if wxRsp.Code != wechat.Success {
+		return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+	}
+	order := wxRsp.Response
+	rsp = &OrderResponse{
+		Provider:    w.Name(),
+		OutTradeNo:  order.OutTradeNo,
+		TradeNo:     order.TransactionId,
+		Status:      wechat.NormalizeTradeStatus(order.TradeState),
+		RawStatus:   order.TradeState,
+		Currency:    defaultCurrency,
+		SuccessTime: order.SuccessTime,
+		Raw:         wxRsp,
+	}
+	if order.Amount != nil {
+		rsp.Amount = int64(order.Amount.Total)
+		if order.Amount.Currency != gopay.NULL {
+			rsp.Currency = order.Amount.Currency
+		}
+	}
+	return rsp, nil
+}
+
+// Refund 微信退款 TotalAmount 必填
+func (w *Wechat) Refund(ctx context.Context, req *RefundRequest) (rsp *RefundResponse, err error) {
+	if err = req.check(); err != nil {
+		return nil, err
+	}
+	if req.TotalAmount <= 0 {
+		return nil, fmt.Errorf("[%w], %v", gopay.MissParamErr, "total_amount")
+	}
+	currency := currencyOrDefault(req.Currency)
+	bm := make(gopay.BodyMap)
+	if req.TradeNo != gopay.NULL {
+		bm.Set("transaction_id", req.TradeNo)
+	} else {
+		bm.Set("out_trade_no", req.OutTradeNo)
+	}
+	bm.Set("out_refund_no", req.OutRefundNo).
+		SetBodyMap("amount", func(b gopay.BodyMap) {
+			b.Set("refund", req.Amount).
+				Set("total", req.TotalAmount).
+				Set("currency", currency)
+		})
+	if req.Reason != gopay.NULL {
+		bm.Set("reason", req.Reason)
+	}
+	if req.NotifyUrl != gopay.NULL {
+		bm.Set("notify_url", req.NotifyUrl)
+	}
+	wxRsp, err := w.client.V3Refund(ctx, mergeExtra(bm, req.Extra))
+	if err != nil {
+		return nil, err
+	}
+	if wxRsp.Code != wechat.Success {
+		return nil, wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+	}
+	refund := wxRsp.Response
+	rsp = &RefundResponse{
+		Provider:    w.Name(),
+		OutTradeNo:  refund.OutTradeNo,
+		TradeNo:     refund.TransactionId,
+		OutRefundNo: refund.OutRefundNo,
+		RefundNo:    refund.RefundId,
+		Amount:      req.Amount,
+		Currency:    currency,
+		RawStatus:   refund.Status,
+		Raw:         wxRsp,
+	}
+	return rsp, nil
+}
+
+// CloseOrder 微信仅支持按商户订单号关单
+func (w *Wechat) CloseOrder(ctx context.Context, req *CloseRequest) (err error) {
+	if err = req.check(); err != nil {
+		return err
+	}
+	if req.OutTradeNo == gopay.NULL {
+		return fmt.Errorf("[%w], %v", gopay.MissParamErr, "out_trade_no")
+	}
+	wxRsp, err := w.client.V3TransactionCloseOrder(ctx, req.OutTradeNo)
+	if err != nil {
+		return err
+	}
+	if wxRsp.Code != wechat.Success {
+		return wechatBizErr(wxRsp.Code, wxRsp.ErrResponse, wxRsp.Error)
+	}
+	return nil
+}
+
+func wechatBizErr(code int, errRsp wechat.ErrResponse, errStr string) error {
+	if errRsp.Code == gopay.NULL {
+		return &BizErr{Provider: ProviderWechat, Code: strconv.Itoa(code), Msg: errStr}
+	}
+	return &BizErr{Provider: ProviderWechat, Code: errRsp.Code, Msg: errRsp.Message}
+}
@@ -0,0 +1,357 @@
+package gopay
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string // 字段路径，如 amount.total、detail.goods_detail[0].quantity
+	Msg     string // 错误描述，如 must be > 0
+	Missing bool   // 必填字段为空
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Msg
+}
+
+// ValidationError 请求参数本地校验错误，汇总了所有不合法的字段
+// errors.Is(err, ParamValidateErr) 恒为 true；存在必填字段为空时 errors.Is(err, MissParamErr) 也为 true
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("[%v], %s", ParamValidateErr, strings.Join(msgs, "; "))
+}
+
+func (e ValidationError) Unwrap() []error {
+	errs := []error{ParamValidateErr}
+	for _, fe := range e {
+		if fe.Missing {
+			return append(errs, MissParamErr)
+		}
+	}
+	return errs
+}
+
+// Add 添加字段错误
+func (e *ValidationError) Add(field, msg string) {
+	*e = append(*e, &FieldError{Field: field, Msg: msg})
+}
+
+// AddMissing 添加必填字段为空错误
+func (e *ValidationError) AddMissing(field, msg string) {
+	*e = append(*e, &FieldError{Field: field, Msg: msg, Missing: true})
+}
+
+// Err 没有字段错误时返回 nil
+func (e ValidationError) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// CheckStruct 按 validate 标签校验结构体（或结构体指针），返回所有字段错误，字段路径取自 json 标签
+// 规则由 SchemaOf() 转换为 Schema 后校验，与 BodyMap 的 Schema 校验为同一套逻辑
+func CheckStruct(v any) (errs ValidationError) {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return errs
+	}
+	return schemaOf(rt).CheckStruct(v)
+}
+
+// ValidateStruct 同 CheckStruct()，没有字段错误时返回 nil
+func ValidateStruct(v any) error {
+	return CheckStruct(v).Err()
+}
+
+// StructToBodyMap 结构体按 json 标签转换为 BodyMap，嵌套对象转换为 BodyMap，数字保持原样
+// extra 中的参数会覆盖同名字段，用于传入请求结构体未定义的参数
+func StructToBodyMap(v any, extra BodyMap) (BodyMap, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %v", MarshalErr, err)
+	}
+	var m map[string]any
+	if err = decodeJSON(bs, &m); err != nil {
+		return nil, fmt.Errorf("[%w]: %v", UnmarshalErr, err)
+	}
+	bm := toBodyMapValue(m).(BodyMap)
+	for k, v := range extra {
+		bm.Set(k, v)
+	}
+	return bm, nil
+}
+
+func toBodyMapValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		bm := make(BodyMap, len(val))
+		for k, item := range val {
+			bm[k] = toBodyMapValue(item)
+		}
+		return bm
+	case []any:
+		for i, item := range val {
+			val[i] = toBodyMapValue(item)
+		}
+	}
+	return v
+}
+
+var schemaCache sync.Map // reflect.Type -> *Schema
+
+// SchemaOf 按结构体（或结构体指针）的 json、validate 标签生成 Schema，用于校验同一接口的 BodyMap 参数，结果按类型缓存
+// 支持的规则，多个规则以逗号分隔，如 `json:"description" validate:"required,maxlen=127"`：
+//
+//	required           必填，字符串、切片不能为空，数字不能为 0，指针不能为 nil
+//	required_without=x 同级字段 x 为空时必填，用于 out_trade_no 与 transaction_id 必填其一
+//	minlen=n、maxlen=n 字符串字符数（非字节数）或切片元素个数的范围
+//	len=n              字符串字符数必须为 n
+//	gt=n、min=n、max=n 数字的范围
+//	enum=a|b           枚举值
+//	pattern=expr       字符串必须匹配正则表达式，expr 不能包含逗号
+//	decimal            大于 0 的十进制金额，最多 2 位小数，如支付宝 total_amount
+//
+// 除 required 外的规则在字段为空时不校验；结构体、结构体指针及其切片转换为 Object、Array，匿名嵌入的结构体按 json 规则展开
+// 规则不合法时 panic
+func SchemaOf(v any) *Schema {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("gopay: SchemaOf(%T) requires a struct", v))
+	}
+	return schemaOf(rt)
+}
+
+func schemaOf(rt reflect.Type) *Schema {
+	if s, ok := schemaCache.Load(rt); ok {
+		return s.(*Schema)
+	}
+	s, _ := schemaCache.LoadOrStore(rt, NewSchema(structFields(rt)...))
+	return s.(*Schema)
+}
+
+// jsonFields 结构体的 json 字段，匿名嵌入的结构体展开，外层字段覆盖同名的嵌入字段
+func jsonFields(rt reflect.Type) (names []string, list []reflect.StructField) {
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == NULL {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedNames, embedList := jsonFields(ft)
+				for j, sub := range embedList {
+					sub.Index = append([]int{i}, sub.Index...)
+					names, list = setJsonField(names, list, embedNames[j], sub, false)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == NULL {
+			name = sf.Name
+		}
+		names, list = setJsonField(names, list, name, sf, true)
+	}
+	return names, list
+}
+
+func setJsonField(names []string, list []reflect.StructField, name string, sf reflect.StructField, override bool) ([]string, []reflect.StructField) {
+	if i := slices.Index(names, name); i >= 0 {
+		if override {
+			list[i] = sf
+		}
+		return names, list
+	}
+	return append(names, name), append(list, sf)
+}
+
+func structFields(rt reflect.Type) []*Field {
+	names, list := jsonFields(rt)
+	fields := make([]*Field, len(list))
+	for i, sf := range list {
+		fields[i] = typeField(names[i], sf.Type, sf.Tag.Get("validate"))
+	}
+	return fields
+}
+
+// typeField 按字段类型及 validate 规则生成 Field
+func typeField(name string, rt reflect.Type, rules string) *Field {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	var f *Field
+	switch rt.Kind() {
+	case reflect.String:
+		f = String(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = Int(name)
+	case reflect.Float32, reflect.Float64:
+		f = Number(name)
+	case reflect.Bool:
+		f = Bool(name)
+	case reflect.Struct:
+		f = Object(name, structFields(rt)...)
+	case reflect.Slice, reflect.Array:
+		f = Array(name, typeField(NULL, rt.Elem(), NULL))
+	default:
+		f = Any(name)
+	}
+	if rules == NULL {
+		return f
+	}
+	for _, r := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(r), "=")
+		switch rule {
+		case "required":
+			f.Required()
+		case "required_without":
+			f.RequiredWithout(arg)
+		case "minlen", "maxlen", "len":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				panic("gopay: invalid rule " + r + " of " + name)
+			}
+			switch rule {
+			case "minlen":
+				f.MinLen(n)
+			case "maxlen":
+				f.MaxLen(n)
+			default:
+				f.Len(n)
+			}
+		case "gt", "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic("gopay: invalid rule " + r + " of " + name)
+			}
+			switch rule {
+			case "gt":
+				f.Gt(n)
+			case "min":
+				f.Min(n)
+			default:
+				f.Max(n)
+			}
+		case "enum":
+			f.Enum(strings.Split(arg, "|")...)
+		case "pattern":
+			f.Pattern(arg)
+		case "decimal":
+			f.typ = TypeDecimal
+			f.Gt(0)
+		default:
+			panic("gopay: unknown rule " + r + " of " + name)
+		}
+	}
+	return f
+}
+
+// CheckStruct 按 Schema 校验结构体（或结构体指针）的 json 字段，数字 0、false 等空值视为未填写
+func (s *Schema) CheckStruct(v any) (errs ValidationError) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			errs.AddMissing("", "is required")
+			return errs
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		errs.checkObject("", structValues(rv), s.fields)
+	}
+	return errs
+}
+
+// ValidateStruct 同 CheckStruct()，没有字段错误时返回 nil
+func (s *Schema) ValidateStruct(v any) error {
+	return s.CheckStruct(v).Err()
+}
+
+// structObject 结构体的字段值，字段均为空时不视为空对象，仍校验子字段，如 amount.total: is required
+type structObject map[string]any
+
+// structValues 结构体转换为 Schema 校验使用的值，空值（见 isEmptyValue）视为未填写
+func structValues(rv reflect.Value) structObject {
+	names, list := jsonFields(rv.Type())
+	obj := make(structObject, len(list))
+	for i, sf := range list {
+		fv, err := rv.FieldByIndexErr(sf.Index)
+		if err != nil || isEmptyValue(fv) {
+			continue // 嵌入的结构体指针为 nil
+		}
+		obj[names[i]] = reflectValue(fv)
+	}
+	return obj
+}
+
+func reflectValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Struct:
+		return structValues(v)
+	case reflect.Slice, reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = reflectValue(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,142 @@
+package gopay
+
+import (
+	"errors"
+	"testing"
+)
+
+type testAmount struct {
+	Total    int64  `json:"total" validate:"required,gt=0"`
+	Currency string `json:"currency,omitempty" validate:"enum=CNY|USD"`
+}
+
+type testGoods struct {
+	Id       string `json:"id" validate:"required,maxlen=4"`
+	Quantity int64  `json:"quantity" validate:"min=1"`
+}
+
+type testBase struct {
+	OutTradeNo    string `json:"out_trade_no,omitempty" validate:"required_without=transaction_id,maxlen=8"`
+	TransactionId string `json:"transaction_id,omitempty"`
+}
+
+type testRequest struct {
+	testBase
+	Description string       `json:"description" validate:"required,maxlen=4"`
+	Price       string       `json:"price,omitempty" validate:"decimal"`
+	Amount      *testAmount  `json:"amount" validate:"required"`
+	Goods       []*testGoods `json:"goods,omitempty" validate:"maxlen=2"`
+	Extra       BodyMap      `json:"-"`
+	Detail      *testAmount  `json:"detail,omitempty"`
+}
+
+func TestCheckStruct(t *testing.T) {
+	req := &testRequest{
+		Description: "商品描述",
+		Price:       "0.01",
+		Amount:      &testAmount{Total: 1, Currency: "CNY"},
+		Goods:       []*testGoods{{Id: "g1", Quantity: 1}},
+	}
+	req.TransactionId = "4200000001"
+	if err := ValidateStruct(req); err != nil {
+		t.Fatalf("ValidateStruct: %v", err)
+	}
+
+	req = &testRequest{
+		Description: "超过四个字",
+		Price:       "0.001",
+		Amount:      &testAmount{Currency: "JPY"},
+		Goods:       []*testGoods{{Id: "g1", Quantity: -1}, {Quantity: 1}, {Id: "g3", Quantity: 1}},
+	}
+	err := ValidateStruct(req)
+	if !errors.Is(err, ParamValidateErr) || !errors.Is(err, MissParamErr) {
+		t.Fatalf("err = %v", err)
+	}
+	want := "[invalid parameter], out_trade_no: is required when transaction_id is empty; " +
+		"description: length must be <= 4; price: must be a decimal amount with at most 2 decimal places; " +
+		"amount.total: is required; amount.currency: must be one of CNY|USD; goods: length must be <= 2; " +
+		"goods[0].quantity: must be >= 1; goods[1].id: is required"
+	if err.Error() != want {
+		t.Errorf("err = %s\nwant  %s", err, want)
+	}
+
+	err = ValidateStruct(&testRequest{testBase: testBase{OutTradeNo: "A"}, Description: "A", Amount: &testAmount{Total: 1, Currency: "USD"}})
+	if err != nil {
+		t.Errorf("err = %v", err)
+	}
+	err = ValidateStruct(&testRequest{testBase: testBase{OutTradeNo: "123456789"}, Description: "A", Amount: &testAmount{Total: 1}})
+	if !errors.Is(err, ParamValidateErr) || errors.Is(err, MissParamErr) {
+		t.Errorf("err = %v", err)
+	}
+	// 字段均为空的嵌套结构体仍校验子字段
+	err = ValidateStruct(&testRequest{testBase: testBase{OutTradeNo: "A"}, Description: "A", Amount: &testAmount{}})
+	if err == nil || err.Error() != "[invalid parameter], amount.total: is required" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestStructToBodyMap(t *testing.T) {
+	req := &testRequest{
+		testBase:    testBase{OutTradeNo: "A1"},
+		Description: "desc",
+		Amount:      &testAmount{Total: 100},
+		Goods:       []*testGoods{{Id: "g1", Quantity: 2}},
+	}
+	bm, err := StructToBodyMap(req, BodyMap{"attach": "x", "description": "override"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bm.GetString("out_trade_no") != "A1" || bm.GetString("description") != "override" || bm.GetString("attach") != "x" {
+		t.Errorf("bm = %s", bm.JsonBody())
+	}
+	amount, ok := bm.GetAny("amount").(BodyMap)
+	if !ok || amount.GetString("total") != "100" {
+		t.Errorf("amount = %#v", bm.GetAny("amount"))
+	}
+	goods, ok := bm.GetAny("goods").([]any)
+	if !ok || len(goods) != 1 || goods[0].(BodyMap).GetString("quantity") != "2" {
+		t.Errorf("goods = %#v", bm.GetAny("goods"))
+	}
+	if bm.JsonBody() != `{"amount":{"total":100},"attach":"x","description":"override","goods":[{"id":"g1","quantity":2}],"out_trade_no":"A1"}` {
+		t.Errorf("json = %s", bm.JsonBody())
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf(&testRequest{})
+	if SchemaOf(testRequest{}) != schema {
+		t.Error("SchemaOf not cached")
+	}
+	// 与结构体校验相同的规则用于 BodyMap
+	bm := make(BodyMap)
+	bm.Set("description", "超过四个字").
+		Set("price", "0").
+		SetBodyMap("amount", func(b BodyMap) {
+			b.Set("total", 0).Set("currency", "JPY")
+		}).
+		Set("goods", []BodyMap{{"id": "g1", "quantity": 0}})
+	want := "[invalid parameter], out_trade_no: is required when transaction_id is empty; " +
+		"description: length must be <= 4; price: must be > 0; amount.total: must be > 0; " +
+		"amount.currency: must be one of CNY|USD; goods[0].quantity: must be >= 1"
+	if err := bm.Validate(schema); err == nil || err.Error() != want {
+		t.Errorf("err = %v\nwant  %s", err, want)
+	}
+	// 结构体转换的 BodyMap 与结构体校验结果一致
+	req := &testRequest{testBase: testBase{TransactionId: "4200000001"}, Description: "A", Amount: &testAmount{Total: 1}}
+	bm, _ = StructToBodyMap(req, nil)
+	if err := schema.Validate(bm); err != nil {
+		t.Errorf("err = %v", err)
+	}
+
+	h5 := schema.Require("detail", "detail.currency").Omit("goods")
+	if err := h5.Validate(bm); err == nil || err.Error() != "[invalid parameter], detail: is required" {
+		t.Errorf("err = %v", err)
+	}
+	bm.Set("detail", BodyMap{"total": 1}).Set("goods", "ignored")
+	if err := h5.Validate(bm); err == nil || err.Error() != "[invalid parameter], detail.currency: is required" {
+		t.Errorf("err = %v", err)
+	}
+	if err := schema.Validate(bm); err == nil || err.Error() != "[invalid parameter], goods: must be an array" {
+		t.Errorf("original schema changed: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+// Package bill 解析微信支付交易账单、资金账单（V2 DownloadBill/DownloadFundFlow 与 V3 V3BillDownLoadBill 返回的文件）
+//
+// 账单为 CSV 格式：首行为表头，明细行每个字段以 ` 开头，末尾两行为汇总表头与汇总数据；
+// tar_type = GZIP 时为 GZIP 压缩文件，自动识别并解压
+package bill
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/internal/billcsv"
+)
+
+// TradeReader 交易账单读取器
+type TradeReader = Reader[TradeRecord, TradeSummary]
+
+// FundFlowReader 资金账单读取器
+type FundFlowReader = Reader[FundFlowRecord, FundFlowSummary]
+
+// Reader 流式账单读取器，逐行解析明细，读取完毕后可获取汇总
+type Reader[R, S any] struct {
+	csv       *csv.Reader
+	columns   []*billcsv.Column // 明细表头
+	summary   *S
+	hash      hash.Hash
+	hashValue string
+	src       io.Reader // 解压后的数据流，用于读取剩余数据计算摘要
+	rc        io.Closer
+	done      bool
+	err       error
+}
+
+type Option func(*options)
+
+type options struct {
+	hashType  string
+	hashValue string
+	closer    io.Closer
+}
+
+// WithHash 校验账单摘要，hashType 支持 SHA1（V3 默认）、SHA256、MD5
+// 摘要按解压后的账单内容计算，不一致时 Next() 在读取结束时返回 gopay.BillHashErr
+func WithHash(hashType, hashValue string) Option {
+	return func(o *options) {
+		o.hashType = hashType
+		o.hashValue = hashValue
+	}
+}
+
+// WithCloser 读取完毕或出错后自动关闭 c，如下载账单的 http 应答 body
+func WithCloser(c io.Closer) Option {
+	return func(o *options) {
+		o.closer = c
+	}
+}
+
+// NewTradeReader 创建交易账单读取器
+func NewTradeReader(r io.Reader, opts ...Option) (*TradeReader, error) {
+	return NewReader[TradeRecord, TradeSummary](r, opts...)
+}
+
+// NewFundFlowReader 创建资金账单读取器
+func NewFundFlowReader(r io.Reader, opts ...Option) (*FundFlowReader, error) {
+	return NewReader[FundFlowRecord, FundFlowSummary](r, opts...)
+}
+
+// NewReader 创建账单读取器，R、S 为带 bill tag 的明细与汇总结构体
+func NewReader[R, S any](r io.Reader, opts ...Option) (*Reader[R, S], error) {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	br := bufio.NewReader(r)
+	src := io.Reader(br)
+	// GZIP 魔数 1f 8b
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("[%w]: gzip: %v", gopay.BillFormatErr, err)
+		}
+		src = gr
+	}
+	rd := &Reader[R, S]{hashValue: strings.ToLower(o.hashValue), rc: o.closer}
+	if o.hashType != gopay.NULL {
+		switch strings.ToUpper(o.hashType) {
+		case "SHA1":
+			rd.hash = sha1.New()
+		case "SHA256":
+			rd.hash = sha256.New()
+		case "MD5":
+			rd.hash = md5.New()
+		default:
+			return nil, fmt.Errorf("[%w]: unsupported hash type %s", gopay.BillFormatErr, o.hashType)
+		}
+		src = io.TeeReader(src, rd.hash)
+	}
+	rd.src = src
+	rd.csv = csv.NewReader(src)
+	rd.csv.FieldsPerRecord = -1
+	rd.csv.LazyQuotes = true
+	rd.csv.ReuseRecord = true
+
+	header, err := rd.csv.Read()
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: read header: %v", gopay.BillFormatErr, err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff") // UTF-8 BOM
+	rd.columns, _ = billcsv.Columns(reflect.TypeFor[R](), header, cleanField)
+	if rd.columns[0].Field < 0 {
+		return nil, fmt.Errorf("[%w]: unknown header %v", gopay.BillFormatErr, header)
+	}
+	return rd, nil
+}
+
+// Next 读取下一条明细，读取完毕返回 io.EOF
+func (r *Reader[R, S]) Next() (record *R, err error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for {
+		row, err := r.csv.Read()
+		if err == io.EOF {
+			r.err = r.finish()
+			_ = r.Close()
+			return nil, r.err
+		}
+		if err != nil {
+			r.err = fmt.Errorf("[%w]: %v", gopay.BillFormatErr, err)
+			_ = r.Close()
+			return nil, r.err
+		}
+		if len(row) == 0 || len(row) == 1 && cleanField(row[0]) == gopay.NULL {
+			continue
+		}
+		// 汇总表头不以 ` 开头
+		if !strings.HasPrefix(strings.TrimSpace(row[0]), "`") {
+			if r.err = r.readSummary(row); r.err == nil {
+				r.err = r.finish()
+			}
+			_ = r.Close()
+			return nil, r.err
+		}
+		record = new(R)
+		billcsv.Fill(reflect.ValueOf(record).Elem(), r.columns, row, cleanField)
+		return record, nil
+	}
+}
+
+// All 遍历全部明细，遇到错误时返回 error 并结束
+func (r *Reader[R, S]) All() iter.Seq2[*R, error] {
+	return func(yield func(*R, error) bool) {
+		for {
+			record, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Summary 账单汇总，Next() 返回 io.EOF 后可用，账单无汇总时为 nil
+func (r *Reader[R, S]) Summary() *S {
+	return r.summary
+}
+
+// Close 关闭 WithCloser 设置的数据流，未读取完毕时可提前调用
+func (r *Reader[R, S]) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	rc := r.rc
+	r.rc = nil
+	return rc.Close()
+}
+
+func (r *Reader[R, S]) readSummary(header []string) error {
+	// ReuseRecord 下次读取会覆盖 header，先解析汇总表头
+	columns, _ := billcsv.Columns(reflect.TypeFor[S](), header, cleanField)
+	row, err := r.csv.Read()
+	if err != nil {
+		return fmt.Errorf("[%w]: read summary: %v", gopay.BillFormatErr, err)
+	}
+	r.summary = new(S)
+	billcsv.Fill(reflect.ValueOf(r.summary).Elem(), columns, row, cleanField)
+	return nil
+}
+
+// finish 读取剩余数据并校验摘要
+func (r *Reader[R, S]) finish() error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	if r.hash == nil {
+		return io.EOF
+	}
+	if _, err := io.Copy(io.Discard, r.src); err != nil {
+		return fmt.Errorf("[%w]: %v", gopay.BillFormatErr, err)
+	}
+	if sum := hex.EncodeToString(r.hash.Sum(nil)); sum != r.hashValue {
+		return fmt.Errorf("[%w]: got %s, want %s", gopay.BillHashErr, sum, r.hashValue)
+	}
+	return io.EOF
+}
+
+// cleanField 去除字段前的 ` 及首尾空白
+func cleanField(s string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "`"))
+}
@@ -0,0 +1,109 @@
+package bill
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+const tradeBillAll = "\ufeff交易时间,公众账号ID,商户号,特约商户号,设备号,微信订单号,商户订单号,用户标识,交易类型,交易状态,付款银行,货币种类,应结订单金额,代金券金额,微信退款单号,商户退款单号,退款金额,充值券退款金额,退款类型,退款状态,商品名称,商户数据包,手续费,费率,订单金额,申请退款金额,费率备注\r\n" +
+	"`2014-11-10 16:33:45,`wx2421b1c4370ec43b,`10000100,`0,`1000,`1001690740201411100005734289,`1415640626,`085e9858e3ba5186aafcbaed1,`MICROPAY,`SUCCESS,`OTHERS,`CNY,`0.01,`0.0,`0,`0,`0,`0,`,`,`被扫支付测试,`订单额外描述,`0,`0.60%,`0.01,`0,`\r\n" +
+	"`2014-11-10 16:46:14,`wx2421b1c4370ec43b,`10000100,`0,`1000,`1002780740201411100005729794,`1415635270,`085e9858e90ca40c0b5aee463,`MICROPAY,`REFUND,`OTHERS,`CNY,`0.01,`0.0,`2008450740201411110000174436,`1415701182,`0.01,`0,`ORIGINAL,`SUCCESS,`被扫支付测试,`订单额外描述,`0,`0.60%,`0.01,`0.01,`\r\n" +
+	"总交易单数,应结订单总金额,退款总金额,充值券退款总金额,手续费总金额,订单总金额,申请退款总金额\r\n" +
+	"`2,`0.02,`0.01,`0.00,`0,`0.02,`0.01\r\n"
+
+const fundFlowBill = "记账时间,微信支付业务单号,资金流水单号,业务名称,业务类型,收支类型,收支金额（元）,账户结余（元）,资金变更提交申请人,备注,业务凭证号\r\n" +
+	"`2018-02-01 04:21:23,`50000305742018020103387128253,`1900009231201802015884652186,`退款,`退款,`支出,`0.02,`0.17,`system,`缺货,`REF4200000068201801293084726067\r\n" +
+	"资金流水总笔数,收入笔数,收入金额,支出笔数,支出金额\r\n" +
+	"`1.0,`0.0,`0.00,`1.0,`0.02\r\n"
+
+func TestTradeReader(t *testing.T) {
+	// GZIP 压缩并校验摘要
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, _ = gw.Write([]byte(tradeBillAll))
+	_ = gw.Close()
+	sum := sha1.Sum([]byte(tradeBillAll))
+
+	r, err := NewTradeReader(bytes.NewReader(buf.Bytes()), WithHash("SHA1", strings.ToUpper(hex.EncodeToString(sum[:]))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []*TradeRecord
+	for record, err := range r.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records", len(records))
+	}
+	if rec := records[1]; rec.OutTradeNo != "1415635270" || rec.TradeState != "REFUND" || rec.RefundId != "2008450740201411110000174436" ||
+		rec.SettlementRefundFee != "0.01" || rec.Body != "被扫支付测试" || rec.TradeTime != "2014-11-10 16:46:14" || rec.Rate != "0.60%" {
+		t.Fatalf("record: %+v", rec)
+	}
+	if s := r.Summary(); s == nil || s.TotalCount != "2" || s.SettlementTotalFee != "0.02" || s.ApplyRefundFee != "0.01" {
+		t.Fatalf("summary: %+v", s)
+	}
+
+	// 摘要不一致，读取结束后关闭数据流
+	rc := &closeCounter{Reader: strings.NewReader(tradeBillAll)}
+	r, err = NewTradeReader(rc, WithHash("SHA1", "da39a3ee5e6b4b0d3255bfef95601890afd80709"), WithCloser(rc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err = r.Next(); err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, gopay.BillHashErr) || rc.closed != 1 {
+		t.Fatalf("err: %v, closed: %d", err, rc.closed)
+	}
+	if _ = r.Close(); rc.closed != 1 {
+		t.Fatalf("closed: %d", rc.closed)
+	}
+}
+
+type closeCounter struct {
+	io.Reader
+	closed int
+}
+
+func (c *closeCounter) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestFundFlowReader(t *testing.T) {
+	r, err := NewFundFlowReader(strings.NewReader(fundFlowBill))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.FinancialType != "支出" || rec.Amount != "0.02" || rec.Balance != "0.17" || rec.VoucherNo != "REF4200000068201801293084726067" {
+		t.Fatalf("record: %+v", rec)
+	}
+	if _, err = r.Next(); err != io.EOF {
+		t.Fatalf("err: %v", err)
+	}
+	if s := r.Summary(); s == nil || s.TotalCount != "1.0" || s.ExpenseAmount != "0.02" {
+		t.Fatalf("summary: %+v", s)
+	}
+
+	// 非账单内容
+	if _, err = NewFundFlowReader(strings.NewReader(`<xml><return_code>FAIL</return_code></xml>`)); !errors.Is(err, gopay.BillFormatErr) {
+		t.Fatalf("err: %v", err)
+	}
+}
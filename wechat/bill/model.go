@@ -0,0 +1,77 @@
+package bill
+
+// 账单字段通过 bill tag 与表头对应，多个表头名称用 | 分隔（兼容 V2、V3 及不同账单类型）
+// 金额单位为元，保留账单原始字符串
+
+// TradeRecord 交易账单明细，适用于 bill_type = ALL、SUCCESS、REFUND
+// 不同账单类型的列不同，不存在的列为空
+type TradeRecord struct {
+	TradeTime           string            `bill:"交易时间"`
+	AppId               string            `bill:"公众账号ID"`
+	MchId               string            `bill:"商户号"`
+	SubMchId            string            `bill:"特约商户号|子商户号"`
+	DeviceInfo          string            `bill:"设备号"`
+	TransactionId       string            `bill:"微信订单号"`
+	OutTradeNo          string            `bill:"商户订单号"`
+	OpenId              string            `bill:"用户标识"`
+	TradeType           string            `bill:"交易类型"`
+	TradeState          string            `bill:"交易状态"`
+	BankType            string            `bill:"付款银行"`
+	FeeType             string            `bill:"货币种类"`
+	SettlementTotalFee  string            `bill:"应结订单金额"`
+	CouponFee           string            `bill:"代金券金额|代金券或立减优惠金额"`
+	RefundApplyTime     string            `bill:"退款申请时间"`
+	RefundSuccessTime   string            `bill:"退款成功时间"`
+	RefundId            string            `bill:"微信退款单号"`
+	OutRefundNo         string            `bill:"商户退款单号"`
+	SettlementRefundFee string            `bill:"退款金额"`
+	CouponRefundFee     string            `bill:"充值券退款金额|代金券或立减优惠退款金额|企业红包退款金额"`
+	RefundChannel       string            `bill:"退款类型"`
+	RefundStatus        string            `bill:"退款状态"`
+	Body                string            `bill:"商品名称"`
+	Attach              string            `bill:"商户数据包"`
+	ServiceFee          string            `bill:"手续费"`
+	Rate                string            `bill:"费率"`
+	TotalFee            string            `bill:"订单金额"`
+	RefundFee           string            `bill:"申请退款金额"`
+	RateNote            string            `bill:"费率备注"`
+	Extra               map[string]string `bill:"-"` // 未识别的列，key 为表头
+}
+
+// TradeSummary 交易账单汇总
+type TradeSummary struct {
+	TotalCount         string            `bill:"总交易单数"`
+	SettlementTotalFee string            `bill:"应结订单总金额"`
+	RefundFee          string            `bill:"退款总金额"`
+	CouponRefundFee    string            `bill:"充值券退款总金额|代金券或立减优惠退款总金额|企业红包退款总金额"`
+	ServiceFee         string            `bill:"手续费总金额"`
+	TotalFee           string            `bill:"订单总金额"`
+	ApplyRefundFee     string            `bill:"申请退款总金额"`
+	Extra              map[string]string `bill:"-"`
+}
+
+// FundFlowRecord 资金账单明细
+type FundFlowRecord struct {
+	BillingTime   string            `bill:"记账时间"`
+	TransactionId string            `bill:"微信支付业务单号"`
+	FlowId        string            `bill:"资金流水单号"`
+	BizName       string            `bill:"业务名称"`
+	BizType       string            `bill:"业务类型"`
+	FinancialType string            `bill:"收支类型"`
+	Amount        string            `bill:"收支金额（元）|收支金额(元)"`
+	Balance       string            `bill:"账户结余（元）|账户结余(元)"`
+	Applicant     string            `bill:"资金变更提交申请人"`
+	Memo          string            `bill:"备注"`
+	VoucherNo     string            `bill:"业务凭证号"`
+	Extra         map[string]string `bill:"-"`
+}
+
+// FundFlowSummary 资金账单汇总
+type FundFlowSummary struct {
+	TotalCount    string            `bill:"资金流水总笔数"`
+	IncomeCount   string            `bill:"收入笔数"`
+	IncomeAmount  string            `bill:"收入金额"`
+	ExpenseCount  string            `bill:"支出笔数"`
+	ExpenseAmount string            `bill:"支出金额"`
+	Extra         map[string]string `bill:"-"`
+}
@@ -47,7 +47,7 @@ func NewClient(appId, mchId, apiKey string, isProd bool) (client *Client) {
 		ApiKey:      apiKey,
 		IsProd:      isProd,
 		DebugSwitch: gopay.DebugOff,
-		logger:      logger,
+		logger:      gopay.NewRedactLogger(logger, nil),
 		sha256Hash:  hmac.New(sha256.New, []byte(apiKey)),
 		md5Hash:     md5.New(),
 		hc:          xhttp.NewClient(),
@@ -62,6 +62,21 @@ func (w *Client) SetBodySize(sizeMB int) {
 	}
 }
 
+// SetRetryPolicy 设置请求重试策略，同时作用于普通请求与证书请求，policy.Classifier 为空时使用 RetryClassifier
+// 重试复用首次请求的请求体（out_trade_no、nonce_str、sign 不变），之后调用 SetHttpClient、SetTLSHttpClient 需重新设置
+func (w *Client) SetRetryPolicy(policy *xhttp.RetryPolicy) {
+	policy = withClassifier(policy)
+	w.hc.SetRetryPolicy(policy)
+	w.tlsHc.SetRetryPolicy(policy)
+}
+
+// AddInterceptor 添加请求拦截器，同时作用于普通请求与证书请求
+// 之后调用 SetHttpClient、SetTLSHttpClient 需重新添加
+func (w *Client) AddInterceptor(interceptors ...*xhttp.Interceptor) {
+	w.hc.AddInterceptor(interceptors...)
+	w.tlsHc.AddInterceptor(interceptors...)
+}
+
 // SetHttpClient 设置自定义的xhttp.Client
 func (w *Client) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
@@ -78,7 +93,7 @@ func (w *Client) SetTLSHttpClient(client *xhttp.Client) {
 
 func (w *Client) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		w.logger = logger
+		w.logger = gopay.NewRedactLogger(logger, nil)
 	}
 }
 
@@ -221,7 +236,7 @@ func (w *Client) doSanBoxPost(ctx context.Context, bm gopay.BodyMap, path string
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.hc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -257,11 +272,11 @@ func (w *Client) doProdPostSelf(ctx context.Context, bm gopay.BodyMap, path stri
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	httpClient := xhttp.NewClient()
+	httpClient := xhttp.NewClient().AddInterceptor(w.hc.Interceptors()...)
 	if w.IsProd && tlsConfig != nil {
 		httpClient.SetHttpTLSConfig(tlsConfig)
 	}
-	res, bs, err := httpClient.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := httpClient.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +312,7 @@ func (w *Client) doProdPost(ctx context.Context, bm gopay.BodyMap, path string)
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.hc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -332,7 +347,7 @@ func (w *Client) doProdPostTLS(ctx context.Context, bm gopay.BodyMap, path strin
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -357,7 +372,7 @@ func (w *Client) doProdPostPure(ctx context.Context, bm gopay.BodyMap, path stri
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.hc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -382,7 +397,7 @@ func (w *Client) doProdPostPureTLS(ctx context.Context, bm gopay.BodyMap, path s
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -417,7 +432,7 @@ func (w *Client) doProdGet(ctx context.Context, bm gopay.BodyMap, path, signType
 		w.logger.Debugf("Wechat_Request: %s", bm.JsonBody())
 	}
 	uri := url + "?" + bm.EncodeURLParams()
-	res, bs, err := w.hc.Req(xhttp.TypeXML).Get(uri).EndBytes(ctx)
+	res, bs, err := w.hc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, path).Get(uri).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
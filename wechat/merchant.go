@@ -34,7 +34,7 @@ func (w *Client) Transfer(ctx context.Context, bm gopay.BodyMap) (wxRsp *Transfe
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, transfers).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (w *Client) GetTransferInfo(ctx context.Context, bm gopay.BodyMap) (wxRsp *
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, getTransferInfo).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +119,7 @@ func (w *Client) PayBank(ctx context.Context, bm gopay.BodyMap) (wxRsp *PayBankR
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, payBank).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +158,7 @@ func (w *Client) QueryBank(ctx context.Context, bm gopay.BodyMap) (wxRsp *QueryB
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, queryBank).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +192,7 @@ func (w *Client) GetRSAPublicKey(ctx context.Context, bm gopay.BodyMap) (wxRsp *
 	if w.DebugSwitch == gopay.DebugOn {
 		w.logger.Debugf("Wechat_Request: %s", req)
 	}
-	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).Post(url).SendString(req).EndBytes(ctx)
+	res, bs, err := w.tlsHc.Req(xhttp.TypeXML).SetApi(gopay.ProviderWechat, getPublicKey).Post(url).SendString(req).EndBytes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,113 @@
+package wechat
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/w6xian/gopay"
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 微信支付异步通知处理器，实现 http.Handler
+// 支付通知：解析 -> 验签 -> OnPay；退款通知：解析 -> 解密 req_info -> OnRefund
+// 应答 NotifyResponse XML，回调返回 error 时应答 FAIL，微信会按策略重试通知
+// 未注册对应回调的通知直接应答 SUCCESS
+type NotifyHandler struct {
+	apiKey   string
+	signType string
+	onPay    func(ctx context.Context, notifyReq *NotifyRequest) error
+	onRefund func(ctx context.Context, notifyReq *RefundNotifyRequest, refundNotify *RefundNotify) error
+	onError  func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化异步通知处理器
+// apiKey：API秘钥值
+// signType：下单时使用的签名类型，通知中未携带 sign_type 时使用，为空默认 SignType_MD5
+func NewNotifyHandler(apiKey, signType string) *NotifyHandler {
+	if signType == gopay.NULL {
+		signType = SignType_MD5
+	}
+	return &NotifyHandler{apiKey: apiKey, signType: signType}
+}
+
+// OnPay 注册支付结果通知回调
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *NotifyRequest) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnRefund 注册退款结果通知回调，refundNotify 为解密后的 req_info
+func (h *NotifyHandler) OnRefund(fn func(ctx context.Context, notifyReq *RefundNotifyRequest, refundNotify *RefundNotify) error) *NotifyHandler {
+	h.onRefund = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签、解密失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	rsp := &NotifyResponse{ReturnCode: gopay.SUCCESS, ReturnMsg: gopay.OK}
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		rsp = &NotifyResponse{ReturnCode: gopay.FAIL, ReturnMsg: gopay.FAIL}
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(rsp.ToXmlString()))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	bs, err := io.ReadAll(io.LimitReader(req.Body, int64(3<<20)))
+	defer req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+	bm := make(gopay.BodyMap)
+	if err = xml.Unmarshal(bs, &bm); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	ctx := req.Context()
+	// 退款通知不带签名，通过 API 秘钥解密 req_info 保证数据可信
+	if bm.GetString("req_info") != gopay.NULL {
+		notifyReq := new(RefundNotifyRequest)
+		if err = xml.Unmarshal(bs, notifyReq); err != nil {
+			return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+		}
+		refundNotify, err := DecryptRefundNotifyReqInfo(notifyReq.ReqInfo, h.apiKey)
+		if err != nil {
+			return err
+		}
+		if h.onRefund != nil {
+			return h.onRefund(ctx, notifyReq, refundNotify)
+		}
+		return nil
+	}
+	signType := bm.GetString("sign_type")
+	if signType == gopay.NULL {
+		signType = h.signType
+	}
+	ok, err := VerifySign(h.apiKey, signType, bm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, "notify sign verify failed")
+	}
+	notifyReq := new(NotifyRequest)
+	if err = xml.Unmarshal(bs, notifyReq); err != nil {
+		return fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
+	}
+	if h.onPay != nil {
+		return h.onPay(ctx, notifyReq)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+)
+
+func TestNotifyHandler(t *testing.T) {
+	key := "ziR0QKsTUfMOuochC9RfCdmfHECorQAP"
+	bm := make(gopay.BodyMap)
+	bm.Set("return_code", gopay.SUCCESS).
+		Set("result_code", gopay.SUCCESS).
+		Set("appid", "wx2421b1c4370ec43b").
+		Set("mch_id", "10000100").
+		Set("nonce_str", "5d2b6c2a8db53831f7eda20af46e531c").
+		Set("out_trade_no", "GZ202001101420131999").
+		Set("transaction_id", "1004400740201409030005092168").
+		Set("total_fee", "1")
+	bm.Set("sign", GetReleaseSign(key, SignType_MD5, bm))
+	body, err := xml.Marshal(bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *NotifyRequest
+	h := NewNotifyHandler(key, SignType_MD5).
+		OnPay(func(ctx context.Context, notifyReq *NotifyRequest) error {
+			got = notifyReq
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Debugf("notify err: %v", err)
+		})
+	serve := func(body []byte) string {
+		req := httptest.NewRequest(http.MethodPost, "/notify/wechat", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	if ack := serve(body); !strings.Contains(ack, "<![CDATA[SUCCESS]]>") {
+		t.Fatalf("ack = %s, want SUCCESS", ack)
+	}
+	if got == nil || got.OutTradeNo != "GZ202001101420131999" {
+		t.Fatalf("OnPay got: %+v", got)
+	}
+
+	// 篡改订单号，验签失败
+	tampered := bytes.Replace(body, []byte("GZ202001101420131999"), []byte("GZ202001101420132000"), 1)
+	if ack := serve(tampered); !strings.Contains(ack, "<![CDATA[FAIL]]>") {
+		t.Errorf("tampered ack = %s, want FAIL", ack)
+	}
+}
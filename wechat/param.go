@@ -86,23 +86,22 @@ func (w *Client) addCertFileContentOrPath(certFile, keyFile, pkcs12File any) (er
 	if err = checkCertFilePathOrContent(certFile, keyFile, pkcs12File); err != nil {
 		return
 	}
-	config, err := w.addCertConfig(certFile, keyFile, pkcs12File)
+	certificate, err := w.addCertConfig(certFile, keyFile, pkcs12File)
 	if err != nil {
 		return
 	}
-	w.tlsHc.SetHttpTLSConfig(config)
+	// 服务端证书校验及公钥固定沿用 tlsHc 的配置
+	w.tlsHc.SetCertificates(certificate)
 	return
 }
 
-func (w *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tls.Config, err error) {
+// addCertConfig 解析商户 API 证书，用于双向 TLS
+func (w *Client) addCertConfig(certFile, keyFile, pkcs12File any) (certificate tls.Certificate, err error) {
 	if certFile == nil && keyFile == nil && pkcs12File == nil {
-		return nil, errors.New("cert parse failed or nil")
+		return certificate, errors.New("cert parse failed or nil")
 	}
 
-	var (
-		certPem, keyPem []byte
-		certificate     tls.Certificate
-	)
+	var certPem, keyPem []byte
 	if certFile != nil && keyFile != nil {
 		if _, ok := certFile.([]byte); ok {
 			certPem = certFile.([]byte)
@@ -115,7 +114,7 @@ func (w *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 			keyPem, err = os.ReadFile(keyFile.(string))
 		}
 		if err != nil {
-			return nil, fmt.Errorf("os.ReadFile: %w", err)
+			return certificate, fmt.Errorf("os.ReadFile: %w", err)
 		}
 	} else if pkcs12File != nil {
 		var pfxData []byte
@@ -123,12 +122,12 @@ func (w *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 			pfxData = pkcs12File.([]byte)
 		} else {
 			if pfxData, err = os.ReadFile(pkcs12File.(string)); err != nil {
-				return nil, fmt.Errorf("os.ReadFile: %w", err)
+				return certificate, fmt.Errorf("os.ReadFile: %w", err)
 			}
 		}
 		blocks, err := pkcs12.ToPEM(pfxData, w.MchId)
 		if err != nil {
-			return nil, fmt.Errorf("pkcs12.ToPEM: %w", err)
+			return certificate, fmt.Errorf("pkcs12.ToPEM: %w", err)
 		}
 		for _, b := range blocks {
 			keyPem = append(keyPem, pem.EncodeToMemory(b)...)
@@ -137,15 +136,11 @@ func (w *Client) addCertConfig(certFile, keyFile, pkcs12File any) (tlsConfig *tl
 	}
 	if certPem != nil && keyPem != nil {
 		if certificate, err = tls.X509KeyPair(certPem, keyPem); err != nil {
-			return nil, fmt.Errorf("tls.LoadX509KeyPair: %w", err)
-		}
-		tlsConfig = &tls.Config{
-			Certificates:       []tls.Certificate{certificate},
-			InsecureSkipVerify: true,
+			return certificate, fmt.Errorf("tls.LoadX509KeyPair: %w", err)
 		}
-		return tlsConfig, nil
+		return certificate, nil
 	}
-	return nil, errors.New("cert files must all nil or all not nil")
+	return certificate, errors.New("cert files must all nil or all not nil")
 }
 
 func checkCertFilePathOrContent(certFile, keyFile, pkcs12File any) error {
@@ -0,0 +1,43 @@
+package wechat
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// retryErrCodes 微信支付 V2 可使用相同参数重试的业务错误码
+var retryErrCodes = map[string]bool{
+	"SYSTEMERROR": true, // 系统超时等
+	"BANKERROR":   true, // 银行系统异常
+}
+
+// RetryClassifier 微信支付 V2 重试判断
+// 微信支付接口以 out_trade_no、out_refund_no 等业务单号保证幂等，按 xhttp.IdempotentRetryClassifier 重试网络错误与 HTTP 状态码，
+// 另外 err_code 为 SYSTEMERROR、BANKERROR 时重试
+func RetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if xhttp.IdempotentRetryClassifier(req, res, body, err) {
+		return true
+	}
+	if err != nil || len(body) == 0 {
+		return false
+	}
+	rsp := new(struct {
+		ReturnCode string `xml:"return_code"`
+		ErrCode    string `xml:"err_code"`
+	})
+	if xml.Unmarshal(body, rsp) != nil {
+		return false
+	}
+	return retryErrCodes[rsp.ErrCode]
+}
+
+func withClassifier(policy *xhttp.RetryPolicy) *xhttp.RetryPolicy {
+	if policy == nil || policy.Classifier != nil {
+		return policy
+	}
+	p := *policy
+	p.Classifier = RetryClassifier
+	return &p
+}
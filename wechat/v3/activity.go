@@ -16,7 +16,7 @@ func (c *ClientV3) V3PayGiftActivityCreate(ctx context.Context, bm gopay.BodyMap
 	if err := bm.CheckEmptyError("activity_base_info", "award_send_rule"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3PayGiftActivityCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3PayGiftActivityCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -169,7 +169,7 @@ func (c *ClientV3) V3PayGiftActivityMerchantAdd(ctx context.Context, activityId
 		return nil, err
 	}
 	uri := fmt.Sprintf(v3PayGiftActivityMerchantAdd, activityId)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +197,7 @@ func (c *ClientV3) V3PayGiftActivityMerchantDelete(ctx context.Context, activity
 		return nil, err
 	}
 	uri := fmt.Sprintf(v3PayGiftActivityMerchantDelete, activityId)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -11,13 +11,13 @@ import (
 )
 
 // 提交申请单API
-// 注意：本接口会提交一些敏感信息，需调用 client.V3EncryptText() 进行加密
+// 注意：本接口会提交一些敏感信息，需调用 client.V3EncryptText() 进行加密（或设置为 wechat.SensitiveText 由 SDK 自动加密）
 // Code = 0 is success
 func (c *ClientV3) V3Apply4SubSubmit(ctx context.Context, bm gopay.BodyMap) (*Apply4SubSubmitRsp, error) {
 	if err := bm.CheckEmptyError("business_code", "contact_info", "subject_info", "business_info", "settlement_info", "bank_account_info"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3Apply4SubSubmit, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3Apply4SubSubmit, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +97,7 @@ func (c *ClientV3) V3Apply4SubModifySettlement(ctx context.Context, bm gopay.Bod
 	}
 	postUrl := fmt.Sprintf(v3Apply4SubModifySettlement, bm["sub_mchid"])
 	bm.Remove("sub_mchid")
-	authorization, err := c.authorization(MethodPost, postUrl, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, postUrl, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +124,7 @@ func (c *ClientV3) V3AsyncApply4SubModifySettlement(ctx context.Context, bm gopa
 	mode := bm.Get("modify_mode")
 	postUrl := fmt.Sprintf(v3Apply4SubModifySettlement, bm["sub_mchid"])
 	bm.Remove("sub_mchid")
-	authorization, err := c.authorization(MethodPost, postUrl, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, postUrl, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/go-pay/util/js"
 	"github.com/go-pay/xtime"
 	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/wechat/bill"
 )
 
 // 申请交易账单API
@@ -158,6 +160,16 @@ func (c *ClientV3) V3BillSubFundFlowBill(ctx context.Context, bm gopay.BodyMap)
 // 下载账单API
 // Code = 0 is success
 func (c *ClientV3) V3BillDownLoadBill(ctx context.Context, downloadUrl string) (fileBytes []byte, err error) {
+	body, err := c.downloadBill(ctx, downloadUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// downloadBill 下载账单文件，返回未读取的应答 body，由调用方读取并关闭
+func (c *ClientV3) downloadBill(ctx context.Context, downloadUrl string) (body io.ReadCloser, err error) {
 	if downloadUrl == gopay.NULL {
 		return nil, errors.New("invalid download url")
 	}
@@ -169,12 +181,50 @@ func (c *ClientV3) V3BillDownLoadBill(ctx context.Context, downloadUrl string) (
 	if err != nil {
 		return nil, err
 	}
-	res, _, bs, err := c.doProdGet(ctx, split[1], authorization)
+	res, bs, err := c.doProdGetStream(ctx, split[1], authorization)
 	if err != nil {
 		return nil, err
 	}
 	if res.StatusCode != http.StatusOK {
 		return nil, errors.New(string(bs))
 	}
-	return bs, nil
+	return res.Body, nil
+}
+
+// 下载并解析交易账单
+// tradeBill：V3BillTradeBill() 返回的 Response，按 HashType、HashValue 校验账单文件
+// 文件不一致时，读取结束时返回 gopay.BillHashErr
+// 边下载边解析并计算摘要，不将账单文件读入内存，读取完毕或出错后自动关闭连接，提前结束读取时请调用 reader.Close()
+func (c *ClientV3) V3BillTradeBillReader(ctx context.Context, tradeBill *TradeBill) (reader *bill.TradeReader, err error) {
+	if tradeBill == nil {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "tradeBill")
+	}
+	body, err := c.downloadBill(ctx, tradeBill.DownloadUrl)
+	if err != nil {
+		return nil, err
+	}
+	if reader, err = bill.NewTradeReader(body, bill.WithHash(tradeBill.HashType, tradeBill.HashValue), bill.WithCloser(body)); err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// 下载并解析资金账单
+// fundFlowBill：V3BillFundFlowBill()、V3BillSubFundFlowBill() 返回的 Response，按 HashType、HashValue 校验账单文件
+// 文件不一致时，读取结束时返回 gopay.BillHashErr
+// 边下载边解析并计算摘要，不将账单文件读入内存，读取完毕或出错后自动关闭连接，提前结束读取时请调用 reader.Close()
+func (c *ClientV3) V3BillFundFlowBillReader(ctx context.Context, fundFlowBill *TradeBill) (reader *bill.FundFlowReader, err error) {
+	if fundFlowBill == nil {
+		return nil, fmt.Errorf("[%w]: %v", gopay.MissParamErr, "fundFlowBill")
+	}
+	body, err := c.downloadBill(ctx, fundFlowBill.DownloadUrl)
+	if err != nil {
+		return nil, err
+	}
+	if reader, err = bill.NewFundFlowReader(body, bill.WithHash(fundFlowBill.HashType, fundFlowBill.HashValue), bill.WithCloser(body)); err != nil {
+		_ = body.Close()
+		return nil, err
+	}
+	return reader, nil
 }
@@ -0,0 +1,70 @@
+package wechat
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestBillReaderStream(t *testing.T) {
+	const fundFlowBill = "记账时间,微信支付业务单号,资金流水单号,业务名称,业务类型,收支类型,收支金额（元）,账户结余（元）,资金变更提交申请人,备注,业务凭证号\r\n" +
+		"`2018-02-01 04:21:23,`50000305742018020103387128253,`1900009231201802015884652186,`退款,`退款,`支出,`0.02,`0.17,`system,`缺货,`REF4200000068201801293084726067\r\n" +
+		"资金流水总笔数,收入笔数,收入金额,支出笔数,支出金额\r\n" +
+		"`1.0,`0.0,`0.00,`1.0,`0.02\r\n"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/billdownload/file" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"NOT_FOUND","message":"账单文件不存在"}`))
+			return
+		}
+		_, _ = w.Write([]byte(fundFlowBill))
+	}))
+	defer ts.Close()
+
+	c, err := NewClientV3("1900000001", "serial", "apiv3key", PrivateKeyContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetProxyHost(ts.URL)
+
+	sum := sha1.Sum([]byte(fundFlowBill))
+	fundFlow := &TradeBill{HashType: "SHA1", HashValue: hex.EncodeToString(sum[:]), DownloadUrl: "https://api.mch.weixin.qq.com/v3/billdownload/file?token=xxx"}
+	r, err := c.V3BillFundFlowBillReader(ctx, fundFlow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records int
+	for record, err := range r.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.BizType != "退款" || record.Amount != "0.02" {
+			t.Fatalf("record: %+v", record)
+		}
+		records++
+	}
+	if s := r.Summary(); records != 1 || s == nil || s.TotalCount != "1.0" {
+		t.Fatalf("records = %d, summary = %+v", records, s)
+	}
+
+	// 摘要不一致
+	fundFlow.HashValue = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+	if r, err = c.V3BillFundFlowBillReader(ctx, fundFlow); err != nil {
+		t.Fatal(err)
+	}
+	for _, err = range r.All() {
+	}
+	if !errors.Is(err, gopay.BillHashErr) {
+		t.Fatalf("err = %v", err)
+	}
+
+	// 下载失败
+	if _, err = c.V3BillTradeBillReader(ctx, &TradeBill{DownloadUrl: "https://api.mch.weixin.qq.com/v3/billdownload/none"}); err == nil {
+		t.Fatal("want error")
+	}
+}
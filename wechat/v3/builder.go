@@ -0,0 +1,185 @@
+package wechat
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 核心支付接口的强类型请求参数，调用 BodyMap() 在签名前完成本地校验（必填、枚举、长度），再传给对应的 V3 方法
+// 未定义的参数可通过 Extra 传入，Extra 中的同名参数会覆盖结构体字段
+//
+//	bm, err := (&wechat.JsapiRequest{...}).BodyMap()
+//	if err != nil {
+//		return err // 如：[invalid parameter], amount.total: must be > 0; payer.openid: is required
+//	}
+//	wxRsp, err := client.V3TransactionJsapi(ctx, bm)
+
+// PrepayRequest APP、Native 下单请求参数，同时为 JSAPI、H5 下单的公共参数
+// 文档：https://pay.weixin.qq.com/docs/merchant/apis/in-app-payment/direct-jsons/app-prepay.html
+type PrepayRequest struct {
+	Appid         string        `json:"appid" validate:"required,maxlen=32"`
+	Mchid         string        `json:"mchid,omitempty" validate:"maxlen=32"` // 为空时使用 Client 的商户号
+	Description   string        `json:"description" validate:"required,maxlen=127"`
+	OutTradeNo    string        `json:"out_trade_no" validate:"required,minlen=6,maxlen=32,pattern=^[0-9A-Za-z_|*-]+$"`
+	TimeExpire    string        `json:"time_expire,omitempty" validate:"maxlen=64"` // rfc3339 格式，如 2018-06-08T10:34:56+08:00
+	Attach        string        `json:"attach,omitempty" validate:"maxlen=128"`
+	NotifyUrl     string        `json:"notify_url" validate:"required,maxlen=256"`
+	GoodsTag      string        `json:"goods_tag,omitempty" validate:"maxlen=32"`
+	SupportFapiao bool          `json:"support_fapiao,omitempty"`
+	Amount        *PrepayAmount `json:"amount" validate:"required"`
+	Detail        *PrepayDetail `json:"detail,omitempty"`
+	SceneInfo     *PrepayScene  `json:"scene_info,omitempty"`
+	SettleInfo    *PrepaySettle `json:"settle_info,omitempty"`
+	Extra         gopay.BodyMap `json:"-"`
+}
+
+// JsapiRequest JSAPI、小程序下单请求参数
+type JsapiRequest struct {
+	PrepayRequest
+	Payer *PrepayPayer `json:"payer" validate:"required"`
+}
+
+// H5Request H5 下单请求参数，scene_info、scene_info.h5_info 必填
+type H5Request struct {
+	PrepayRequest
+}
+
+type PrepayAmount struct {
+	Total    int64  `json:"total" validate:"required,gt=0"` // 单位：分
+	Currency string `json:"currency,omitempty" validate:"enum=CNY"`
+}
+
+type PrepayPayer struct {
+	Openid string `json:"openid" validate:"required,maxlen=128"`
+}
+
+type PrepayDetail struct {
+	CostPrice   int64                `json:"cost_price,omitempty" validate:"min=0"`
+	InvoiceId   string               `json:"invoice_id,omitempty" validate:"maxlen=32"`
+	GoodsDetail []*PrepayGoodsDetail `json:"goods_detail,omitempty" validate:"maxlen=6000"`
+}
+
+type PrepayGoodsDetail struct {
+	MerchantGoodsId  string `json:"merchant_goods_id" validate:"required,maxlen=32"`
+	WechatpayGoodsId string `json:"wechatpay_goods_id,omitempty" validate:"maxlen=32"`
+	GoodsName        string `json:"goods_name,omitempty" validate:"maxlen=256"`
+	Quantity         int64  `json:"quantity" validate:"required,gt=0"`
+	UnitPrice        int64  `json:"unit_price" validate:"min=0"`
+}
+
+type PrepayScene struct {
+	PayerClientIp string             `json:"payer_client_ip" validate:"required,maxlen=45"`
+	DeviceId      string             `json:"device_id,omitempty" validate:"maxlen=32"`
+	StoreInfo     *PrepayStoreInfo   `json:"store_info,omitempty"`
+	H5Info        *PrepaySceneH5Info `json:"h5_info,omitempty"`
+}
+
+type PrepayStoreInfo struct {
+	Id       string `json:"id" validate:"required,maxlen=32"`
+	Name     string `json:"name,omitempty" validate:"maxlen=256"`
+	AreaCode string `json:"area_code,omitempty" validate:"maxlen=32"`
+	Address  string `json:"address,omitempty" validate:"maxlen=512"`
+}
+
+type PrepaySceneH5Info struct {
+	Type        string `json:"type" validate:"required,enum=iOS|Android|Wap"`
+	AppName     string `json:"app_name,omitempty" validate:"maxlen=64"`
+	AppUrl      string `json:"app_url,omitempty" validate:"maxlen=128"`
+	BundleId    string `json:"bundle_id,omitempty" validate:"maxlen=128"`
+	PackageName string `json:"package_name,omitempty" validate:"maxlen=128"`
+}
+
+type PrepaySettle struct {
+	ProfitSharing bool `json:"profit_sharing"`
+}
+
+// RefundRequest 退款申请请求参数，transaction_id 与 out_trade_no 必填其一
+// 文档：https://pay.weixin.qq.com/docs/merchant/apis/refund/refunds/create.html
+type RefundRequest struct {
+	SubMchid      string                `json:"sub_mchid,omitempty" validate:"maxlen=32"` // 服务商模式
+	TransactionId string                `json:"transaction_id,omitempty" validate:"required_without=out_trade_no,maxlen=32"`
+	OutTradeNo    string                `json:"out_trade_no,omitempty" validate:"maxlen=32"`
+	OutRefundNo   string                `json:"out_refund_no" validate:"required,maxlen=64"`
+	Reason        string                `json:"reason,omitempty" validate:"maxlen=80"`
+	NotifyUrl     string                `json:"notify_url,omitempty" validate:"maxlen=256"`
+	FundsAccount  string                `json:"funds_account,omitempty" validate:"enum=AVAILABLE|UNAVAILABLE"`
+	Amount        *RefundRequestAmount  `json:"amount" validate:"required"`
+	GoodsDetail   []*RefundRequestGoods `json:"goods_detail,omitempty"`
+	Extra         gopay.BodyMap         `json:"-"`
+}
+
+type RefundRequestAmount struct {
+	Refund   int64               `json:"refund" validate:"required,gt=0"`
+	From     []*RefundAmountFrom `json:"from,omitempty"`
+	Total    int64               `json:"total" validate:"required,gt=0"`
+	Currency string              `json:"currency" validate:"required,enum=CNY"`
+}
+
+type RefundAmountFrom struct {
+	Account string `json:"account" validate:"required,enum=AVAILABLE|UNAVAILABLE"`
+	Amount  int64  `json:"amount" validate:"required,gt=0"`
+}
+
+type RefundRequestGoods struct {
+	MerchantGoodsId  string `json:"merchant_goods_id" validate:"required,maxlen=32"`
+	WechatpayGoodsId string `json:"wechatpay_goods_id,omitempty" validate:"maxlen=32"`
+	GoodsName        string `json:"goods_name,omitempty" validate:"maxlen=256"`
+	UnitPrice        int64  `json:"unit_price" validate:"min=0"`
+	RefundAmount     int64  `json:"refund_amount" validate:"required,gt=0"`
+	RefundQuantity   int64  `json:"refund_quantity" validate:"required,gt=0"`
+}
+
+// Validate 本地校验请求参数，V3TransactionApp()、V3TransactionNative() 使用
+func (r *PrepayRequest) Validate() error {
+	return SchemaTransactionApp.ValidateStruct(r)
+}
+
+// BodyMap 校验并转换为 V3TransactionApp()、V3TransactionNative() 的请求参数
+func (r *PrepayRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return StructToBodyMap(r, r.Extra)
+}
+
+// Validate 本地校验请求参数，V3TransactionJsapi() 使用
+func (r *JsapiRequest) Validate() error {
+	return SchemaTransactionJsapi.ValidateStruct(r)
+}
+
+// BodyMap 校验并转换为 V3TransactionJsapi() 的请求参数
+func (r *JsapiRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return StructToBodyMap(r, r.Extra)
+}
+
+// Validate 本地校验请求参数，V3TransactionH5() 使用
+func (r *H5Request) Validate() error {
+	return SchemaTransactionH5.ValidateStruct(r)
+}
+
+// BodyMap 校验并转换为 V3TransactionH5() 的请求参数
+func (r *H5Request) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return StructToBodyMap(r, r.Extra)
+}
+
+// Validate 本地校验请求参数，V3Refund() 使用
+func (r *RefundRequest) Validate() error {
+	errs := SchemaRefund.CheckStruct(r)
+	if r.Amount != nil && r.Amount.Total > 0 && r.Amount.Refund > r.Amount.Total {
+		errs.Add("amount.refund", "must be <= amount.total")
+	}
+	return errs.Err()
+}
+
+// BodyMap 校验并转换为 V3Refund() 的请求参数
+func (r *RefundRequest) BodyMap() (gopay.BodyMap, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return StructToBodyMap(r, r.Extra)
+}
@@ -0,0 +1,101 @@
+package wechat
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/w6xian/gopay"
+)
+
+func TestRequestBodyMap(t *testing.T) {
+	prepay := PrepayRequest{
+		Appid:       "wx2421b1c4370ec43b",
+		Description: "测试Jsapi支付商品",
+		OutTradeNo:  "1217752501201407033233368018",
+		NotifyUrl:   "https://www.fmm.ink",
+		Amount:      &PrepayAmount{Total: 1, Currency: "CNY"},
+	}
+	tests := []struct {
+		name    string
+		req     interface{ BodyMap() (gopay.BodyMap, error) }
+		body    string
+		wantErr string
+	}{
+		{"app", &prepay,
+			`{"amount":{"currency":"CNY","total":1},"appid":"wx2421b1c4370ec43b","description":"测试Jsapi支付商品","notify_url":"https://www.fmm.ink","out_trade_no":"1217752501201407033233368018"}`, ""},
+		{"jsapi", &JsapiRequest{PrepayRequest: prepay, Payer: &PrepayPayer{Openid: "oUpF8uMuAJO_M2pxb1Q9zNjWeS6o"}},
+			`{"amount":{"currency":"CNY","total":1},"appid":"wx2421b1c4370ec43b","description":"测试Jsapi支付商品","notify_url":"https://www.fmm.ink","out_trade_no":"1217752501201407033233368018","payer":{"openid":"oUpF8uMuAJO_M2pxb1Q9zNjWeS6o"}}`, ""},
+		{"jsapi without payer", &JsapiRequest{PrepayRequest: prepay}, "", "[invalid parameter], payer: is required"},
+		{"h5 without scene", &H5Request{PrepayRequest: prepay}, "", "[invalid parameter], scene_info: is required"},
+		{"h5 type", &H5Request{PrepayRequest: func() PrepayRequest {
+			r := prepay
+			r.SceneInfo = &PrepayScene{PayerClientIp: "127.0.0.1", H5Info: &PrepaySceneH5Info{Type: "PC"}}
+			return r
+		}()}, "", "[invalid parameter], scene_info.h5_info.type: must be one of iOS|Android|Wap"},
+		{"out_trade_no pattern", &PrepayRequest{Appid: "wx01", Description: "d", OutTradeNo: "12 34 56", NotifyUrl: "https://www.fmm.ink", Amount: &PrepayAmount{}},
+			"", "[invalid parameter], out_trade_no: must match ^[0-9A-Za-z_|*-]+$; amount.total: is required"},
+		{"refund", &RefundRequest{OutTradeNo: "1217752501201407033233368018", OutRefundNo: "r01", Amount: &RefundRequestAmount{Refund: 1, Total: 1, Currency: "CNY"}},
+			`{"amount":{"currency":"CNY","refund":1,"total":1},"out_refund_no":"r01","out_trade_no":"1217752501201407033233368018"}`, ""},
+		{"refund without order", &RefundRequest{OutRefundNo: "r01", Amount: &RefundRequestAmount{Refund: 1, Total: 1, Currency: "CNY"}},
+			"", "[invalid parameter], transaction_id: is required when out_trade_no is empty"},
+		{"refund exceeds total", &RefundRequest{TransactionId: "4200000000", OutRefundNo: "r01", Amount: &RefundRequestAmount{Refund: 2, Total: 1, Currency: "CNY"}},
+			"", "[invalid parameter], amount.refund: must be <= amount.total"},
+	}
+	for _, tt := range tests {
+		bm, err := tt.req.BodyMap()
+		if tt.wantErr != "" {
+			if !errors.Is(err, gopay.ParamValidateErr) || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %s", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if body := bm.JsonBody(); body != tt.body {
+			t.Errorf("%s: body = %s", tt.name, body)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"PARAM_ERROR","message":"参数错误"}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClientV3("1900000001", "serial", "apiv3key", PrivateKeyContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetProxyHost(ts.URL)
+
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wx01").
+		Set("description", "测试Jsapi支付商品").
+		Set("out_trade_no", "1217752501201407033233368018").
+		Set("notify_url", "https://www.fmm.ink").
+		SetBodyMap("amount", func(b gopay.BodyMap) {
+			b.Set("total", 0)
+		})
+	// 默认不校验，直接发起请求
+	wxRsp, err := c.V3TransactionJsapi(ctx, bm)
+	if err != nil || wxRsp.Code != http.StatusBadRequest || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+	// 开启后校验不通过，不发起请求
+	c.SetValidateRequest(true)
+	_, err = c.V3TransactionJsapi(ctx, bm)
+	if want := "[invalid parameter], amount.total: must be > 0; payer: is required"; !errors.Is(err, gopay.ParamValidateErr) || err.Error() != want || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+	if _, err = c.V3Refund(ctx, gopay.BodyMap{"out_refund_no": "r01"}); !errors.Is(err, gopay.ParamValidateErr) || hits != 1 {
+		t.Fatalf("hits = %d, err = %v", hits, err)
+	}
+}
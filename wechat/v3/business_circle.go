@@ -13,7 +13,7 @@ import (
 // 商圈积分同步
 // Code = 0 is success
 func (c *ClientV3) V3BusinessPointsSync(ctx context.Context, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusinessPointsSync, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusinessPointsSync, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +88,7 @@ func (c *ClientV3) V3BusinessPointsStatusQuery(ctx context.Context, openid strin
 // 商圈会员停车状态同步
 // Code = 0 is success
 func (c *ClientV3) V3BusinessParkingSync(ctx context.Context, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusinessParkingSync, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusinessParkingSync, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -8,23 +8,17 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"runtime"
-	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/go-pay/crypto/aes"
 	"github.com/go-pay/crypto/xpem"
 	"github.com/go-pay/errgroup"
 	"github.com/go-pay/util"
 	"github.com/go-pay/util/convert"
 	"github.com/go-pay/util/js"
-	"github.com/go-pay/util/retry"
-	"github.com/go-pay/xtime"
 	"github.com/w6xian/gopay"
 	"github.com/w6xian/gopay/pkg/xhttp"
 )
@@ -128,10 +122,9 @@ func GetPlatformCerts(ctx context.Context, mchid, apiV3Key, serialNo, privateKey
 		if cert.EncryptCertificate != nil {
 			ec := cert.EncryptCertificate
 			eg.Go(func(ctx context.Context) error {
-				cipherBytes, _ := base64.StdEncoding.DecodeString(ec.Ciphertext)
-				pubKeyBytes, err := aes.GCMDecrypt(cipherBytes, []byte(ec.Nonce), []byte(ec.AssociatedData), []byte(apiV3Key))
+				pubKeyBytes, err := decryptResource(ec.Algorithm, ec.Ciphertext, ec.Nonce, ec.AssociatedData, apiV3Key)
 				if err != nil {
-					return fmt.Errorf("aes.GCMDecrypt, err:%+v", err)
+					return err
 				}
 				pci := &PlatformCertItem{
 					EffectiveTime: cert.EffectiveTime,
@@ -167,21 +160,18 @@ func GetPlatformSM2Certs(ctx context.Context, mchid, apiV3Key, serialNo, private
 // 注意2：请预先通过 wechat.GetPlatformCerts() 获取 微信平台公钥证书 和 证书序列号
 // 部分接口请求参数中敏感信息加密，使用此 微信支付平台公钥 和 证书序列号
 func (c *ClientV3) SetPlatformCert(wxPublicKeyContent []byte, wxSerialNo string) (err error) {
-	pubKey, err := xpem.DecodePublicKey(wxPublicKeyContent)
+	pc, err := c.decodePlatformCert(wxPublicKeyContent, wxSerialNo)
 	if err != nil {
 		return err
 	}
-	if pubKey == nil {
-		return errors.New("xpem.DecodePublicKey() failed, pubKey is nil")
-	}
-	c.wxPublicKey = pubKey
-	c.WxSerialNo = wxSerialNo
+	c.setPlatformCert(pc)
 	return nil
 }
 
 // 获取最新的 微信平台证书
 func (c *ClientV3) WxPublicKey() (wxPublicKey *rsa.PublicKey) {
-	return c.wxPublicKey
+	_, wxPublicKey = c.platformCert()
+	return wxPublicKey
 }
 
 // 获取 微信平台证书 Map（readonly）
@@ -198,55 +188,12 @@ func (c *ClientV3) WxPublicKeyMap() (wxPublicKeyMap map[string]*rsa.PublicKey) {
 // 获取证书Map集并选择最新的有效证书序列号（默认RSA证书）
 // 文档说明：https://pay.weixin.qq.com/docs/merchant/apis/platform-certificate/api-v3-get-certificates/get.html
 func (c *ClientV3) GetAndSelectNewestCert(certType ...CertType) (serialNo string, snCertMap map[string]string, err error) {
-	certs, err := c.getPlatformCerts(certType...)
+	certs, err := c.getPlatformCerts(c.ctx, certType...)
 	if err != nil {
 		return gopay.NULL, nil, err
 	}
 	if certs.Code == Success && len(certs.Certs) > 0 {
-		snCertMap = make(map[string]string)
-		// only one
-		if len(certs.Certs) == 1 {
-			formatExpire := xtime.FormatDateTime(certs.Certs[0].ExpireTime)
-			expireTime, err := time.ParseInLocation(xtime.TimeLayout, formatExpire, time.Local)
-			if err != nil {
-				return gopay.NULL, nil, fmt.Errorf("time.ParseInLocation(%s, %s),err:%w", xtime.TimeLayout, formatExpire, err)
-			}
-			if time.Since(expireTime) > 0 {
-				return gopay.NULL, nil, fmt.Errorf("wechat platform API cert expired, expired time: %s", formatExpire)
-			}
-			serialNo = certs.Certs[0].SerialNo
-			snCertMap[serialNo] = certs.Certs[0].PublicKey
-			return serialNo, snCertMap, nil
-		}
-		// more one
-		var (
-			effectiveTs []int
-			certMap     = make(map[int]*PlatformCertItem)
-		)
-		for _, v := range certs.Certs {
-			formatEffective := xtime.FormatDateTime(v.EffectiveTime)
-			formatExpire := xtime.FormatDateTime(v.ExpireTime)
-			effectiveTime, err := time.ParseInLocation(xtime.TimeLayout, formatEffective, time.Local)
-			if err != nil {
-				return gopay.NULL, nil, fmt.Errorf("time.ParseInLocation(%s, %s),err:%w", xtime.TimeLayout, formatEffective, err)
-			}
-			expireTime, err := time.ParseInLocation(xtime.TimeLayout, formatExpire, time.Local)
-			if err != nil {
-				return gopay.NULL, nil, fmt.Errorf("time.ParseInLocation(%s, %s),err:%w", xtime.TimeLayout, formatExpire, err)
-			}
-			if time.Since(expireTime) > 0 {
-				// expired
-				continue
-			}
-			eu := int(effectiveTime.Unix())
-			effectiveTs = append(effectiveTs, eu)
-			certMap[eu] = v
-			snCertMap[v.SerialNo] = v.PublicKey
-		}
-		sort.Ints(effectiveTs)
-		// newest cert
-		newestCert := certMap[effectiveTs[len(effectiveTs)-1]]
-		return newestCert.SerialNo, snCertMap, nil
+		return selectNewestCert(certs.Certs)
 	}
 	// failed
 	return gopay.NULL, nil, fmt.Errorf("GetAndSelectNewestCert() failed or certs is empty: %+v", certs)
@@ -277,7 +224,7 @@ func (c *ClientV3) GetAndSelectNewestCertALL() (serialNo string, snCertMap map[s
 //   - 加密请求消息中的敏感信息时，使用最新的平台证书（即：证书启用时间较晚的证书）
 //
 // 文档说明：https://pay.weixin.qq.com/docs/merchant/apis/platform-certificate/api-v3-get-certificates/get.html
-func (c *ClientV3) getPlatformCerts(certType ...CertType) (certs *PlatformCertRsp, err error) {
+func (c *ClientV3) getPlatformCerts(ctx context.Context, certType ...CertType) (certs *PlatformCertRsp, err error) {
 	var (
 		eg  = new(errgroup.Group)
 		mu  sync.Mutex
@@ -293,7 +240,7 @@ func (c *ClientV3) getPlatformCerts(certType ...CertType) (certs *PlatformCertRs
 	if err != nil {
 		return nil, err
 	}
-	res, _, bs, err := c.doProdGet(c.ctx, uri, authorization)
+	res, _, bs, err := c.doProdGet(ctx, uri, authorization)
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +259,7 @@ func (c *ClientV3) getPlatformCerts(certType ...CertType) (certs *PlatformCertRs
 		if cert.EncryptCertificate != nil {
 			ec := cert.EncryptCertificate
 			eg.Go(func(ctx context.Context) error {
-				pubKey, err := c.decryptCerts(ec.Ciphertext, ec.Nonce, ec.AssociatedData)
+				pubKey, err := c.decryptCerts(ec.Algorithm, ec.Ciphertext, ec.Nonce, ec.AssociatedData)
 				if err != nil {
 					return err
 				}
@@ -335,50 +282,11 @@ func (c *ClientV3) getPlatformCerts(certType ...CertType) (certs *PlatformCertRs
 	return certs, nil
 }
 
-// 解密加密的证书
-func (c *ClientV3) decryptCerts(ciphertext, nonce, additional string) (wxCerts string, err error) {
-	cipherBytes, _ := base64.StdEncoding.DecodeString(ciphertext)
-	decrypt, err := aes.GCMDecrypt(cipherBytes, []byte(nonce), []byte(additional), c.ApiV3Key)
+// 解密加密的证书，仅支持 AEAD_AES_256_GCM
+func (c *ClientV3) decryptCerts(algorithm, ciphertext, nonce, additional string) (wxCerts string, err error) {
+	decrypt, err := decryptResource(algorithm, ciphertext, nonce, additional, string(c.ApiV3Key))
 	if err != nil {
-		return "", fmt.Errorf("aes.GCMDecrypt, err:%w", err)
+		return "", err
 	}
 	return string(decrypt), nil
 }
-
-func (c *ClientV3) autoCheckCertProc() {
-	c.logger.Warn("auto refresh wechat platform public key")
-	defer func() {
-		if r := recover(); r != nil {
-			buf := make([]byte, 64<<10)
-			buf = buf[:runtime.Stack(buf, false)]
-			c.logger.Errorf("autoCheckCertProc: panic recovered: %s\n%s", r, buf)
-			// 重启
-			c.autoCheckCertProc()
-		}
-	}()
-	for {
-		time.Sleep(time.Hour * 12)
-		err := retry.Retry(func() error {
-			serialNo, snCertMap, err := c.GetAndSelectNewestCert()
-			if err != nil {
-				return err
-			}
-			for sn, cert := range snCertMap {
-				pubKey, err := xpem.DecodePublicKey([]byte(cert))
-				if err != nil {
-					return err
-				}
-				c.SnCertMap.Store(sn, pubKey)
-				if sn == serialNo {
-					c.wxPublicKey = pubKey
-				}
-			}
-			c.WxSerialNo = serialNo
-			return nil
-		}, 3, time.Second)
-		if err != nil {
-			c.logger.Errorf("c.GetAndSelectNewestCert()，err:%+v", err)
-			continue
-		}
-	}
-}
@@ -0,0 +1,328 @@
+package wechat
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-pay/xtime"
+	"github.com/w6xian/gopay"
+)
+
+const (
+	// 微信要求证书刷新间隔小于 12 小时
+	defaultCertRefreshInterval = 11 * time.Hour
+	// 验签发现未知证书序列号时，两次拉取证书的最小间隔
+	minCertRefreshGap = time.Minute
+)
+
+// platformCert 当前使用的微信平台证书，整体原子替换
+type platformCert struct {
+	serialNo  string
+	publicKey *rsa.PublicKey
+	expireAt  time.Time
+}
+
+// CertSnapshot 微信平台证书快照，保存于 CertStore
+type CertSnapshot struct {
+	Certs     []*PlatformCertItem `json:"certs"`
+	UpdatedAt time.Time           `json:"updated_at"` // 从微信拉取的时间
+}
+
+// CertStore 微信平台证书共享存储，由业务方实现（如 Redis、数据库）
+// 多实例部署时，快照未过期（UpdatedAt 在刷新间隔内）的实例直接使用存储中的证书，避免每个实例都请求 /v3/certificates
+type CertStore interface {
+	// Load 读取商户的证书快照，不存在时返回 nil, nil
+	Load(ctx context.Context, mchid string) (snap *CertSnapshot, err error)
+	// Save 保存商户的证书快照
+	Save(ctx context.Context, mchid string, snap *CertSnapshot) error
+}
+
+// CertManager 微信平台证书管理：定时刷新、原子替换、证书轮换与即将过期回调
+// 通过 client.CertManager() 获取，client.AutoVerifySign() 时自动启动
+type CertManager struct {
+	client   *ClientV3
+	mu       sync.Mutex // 串行化刷新及配置
+	store    CertStore
+	interval time.Duration
+	onRotate func(oldSerialNo, newSerialNo string)
+	onExpire func(serialNo string, expireAt time.Time)
+	expireIn time.Duration
+	lastPull time.Time
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newCertManager(c *ClientV3) *CertManager {
+	return &CertManager{client: c, interval: defaultCertRefreshInterval}
+}
+
+// SetRefreshInterval 设置证书刷新间隔，默认 11 小时，微信要求小于 12 小时
+func (m *CertManager) SetRefreshInterval(interval time.Duration) *CertManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if interval > 0 {
+		m.interval = interval
+	}
+	return m
+}
+
+// SetStore 设置证书共享存储，多实例部署时推荐设置
+func (m *CertManager) SetStore(store CertStore) *CertManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.store = store
+	return m
+}
+
+// OnRotate 设置证书轮换回调，最新证书序列号变化时调用
+func (m *CertManager) OnRotate(fn func(oldSerialNo, newSerialNo string)) *CertManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRotate = fn
+	return m
+}
+
+// OnExpire 设置证书即将过期回调，每次刷新后最新证书在 within 内过期时调用
+func (m *CertManager) OnExpire(within time.Duration, fn func(serialNo string, expireAt time.Time)) *CertManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireIn = within
+	m.onExpire = fn
+	return m
+}
+
+// Start 启动定时刷新，ctx 取消或调用 Close() 后停止，重复调用无效
+func (m *CertManager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	go m.loop(ctx, m.done)
+}
+
+// Close 停止定时刷新并等待刷新协程退出，可重复调用
+func (m *CertManager) Close() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Refresh 立即刷新证书，优先使用共享存储中未过期的快照
+func (m *CertManager) Refresh(ctx context.Context) (err error) {
+	return m.refresh(ctx, false)
+}
+
+func (m *CertManager) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	m.client.logger.Warn("auto refresh wechat platform public key")
+	timer := time.NewTimer(m.nextDelay())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		var err error
+		for i := 0; i < 3; i++ {
+			if err = m.safeRefresh(ctx); err == nil || ctx.Err() != nil {
+				break
+			}
+			if sleepErr := sleepCtx(ctx, time.Second); sleepErr != nil {
+				break
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			m.client.logger.Errorf("CertManager.Refresh()，err:%+v", err)
+		}
+		timer.Reset(m.nextDelay())
+	}
+}
+
+func (m *CertManager) safeRefresh(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 64<<10)
+			buf = buf[:runtime.Stack(buf, false)]
+			err = fmt.Errorf("panic recovered: %v\n%s", r, buf)
+		}
+	}()
+	return m.refresh(ctx, false)
+}
+
+// nextDelay 刷新间隔减去随机抖动 [0, interval/10]，避免多实例同时刷新，且不超过设置的刷新间隔
+func (m *CertManager) nextDelay() time.Duration {
+	m.mu.Lock()
+	interval := m.interval
+	m.mu.Unlock()
+	return interval - time.Duration(rand.Int63n(int64(interval)/10+1))
+}
+
+// refresh 刷新证书，pull 为 true 时跳过共享存储直接从微信拉取（两次拉取间隔不小于 minCertRefreshGap）
+// 回调在释放锁后执行，回调内可调用 CertManager 的方法
+func (m *CertManager) refresh(ctx context.Context, pull bool) (err error) {
+	m.mu.Lock()
+	old, newest, err := m.refreshLocked(ctx, pull)
+	onRotate, onExpire, expireIn := m.onRotate, m.onExpire, m.expireIn
+	m.mu.Unlock()
+	if err != nil || newest == nil {
+		return err
+	}
+	if old != nil && old.serialNo != newest.serialNo && onRotate != nil {
+		onRotate(old.serialNo, newest.serialNo)
+	}
+	if onExpire != nil && time.Until(newest.expireAt) < expireIn {
+		onExpire(newest.serialNo, newest.expireAt)
+	}
+	return nil
+}
+
+func (m *CertManager) refreshLocked(ctx context.Context, pull bool) (old, newest *platformCert, err error) {
+	var snap *CertSnapshot
+	if !pull && m.store != nil {
+		if snap, err = m.store.Load(ctx, m.client.Mchid); err != nil {
+			return nil, nil, err
+		}
+		if snap != nil && time.Since(snap.UpdatedAt) >= m.interval {
+			snap = nil
+		}
+	}
+	if snap == nil {
+		if pull && time.Since(m.lastPull) < minCertRefreshGap {
+			return nil, nil, nil
+		}
+		certs, err := m.client.getPlatformCerts(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if certs.Code != Success || len(certs.Certs) == 0 {
+			return nil, nil, fmt.Errorf("get platform certs failed or certs is empty: %+v", certs)
+		}
+		m.lastPull = time.Now()
+		snap = &CertSnapshot{Certs: certs.Certs, UpdatedAt: m.lastPull}
+		if m.store != nil {
+			if err = m.store.Save(ctx, m.client.Mchid, snap); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if newest, err = m.apply(snap); err != nil {
+		return nil, nil, err
+	}
+	return m.client.cert.Swap(newest), newest, nil
+}
+
+// apply 解析快照，证书公钥写入 SnCertMap，返回最新证书
+func (m *CertManager) apply(snap *CertSnapshot) (newest *platformCert, err error) {
+	serialNo, snCertMap, err := selectNewestCert(snap.Certs)
+	if err != nil {
+		return nil, err
+	}
+	for sn, cert := range snCertMap {
+		pc, err := m.client.decodePlatformCert([]byte(cert), sn)
+		if err != nil {
+			return nil, err
+		}
+		m.client.storePlatformCert(pc)
+		if sn == serialNo {
+			newest = pc
+		}
+	}
+	for _, v := range snap.Certs {
+		if v.SerialNo == serialNo {
+			newest.expireAt, _ = parseCertTime(v.ExpireTime)
+		}
+	}
+	return newest, nil
+}
+
+// selectNewestCert 过滤已过期的证书，并选择启用时间最晚的证书
+func selectNewestCert(certs []*PlatformCertItem) (serialNo string, snCertMap map[string]string, err error) {
+	var (
+		newest   time.Time
+		hasValid bool
+	)
+	snCertMap = make(map[string]string)
+	for _, v := range certs {
+		effectiveTime, err := parseCertTime(v.EffectiveTime)
+		if err != nil {
+			return gopay.NULL, nil, err
+		}
+		expireTime, err := parseCertTime(v.ExpireTime)
+		if err != nil {
+			return gopay.NULL, nil, err
+		}
+		if time.Since(expireTime) > 0 {
+			// expired
+			continue
+		}
+		snCertMap[v.SerialNo] = v.PublicKey
+		if !hasValid || effectiveTime.After(newest) {
+			newest, serialNo, hasValid = effectiveTime, v.SerialNo, true
+		}
+	}
+	if !hasValid {
+		return gopay.NULL, nil, errors.New("wechat platform API certs all expired")
+	}
+	return serialNo, snCertMap, nil
+}
+
+func parseCertTime(t string) (time.Time, error) {
+	formatted := xtime.FormatDateTime(t)
+	tm, err := time.ParseInLocation(xtime.TimeLayout, formatted, time.Local)
+	if err != nil {
+		return tm, fmt.Errorf("time.ParseInLocation(%s, %s),err:%w", xtime.TimeLayout, formatted, err)
+	}
+	return tm, nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// =============================== 内存 ===============================
+
+// MemoryCertStore 基于内存的证书存储，同一进程内多个 ClientV3 共享
+type MemoryCertStore struct {
+	mu    sync.RWMutex
+	snaps map[string]*CertSnapshot
+}
+
+// NewMemoryCertStore 初始化内存证书存储
+func NewMemoryCertStore() *MemoryCertStore {
+	return &MemoryCertStore{snaps: make(map[string]*CertSnapshot)}
+}
+
+func (s *MemoryCertStore) Load(_ context.Context, mchid string) (snap *CertSnapshot, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.snaps[mchid], nil
+}
+
+func (s *MemoryCertStore) Save(_ context.Context, mchid string, snap *CertSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snaps[mchid] = snap
+	return nil
+}
@@ -0,0 +1,16 @@
+package wechat
+
+import (
+	"testing"
+	"time"
+)
+
+// 刷新间隔只向下抖动，不超过微信要求的 12 小时
+func TestCertManagerNextDelay(t *testing.T) {
+	m := newCertManager(nil)
+	for i := 0; i < 1000; i++ {
+		if d := m.nextDelay(); d > defaultCertRefreshInterval || d < defaultCertRefreshInterval*9/10 || d >= 12*time.Hour {
+			t.Fatalf("nextDelay = %s", d)
+		}
+	}
+}
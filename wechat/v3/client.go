@@ -5,6 +5,7 @@ import (
 	"crypto/rsa"
 	"errors"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-pay/crypto/xpem"
 	"github.com/go-pay/smap"
@@ -15,20 +16,26 @@ import (
 
 // ClientV3 微信支付 V3
 type ClientV3 struct {
-	Mchid         string
-	ApiV3Key      []byte
-	SerialNo      string
-	WxSerialNo    string // 微信支付公钥ID（微信平台证书序列号）
-	proxyHost     string // 代理host地址
-	autoSign      bool
-	hc            *xhttp.Client
-	privateKey    *rsa.PrivateKey
-	wxPublicKey   *rsa.PublicKey
-	ctx           context.Context
-	DebugSwitch   gopay.DebugSwitch
-	requestIdFunc xhttp.RequestIdHandler
-	logger        xlog.XLogger
-	SnCertMap     smap.Map[string, *rsa.PublicKey] // key: serial_no
+	Mchid    string
+	ApiV3Key []byte
+	SerialNo string
+	// Deprecated: 仅为兼容保留，SDK 不再读写此字段；
+	// 请使用 SetPlatformCert() 设置、GetWxSerialNo() 获取当前微信平台证书序列号（微信支付公钥ID）
+	WxSerialNo      string
+	proxyHost       string // 代理host地址
+	failover        *hostFailover
+	autoSign        bool
+	autoDecrypt     bool // 自动解密返回参数中的敏感信息
+	validateRequest bool // 请求前按接口的 Schema 校验参数
+	hc              *xhttp.Client
+	privateKey      *rsa.PrivateKey
+	cert            atomic.Pointer[platformCert] // 当前使用的微信平台证书
+	certManager     *CertManager
+	ctx             context.Context
+	DebugSwitch     gopay.DebugSwitch
+	requestIdFunc   xhttp.RequestIdHandler
+	logger          xlog.XLogger
+	SnCertMap       smap.Map[string, *rsa.PublicKey] // key: serial_no
 }
 
 // NewClientV3 初始化微信客户端 V3
@@ -53,10 +60,12 @@ func NewClientV3(mchid, serialNo, apiV3Key, privateKey string) (client *ClientV3
 		privateKey:    priKey,
 		ctx:           context.Background(),
 		DebugSwitch:   gopay.DebugOff,
-		logger:        logger,
+		logger:        gopay.NewRedactLogger(logger, nil),
 		requestIdFunc: defaultRequestIdFunc,
 		hc:            xhttp.NewClient(),
+		failover:      newHostFailover(),
 	}
+	client.certManager = newCertManager(client)
 	return client, nil
 }
 
@@ -68,32 +77,19 @@ func (c *ClientV3) SetRequestIdFunc(requestIdFunc xhttp.RequestIdHandler) {
 }
 
 // AutoVerifySign 开启请求完自动验签功能（默认不开启，推荐开启）
-// 开启自动验签，自动开启每12小时一次轮询，请求最新证书操作
+// 开启自动验签，自动开启每11小时（减去随机抖动）一次轮询，请求最新证书操作
 // autoRefresh：是否自动刷新证书，默认 true：自动刷新
 // 说明：开启自动验签功能，会自动获取并刷新微信平台证书，并同步验签。
 // 注意：此方法仅支持微信平台证书验签，不支持微信支付公钥验签，如使用微信支付公钥验签请使用 AutoVerifySignByPublicKey() 方法
 func (c *ClientV3) AutoVerifySign(autoRefresh ...bool) (err error) {
-	wxSerialNo, certMap, err := c.GetAndSelectNewestCert()
-	if err != nil {
+	if err = c.certManager.Refresh(c.ctx); err != nil {
 		return err
 	}
-	for sn, cert := range certMap {
-		// decode cert
-		pubKey, err := xpem.DecodePublicKey([]byte(cert))
-		if err != nil {
-			return err
-		}
-		c.SnCertMap.Store(sn, pubKey)
-		if sn == wxSerialNo {
-			c.wxPublicKey = pubKey
-		}
-	}
-	c.WxSerialNo = wxSerialNo
 	c.autoSign = true
 	if len(autoRefresh) == 1 && !autoRefresh[0] {
 		return nil
 	}
-	go c.autoCheckCertProc()
+	c.certManager.Start(c.ctx)
 	return nil
 }
 
@@ -109,20 +105,62 @@ func (c *ClientV3) AutoVerifySignByPublicKey(wxPublicKeyContent []byte, wxPublic
 // wxPublicKeyContent：微信公钥证书文件内容[]byte
 // wxPublicKeyID：微信公钥证书ID，即 证书序列号
 func (c *ClientV3) AutoVerifySignByCert(wxPublicKeyContent []byte, wxPublicKeyID string) (err error) {
-	pubKey, err := xpem.DecodePublicKey(wxPublicKeyContent)
+	pc, err := c.decodePlatformCert(wxPublicKeyContent, wxPublicKeyID)
 	if err != nil {
 		return err
 	}
-	if pubKey == nil {
-		return errors.New("xpem.DecodePublicKey() failed, pubKey is nil")
-	}
-	c.SnCertMap.Store(wxPublicKeyID, pubKey)
-	c.wxPublicKey = pubKey
-	c.WxSerialNo = wxPublicKeyID
+	c.storePlatformCert(pc)
+	c.setPlatformCert(pc)
 	c.autoSign = true
 	return nil
 }
 
+// CertManager 返回微信平台证书管理器，可设置刷新间隔、共享存储及证书轮换、过期回调
+func (c *ClientV3) CertManager() *CertManager {
+	return c.certManager
+}
+
+// Close 停止微信平台证书自动刷新
+func (c *ClientV3) Close() {
+	c.certManager.Close()
+}
+
+// GetWxSerialNo 返回当前使用的微信平台证书序列号（微信支付公钥ID）
+func (c *ClientV3) GetWxSerialNo() string {
+	serialNo, _ := c.platformCert()
+	return serialNo
+}
+
+// setPlatformCert 设置当前使用的微信平台证书
+func (c *ClientV3) setPlatformCert(pc *platformCert) {
+	c.cert.Store(pc)
+}
+
+// decodePlatformCert 解析微信平台证书或微信支付公钥
+func (c *ClientV3) decodePlatformCert(wxPublicKeyContent []byte, serialNo string) (pc *platformCert, err error) {
+	pc = &platformCert{serialNo: serialNo}
+	if pc.publicKey, err = xpem.DecodePublicKey(wxPublicKeyContent); err != nil {
+		return nil, err
+	}
+	if pc.publicKey == nil {
+		return nil, errors.New("xpem.DecodePublicKey() failed, pubKey is nil")
+	}
+	return pc, nil
+}
+
+// storePlatformCert 平台证书公钥写入 SnCertMap
+func (c *ClientV3) storePlatformCert(pc *platformCert) {
+	c.SnCertMap.Store(pc.serialNo, pc.publicKey)
+}
+
+// platformCert 当前使用的微信平台证书，未设置时返回空
+func (c *ClientV3) platformCert() (serialNo string, publicKey *rsa.PublicKey) {
+	if pc := c.cert.Load(); pc != nil {
+		return pc.serialNo, pc.publicKey
+	}
+	return "", nil
+}
+
 // SetBodySize 设置http response body size(MB)
 func (c *ClientV3) SetBodySize(sizeMB int) {
 	if sizeMB > 0 {
@@ -130,6 +168,12 @@ func (c *ClientV3) SetBodySize(sizeMB int) {
 	}
 }
 
+// SetRetryPolicy 设置请求重试策略，policy.Classifier 为空时使用 RetryClassifier
+// 重试复用首次请求的请求头与请求体（签名、out_trade_no 不变），之后调用 SetHttpClient 需重新设置
+func (c *ClientV3) SetRetryPolicy(policy *xhttp.RetryPolicy) {
+	c.hc.SetRetryPolicy(withClassifier(policy))
+}
+
 // SetHttpClient 设置自定义的xhttp.Client
 func (c *ClientV3) SetHttpClient(client *xhttp.Client) {
 	if client != nil {
@@ -140,8 +184,28 @@ func (c *ClientV3) SetHttpClient(client *xhttp.Client) {
 // SetLogger 设置自定义 logger
 func (c *ClientV3) SetLogger(logger xlog.XLogger) {
 	if logger != nil {
-		c.logger = logger
+		c.logger = gopay.NewRedactLogger(logger, nil)
+	}
+}
+
+// SetAutoDecrypt 设置是否自动解密返回参数中的敏感信息（默认不开启）
+// 开启后，投诉人联系方式、转账收款方姓名等标记 `sensitive:"true"` 的返回参数在验签后使用商户私钥解密，无需再调用 client.V3DecryptText()
+func (c *ClientV3) SetAutoDecrypt(autoDecrypt bool) {
+	c.autoDecrypt = autoDecrypt
+}
+
+// SetValidateRequest 设置是否在请求前按接口的 Schema 本地校验参数（默认不开启）
+// 开启后，下单、退款接口在签名前使用 SchemaTransactionJsapi、SchemaRefund 等校验 BodyMap，不合法时返回 gopay.ParamValidateErr，不发起请求
+func (c *ClientV3) SetValidateRequest(validate bool) {
+	c.validateRequest = validate
+}
+
+// checkRequest 开启 SetValidateRequest() 时按 schema 校验请求参数
+func (c *ClientV3) checkRequest(schema *gopay.Schema, bm gopay.BodyMap) error {
+	if !c.validateRequest {
+		return nil
 	}
+	return schema.Validate(bm)
 }
 
 // SetProxyHost 设置的 ProxyHost
@@ -16,7 +16,7 @@ import (
 func (c *ClientV3) V3ComplaintNotifyUrlCreate(ctx context.Context, url string) (wxRsp *ComplaintNotifyUrlRsp, err error) {
 	bm := make(gopay.BodyMap)
 	bm.Set("url", url)
-	authorization, err := c.authorization(MethodPost, v3ComplaintNotifyUrlCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ComplaintNotifyUrlCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func (c *ClientV3) V3ComplaintNotifyUrlQuery(ctx context.Context) (wxRsp *Compla
 func (c *ClientV3) V3ComplaintNotifyUrlUpdate(ctx context.Context, url string) (wxRsp *ComplaintNotifyUrlRsp, err error) {
 	bm := make(gopay.BodyMap)
 	bm.Set("url", url)
-	authorization, err := c.authorization(MethodPut, v3ComplaintNotifyUrlUpdate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPut, v3ComplaintNotifyUrlUpdate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +198,10 @@ func (c *ClientV3) V3ComplaintList(ctx context.Context, bm gopay.BodyMap) (wxRsp
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 查询投诉协商历史API
@@ -250,14 +253,17 @@ func (c *ClientV3) V3ComplaintDetail(ctx context.Context, complaintId string) (w
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 回复用户API
 // Code = 0 is success
 func (c *ClientV3) V3ComplaintResponse(ctx context.Context, complaintId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3ComplaintResponse, complaintId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +285,7 @@ func (c *ClientV3) V3ComplaintResponse(ctx context.Context, complaintId string,
 // Code = 0 is success
 func (c *ClientV3) V3ComplaintComplete(ctx context.Context, complaintId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3ComplaintComplete, complaintId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -301,7 +307,7 @@ func (c *ClientV3) V3ComplaintComplete(ctx context.Context, complaintId string,
 // Code = 0 is success
 func (c *ClientV3) V3ComplaintUpdateRefundProgress(ctx context.Context, complaintId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3ComplaintUpdateRefundProgress, complaintId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -25,6 +25,8 @@ const (
 
 	Authorization = "WECHATPAY2-SHA256-RSA2048"
 
+	AlgorithmAES256GCM = "AEAD_AES_256_GCM"
+
 	v3BaseUrlCh       = "https://api.mch.weixin.qq.com"  // 中国国内
 	v3BaseUrlChBackup = "https://api2.mch.weixin.qq.com" // 中国国内
 
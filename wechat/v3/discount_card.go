@@ -13,7 +13,7 @@ import (
 // 预受理领卡请求API
 // Code = 0 is success
 func (c *ClientV3) V3DiscountCardApply(ctx context.Context, bm gopay.BodyMap) (wxRsp *DiscountCardApplyRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3CardPre, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CardPre, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func (c *ClientV3) V3DiscountCardAddUser(ctx context.Context, bm gopay.BodyMap)
 	}
 	uri := fmt.Sprintf(v3CardAddUser, bm.GetString("out_card_code"))
 	bm.Remove("out_card_code")
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -11,10 +11,10 @@ import (
 )
 
 // 二级商户进件API
-// 注意：本接口会提交一些敏感信息，需调用 client.V3EncryptText() 进行加密。部分图片参数，请先调用 client.V3MediaUploadImage() 上传，获取MediaId
+// 注意：本接口会提交一些敏感信息，需调用 client.V3EncryptText() 进行加密（或设置为 wechat.SensitiveText 由 SDK 自动加密）。部分图片参数，请先调用 client.V3MediaUploadImage() 上传，获取MediaId
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceApply(ctx context.Context, bm gopay.BodyMap) (*EcommerceApplyRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceApply, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceApply, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (c *ClientV3) V3EcommerceApplyStatus(ctx context.Context, applyId int64, ou
 // 请求分账API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceProfitShare(ctx context.Context, bm gopay.BodyMap) (*EcommerceProfitShareRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceProfitShare, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceProfitShare, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +121,7 @@ func (c *ClientV3) V3EcommerceProfitShareQuery(ctx context.Context, bm gopay.Bod
 // 请求分账回退API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceProfitShareReturn(ctx context.Context, bm gopay.BodyMap) (*EcommerceProfitShareReturnRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceProfitShareReturn, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceProfitShareReturn, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -170,7 +170,7 @@ func (c *ClientV3) V3EcommerceProfitShareReturnResult(ctx context.Context, bm go
 // 完结分账API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceProfitShareFinish(ctx context.Context, bm gopay.BodyMap) (*EcommerceProfitShareFinishRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceProfitShareFinish, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceProfitShareFinish, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +219,7 @@ func (c *ClientV3) V3EcommerceProfitShareUnsplitAmount(ctx context.Context, tran
 // 添加分账接收方API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceProfitShareAddReceiver(ctx context.Context, bm gopay.BodyMap) (*EcommerceProfitShareAddReceiverRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceProfitShareAddReceiver, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceProfitShareAddReceiver, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -243,7 +243,7 @@ func (c *ClientV3) V3EcommerceProfitShareAddReceiver(ctx context.Context, bm gop
 // 删除分账接收方API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceProfitShareDeleteReceiver(ctx context.Context, bm gopay.BodyMap) (*EcommerceProfitShareDeleteReceiverRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceProfitShareDeleteReceiver, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceProfitShareDeleteReceiver, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +268,7 @@ func (c *ClientV3) V3EcommerceProfitShareDeleteReceiver(ctx context.Context, bm
 // 请求补差API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceSubsidies(ctx context.Context, bm gopay.BodyMap) (*EcommerceSubsidiesRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceSubsidies, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceSubsidies, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +292,7 @@ func (c *ClientV3) V3EcommerceSubsidies(ctx context.Context, bm gopay.BodyMap) (
 // 请求补差回退API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceSubsidiesReturn(ctx context.Context, bm gopay.BodyMap) (*EcommerceSubsidiesReturnRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceSubsidiesReturn, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceSubsidiesReturn, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -316,7 +316,7 @@ func (c *ClientV3) V3EcommerceSubsidiesReturn(ctx context.Context, bm gopay.Body
 // 取消补差API
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceSubsidiesCancel(ctx context.Context, bm gopay.BodyMap) (*EcommerceSubsidiesCancelRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3EcommerceSubsidiesCancel, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceSubsidiesCancel, bm)
 	if err != nil {
 		return nil, err
 	}
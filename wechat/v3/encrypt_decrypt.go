@@ -16,11 +16,18 @@ import (
 )
 
 // 敏感信息加密，默认使用最新的有效微信平台证书加密
+// 请求头 Wechatpay-Serial 需与加密使用的证书一致，推荐使用 SensitiveText 由 SDK 自动加密
 func (c *ClientV3) V3EncryptText(text string) (cipherText string, err error) {
-	if c.wxPublicKey == nil || c.WxSerialNo == "" {
-		return gopay.NULL, errors.New("WxPublicKey or WxSerialNo is null")
+	pc, err := c.encryptCert()
+	if err != nil {
+		return gopay.NULL, err
 	}
-	cipherByte, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, c.wxPublicKey, []byte(text), nil)
+	return pc.encrypt(text)
+}
+
+// encrypt 使用平台证书公钥加密敏感信息
+func (pc *platformCert) encrypt(text string) (cipherText string, err error) {
+	cipherByte, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pc.publicKey, []byte(text), nil)
 	if err != nil {
 		return "", fmt.Errorf("rsa.EncryptOAEP: %w", err)
 	}
@@ -76,6 +83,17 @@ func V3DecryptNotifyCipherTextToBytes(ciphertext, nonce, additional, apiV3Key st
 	return decrypt, nil
 }
 
+// decryptResource 按 algorithm 解密通知、证书等加密数据，algorithm 为空时按 AEAD_AES_256_GCM 处理
+// 国密 AEAD_SM4_GCM 暂不支持，返回错误
+func decryptResource(algorithm, ciphertext, nonce, additional, apiV3Key string) (decrypt []byte, err error) {
+	switch algorithm {
+	case AlgorithmAES256GCM, "":
+		return V3DecryptNotifyCipherTextToBytes(ciphertext, nonce, additional, apiV3Key)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", algorithm)
+	}
+}
+
 // 解密 统一数据 到指针结构体对象
 func V3DecryptNotifyCipherTextToStruct(ciphertext, nonce, additional, apiV3Key string, objPtr any) (err error) {
 	//验证参数类型
@@ -62,3 +62,10 @@ func TestV3EncryptTextAndV3DecryptText(t *testing.T) {
 	}
 	xlog.Debugf("decrypt text: %s", originText)
 }
+
+// 国密 AEAD_SM4_GCM 暂不支持，应返回错误而不是按 AES 解密
+func TestDecryptResourceUnsupportedAlgorithm(t *testing.T) {
+	if _, err := decryptResource("AEAD_SM4_GCM", "", "", "", "Cy3tqWc0tUMiF8ZtGq5yWbDCqAhuQQTE"); err == nil {
+		t.Fatal("AEAD_SM4_GCM should be unsupported")
+	}
+}
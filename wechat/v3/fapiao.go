@@ -16,7 +16,7 @@ func (c *ClientV3) V3InvoiceCardTemplateCreate(ctx context.Context, bm gopay.Bod
 	if err := bm.CheckEmptyError("card_appid", "card_template_information"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3InvoiceCardTemplateCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3InvoiceCardTemplateCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +40,7 @@ func (c *ClientV3) V3InvoiceCardTemplateCreate(ctx context.Context, bm gopay.Bod
 // 配置开发选项
 // Code = 0 is success
 func (c *ClientV3) V3InvoiceMerchantDevConfig(ctx context.Context, bm gopay.BodyMap) (*InvoiceMerchantDevConfigRsp, error) {
-	authorization, err := c.authorization(MethodPATCH, v3InvoiceMerchantDevConfig, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, v3InvoiceMerchantDevConfig, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +224,7 @@ func (c *ClientV3) V3InvoiceCreate(ctx context.Context, bm gopay.BodyMap) (*Empt
 	if err := bm.CheckEmptyError("scene", "fapiao_apply_id", "buyer_information", "fapiao_information"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3InvoiceCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3InvoiceCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +249,7 @@ func (c *ClientV3) V3InvoiceReverse(ctx context.Context, fapiaoApplyId string, b
 		return nil, err
 	}
 	uri := fmt.Sprintf(v3InvoiceReverse, fapiaoApplyId)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -335,7 +335,7 @@ func (c *ClientV3) V3InvoiceInsertCard(ctx context.Context, fapiaoApplyId string
 		return nil, err
 	}
 	uri := fmt.Sprintf(v3InvoiceInsertCard, fapiaoApplyId)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
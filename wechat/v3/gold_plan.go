@@ -13,7 +13,7 @@ import (
 // 点金计划管理API
 // Code = 0 is success
 func (c *ClientV3) V3GoldPlanManage(ctx context.Context, bm gopay.BodyMap) (wxRsp *GoldPlanManageRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3GoldPlanManage, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3GoldPlanManage, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func (c *ClientV3) V3GoldPlanManage(ctx context.Context, bm gopay.BodyMap) (wxRs
 // 商家小票管理API
 // Code = 0 is success
 func (c *ClientV3) V3GoldPlanBillManage(ctx context.Context, bm gopay.BodyMap) (wxRsp *GoldPlanManageRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3GoldPlanBillManage, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3GoldPlanBillManage, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +61,7 @@ func (c *ClientV3) V3GoldPlanBillManage(ctx context.Context, bm gopay.BodyMap) (
 // 同业过滤标签管理API
 // Code = 0 is success
 func (c *ClientV3) V3GoldPlanFilterManage(ctx context.Context, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3GoldPlanFilterManage, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3GoldPlanFilterManage, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,7 @@ func (c *ClientV3) V3GoldPlanFilterManage(ctx context.Context, bm gopay.BodyMap)
 // 开通广告展示API
 // Code = 0 is success
 func (c *ClientV3) V3GoldPlanOpenAdShow(ctx context.Context, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
-	authorization, err := c.authorization(MethodPATCH, v3GoldPlanOpenAdShow, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, v3GoldPlanOpenAdShow, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +103,7 @@ func (c *ClientV3) V3GoldPlanOpenAdShow(ctx context.Context, bm gopay.BodyMap) (
 // 关闭广告展示API
 // Code = 0 is success
 func (c *ClientV3) V3GoldPlanCloseAdShow(ctx context.Context, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
-	authorization, err := c.authorization(MethodPATCH, v3GoldPlanCloseAdShow, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, v3GoldPlanCloseAdShow, bm)
 	if err != nil {
 		return nil, err
 	}
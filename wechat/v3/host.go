@@ -0,0 +1,129 @@
+package wechat
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/w6xian/gopay"
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// 主域名失败后的默认冷却时间
+const defaultHostCoolDown = time.Minute
+
+// hostFailover 主备域名选择
+// 主域名网络错误或网关错误（502、503、504）时使用备用域名重试，冷却时间内优先使用备用域名，之后重新尝试主域名
+type hostFailover struct {
+	mu        sync.Mutex
+	enable    bool
+	primary   string
+	backup    string
+	coolDown  time.Duration
+	downUntil time.Time // 主域名冷却截止时间
+}
+
+func newHostFailover() *hostFailover {
+	return &hostFailover{
+		enable:   true,
+		primary:  v3BaseUrlCh,
+		backup:   v3BaseUrlChBackup,
+		coolDown: defaultHostCoolDown,
+	}
+}
+
+// hosts 本次请求依次尝试的域名
+func (h *hostFailover) hosts() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.enable {
+		return []string{h.primary}
+	}
+	if time.Now().Before(h.downUntil) {
+		return []string{h.backup, h.primary}
+	}
+	return []string{h.primary, h.backup}
+}
+
+// current 当前优先使用的域名
+func (h *hostFailover) current() string {
+	return h.hosts()[0]
+}
+
+// report 记录请求结果，主域名失败时进入冷却，成功时恢复
+func (h *hostFailover) report(host string, failed bool) {
+	if host != h.primary {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if failed {
+		h.downUntil = time.Now().Add(h.coolDown)
+		return
+	}
+	h.downUntil = time.Time{}
+}
+
+// shouldFailover 网络错误或网关错误时切换域名，context 取消或超时不切换
+func shouldFailover(res *http.Response, err error) bool {
+	if err != nil {
+		return xhttp.IsTemporaryErr(err)
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// SetHostFailover 设置主备域名自动切换，默认开启，冷却时间 1 分钟
+// enable：是否开启，关闭后仅使用主域名 https://api.mch.weixin.qq.com
+// coolDown：主域名失败后，冷却时间内优先使用备用域名 https://api2.mch.weixin.qq.com，之后重新尝试主域名，<= 0 时不修改
+// 说明：设置 ProxyHost 后仅使用 ProxyHost，不切换域名
+func (c *ClientV3) SetHostFailover(enable bool, coolDown time.Duration) {
+	c.failover.mu.Lock()
+	defer c.failover.mu.Unlock()
+	c.failover.enable = enable
+	if coolDown > 0 {
+		c.failover.coolDown = coolDown
+	}
+	if !enable {
+		c.failover.downUntil = time.Time{}
+	}
+}
+
+// GetHost 返回当前请求优先使用的域名，设置 ProxyHost 时返回 ProxyHost
+func (c *ClientV3) GetHost() string {
+	if c.proxyHost != "" {
+		return c.proxyHost
+	}
+	return c.failover.current()
+}
+
+// doWithFailover 向可用域名发送请求，网络错误或网关错误时在 xhttp 请求循环内切换域名（计入 RetryPolicy 的最大请求次数），返回应答及实际处理请求的域名
+func (c *ClientV3) doWithFailover(ctx context.Context, req *xhttp.Request, path string, send func(url string) (*http.Response, []byte, error)) (res *http.Response, bs []byte, host string, err error) {
+	if c.proxyHost != "" {
+		res, bs, err = send(c.proxyHost + path)
+		return res, bs, c.proxyHost, err
+	}
+	hosts := c.failover.hosts()
+	host = hosts[0]
+	req.SetFailover(func(url string, res *http.Response, err error) string {
+		host = strings.TrimSuffix(url, path)
+		failed := shouldFailover(res, err)
+		c.failover.report(host, failed)
+		i := slices.Index(hosts, host)
+		if !failed || i < 0 || i == len(hosts)-1 {
+			return ""
+		}
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Failover: %s failed, switch to %s", host, hosts[i+1])
+		}
+		return hosts[i+1] + path
+	})
+	res, bs, err = send(host + path)
+	return res, bs, host, err
+}
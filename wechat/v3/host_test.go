@@ -0,0 +1,141 @@
+package wechat
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+func TestHostFailover(t *testing.T) {
+	primaryHits := 0
+	primaryDown := true
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits++
+		if primaryDown {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"host":"primary"}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"host":"backup"}`))
+	}))
+	defer backup.Close()
+
+	c, err := NewClientV3("1900000001", "serial", "apiv3key", PrivateKeyContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.failover.primary, c.failover.backup = primary.URL, backup.URL
+	c.SetHostFailover(true, 50*time.Millisecond)
+
+	// 主域名网关错误，切换至备用域名
+	res, si, _, err := c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusOK || si.Host != backup.URL || primaryHits != 1 {
+		t.Fatalf("status = %d, host = %s, primaryHits = %d", res.StatusCode, si.Host, primaryHits)
+	}
+	// 冷却时间内直接使用备用域名
+	if c.GetHost() != backup.URL {
+		t.Fatalf("host = %s", c.GetHost())
+	}
+	if _, si, _, _ = c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth"); si.Host != backup.URL || primaryHits != 1 {
+		t.Fatalf("host = %s, primaryHits = %d", si.Host, primaryHits)
+	}
+	// 冷却结束后恢复主域名
+	primaryDown = false
+	time.Sleep(60 * time.Millisecond)
+	if _, si, _, _ = c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth"); si.Host != primary.URL {
+		t.Fatalf("host = %s", si.Host)
+	}
+
+	// 关闭切换后仅使用主域名
+	primaryDown = true
+	c.SetHostFailover(false, 0)
+	res, si, _, err = c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth")
+	if err != nil || res.StatusCode != http.StatusBadGateway || si.Host != primary.URL {
+		t.Fatalf("status = %d, host = %s, err = %v", res.StatusCode, si.Host, err)
+	}
+
+	// 设置 ProxyHost 后不切换
+	c.SetHostFailover(true, 0)
+	c.SetProxyHost(primary.URL)
+	if _, si, _, _ = c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth"); si.Host != primary.URL {
+		t.Fatalf("proxy host = %s", si.Host)
+	}
+}
+
+func TestHostFailoverNetworkErr(t *testing.T) {
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer backup.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close() // 连接被拒绝
+
+	c, err := NewClientV3("1900000001", "serial", "apiv3key", PrivateKeyContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.failover.primary, c.failover.backup = down.URL, backup.URL
+	_, si, _, err := c.doProdGet(ctx, "/v3/certificates", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if si.Host != backup.URL {
+		t.Fatalf("host = %s", si.Host)
+	}
+}
+
+// 切换域名计入重试次数，主备域名都失败时总请求次数不超过 MaxAttempts
+func TestHostFailoverAttempts(t *testing.T) {
+	var primaryHits, backupHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backupHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer backup.Close()
+
+	c, err := NewClientV3("1900000001", "serial", "apiv3key", PrivateKeyContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.failover.primary, c.failover.backup = primary.URL, backup.URL
+	tests := []struct {
+		policy          *xhttp.RetryPolicy
+		primary, backup int32
+	}{
+		{nil, 1, 1}, // 未设置重试，仅切换一次
+		{&xhttp.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}, 1, 2},
+		{&xhttp.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}, 1, 4},
+	}
+	for _, tt := range tests {
+		primaryHits.Store(0)
+		backupHits.Store(0)
+		c.SetHostFailover(true, time.Nanosecond)
+		c.SetRetryPolicy(tt.policy)
+		time.Sleep(time.Millisecond)
+		res, si, _, err := c.doProdGet(ctx, "/v3/pay/transactions/id/1", "auth")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != http.StatusServiceUnavailable || si.Host != backup.URL {
+			t.Fatalf("status = %d, host = %s", res.StatusCode, si.Host)
+		}
+		if primaryHits.Load() != tt.primary || backupHits.Load() != tt.backup {
+			t.Errorf("policy %+v: primary = %d, backup = %d, want %d, %d", tt.policy, primaryHits.Load(), backupHits.Load(), tt.primary, tt.backup)
+		}
+	}
+}
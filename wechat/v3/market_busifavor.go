@@ -13,7 +13,7 @@ import (
 // 创建商家券
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorBatchCreate(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorCreateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorBatchCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorBatchCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +62,7 @@ func (c *ClientV3) V3BusiFavorBatchDetail(ctx context.Context, stockId string) (
 // 核销用户券
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorUse(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorUseRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorUse, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorUse, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +137,7 @@ func (c *ClientV3) V3BusiFavorUserCouponDetail(ctx context.Context, openid, coup
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorCodeUpload(ctx context.Context, stockId string, bm gopay.BodyMap) (wxRsp *BusiFavorCodeUploadRsp, err error) {
 	url := fmt.Sprintf(v3BusiFavorCodeUpload, stockId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +161,7 @@ func (c *ClientV3) V3BusiFavorCodeUpload(ctx context.Context, stockId string, bm
 // 设置商家券事件通知地址
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorCallbackUrlSet(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorCallbackUrlSetRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorCallbackUrlSet, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorCallbackUrlSet, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +210,7 @@ func (c *ClientV3) V3BusiFavorCallbackUrl(ctx context.Context, mchid string) (wx
 // 关联订单信息
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorAssociate(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorAssociateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorAssociate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorAssociate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +234,7 @@ func (c *ClientV3) V3BusiFavorAssociate(ctx context.Context, bm gopay.BodyMap) (
 // 取消关联订单信息
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorDisassociate(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorDisassociateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorDisassociate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorDisassociate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -259,7 +259,7 @@ func (c *ClientV3) V3BusiFavorDisassociate(ctx context.Context, bm gopay.BodyMap
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorBatchUpdate(ctx context.Context, stockId string, bm gopay.BodyMap) (wxRsp *BusiFavorBatchUpdateRsp, err error) {
 	url := fmt.Sprintf(v3BusiFavorBatchUpdate, stockId)
-	authorization, err := c.authorization(MethodPATCH, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -284,7 +284,7 @@ func (c *ClientV3) V3BusiFavorBatchUpdate(ctx context.Context, stockId string, b
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorInfoUpdate(ctx context.Context, stockId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3BusiFavorInfoUpdate, stockId)
-	authorization, err := c.authorization(MethodPATCH, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +306,7 @@ func (c *ClientV3) V3BusiFavorInfoUpdate(ctx context.Context, stockId string, bm
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorSend(ctx context.Context, cardId string, bm gopay.BodyMap) (wxRsp *BusiFavorSendRsp, err error) {
 	url := fmt.Sprintf(v3BusiFavorSend, cardId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -330,7 +330,7 @@ func (c *ClientV3) V3BusiFavorSend(ctx context.Context, cardId string, bm gopay.
 // 申请退券
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorReturn(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorReturnRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorReturn, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorReturn, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -354,7 +354,7 @@ func (c *ClientV3) V3BusiFavorReturn(ctx context.Context, bm gopay.BodyMap) (wxR
 // 使券失效
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorDeactivate(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorDeactivateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorDeactivate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorDeactivate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +378,7 @@ func (c *ClientV3) V3BusiFavorDeactivate(ctx context.Context, bm gopay.BodyMap)
 // 营销补差付款
 // Code = 0 is success
 func (c *ClientV3) V3BusiFavorSubsidyPay(ctx context.Context, bm gopay.BodyMap) (wxRsp *BusiFavorSubsidyPayRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3BusiFavorSubsidyPay, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3BusiFavorSubsidyPay, bm)
 	if err != nil {
 		return nil, err
 	}
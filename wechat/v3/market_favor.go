@@ -14,7 +14,7 @@ import (
 // 创建代金券批次
 // Code = 0 is success
 func (c *ClientV3) V3FavorBatchCreate(ctx context.Context, bm gopay.BodyMap) (wxRsp *FavorBatchCreateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3FavorBatchCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3FavorBatchCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +39,7 @@ func (c *ClientV3) V3FavorBatchCreate(ctx context.Context, bm gopay.BodyMap) (wx
 // Code = 0 is success
 func (c *ClientV3) V3FavorBatchGrant(ctx context.Context, openid string, bm gopay.BodyMap) (wxRsp *FavorBatchGrantRsp, err error) {
 	url := fmt.Sprintf(v3FavorBatchGrant, openid)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func (c *ClientV3) V3FavorBatchStart(ctx context.Context, stockId, stockCreatorM
 	url := fmt.Sprintf(v3FavorBatchStart, stockId)
 	bm := make(gopay.BodyMap)
 	bm.Set("stock_creator_mchid", stockCreatorMchid)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -321,7 +321,7 @@ func (c *ClientV3) V3FavorCallbackUrl(ctx context.Context, mchid string) (wxRsp
 // 设置消息通知地址
 // Code = 0 is success
 func (c *ClientV3) V3FavorCallbackUrlSet(ctx context.Context, bm gopay.BodyMap) (wxRsp *FavorCallbackUrlSetRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3FavorCallbackUrlSet, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3FavorCallbackUrlSet, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -348,7 +348,7 @@ func (c *ClientV3) V3FavorBatchPause(ctx context.Context, stockId, stockCreatorM
 	url := fmt.Sprintf(v3FavorBatchPause, stockId)
 	bm := make(gopay.BodyMap)
 	bm.Set("stock_creator_mchid", stockCreatorMchid)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -375,7 +375,7 @@ func (c *ClientV3) V3FavorBatchRestart(ctx context.Context, stockId, stockCreato
 	url := fmt.Sprintf(v3FavorBatchRestart, stockId)
 	bm := make(gopay.BodyMap)
 	bm.Set("stock_creator_mchid", stockCreatorMchid)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
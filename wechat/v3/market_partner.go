@@ -13,7 +13,7 @@ import (
 // 建立合作关系
 // Code = 0 is success
 func (c *ClientV3) V3PartnershipsBuild(ctx context.Context, idempotencyKey string, bm gopay.BodyMap) (wxRsp *PartnershipsBuildRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3PartnershipsBuild, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3PartnershipsBuild, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func (c *ClientV3) V3PartnershipsBuild(ctx context.Context, idempotencyKey strin
 // 终止合作关系
 // Code = 0 is success
 func (c *ClientV3) V3PartnershipsTerminate(ctx context.Context, idempotencyKey string, bm gopay.BodyMap) (wxRsp *PartnershipsTerminateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3PartnershipsTerminate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3PartnershipsTerminate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,133 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+func TestCertManager(t *testing.T) {
+	srv, err := NewServer(testMchid, testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	var pulls atomic.Int32
+	counter := &xhttp.Interceptor{BeforeSend: func(req *http.Request, info *xhttp.RequestInfo) (*http.Request, error) {
+		if strings.HasPrefix(req.URL.Path, "/v3/certificates") {
+			pulls.Add(1)
+		}
+		return req, nil
+	}}
+	store := wechat.NewMemoryCertStore()
+	newClient := func() *wechat.ClientV3 {
+		priKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		priPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priKey)})
+		client, err := wechat.NewClientV3(testMchid, "3775B6A45ACD588826D15E583A95F5DD", testApiV3Key, string(priPem))
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetProxyHost(srv.URL)
+		client.SetHttpClient(xhttp.NewClient().AddInterceptor(counter))
+		client.CertManager().SetStore(store)
+		return client
+	}
+
+	// 共享存储：第二个客户端直接使用第一个客户端拉取的证书
+	client1, client2 := newClient(), newClient()
+	if err = client1.AutoVerifySign(false); err != nil {
+		t.Fatal(err)
+	}
+	if err = client2.AutoVerifySign(false); err != nil {
+		t.Fatal(err)
+	}
+	if n := pulls.Load(); n != 1 {
+		t.Fatalf("pulls = %d, want 1", n)
+	}
+	if client2.GetWxSerialNo() != srv.SerialNo {
+		t.Fatalf("WxSerialNo = %s, want %s", client2.GetWxSerialNo(), srv.SerialNo)
+	}
+
+	// 证书轮换
+	newSerial, newCert := newTestCert(t)
+	now := time.Now()
+	_ = store.Save(context.Background(), testMchid, &wechat.CertSnapshot{
+		UpdatedAt: now,
+		Certs: []*wechat.PlatformCertItem{
+			{SerialNo: srv.SerialNo, EffectiveTime: now.Add(-time.Hour).Format(timeLayout), ExpireTime: now.Add(time.Hour).Format(timeLayout), PublicKey: string(srv.Certificate())},
+			{SerialNo: newSerial, EffectiveTime: now.Format(timeLayout), ExpireTime: now.Add(24 * time.Hour).Format(timeLayout), PublicKey: newCert},
+		},
+	})
+	var rotated, expiring string
+	client1.CertManager().
+		OnRotate(func(oldSerialNo, newSerialNo string) { rotated = oldSerialNo + ">" + newSerialNo }).
+		OnExpire(48*time.Hour, func(serialNo string, expireAt time.Time) { expiring = serialNo })
+	if err = client1.CertManager().Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rotated != srv.SerialNo+">"+newSerial || expiring != newSerial || client1.GetWxSerialNo() != newSerial {
+		t.Fatalf("rotated = %s, expiring = %s, serialNo = %s", rotated, expiring, client1.GetWxSerialNo())
+	}
+	if _, ok := client1.SnCertMap.Load(srv.SerialNo); !ok {
+		t.Fatal("old cert removed before expired")
+	}
+
+	// 定时刷新，Close 后停止
+	client3 := newClient()
+	client3.CertManager().SetStore(nil).SetRefreshInterval(10 * time.Millisecond)
+	before := pulls.Load()
+	client3.CertManager().Start(context.Background())
+	deadline := time.Now().Add(2 * time.Second)
+	for pulls.Load()-before < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	client3.Close()
+	stopped := pulls.Load()
+	if stopped-before < 2 {
+		t.Fatalf("pulls after start = %d", stopped-before)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if pulls.Load() != stopped {
+		t.Fatal("refresh still running after Close()")
+	}
+	client3.Close()
+
+	// context 取消后停止
+	ctx, cancel := context.WithCancel(context.Background())
+	client4 := newClient()
+	client4.CertManager().SetRefreshInterval(time.Hour).Start(ctx)
+	cancel()
+	client4.Close()
+}
+
+func newTestCert(t *testing.T) (serialNo, certPem string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "Tenpay.com Root CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.ToUpper(tpl.SerialNumber.Text(16)), string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
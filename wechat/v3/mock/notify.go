@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-pay/crypto/aes"
+	"github.com/go-pay/util"
+	"github.com/w6xian/gopay"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+// SendNotify 向 notifyUrl 发送回调通知
+// resource 序列化为 JSON 后使用 ApiV3Key 以 AEAD_AES_256_GCM 加密，请求头使用平台私钥签名
+// 商户应答非 HTTP 200/204 时返回 error
+func (s *Server) SendNotify(ctx context.Context, notifyUrl, eventType, resourceType string, resource any) (err error) {
+	plain, err := json.Marshal(resource)
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gopay.MarshalErr, err)
+	}
+	nonce := util.RandomString(12)
+	associatedData := resourceType
+	cipherText, err := aes.GCMEncrypt(plain, []byte(nonce), []byte(associatedData), []byte(s.ApiV3Key))
+	if err != nil {
+		return fmt.Errorf("aes.GCMEncrypt, err:%w", err)
+	}
+	notifyReq := &wechat.V3NotifyReq{
+		Id:           util.RandomString(36),
+		CreateTime:   time.Now().Format(timeLayout),
+		EventType:    eventType,
+		Summary:      eventType,
+		ResourceType: "encrypt-resource",
+		Resource: &wechat.Resource{
+			Algorithm:      "AEAD_AES_256_GCM",
+			OriginalType:   resourceType,
+			Ciphertext:     base64.StdEncoding.EncodeToString(cipherText),
+			AssociatedData: associatedData,
+			Nonce:          nonce,
+		},
+	}
+	bs, err := json.Marshal(notifyReq)
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gopay.MarshalErr, err)
+	}
+	header, err := s.signHeader(bs)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyUrl, bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("notify[%s] response: %d > %s", notifyReq.Id, res.StatusCode, string(body))
+	}
+	return nil
+}
+
+// NotifyPay 模拟用户完成支付，并向 notifyUrl 发送支付成功通知（TRANSACTION.SUCCESS）
+func (s *Server) NotifyPay(ctx context.Context, notifyUrl, outTradeNo string) (err error) {
+	order, err := s.PayOrder(outTradeNo)
+	if err != nil {
+		return err
+	}
+	result := &wechat.V3DecryptPayResult{
+		Appid:          order.Appid,
+		Mchid:          order.Mchid,
+		OutTradeNo:     order.OutTradeNo,
+		TransactionId:  order.TransactionId,
+		TradeType:      order.TradeType,
+		TradeState:     order.TradeState,
+		TradeStateDesc: order.TradeStateDesc,
+		BankType:       order.BankType,
+		Attach:         order.Attach,
+		SuccessTime:    order.SuccessTime,
+		Payer:          order.Payer,
+		Amount:         order.Amount,
+	}
+	return s.SendNotify(ctx, notifyUrl, wechat.EventTypeTransactionSuccess, "transaction", result)
+}
+
+// NotifyRefund 模拟退款到账，并向 notifyUrl 发送退款成功通知（REFUND.SUCCESS）
+func (s *Server) NotifyRefund(ctx context.Context, notifyUrl, outRefundNo string) (err error) {
+	refund, err := s.CompleteRefund(outRefundNo)
+	if err != nil {
+		return err
+	}
+	result := &wechat.V3DecryptRefundResult{
+		Mchid:               s.Mchid,
+		OutTradeNo:          refund.OutTradeNo,
+		TransactionId:       refund.TransactionId,
+		OutRefundNo:         refund.OutRefundNo,
+		RefundId:            refund.RefundId,
+		RefundStatus:        refund.Status,
+		SuccessTime:         refund.SuccessTime,
+		UserReceivedAccount: refund.UserReceivedAccount,
+		Amount: &wechat.RefundNotifyAmount{
+			Total:       refund.Amount.Total,
+			Refund:      refund.Amount.Refund,
+			PayerTotal:  refund.Amount.PayerTotal,
+			PayerRefund: refund.Amount.PayerRefund,
+		},
+	}
+	return s.SendNotify(ctx, notifyUrl, wechat.EventTypeRefundSuccess, "refund", result)
+}
@@ -0,0 +1,151 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/w6xian/gopay"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+type testTransferRequest struct {
+	Appid           string `json:"appid"`
+	OutBillNo       string `json:"out_bill_no"`
+	TransferSceneId string `json:"transfer_scene_id"`
+	Openid          string `json:"openid"`
+	UserName        string `json:"user_name,omitempty" sensitive:"true"`
+	TransferAmount  int    `json:"transfer_amount"`
+	TransferRemark  string `json:"transfer_remark"`
+}
+
+func TestSensitive(t *testing.T) {
+	srv, err := NewServer(testMchid, testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	// BodyMap 参数自动加密
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wxd678efh567hg6787").
+		Set("out_bill_no", "plfk2020042013").
+		Set("transfer_scene_id", "1000").
+		Set("openid", "o-MYE42l80oelYMDE34nYD456Xoy").
+		Set("user_name", wechat.SensitiveText("张三")).
+		Set("transfer_amount", 400).
+		Set("transfer_remark", "新会员开通有礼")
+	rsp, err := client.V3TransferBills(ctx, bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Code != wechat.Success {
+		t.Fatalf("V3TransferBills: %+v", rsp)
+	}
+	if name, _ := srv.TransferUserName("plfk2020042013"); name != "张三" {
+		t.Fatalf("user_name = %s", name)
+	}
+	// 加密使用 BodyMap 副本，传入的 bm 不变，可重复使用
+	if bm.GetAny("user_name") != wechat.SensitiveText("张三") || !strings.Contains(bm.JsonBody(), "张三") {
+		t.Fatalf("bm modified: %s", bm.JsonBody())
+	}
+	bm.Set("out_bill_no", "plfk2020042016")
+	if rsp, err = client.V3TransferBills(ctx, bm); err != nil || rsp.Code != wechat.Success {
+		t.Fatalf("V3TransferBills: %+v, err: %v", rsp, err)
+	}
+	if name, _ := srv.TransferUserName("plfk2020042016"); name != "张三" {
+		t.Fatalf("user_name = %s", name)
+	}
+
+	// 结构体 sensitive 标签自动加密
+	bm, err = wechat.StructToBodyMap(&testTransferRequest{
+		Appid:           "wxd678efh567hg6787",
+		OutBillNo:       "plfk2020042014",
+		TransferSceneId: "1000",
+		Openid:          "o-MYE42l80oelYMDE34nYD456Xoy",
+		UserName:        "李四",
+		TransferAmount:  400,
+		TransferRemark:  "新会员开通有礼",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp, err = client.V3TransferBills(ctx, bm); err != nil || rsp.Code != wechat.Success {
+		t.Fatalf("V3TransferBills: %+v, err: %v", rsp, err)
+	}
+	if name, _ := srv.TransferUserName("plfk2020042014"); name != "李四" {
+		t.Fatalf("user_name = %s", name)
+	}
+
+	// 返回参数解密
+	srv.AddTransferDetail(&wechat.TransferMerchantDetail{OutBatchNo: "plfk2020042015", OutDetailNo: "x23zy545Bd5436", DetailStatus: "SUCCESS", UserName: "王五"})
+	detailRsp, err := client.V3TransferMerchantDetail(ctx, "plfk2020042015", "x23zy545Bd5436")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detailRsp.Code != wechat.Success || detailRsp.Response.UserName == "王五" {
+		t.Fatalf("V3TransferMerchantDetail: %+v", detailRsp.Response)
+	}
+	if err = client.V3DecryptSensitive(detailRsp.Response); err != nil || detailRsp.Response.UserName != "王五" {
+		t.Fatalf("V3DecryptSensitive: %s, err: %v", detailRsp.Response.UserName, err)
+	}
+	client.SetAutoDecrypt(true)
+	if detailRsp, err = client.V3TransferMerchantDetail(ctx, "plfk2020042015", "x23zy545Bd5436"); err != nil || detailRsp.Response.UserName != "王五" {
+		t.Fatalf("auto decrypt: %+v, err: %v", detailRsp.Response, err)
+	}
+}
+
+func TestSensitiveSetPlatformCert(t *testing.T) {
+	srv, err := NewServer(testMchid, testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	priKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priKey)})
+	client, err := wechat.NewClientV3(testMchid, "3775B6A45ACD588826D15E583A95F5DD", testApiV3Key, string(priPem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetProxyHost(srv.URL)
+
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wxd678efh567hg6787").
+		Set("out_bill_no", "plfk2020042013").
+		Set("transfer_scene_id", "1000").
+		Set("openid", "o-MYE42l80oelYMDE34nYD456Xoy").
+		Set("user_name", wechat.SensitiveText("张三")).
+		Set("transfer_amount", 400)
+	// 未设置平台证书，无法加密
+	if _, err = client.V3TransferBills(context.Background(), bm); err == nil {
+		t.Fatal("encrypt without platform cert")
+	}
+
+	// 仅设置 WxSerialNo 字段不再生效，需通过 SetPlatformCert() 设置
+	client.WxSerialNo = srv.SerialNo
+	if _, err = client.V3TransferBills(context.Background(), bm); err == nil {
+		t.Fatal("encrypt with deprecated WxSerialNo")
+	}
+	if err = client.SetPlatformCert(srv.Certificate(), srv.SerialNo); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := client.V3TransferBills(context.Background(), bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Code != wechat.Success {
+		t.Fatalf("V3TransferBills: %+v", rsp)
+	}
+	if name, _ := srv.TransferUserName("plfk2020042013"); name != "张三" {
+		t.Fatalf("user_name = %s", name)
+	}
+}
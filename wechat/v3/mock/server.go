@@ -0,0 +1,623 @@
+// Package mock 微信支付 V3 模拟服务端，基于 httptest 实现，用于商户侧单元测试与联调
+//
+// 模拟服务端使用随机生成的平台证书对应答签名，并通过 /v3/certificates 下发加密的平台证书，
+// 客户端调用 client.SetProxyHost(srv.URL) 与 client.AutoVerifySign() 后即可完成同步验签。
+package mock
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-pay/crypto/aes"
+	"github.com/go-pay/util"
+	"github.com/w6xian/gopay"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+const (
+	// 交易状态
+	TradeStateSuccess = "SUCCESS"
+	TradeStateRefund  = "REFUND"
+	TradeStateNotPay  = "NOTPAY"
+
+	// 退款状态
+	RefundStatusSuccess    = "SUCCESS"
+	RefundStatusProcessing = "PROCESSING"
+
+	// 转账状态
+	TransferStateWaitUserConfirm = "WAIT_USER_CONFIRM"
+
+	timeLayout = time.RFC3339
+)
+
+// Server 微信支付 V3 模拟服务端
+// 已实现接口：
+//   - GET  /v3/certificates
+//   - POST /v3/pay/transactions/jsapi
+//   - GET  /v3/pay/transactions/id/{transaction_id}
+//   - GET  /v3/pay/transactions/out-trade-no/{out_trade_no}
+//   - POST /v3/refund/domestic/refunds
+//   - POST /v3/fund-app/mch-transfer/transfer-bills
+//   - GET  /v3/transfer/batches/out-batch-no/{out_batch_no}/details/out-detail-no/{out_detail_no}
+type Server struct {
+	*httptest.Server
+	Mchid    string // 商户号，请求 Authorization 中的 mchid 需与之一致
+	ApiV3Key string // APIv3Key，用于加密平台证书与回调通知
+	SerialNo string // 平台证书序列号，即应答头 Wechatpay-Serial
+
+	privateKey        *rsa.PrivateKey
+	certPem           []byte
+	merchantPublicKey *rsa.PublicKey
+
+	mu        sync.Mutex
+	seq       int64
+	orders    map[string]*wechat.QueryOrder             // key: out_trade_no
+	refunds   map[string]*wechat.RefundOrderResponse    // key: out_refund_no
+	transfers map[string]*wechat.TransferBills          // key: out_bill_no
+	userNames map[string]string                         // key: out_bill_no，解密后的收款用户姓名
+	details   map[string]*wechat.TransferMerchantDetail // key: out_batch_no + "/" + out_detail_no
+}
+
+// NewServer 初始化并启动模拟服务端，使用完毕请调用 srv.Close()
+// mchid：商户号
+// apiV3Key：APIv3Key，长度为 32 字节
+func NewServer(mchid, apiV3Key string) (srv *Server, err error) {
+	if mchid == gopay.NULL || apiV3Key == gopay.NULL {
+		return nil, gopay.MissWechatInitParamErr
+	}
+	if len(apiV3Key) != 32 {
+		return nil, errors.New("apiV3Key length must be 32")
+	}
+	priKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Tenpay.com Root CA", Organization: []string{"Tenpay.com"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(5, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &priKey.PublicKey, priKey)
+	if err != nil {
+		return nil, err
+	}
+	srv = &Server{
+		Mchid:      mchid,
+		ApiV3Key:   apiV3Key,
+		SerialNo:   strings.ToUpper(serial.Text(16)),
+		privateKey: priKey,
+		certPem:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		orders:     make(map[string]*wechat.QueryOrder),
+		refunds:    make(map[string]*wechat.RefundOrderResponse),
+		transfers:  make(map[string]*wechat.TransferBills),
+		userNames:  make(map[string]string),
+		details:    make(map[string]*wechat.TransferMerchantDetail),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v3/certificates", srv.handleCertificates)
+	mux.HandleFunc("POST /v3/pay/transactions/jsapi", srv.handleJsapi)
+	mux.HandleFunc("GET /v3/pay/transactions/id/{transaction_id}", srv.handleQueryOrder)
+	mux.HandleFunc("GET /v3/pay/transactions/out-trade-no/{out_trade_no}", srv.handleQueryOrder)
+	mux.HandleFunc("POST /v3/refund/domestic/refunds", srv.handleRefund)
+	mux.HandleFunc("POST /v3/fund-app/mch-transfer/transfer-bills", srv.handleTransferBills)
+	mux.HandleFunc("GET /v3/transfer/batches/out-batch-no/{out_batch_no}/details/out-detail-no/{out_detail_no}", srv.handleTransferDetail)
+	srv.Server = httptest.NewServer(mux)
+	return srv, nil
+}
+
+// SetMerchantPublicKey 设置商户API证书公钥，设置后校验请求 Authorization 中的签名，默认仅校验 mchid
+func (s *Server) SetMerchantPublicKey(publicKey *rsa.PublicKey) *Server {
+	s.merchantPublicKey = publicKey
+	return s
+}
+
+// TransferUserName 返回转账单解密后的收款用户姓名 user_name
+func (s *Server) TransferUserName(outBillNo string) (userName string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	userName, ok = s.userNames[outBillNo]
+	return userName, ok
+}
+
+// AddTransferDetail 预置转账明细单，查询时 user_name 使用商户API证书公钥加密，需先调用 SetMerchantPublicKey()
+func (s *Server) AddTransferDetail(detail *wechat.TransferMerchantDetail) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := *detail
+	s.details[d.OutBatchNo+"/"+d.OutDetailNo] = &d
+	return s
+}
+
+// Certificate 返回平台证书 PEM 内容，可用于 client.AutoVerifySignByCert()
+func (s *Server) Certificate() []byte {
+	return s.certPem
+}
+
+// Order 返回商户订单号对应的订单
+func (s *Server) Order(outTradeNo string) (order *wechat.QueryOrder, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[outTradeNo]
+	if !ok {
+		return nil, false
+	}
+	cp := *o
+	return &cp, true
+}
+
+// PayOrder 模拟用户完成支付，订单状态置为 SUCCESS
+func (s *Server) PayOrder(outTradeNo string) (order *wechat.QueryOrder, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[outTradeNo]
+	if !ok {
+		return nil, fmt.Errorf("order[%s] not exist", outTradeNo)
+	}
+	if o.TradeState == TradeStateNotPay {
+		o.TradeState = TradeStateSuccess
+		o.TradeStateDesc = "支付成功"
+		o.BankType = "OTHERS"
+		o.SuccessTime = time.Now().Format(timeLayout)
+		o.Amount.PayerTotal = o.Amount.Total
+		o.Amount.PayerCurrency = o.Amount.Currency
+	}
+	cp := *o
+	return &cp, nil
+}
+
+// CompleteRefund 模拟退款到账，退款状态置为 SUCCESS
+func (s *Server) CompleteRefund(outRefundNo string) (refund *wechat.RefundOrderResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.refunds[outRefundNo]
+	if !ok {
+		return nil, fmt.Errorf("refund[%s] not exist", outRefundNo)
+	}
+	if r.Status == RefundStatusProcessing {
+		r.Status = RefundStatusSuccess
+		r.SuccessTime = time.Now().Format(timeLayout)
+	}
+	cp := *r
+	return &cp, nil
+}
+
+// =============================== handlers ===============================
+
+func (s *Server) handleCertificates(w http.ResponseWriter, r *http.Request) {
+	if err := s.authenticate(r, nil); err != nil {
+		s.writeError(w, http.StatusUnauthorized, "SIGN_ERROR", err.Error())
+		return
+	}
+	nonce := util.RandomString(12)
+	associatedData := "certificate"
+	cipherText, err := aes.GCMEncrypt(s.certPem, []byte(nonce), []byte(associatedData), []byte(s.ApiV3Key))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "SYSTEM_ERROR", err.Error())
+		return
+	}
+	now := time.Now()
+	rsp := &wechat.PlatformCert{Data: []*wechat.CertData{{
+		SerialNo:      s.SerialNo,
+		EffectiveTime: now.Add(-time.Hour).Format(timeLayout),
+		ExpireTime:    now.AddDate(5, 0, 0).Format(timeLayout),
+		EncryptCertificate: &wechat.EncryptCert{
+			Algorithm:      "AEAD_AES_256_GCM",
+			AssociatedData: associatedData,
+			Ciphertext:     base64.StdEncoding.EncodeToString(cipherText),
+			Nonce:          nonce,
+		},
+	}}}
+	s.writeJSON(w, http.StatusOK, rsp)
+}
+
+type jsapiReq struct {
+	Appid       string         `json:"appid"`
+	Mchid       string         `json:"mchid"`
+	Description string         `json:"description"`
+	OutTradeNo  string         `json:"out_trade_no"`
+	Attach      string         `json:"attach"`
+	NotifyUrl   string         `json:"notify_url"`
+	Amount      *wechat.Amount `json:"amount"`
+	Payer       *wechat.Payer  `json:"payer"`
+}
+
+func (s *Server) handleJsapi(w http.ResponseWriter, r *http.Request) {
+	req := new(jsapiReq)
+	if !s.decode(w, r, req) {
+		return
+	}
+	switch {
+	case req.Appid == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 appid")
+		return
+	case req.Description == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 description")
+		return
+	case req.OutTradeNo == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 out_trade_no")
+		return
+	case req.NotifyUrl == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 notify_url")
+		return
+	case req.Amount == nil || req.Amount.Total <= 0:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "amount.total 必须大于 0")
+		return
+	case req.Payer == nil || req.Payer.Openid == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 payer.openid")
+		return
+	case req.Mchid != s.Mchid:
+		s.writeError(w, http.StatusBadRequest, "MCH_NOT_EXISTS", "商户号不存在")
+		return
+	}
+	if req.Amount.Currency == gopay.NULL {
+		req.Amount.Currency = "CNY"
+	}
+	s.mu.Lock()
+	o, ok := s.orders[req.OutTradeNo]
+	if ok && o.TradeState != TradeStateNotPay {
+		s.mu.Unlock()
+		s.writeError(w, http.StatusBadRequest, "ORDERPAID", "该订单已支付")
+		return
+	}
+	if !ok {
+		o = &wechat.QueryOrder{
+			Appid:          req.Appid,
+			Mchid:          req.Mchid,
+			OutTradeNo:     req.OutTradeNo,
+			TransactionId:  s.nextId("4200"),
+			TradeType:      "JSAPI",
+			TradeState:     TradeStateNotPay,
+			TradeStateDesc: "订单未支付",
+			Attach:         req.Attach,
+			Payer:          req.Payer,
+			Amount:         &wechat.Amount{Total: req.Amount.Total, Currency: req.Amount.Currency},
+		}
+		s.orders[req.OutTradeNo] = o
+	}
+	prepayId := "wx" + o.TransactionId
+	s.mu.Unlock()
+	s.writeJSON(w, http.StatusOK, &wechat.Prepay{PrepayId: prepayId})
+}
+
+func (s *Server) handleQueryOrder(w http.ResponseWriter, r *http.Request) {
+	if !s.decode(w, r, nil) {
+		return
+	}
+	if mchid := r.URL.Query().Get("mchid"); mchid != s.Mchid {
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "mchid 与订单不一致")
+		return
+	}
+	var found *wechat.QueryOrder
+	s.mu.Lock()
+	if outTradeNo := r.PathValue("out_trade_no"); outTradeNo != gopay.NULL {
+		found = s.orders[outTradeNo]
+	} else {
+		transactionId := r.PathValue("transaction_id")
+		for _, o := range s.orders {
+			if o.TransactionId == transactionId {
+				found = o
+				break
+			}
+		}
+	}
+	var rsp wechat.QueryOrder
+	if found != nil {
+		rsp = *found
+	}
+	s.mu.Unlock()
+	if found == nil {
+		s.writeError(w, http.StatusNotFound, "ORDER_NOT_EXIST", "订单不存在")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &rsp)
+}
+
+type refundReq struct {
+	TransactionId string `json:"transaction_id"`
+	OutTradeNo    string `json:"out_trade_no"`
+	OutRefundNo   string `json:"out_refund_no"`
+	Reason        string `json:"reason"`
+	NotifyUrl     string `json:"notify_url"`
+	Amount        *struct {
+		Refund   int    `json:"refund"`
+		Total    int    `json:"total"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+}
+
+func (s *Server) handleRefund(w http.ResponseWriter, r *http.Request) {
+	req := new(refundReq)
+	if !s.decode(w, r, req) {
+		return
+	}
+	switch {
+	case req.OutRefundNo == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 out_refund_no")
+		return
+	case req.OutTradeNo == gopay.NULL && req.TransactionId == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "out_trade_no 与 transaction_id 不能同时为空")
+		return
+	case req.Amount == nil || req.Amount.Refund <= 0:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "amount.refund 必须大于 0")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 同一退款单号重复请求，返回原退款单
+	if rf, ok := s.refunds[req.OutRefundNo]; ok {
+		rsp := *rf
+		s.writeJSON(w, http.StatusOK, &rsp)
+		return
+	}
+	var order *wechat.QueryOrder
+	for _, o := range s.orders {
+		if o.OutTradeNo == req.OutTradeNo || (req.TransactionId != gopay.NULL && o.TransactionId == req.TransactionId) {
+			order = o
+			break
+		}
+	}
+	if order == nil {
+		s.writeError(w, http.StatusNotFound, "RESOURCE_NOT_EXISTS", "订单不存在")
+		return
+	}
+	if order.TradeState != TradeStateSuccess && order.TradeState != TradeStateRefund {
+		s.writeError(w, http.StatusForbidden, "NOT_ENOUGH", "订单未支付，无可退金额")
+		return
+	}
+	if req.Amount.Total != order.Amount.Total {
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "amount.total 与订单金额不一致")
+		return
+	}
+	var refunded int
+	for _, rf := range s.refunds {
+		if rf.OutTradeNo == order.OutTradeNo {
+			refunded += rf.Amount.Refund
+		}
+	}
+	if refunded+req.Amount.Refund > order.Amount.Total {
+		s.writeError(w, http.StatusForbidden, "NOT_ENOUGH", "可退金额不足")
+		return
+	}
+	rf := &wechat.RefundOrderResponse{
+		RefundId:            s.nextId("50"),
+		OutRefundNo:         req.OutRefundNo,
+		TransactionId:       order.TransactionId,
+		OutTradeNo:          order.OutTradeNo,
+		Channel:             "ORIGINAL",
+		UserReceivedAccount: "支付用户零钱",
+		CreateTime:          time.Now().Format(timeLayout),
+		Status:              RefundStatusProcessing,
+		Amount: &wechat.RefundOrderAmount{
+			Total:            order.Amount.Total,
+			Refund:           req.Amount.Refund,
+			PayerTotal:       order.Amount.Total,
+			PayerRefund:      req.Amount.Refund,
+			SettlementTotal:  order.Amount.Total,
+			SettlementRefund: req.Amount.Refund,
+			Currency:         order.Amount.Currency,
+		},
+	}
+	s.refunds[req.OutRefundNo] = rf
+	order.TradeState = TradeStateRefund
+	order.TradeStateDesc = "转入退款"
+	rsp := *rf
+	s.writeJSON(w, http.StatusOK, &rsp)
+}
+
+type transferBillsReq struct {
+	Appid           string `json:"appid"`
+	OutBillNo       string `json:"out_bill_no"`
+	TransferSceneId string `json:"transfer_scene_id"`
+	Openid          string `json:"openid"`
+	TransferAmount  int    `json:"transfer_amount"`
+	TransferRemark  string `json:"transfer_remark"`
+	UserName        string `json:"user_name"`
+}
+
+func (s *Server) handleTransferBills(w http.ResponseWriter, r *http.Request) {
+	req := new(transferBillsReq)
+	if !s.decode(w, r, req) {
+		return
+	}
+	switch {
+	case req.Appid == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 appid")
+		return
+	case req.OutBillNo == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 out_bill_no")
+		return
+	case req.TransferSceneId == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 transfer_scene_id")
+		return
+	case req.Openid == gopay.NULL:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "缺少参数 openid")
+		return
+	case req.TransferAmount <= 0:
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "transfer_amount 必须大于 0")
+		return
+	}
+	var userName string
+	if req.UserName != gopay.NULL {
+		// 敏感信息使用平台证书公钥加密，请求头 Wechatpay-Serial 需为对应的证书序列号
+		if serial := r.Header.Get(wechat.HeaderSerial); serial != s.SerialNo {
+			s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "Wechatpay-Serial["+serial+"] 与平台证书序列号不一致")
+			return
+		}
+		cipherBytes, _ := base64.StdEncoding.DecodeString(req.UserName)
+		plain, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, s.privateKey, cipherBytes, nil)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", "user_name 解密失败")
+			return
+		}
+		userName = string(plain)
+	}
+	s.mu.Lock()
+	if userName != gopay.NULL {
+		s.userNames[req.OutBillNo] = userName
+	}
+	tb, ok := s.transfers[req.OutBillNo]
+	if !ok {
+		tb = &wechat.TransferBills{
+			OutBillNo:      req.OutBillNo,
+			TransferBillNo: s.nextId("1330"),
+			CreateTime:     time.Now().Format(timeLayout),
+			State:          TransferStateWaitUserConfirm,
+			PackageInfo:    util.RandomString(64),
+		}
+		s.transfers[req.OutBillNo] = tb
+	}
+	rsp := *tb
+	s.mu.Unlock()
+	s.writeJSON(w, http.StatusOK, &rsp)
+}
+
+func (s *Server) handleTransferDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.decode(w, r, nil) {
+		return
+	}
+	s.mu.Lock()
+	detail, ok := s.details[r.PathValue("out_batch_no")+"/"+r.PathValue("out_detail_no")]
+	s.mu.Unlock()
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "NOT_FOUND", "记录不存在")
+		return
+	}
+	rsp := *detail
+	if rsp.UserName != gopay.NULL {
+		if s.merchantPublicKey == nil {
+			s.writeError(w, http.StatusInternalServerError, "SYSTEM_ERROR", "未设置商户API证书公钥")
+			return
+		}
+		cipherBytes, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, s.merchantPublicKey, []byte(rsp.UserName), nil)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "SYSTEM_ERROR", err.Error())
+			return
+		}
+		rsp.UserName = base64.StdEncoding.EncodeToString(cipherBytes)
+	}
+	s.writeJSON(w, http.StatusOK, &rsp)
+}
+
+// =============================== helpers ===============================
+
+// decode 校验请求签名并解析请求体，失败时已写入错误应答
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, ptr any) bool {
+	bs, err := io.ReadAll(io.LimitReader(r.Body, int64(5<<20)))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", err.Error())
+		return false
+	}
+	if err = s.authenticate(r, bs); err != nil {
+		s.writeError(w, http.StatusUnauthorized, "SIGN_ERROR", err.Error())
+		return false
+	}
+	if ptr == nil {
+		return true
+	}
+	if err = json.Unmarshal(bs, ptr); err != nil {
+		s.writeError(w, http.StatusBadRequest, "PARAM_ERROR", err.Error())
+		return false
+	}
+	return true
+}
+
+// authenticate 校验请求头 Authorization
+func (s *Server) authenticate(r *http.Request, body []byte) error {
+	auth := r.Header.Get(wechat.HeaderAuthorization)
+	params, found := strings.CutPrefix(auth, wechat.Authorization+" ")
+	if !found {
+		return errors.New("Authorization 缺失或认证类型错误")
+	}
+	kv := make(map[string]string, 5)
+	for _, item := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		kv[strings.TrimSpace(k)] = strings.Trim(v, `"`)
+	}
+	if kv["mchid"] != s.Mchid {
+		return fmt.Errorf("Authorization mchid[%s] 与商户号不一致", kv["mchid"])
+	}
+	if s.merchantPublicKey == nil {
+		return nil
+	}
+	str := r.Method + "\n" + r.URL.RequestURI() + "\n" + kv["timestamp"] + "\n" + kv["nonce_str"] + "\n" + string(body) + "\n"
+	sign, err := base64.StdEncoding.DecodeString(kv["signature"])
+	if err != nil {
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, err)
+	}
+	sum256 := sha256.Sum256([]byte(str))
+	if err = rsa.VerifyPKCS1v15(s.merchantPublicKey, crypto.SHA256, sum256[:], sign); err != nil {
+		return fmt.Errorf("[%w]: %v", gopay.VerifySignatureErr, err)
+	}
+	return nil
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, code, message string) {
+	s.writeJSON(w, status, &wechat.ErrResponse{Code: code, Message: message})
+}
+
+// writeJSON 写入应答并使用平台私钥签名
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	bs, _ := json.Marshal(v)
+	header, err := s.signHeader(bs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for k, v := range header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(bs)
+}
+
+// signHeader 生成 Wechatpay-* 签名头，应答与回调通知通用
+func (s *Server) signHeader(body []byte) (header map[string]string, err error) {
+	ts := fmt.Sprintf("%d", time.Now().Unix())
+	nonce := util.RandomString(32)
+	str := ts + "\n" + nonce + "\n" + string(body) + "\n"
+	sum256 := sha256.Sum256([]byte(str))
+	sign, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, sum256[:])
+	if err != nil {
+		return nil, fmt.Errorf("[%w]: %+v", gopay.SignatureErr, err)
+	}
+	return map[string]string{
+		wechat.HeaderTimestamp: ts,
+		wechat.HeaderNonce:     nonce,
+		wechat.HeaderSignature: base64.StdEncoding.EncodeToString(sign),
+		wechat.HeaderSerial:    s.SerialNo,
+	}, nil
+}
+
+// nextId 生成递增的单号，调用方需持有 s.mu
+func (s *Server) nextId(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%s%010d", prefix, time.Now().Format("20060102"), s.seq)
+}
@@ -0,0 +1,163 @@
+package mock
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-pay/xlog"
+	"github.com/w6xian/gopay"
+	wechat "github.com/w6xian/gopay/wechat/v3"
+)
+
+const (
+	testMchid    = "1900000001"
+	testApiV3Key = "Cj5xC9RXf5GFdfl6OnnHCMlxEMbmyVHX"
+)
+
+func newTestClient(t *testing.T, srv *Server) *wechat.ClientV3 {
+	priKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priKey)})
+	srv.SetMerchantPublicKey(&priKey.PublicKey)
+	client, err := wechat.NewClientV3(testMchid, "3775B6A45ACD588826D15E583A95F5DD", testApiV3Key, string(priPem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetProxyHost(srv.URL)
+	if err = client.AutoVerifySign(false); err != nil {
+		t.Fatal(err)
+	}
+	if sn := client.GetWxSerialNo(); sn != srv.SerialNo {
+		t.Fatalf("GetWxSerialNo() = %s, want %s", sn, srv.SerialNo)
+	}
+	return client
+}
+
+func TestServer(t *testing.T) {
+	srv, err := NewServer(testMchid, testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	client := newTestClient(t, srv)
+	ctx := context.Background()
+
+	// 下单
+	bm := make(gopay.BodyMap)
+	bm.Set("appid", "wxd678efh567hg6787").
+		Set("description", "Image形象店-深圳腾大-QQ公仔").
+		Set("out_trade_no", "1217752501201407033233368018").
+		Set("notify_url", "https://www.weixin.qq.com/wxpay/pay.php").
+		SetBodyMap("amount", func(bm gopay.BodyMap) {
+			bm.Set("total", 100).Set("currency", "CNY")
+		}).
+		SetBodyMap("payer", func(bm gopay.BodyMap) {
+			bm.Set("openid", "oUpF8uMuAJO_M2pxb1Q9zNjWeS6o")
+		})
+	prepayRsp, err := client.V3TransactionJsapi(ctx, bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prepayRsp.Code != wechat.Success || prepayRsp.Response.PrepayId == gopay.NULL {
+		t.Fatalf("V3TransactionJsapi: %+v", prepayRsp)
+	}
+
+	// 查询未支付订单
+	queryRsp, err := client.V3TransactionQueryOrder(ctx, wechat.OutTradeNo, "1217752501201407033233368018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRsp.Code != wechat.Success || queryRsp.Response.TradeState != TradeStateNotPay {
+		t.Fatalf("V3TransactionQueryOrder: %+v", queryRsp)
+	}
+
+	// 未支付订单不可退款
+	refundBm := make(gopay.BodyMap)
+	refundBm.Set("out_trade_no", "1217752501201407033233368018").
+		Set("out_refund_no", "1217752501201407033233368019").
+		SetBodyMap("amount", func(bm gopay.BodyMap) {
+			bm.Set("refund", 40).Set("total", 100).Set("currency", "CNY")
+		})
+	refundRsp, err := client.V3Refund(ctx, refundBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refundRsp.Code != http.StatusForbidden || refundRsp.ErrResponse.Code != "NOT_ENOUGH" {
+		t.Fatalf("V3Refund before pay: %+v", refundRsp)
+	}
+
+	// 支付通知
+	var paid *wechat.V3DecryptPayResult
+	notifySrv := httptest.NewServer(wechat.NewNotifyHandler(client).
+		OnPay(func(ctx context.Context, notifyReq *wechat.V3NotifyReq, result *wechat.V3DecryptPayResult) error {
+			paid = result
+			return nil
+		}).
+		OnError(func(req *http.Request, err error) {
+			xlog.Errorf("notify err: %v", err)
+		}))
+	defer notifySrv.Close()
+	if err = srv.NotifyPay(ctx, notifySrv.URL, "1217752501201407033233368018"); err != nil {
+		t.Fatal(err)
+	}
+	if paid == nil || paid.TradeState != TradeStateSuccess || paid.Amount.Total != 100 {
+		t.Fatalf("OnPay got: %+v", paid)
+	}
+
+	// 通过微信支付订单号查询
+	queryRsp, err = client.V3TransactionQueryOrder(ctx, wechat.TransactionId, paid.TransactionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRsp.Response.TradeState != TradeStateSuccess {
+		t.Fatalf("V3TransactionQueryOrder: %+v", queryRsp.Response)
+	}
+
+	// 退款
+	refundRsp, err = client.V3Refund(ctx, refundBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refundRsp.Code != wechat.Success || refundRsp.Response.Status != RefundStatusProcessing {
+		t.Fatalf("V3Refund: %+v", refundRsp)
+	}
+
+	// 转账
+	transferBm := make(gopay.BodyMap)
+	transferBm.Set("appid", "wxd678efh567hg6787").
+		Set("out_bill_no", "plfk2020042013").
+		Set("transfer_scene_id", "1000").
+		Set("openid", "o-MYE42l80oelYMDE34nYD456Xoy").
+		Set("transfer_amount", 400).
+		Set("transfer_remark", "新会员开通有礼")
+	transferRsp, err := client.V3TransferBills(ctx, transferBm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transferRsp.Code != wechat.Success || transferRsp.Response.State != TransferStateWaitUserConfirm {
+		t.Fatalf("V3TransferBills: %+v", transferRsp)
+	}
+
+	// 商户号不一致，认证失败
+	other, err := NewServer("1900000002", testApiV3Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	client.SetProxyHost(other.URL)
+	queryRsp, err = client.V3TransactionQueryOrder(ctx, wechat.OutTradeNo, "1217752501201407033233368018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRsp.Code != http.StatusUnauthorized || queryRsp.ErrResponse.Code != "SIGN_ERROR" {
+		t.Fatalf("query with wrong mchid: %+v", queryRsp)
+	}
+}
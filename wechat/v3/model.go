@@ -169,6 +169,7 @@ type SignInfo struct {
 	HeaderSignature string `json:"Wechatpay-Signature"`
 	HeaderSerial    string `json:"Wechatpay-Serial"`
 	SignBody        string `json:"sign_body"`
+	Host            string `json:"-"` // 实际处理请求的域名，如主域名故障时为备用域名 https://api2.mch.weixin.qq.com
 }
 
 type PlatformCertItem struct {
@@ -299,12 +300,12 @@ type SmartGuideQuery struct {
 }
 
 type SmartGuide struct {
-	GuideId string `json:"guide_id"`          // 服务人员在服务人员系统中的唯一标识
-	StoreId int    `json:"store_id"`          // 门店在微信支付商户平台的唯一标识
-	Name    string `json:"name"`              // 服务人员姓名
-	Mobile  string `json:"mobile"`            // 员工在商户个人/企业微信通讯录上设置的手机号码（加密信息，需解密）
-	Userid  string `json:"userid,omitempty"`  // 员工在商户企业微信通讯录使用的唯一标识，使用企业微信商家时返回
-	WorkId  string `json:"work_id,omitempty"` // 服务人员通过小程序注册时填写的工号，使用个人微信商家时返回
+	GuideId string `json:"guide_id"`                // 服务人员在服务人员系统中的唯一标识
+	StoreId int    `json:"store_id"`                // 门店在微信支付商户平台的唯一标识
+	Name    string `json:"name"`                    // 服务人员姓名
+	Mobile  string `json:"mobile" sensitive:"true"` // 员工在商户个人/企业微信通讯录上设置的手机号码（加密信息，需解密）
+	Userid  string `json:"userid,omitempty"`        // 员工在商户企业微信通讯录使用的唯一标识，使用企业微信商家时返回
+	WorkId  string `json:"work_id,omitempty"`       // 服务人员通过小程序注册时填写的工号，使用个人微信商家时返回
 }
 
 type GoldPlanManage struct {
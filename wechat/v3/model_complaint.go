@@ -46,17 +46,17 @@ type ComplaintList struct {
 }
 
 type ComplaintListItem struct {
-	ComplaintId           string                `json:"complaint_id"`                   // 投诉单对应的投诉单号
-	ComplaintTime         string                `json:"complaint_time"`                 // 投诉时间, 例如：2015-05-20T13:29:35.120+08:00表示北京时间2015年05月20日13点29分35秒
-	ComplaintDetail       string                `json:"complaint_detail"`               // 投诉的具体描述
-	ComplaintState        string                `json:"complaint_state"`                // 投诉单状态, PENDING：待处理, PROCESSING：处理中, PROCESSED：已处理完成
-	PayerPhone            string                `json:"payer_phone,omitempty"`          // 投诉人联系方式。该字段已做加密处理
-	ComplaintOrderInfo    []*ComplaintOrderInfo `json:"complaint_order_info,omitempty"` // 投诉单关联订单信息
-	ServiceOrderInfo      []*ServiceOrderInfo   `json:"service_order_info,omitempty"`   // 投诉单关联服务订单信息
-	ComplaintFullRefunded bool                  `json:"complaint_full_refunded"`        // 投诉单下所有订单是否已全部全额退款
-	IncomingUserResponse  bool                  `json:"incoming_user_response"`         // 投诉单是否有待回复的用户留言
-	UserComplaintTimes    int                   `json:"user_complaint_times"`           // 用户投诉次数
-	ComplaintMediaList    []*ComplaintMediaList `json:"complaint_media_list,omitempty"` // 投诉资料列表
+	ComplaintId           string                `json:"complaint_id"`                           // 投诉单对应的投诉单号
+	ComplaintTime         string                `json:"complaint_time"`                         // 投诉时间, 例如：2015-05-20T13:29:35.120+08:00表示北京时间2015年05月20日13点29分35秒
+	ComplaintDetail       string                `json:"complaint_detail"`                       // 投诉的具体描述
+	ComplaintState        string                `json:"complaint_state"`                        // 投诉单状态, PENDING：待处理, PROCESSING：处理中, PROCESSED：已处理完成
+	PayerPhone            string                `json:"payer_phone,omitempty" sensitive:"true"` // 投诉人联系方式。该字段已做加密处理
+	ComplaintOrderInfo    []*ComplaintOrderInfo `json:"complaint_order_info,omitempty"`         // 投诉单关联订单信息
+	ServiceOrderInfo      []*ServiceOrderInfo   `json:"service_order_info,omitempty"`           // 投诉单关联服务订单信息
+	ComplaintFullRefunded bool                  `json:"complaint_full_refunded"`                // 投诉单下所有订单是否已全部全额退款
+	IncomingUserResponse  bool                  `json:"incoming_user_response"`                 // 投诉单是否有待回复的用户留言
+	UserComplaintTimes    int                   `json:"user_complaint_times"`                   // 用户投诉次数
+	ComplaintMediaList    []*ComplaintMediaList `json:"complaint_media_list,omitempty"`         // 投诉资料列表
 	ProblemDescription    string                `json:"problem_description"`
 	ProblemType           string                `json:"problem_type"`
 	ApplyRefundAmount     int                   `json:"apply_refund_amount"`
@@ -91,19 +91,19 @@ type SharePowerInfo struct {
 }
 
 type ComplaintDetail struct {
-	ComplaintId           string                `json:"complaint_id"`                   // 投诉单对应的投诉单号
-	ComplaintTime         string                `json:"complaint_time"`                 // 投诉时间, 例如：2015-05-20T13:29:35.120+08:00表示北京时间2015年05月20日13点29分35秒
-	ComplaintDetail       string                `json:"complaint_detail"`               // 投诉的具体描述
-	ComplaintedMchid      string                `json:"complainted_mchid,omitempty"`    // 投诉单对应的被诉商户号。
-	ComplaintState        string                `json:"complaint_state"`                // 投诉单状态, PENDING：待处理, PROCESSING：处理中, PROCESSED：已处理完成
-	PayerPhone            string                `json:"payer_phone,omitempty"`          // 投诉人联系方式。该字段已做加密处理
-	PayerOpenid           string                `json:"payer_openid"`                   // 投诉人在商户appid下的唯一标识
-	ComplaintOrderInfo    []*ComplaintOrderInfo `json:"complaint_order_info,omitempty"` // 投诉单关联订单信息
-	ComplaintMediaList    []*ComplaintMediaList `json:"complaint_media_list,omitempty"` // 投诉资料列表
-	ServiceOrderInfo      []*ServiceOrderInfo   `json:"service_order_info,omitempty"`   // 投诉单关联服务订单信息
-	ComplaintFullRefunded bool                  `json:"complaint_full_refunded"`        // 投诉单下所有订单是否已全部全额退款
-	IncomingUserResponse  bool                  `json:"incoming_user_response"`         // 投诉单是否有待回复的用户留言
-	UserComplaintTimes    int                   `json:"user_complaint_times"`           // 用户投诉次数
+	ComplaintId           string                `json:"complaint_id"`                           // 投诉单对应的投诉单号
+	ComplaintTime         string                `json:"complaint_time"`                         // 投诉时间, 例如：2015-05-20T13:29:35.120+08:00表示北京时间2015年05月20日13点29分35秒
+	ComplaintDetail       string                `json:"complaint_detail"`                       // 投诉的具体描述
+	ComplaintedMchid      string                `json:"complainted_mchid,omitempty"`            // 投诉单对应的被诉商户号。
+	ComplaintState        string                `json:"complaint_state"`                        // 投诉单状态, PENDING：待处理, PROCESSING：处理中, PROCESSED：已处理完成
+	PayerPhone            string                `json:"payer_phone,omitempty" sensitive:"true"` // 投诉人联系方式。该字段已做加密处理
+	PayerOpenid           string                `json:"payer_openid"`                           // 投诉人在商户appid下的唯一标识
+	ComplaintOrderInfo    []*ComplaintOrderInfo `json:"complaint_order_info,omitempty"`         // 投诉单关联订单信息
+	ComplaintMediaList    []*ComplaintMediaList `json:"complaint_media_list,omitempty"`         // 投诉资料列表
+	ServiceOrderInfo      []*ServiceOrderInfo   `json:"service_order_info,omitempty"`           // 投诉单关联服务订单信息
+	ComplaintFullRefunded bool                  `json:"complaint_full_refunded"`                // 投诉单下所有订单是否已全部全额退款
+	IncomingUserResponse  bool                  `json:"incoming_user_response"`                 // 投诉单是否有待回复的用户留言
+	UserComplaintTimes    int                   `json:"user_complaint_times"`                   // 用户投诉次数
 	ProblemDescription    string                `json:"problem_description"`
 	ProblemType           string                `json:"problem_type"`
 	ApplyRefundAmount     int                   `json:"apply_refund_amount"`
@@ -182,37 +182,37 @@ type PartnerTransferQuery struct {
 }
 
 type TransferDetailQuery struct {
-	Mchid          string `json:"mchid"`                 // 微信支付分配的商户号
-	OutBatchNo     string `json:"out_batch_no"`          // 商户系统内部的商家批次单号
-	BatchId        string `json:"batch_id"`              // 微信批次单号，微信商家转账系统返回的唯一标识
-	Appid          string `json:"appid"`                 // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
-	OutDetailNo    string `json:"out_detail_no"`         // 商家明细单号
-	DetailId       string `json:"detail_id"`             // 微信明细单号
-	DetailStatus   string `json:"detail_status"`         // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
-	TransferAmount int    `json:"transfer_amount"`       // 转账金额单位为分
-	TransferRemark string `json:"transfer_remark"`       // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
-	FailReason     string `json:"fail_reason,omitempty"` // 如果转账失败则有失败原因
-	Openid         string `json:"openid"`                // 用户在直连商户appid下的唯一标识
-	UserName       string `json:"user_name"`             // 收款方姓名（加密）
-	InitiateTime   string `json:"initiate_time"`         // 转账发起的时间
-	UpdateTime     string `json:"update_time"`           // 明细最后一次状态变更的时间
+	Mchid          string `json:"mchid"`                      // 微信支付分配的商户号
+	OutBatchNo     string `json:"out_batch_no"`               // 商户系统内部的商家批次单号
+	BatchId        string `json:"batch_id"`                   // 微信批次单号，微信商家转账系统返回的唯一标识
+	Appid          string `json:"appid"`                      // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
+	OutDetailNo    string `json:"out_detail_no"`              // 商家明细单号
+	DetailId       string `json:"detail_id"`                  // 微信明细单号
+	DetailStatus   string `json:"detail_status"`              // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
+	TransferAmount int    `json:"transfer_amount"`            // 转账金额单位为分
+	TransferRemark string `json:"transfer_remark"`            // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
+	FailReason     string `json:"fail_reason,omitempty"`      // 如果转账失败则有失败原因
+	Openid         string `json:"openid"`                     // 用户在直连商户appid下的唯一标识
+	UserName       string `json:"user_name" sensitive:"true"` // 收款方姓名（加密）
+	InitiateTime   string `json:"initiate_time"`              // 转账发起的时间
+	UpdateTime     string `json:"update_time"`                // 明细最后一次状态变更的时间
 }
 
 type PartnerTransferDetail struct {
-	SpMchid        string `json:"sp_mchid"`              // 微信支付分配的服务商商户号
-	OutBatchNo     string `json:"out_batch_no"`          // 商户系统内部的商家批次单号
-	BatchId        string `json:"batch_id"`              // 微信批次单号，微信商家转账系统返回的唯一标识
-	Appid          string `json:"appid"`                 // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
-	OutDetailNo    string `json:"out_detail_no"`         // 商家明细单号
-	DetailId       string `json:"detail_id"`             // 微信明细单号
-	DetailStatus   string `json:"detail_status"`         // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
-	TransferAmount int    `json:"transfer_amount"`       // 转账金额单位为分
-	TransferRemark string `json:"transfer_remark"`       // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
-	FailReason     string `json:"fail_reason,omitempty"` // 如果转账失败则有失败原因
-	Openid         string `json:"openid"`                // 用户在直连商户appid下的唯一标识
-	Username       string `json:"username"`              // 收款方姓名（加密）
-	InitiateTime   string `json:"initiate_time"`         // 转账发起的时间
-	UpdateTime     string `json:"update_time"`           // 明细最后一次状态变更的时间
+	SpMchid        string `json:"sp_mchid"`                  // 微信支付分配的服务商商户号
+	OutBatchNo     string `json:"out_batch_no"`              // 商户系统内部的商家批次单号
+	BatchId        string `json:"batch_id"`                  // 微信批次单号，微信商家转账系统返回的唯一标识
+	Appid          string `json:"appid"`                     // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
+	OutDetailNo    string `json:"out_detail_no"`             // 商家明细单号
+	DetailId       string `json:"detail_id"`                 // 微信明细单号
+	DetailStatus   string `json:"detail_status"`             // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
+	TransferAmount int    `json:"transfer_amount"`           // 转账金额单位为分
+	TransferRemark string `json:"transfer_remark"`           // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
+	FailReason     string `json:"fail_reason,omitempty"`     // 如果转账失败则有失败原因
+	Openid         string `json:"openid"`                    // 用户在直连商户appid下的唯一标识
+	Username       string `json:"username" sensitive:"true"` // 收款方姓名（加密）
+	InitiateTime   string `json:"initiate_time"`             // 转账发起的时间
+	UpdateTime     string `json:"update_time"`               // 明细最后一次状态变更的时间
 }
 
 type TransferMerchantQuery struct {
@@ -248,36 +248,36 @@ type PartnerTransferMerchantQuery struct {
 }
 
 type TransferMerchantDetail struct {
-	OutBatchNo     string `json:"out_batch_no"`          // 商户系统内部的商家批次单号
-	BatchId        string `json:"batch_id"`              // 微信批次单号，微信商家转账系统返回的唯一标识
-	Appid          string `json:"appid"`                 // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
-	OutDetailNo    string `json:"out_detail_no"`         // 商家明细单号
-	DetailId       string `json:"detail_id"`             // 微信明细单号
-	DetailStatus   string `json:"detail_status"`         // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
-	TransferAmount int    `json:"transfer_amount"`       // 转账金额单位为分
-	TransferRemark string `json:"transfer_remark"`       // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
-	FailReason     string `json:"fail_reason,omitempty"` // 如果转账失败则有失败原因
-	Openid         string `json:"openid"`                // 用户在直连商户appid下的唯一标识
-	UserName       string `json:"user_name"`             // 收款方姓名（加密）
-	InitiateTime   string `json:"initiate_time"`         // 转账发起的时间
-	UpdateTime     string `json:"update_time"`           // 明细最后一次状态变更的时间
+	OutBatchNo     string `json:"out_batch_no"`               // 商户系统内部的商家批次单号
+	BatchId        string `json:"batch_id"`                   // 微信批次单号，微信商家转账系统返回的唯一标识
+	Appid          string `json:"appid"`                      // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
+	OutDetailNo    string `json:"out_detail_no"`              // 商家明细单号
+	DetailId       string `json:"detail_id"`                  // 微信明细单号
+	DetailStatus   string `json:"detail_status"`              // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
+	TransferAmount int    `json:"transfer_amount"`            // 转账金额单位为分
+	TransferRemark string `json:"transfer_remark"`            // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
+	FailReason     string `json:"fail_reason,omitempty"`      // 如果转账失败则有失败原因
+	Openid         string `json:"openid"`                     // 用户在直连商户appid下的唯一标识
+	UserName       string `json:"user_name" sensitive:"true"` // 收款方姓名（加密）
+	InitiateTime   string `json:"initiate_time"`              // 转账发起的时间
+	UpdateTime     string `json:"update_time"`                // 明细最后一次状态变更的时间
 }
 
 type PartnerTransferMerchantDetail struct {
-	SpMchid        string `json:"sp_mchid"`              // 微信支付分配的服务商商户号
-	OutBatchNo     string `json:"out_batch_no"`          // 商户系统内部的商家批次单号
-	BatchId        string `json:"batch_id"`              // 微信批次单号，微信商家转账系统返回的唯一标识
-	Appid          string `json:"appid"`                 // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
-	OutDetailNo    string `json:"out_detail_no"`         // 商家明细单号
-	DetailId       string `json:"detail_id"`             // 微信明细单号
-	DetailStatus   string `json:"detail_status"`         // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
-	TransferAmount int    `json:"transfer_amount"`       // 转账金额单位为分
-	TransferRemark string `json:"transfer_remark"`       // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
-	FailReason     string `json:"fail_reason,omitempty"` // 如果转账失败则有失败原因
-	Openid         string `json:"openid"`                // 用户在直连商户appid下的唯一标识
-	Username       string `json:"username"`              // 收款方姓名（加密）
-	InitiateTime   string `json:"initiate_time"`         // 转账发起的时间
-	UpdateTime     string `json:"update_time"`           // 明细最后一次状态变更的时间
+	SpMchid        string `json:"sp_mchid"`                  // 微信支付分配的服务商商户号
+	OutBatchNo     string `json:"out_batch_no"`              // 商户系统内部的商家批次单号
+	BatchId        string `json:"batch_id"`                  // 微信批次单号，微信商家转账系统返回的唯一标识
+	Appid          string `json:"appid"`                     // 申请商户号的appid或商户号绑定的appid（企业号corpid即为此appid）
+	OutDetailNo    string `json:"out_detail_no"`             // 商家明细单号
+	DetailId       string `json:"detail_id"`                 // 微信明细单号
+	DetailStatus   string `json:"detail_status"`             // 明细状态：PROCESSING：转账中，SUCCESS：转账成功，FAIL：转账失败
+	TransferAmount int    `json:"transfer_amount"`           // 转账金额单位为分
+	TransferRemark string `json:"transfer_remark"`           // 单条转账备注（微信用户会收到该备注），UTF8编码，最多允许32个字符
+	FailReason     string `json:"fail_reason,omitempty"`     // 如果转账失败则有失败原因
+	Openid         string `json:"openid"`                    // 用户在直连商户appid下的唯一标识
+	Username       string `json:"username" sensitive:"true"` // 收款方姓名（加密）
+	InitiateTime   string `json:"initiate_time"`             // 转账发起的时间
+	UpdateTime     string `json:"update_time"`               // 明细最后一次状态变更的时间
 }
 
 type TransferReceipt struct {
@@ -1,14 +1,20 @@
 package wechat
 
 import (
+	"context"
 	"crypto/rsa"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
 	"github.com/go-pay/util/js"
 	"github.com/go-pay/xlog"
 	"github.com/w6xian/gopay"
-	"io"
-	"net/http"
 )
 
 type Resource struct {
@@ -322,6 +328,8 @@ type V3NotifyRsp struct {
 // =====================================================================================================================
 
 // 解析微信回调请求的参数到 V3NotifyReq 结构体
+// 注意：仅解析参数，不校验时间戳、不去重：二者需在验签通过后进行，且去重需在业务处理成功后才能记为已处理，
+// 请使用 NotifyHandler，或验签后调用 notifyReq.CheckTimestamp() 及 gopay.HandleNotifyOnce(ctx, deduper, notifyReq.DedupeKey(), fn)
 func V3ParseNotify(req *http.Request) (notifyReq *V3NotifyReq, err error) {
 	bs, err := io.ReadAll(io.LimitReader(req.Body, int64(5<<20))) // default 5MB change the size you want;
 	defer req.Body.Close()
@@ -361,220 +369,212 @@ func (v *V3NotifyReq) VerifySignByPKMap(wxPublicKeyMap map[string]*rsa.PublicKey
 	return errors.New("verify notify sign, bug SignInfo or wxPublicKeyMap is nil")
 }
 
+// 校验通知请求头 Wechatpay-Timestamp 是否在 window 时间窗口内，防止重放
+// 超出时间窗口返回 gopay.NotifyExpiredErr
+func (v *V3NotifyReq) CheckTimestamp(window time.Duration) (err error) {
+	if window <= 0 {
+		return nil
+	}
+	if v.SignInfo == nil {
+		return errors.New("check notify timestamp, bug SignInfo is nil")
+	}
+	ts, err := strconv.ParseInt(v.SignInfo.HeaderTimestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("[%w]: %s=%s", gopay.NotifyExpiredErr, HeaderTimestamp, v.SignInfo.HeaderTimestamp)
+	}
+	return gopay.CheckNotifyTimestamp(time.Unix(ts, 0), window)
+}
+
+// 根据通知 Id 去重，已处理成功返回 gopay.NotifyDuplicateErr，正在处理返回 gopay.NotifyProcessingErr
+// 注意：需在验签通过后调用，业务处理成功后调用 deduper.Commit(ctx, v.DedupeKey())，失败时调用 deduper.Delete() 以便微信重试时可再次处理
+// 推荐直接使用 gopay.HandleNotifyOnce(ctx, deduper, v.DedupeKey(), fn)
+func (v *V3NotifyReq) CheckDuplicate(ctx context.Context, deduper gopay.NotifyDeduper) (err error) {
+	return gopay.CheckNotifyDuplicate(ctx, deduper, v.DedupeKey())
+}
+
+// 通知去重使用的 key
+func (v *V3NotifyReq) DedupeKey() string {
+	return "wechat:" + v.Id
+}
+
 // 解密 统一数据 到指针结构体对象
+// 按 Resource.Algorithm 解密，仅支持 AEAD_AES_256_GCM
 func (v *V3NotifyReq) DecryptCipherTextToStruct(apiV3Key string, objPtr any) (err error) {
-	if v.Resource != nil {
-		err = V3DecryptNotifyCipherTextToStruct(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key, objPtr)
-		if err != nil {
-			return fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
+	if v.Resource == nil {
+		return errors.New("notify data Resource is nil")
+	}
+	//验证参数类型
+	objValue := reflect.ValueOf(objPtr)
+	if objValue.Kind() != reflect.Ptr {
+		return errors.New("传入objPtr 参数类型必须指针")
+	}
+	//验证 any 类型
+	if objValue.Elem().Kind() != reflect.Struct {
+		return errors.New("传入 any 必须是结构体")
+	}
+	decrypt, err := decryptResource(v.Resource.Algorithm, v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
+	if err == nil {
+		if err = json.Unmarshal(decrypt, objPtr); err != nil {
+			err = fmt.Errorf("json.Unmarshal(%s), err:%w", string(decrypt), err)
 		}
-		return nil
 	}
-	return errors.New("notify data Resource is nil")
+	if err != nil {
+		return fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
+	}
+	return nil
 }
 
 // 解密 普通支付 回调中的加密信息
 func (v *V3NotifyReq) DecryptPayCipherText(apiV3Key string) (result *V3DecryptPayResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptPayNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptPayResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 服务商支付 回调中的加密信息
 func (v *V3NotifyReq) DecryptPartnerPayCipherText(apiV3Key string) (result *V3DecryptPartnerPayResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptPartnerPayNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptPartnerPayResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 普通退款 回调中的加密信息
 func (v *V3NotifyReq) DecryptRefundCipherText(apiV3Key string) (result *V3DecryptRefundResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptRefundNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptRefundResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 服务商退款 回调中的加密信息
 func (v *V3NotifyReq) DecryptPartnerRefundCipherText(apiV3Key string) (result *V3DecryptPartnerRefundResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptPartnerRefundNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptPartnerRefundResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 合单支付 回调中的加密信息
 func (v *V3NotifyReq) DecryptCombineCipherText(apiV3Key string) (result *V3DecryptCombineResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptCombineNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptCombineResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 支付分确认订单 回调中的加密信息
 func (v *V3NotifyReq) DecryptScoreCipherText(apiV3Key string) (result *V3DecryptScoreResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptScoreNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptScoreResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 支付分开启/解除授权服务 回调中的加密信息
 func (v *V3NotifyReq) DecryptScorePermissionCipherText(apiV3Key string) (result *V3DecryptScorePermissionResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptScorePermissionNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptScorePermissionResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 分账动账 回调中的加密信息
 func (v *V3NotifyReq) DecryptProfitShareCipherText(apiV3Key string) (result *V3DecryptProfitShareResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptProfitShareNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptProfitShareResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 领券事件 回调中的加密信息
 func (v *V3NotifyReq) DecryptBusifavorCipherText(apiV3Key string) (result *V3DecryptBusifavorResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptBusifavorNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptBusifavorResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 停车入场状态变更 回调中的加密信息
 func (v *V3NotifyReq) DecryptParkingInCipherText(apiV3Key string) (result *V3DecryptParkingInResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptParkingInNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptParkingInResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 订单支付结果 回调中的加密信息
 func (v *V3NotifyReq) DecryptParkingPayCipherText(apiV3Key string) (result *V3DecryptParkingPayResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptParkingPayNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptParkingPayResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 代金券核销事件 回调中的加密信息
 func (v *V3NotifyReq) DecryptCouponUseCipherText(apiV3Key string) (result *V3DecryptCouponResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptCouponUseNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptCouponResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 用户发票抬头填写完成 回调中的加密信息
 func (v *V3NotifyReq) DecryptInvoiceTitleCipherText(apiV3Key string) (result *V3DecryptInvoiceTitleResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptInvoiceTitleNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptInvoiceTitleResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 发票卡券作废/发票开具成功/发票冲红成功/发票插入用户卡包成功 回调中的加密信息
 func (v *V3NotifyReq) DecryptInvoiceCipherText(apiV3Key string) (result *V3DecryptInvoiceResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptInvoiceNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptInvoiceResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 服务商子商户处置记录 回调中的加密信息
 func (v *V3NotifyReq) DecryptViolationCipherText(apiV3Key string) (result *V3DecryptViolationResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptViolationNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptViolationResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 商家转账批次回调通知 回调中的加密信息
 func (v *V3NotifyReq) DecryptTransferBatchCipherText(apiV3Key string) (result *V3DecryptTransferBatchResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptTransferBatchNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptTransferBatchResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // 解密 新版商家转账通知 回调中的加密信息
 func (v *V3NotifyReq) DecryptTransferBillsNotifyCipherText(apiV3Key string) (result *V3DecryptTransferBillsResult, err error) {
-	if v.Resource != nil {
-		result, err = V3DecryptTransferBillsNotifyCipherText(v.Resource.Ciphertext, v.Resource.Nonce, v.Resource.AssociatedData, apiV3Key)
-		if err != nil {
-			return nil, fmt.Errorf("V3NotifyReq(%s) decrypt cipher text err(%w)", js.MarshalString(v), err)
-		}
-		return result, nil
+	result = new(V3DecryptTransferBillsResult)
+	if err = v.DecryptCipherTextToStruct(apiV3Key, result); err != nil {
+		return nil, err
 	}
-	return nil, errors.New("notify data Resource is nil")
+	return result, nil
 }
 
 // Deprecated
@@ -0,0 +1,143 @@
+package wechat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-pay/util/js"
+	"github.com/w6xian/gopay"
+)
+
+const (
+	EventTypeTransactionSuccess = "TRANSACTION.SUCCESS" // 支付成功
+	EventTypeRefundSuccess      = "REFUND.SUCCESS"      // 退款成功
+	EventTypeRefundAbnormal     = "REFUND.ABNORMAL"     // 退款异常
+	EventTypeRefundClosed       = "REFUND.CLOSED"       // 退款关闭
+
+	// 默认通知时间戳有效窗口
+	defaultNotifyTimestampWindow = 5 * time.Minute
+)
+
+var _ http.Handler = (*NotifyHandler)(nil)
+
+// NotifyHandler 微信支付 V3 回调通知处理器，实现 http.Handler
+// 依次完成：解析 -> 验签 -> 按 event_type 解密并分发到回调 -> 应答
+// 成功应答 HTTP 200 及 {"code":"SUCCESS"}，失败应答 HTTP 500 及 {"code":"FAIL","message":"..."}，微信会按策略重试通知
+// 未注册对应回调的通知直接应答成功
+// 默认拒绝 Wechatpay-Timestamp 超出 5 分钟的通知；设置 NotifyDeduper 后，已处理成功的重复通知不再回调并直接应答成功，处理中的重复通知应答失败等待重试
+type NotifyHandler struct {
+	client   *ClientV3
+	window   time.Duration
+	deduper  gopay.NotifyDeduper
+	onPay    func(ctx context.Context, notifyReq *V3NotifyReq, result *V3DecryptPayResult) error
+	onRefund func(ctx context.Context, notifyReq *V3NotifyReq, result *V3DecryptRefundResult) error
+	onNotify func(ctx context.Context, notifyReq *V3NotifyReq) error
+	onError  func(req *http.Request, err error)
+}
+
+// NewNotifyHandler 初始化回调通知处理器
+// 验签使用 client.SnCertMap 中与 Wechatpay-Serial 对应的公钥，请先调用 client.AutoVerifySign() 或 client.AutoVerifySignByPublicKey()
+// 解密使用 client.ApiV3Key
+func NewNotifyHandler(client *ClientV3) *NotifyHandler {
+	return &NotifyHandler{client: client, window: defaultNotifyTimestampWindow}
+}
+
+// SetTimestampWindow 设置通知时间戳有效窗口，<= 0 表示不校验
+func (h *NotifyHandler) SetTimestampWindow(window time.Duration) *NotifyHandler {
+	h.window = window
+	return h
+}
+
+// SetDeduper 设置通知去重存储，多副本部署时请使用共享存储
+func (h *NotifyHandler) SetDeduper(deduper gopay.NotifyDeduper) *NotifyHandler {
+	h.deduper = deduper
+	return h
+}
+
+// OnPay 注册支付成功通知（TRANSACTION.SUCCESS）回调
+func (h *NotifyHandler) OnPay(fn func(ctx context.Context, notifyReq *V3NotifyReq, result *V3DecryptPayResult) error) *NotifyHandler {
+	h.onPay = fn
+	return h
+}
+
+// OnRefund 注册退款结果通知（REFUND.SUCCESS、REFUND.ABNORMAL、REFUND.CLOSED）回调
+func (h *NotifyHandler) OnRefund(fn func(ctx context.Context, notifyReq *V3NotifyReq, result *V3DecryptRefundResult) error) *NotifyHandler {
+	h.onRefund = fn
+	return h
+}
+
+// OnNotify 注册其他类型通知回调，参数为验签通过但未解密的通知，可通过 notifyReq.DecryptCipherTextToStruct() 解密
+// 服务商、合单支付等通知的解密结构与普通支付不同，请通过此回调处理
+func (h *NotifyHandler) OnNotify(fn func(ctx context.Context, notifyReq *V3NotifyReq) error) *NotifyHandler {
+	h.onNotify = fn
+	return h
+}
+
+// OnError 注册错误回调，解析、验签、解密失败及业务回调返回 error 时调用，可用于记录日志
+func (h *NotifyHandler) OnError(fn func(req *http.Request, err error)) *NotifyHandler {
+	h.onError = fn
+	return h
+}
+
+func (h *NotifyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.handle(req); err != nil {
+		if h.onError != nil {
+			h.onError(req, err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(js.MarshalString(&V3NotifyRsp{Code: gopay.FAIL, Message: "失败"})))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(js.MarshalString(&V3NotifyRsp{Code: gopay.SUCCESS, Message: "成功"})))
+}
+
+func (h *NotifyHandler) handle(req *http.Request) (err error) {
+	notifyReq, err := V3ParseNotify(req)
+	if err != nil {
+		return err
+	}
+	serial := notifyReq.SignInfo.HeaderSerial
+	wxPublicKey, ok := h.client.SnCertMap.Load(serial)
+	if !ok {
+		return fmt.Errorf("[%w]: wx public key of serial[%s] not found", gopay.VerifySignatureErr, serial)
+	}
+	if err = notifyReq.VerifySignByPK(wxPublicKey); err != nil {
+		return err
+	}
+	if err = notifyReq.CheckTimestamp(h.window); err != nil {
+		return err
+	}
+	ctx := req.Context()
+	if h.deduper == nil {
+		return h.dispatch(ctx, notifyReq)
+	}
+	return gopay.HandleNotifyOnce(ctx, h.deduper, notifyReq.DedupeKey(), func(ctx context.Context) error {
+		return h.dispatch(ctx, notifyReq)
+	})
+}
+
+func (h *NotifyHandler) dispatch(ctx context.Context, notifyReq *V3NotifyReq) (err error) {
+	apiV3Key := string(h.client.ApiV3Key)
+	switch {
+	case notifyReq.EventType == EventTypeTransactionSuccess && h.onPay != nil:
+		result, err := notifyReq.DecryptPayCipherText(apiV3Key)
+		if err != nil {
+			return err
+		}
+		return h.onPay(ctx, notifyReq, result)
+	case strings.HasPrefix(notifyReq.EventType, "REFUND.") && h.onRefund != nil:
+		result, err := notifyReq.DecryptRefundCipherText(apiV3Key)
+		if err != nil {
+			return err
+		}
+		return h.onRefund(ctx, notifyReq, result)
+	case h.onNotify != nil:
+		return h.onNotify(ctx, notifyReq)
+	}
+	return nil
+}
@@ -13,7 +13,7 @@ import (
 // 用户自主录掌&预授权
 // Code = 0 is success
 func (c *ClientV3) V3PalmServicePreAuthorize(ctx context.Context, bm gopay.BodyMap) (wxRsp *PalmServicePreAuthorizeRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3PalmServicePreAuthorize, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3PalmServicePreAuthorize, bm)
 	if err != nil {
 		return nil, err
 	}
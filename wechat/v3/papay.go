@@ -13,7 +13,7 @@ import (
 // Code = 0 is success
 func (c *ClientV3) V3EntrustPayNotify(ctx context.Context, contractId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3EntrustPayNotify, contractId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +40,7 @@ func (c *ClientV3) V3VehicleParkingQuery(ctx context.Context, bm gopay.BodyMap)
 // Code = 0 is success
 // bm：body 参数
 func (c *ClientV3) V3VehicleParkingIn(ctx context.Context, bm gopay.BodyMap) (wxRsp *VehicleParkingInRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3VehicleParkingIn, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3VehicleParkingIn, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (c *ClientV3) V3VehicleParkingIn(ctx context.Context, bm gopay.BodyMap) (wx
 // Code = 0 is success
 // bm：body 参数
 func (c *ClientV3) V3VehicleParkingFee(ctx context.Context, bm gopay.BodyMap) (wxRsp *VehicleParkingFeeRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3VehicleParkingFee, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3VehicleParkingFee, bm)
 	if err != nil {
 		return nil, err
 	}
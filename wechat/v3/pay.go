@@ -18,7 +18,10 @@ func (c *ClientV3) V3TransactionApp(ctx context.Context, bm gopay.BodyMap) (wxRs
 	if bm.GetString("mchid") == gopay.NULL {
 		bm.Set("mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiApp, bm)
+	if err = c.checkRequest(SchemaTransactionApp, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiApp, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +48,10 @@ func (c *ClientV3) V3TransactionJsapi(ctx context.Context, bm gopay.BodyMap) (wx
 	if bm.GetString("mchid") == gopay.NULL {
 		bm.Set("mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiJsapi, bm)
+	if err = c.checkRequest(SchemaTransactionJsapi, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiJsapi, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +78,10 @@ func (c *ClientV3) V3TransactionNative(ctx context.Context, bm gopay.BodyMap) (w
 	if bm.GetString("mchid") == gopay.NULL {
 		bm.Set("mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiNative, bm)
+	if err = c.checkRequest(SchemaTransactionNative, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiNative, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +108,10 @@ func (c *ClientV3) V3TransactionH5(ctx context.Context, bm gopay.BodyMap) (wxRsp
 	if bm.GetString("mchid") == gopay.NULL {
 		bm.Set("mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiH5, bm)
+	if err = c.checkRequest(SchemaTransactionH5, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiH5, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +140,7 @@ func (c *ClientV3) V3CodePay(ctx context.Context, bm gopay.BodyMap) (wxRsp *Code
 		bm.Set("mchid", c.Mchid)
 	}
 
-	authorization, err := c.authorization(MethodPost, v3ApiCodepay, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiCodepay, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +167,7 @@ func (c *ClientV3) V3QQTransactionH5(ctx context.Context, qqAppid, accessToken,
 	if bm.GetString("mchid") == gopay.NULL {
 		bm.Set("mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiH5, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiH5, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +228,7 @@ func (c *ClientV3) V3TransactionCloseOrder(ctx context.Context, tradeNo string)
 	url := fmt.Sprintf(v3ApiCloseOrder, tradeNo)
 	bm := make(gopay.BodyMap)
 	bm.Set("mchid", c.Mchid)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
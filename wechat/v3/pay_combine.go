@@ -16,7 +16,7 @@ func (c *ClientV3) V3CombineTransactionApp(ctx context.Context, bm gopay.BodyMap
 	if bm.GetString("combine_mchid") == gopay.NULL {
 		bm.Set("combine_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3CombinePayApp, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CombinePayApp, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (c *ClientV3) V3CombineTransactionJsapi(ctx context.Context, bm gopay.BodyM
 	if bm.GetString("combine_mchid") == gopay.NULL {
 		bm.Set("combine_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3CombinePayJsapi, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CombinePayJsapi, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +70,7 @@ func (c *ClientV3) V3CombineTransactionNative(ctx context.Context, bm gopay.Body
 	if bm.GetString("combine_mchid") == gopay.NULL {
 		bm.Set("combine_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3CombineNative, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CombineNative, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +97,7 @@ func (c *ClientV3) V3CombineTransactionH5(ctx context.Context, bm gopay.BodyMap)
 	if bm.GetString("combine_mchid") == gopay.NULL {
 		bm.Set("combine_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3CombinePayH5, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CombinePayH5, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +124,7 @@ func (c *ClientV3) V3CombineQQTransactionH5(ctx context.Context, qqAppid, access
 	if bm.GetString("combine_mchid") == gopay.NULL {
 		bm.Set("combine_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3CombinePayH5, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CombinePayH5, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -175,7 +175,7 @@ func (c *ClientV3) V3CombineQueryOrder(ctx context.Context, traderNo string) (wx
 // Code = 0 is success
 func (c *ClientV3) V3CombineCloseOrder(ctx context.Context, tradeNo string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3CombineClose, tradeNo)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -17,7 +17,10 @@ func (c *ClientV3) V3PartnerTransactionApp(ctx context.Context, bm gopay.BodyMap
 	if bm.GetString("sp_mchid") == gopay.NULL {
 		bm.Set("sp_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiPartnerPayApp, bm)
+	if err = c.checkRequest(SchemaPartnerTransactionApp, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiPartnerPayApp, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +47,10 @@ func (c *ClientV3) V3PartnerTransactionJsapi(ctx context.Context, bm gopay.BodyM
 	if bm.GetString("sp_mchid") == gopay.NULL {
 		bm.Set("sp_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiPartnerJsapi, bm)
+	if err = c.checkRequest(SchemaPartnerTransactionJsapi, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiPartnerJsapi, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +77,10 @@ func (c *ClientV3) V3PartnerTransactionNative(ctx context.Context, bm gopay.Body
 	if bm.GetString("sp_mchid") == gopay.NULL {
 		bm.Set("sp_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiPartnerNative, bm)
+	if err = c.checkRequest(SchemaPartnerTransactionNative, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiPartnerNative, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +107,10 @@ func (c *ClientV3) V3PartnerTransactionH5(ctx context.Context, bm gopay.BodyMap)
 	if bm.GetString("sp_mchid") == gopay.NULL {
 		bm.Set("sp_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, v3ApiPartnerH5, bm)
+	if err = c.checkRequest(SchemaPartnerTransactionH5, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ApiPartnerH5, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +174,7 @@ func (c *ClientV3) V3PartnerCloseOrder(ctx context.Context, tradeNo string, bm g
 	if bm.GetString("sp_mchid") == gopay.NULL {
 		bm.Set("sp_mchid", c.Mchid)
 	}
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -14,7 +14,7 @@ import (
 // 微信会在接到请求后立刻返回请求接收结果，分账结果需要自行调用查询接口来获取
 // Code = 0 is success
 func (c *ClientV3) V3ProfitShareOrder(ctx context.Context, bm gopay.BodyMap) (*ProfitShareOrderRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ProfitShareOrder, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ProfitShareOrder, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (c *ClientV3) V3ProfitShareOrderQuery(ctx context.Context, orderNo string,
 // 请求分账回退API
 // Code = 0 is success
 func (c *ClientV3) V3ProfitShareReturn(ctx context.Context, bm gopay.BodyMap) (*ProfitShareReturnRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ProfitShareReturn, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ProfitShareReturn, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +112,7 @@ func (c *ClientV3) V3ProfitShareReturnResult(ctx context.Context, returnNo strin
 // 解冻剩余资金API
 // Code = 0 is success
 func (c *ClientV3) V3ProfitShareOrderUnfreeze(ctx context.Context, bm gopay.BodyMap) (*ProfitShareOrderUnfreezeRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ProfitShareUnfreeze, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ProfitShareUnfreeze, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +184,10 @@ func (c *ClientV3) V3ProfitShareMerchantConfigs(ctx context.Context, subMchId st
 }
 
 // 新增分账接收方API
+// 注意：分账接收方全称 name 需加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 // Code = 0 is success
 func (c *ClientV3) V3ProfitShareAddReceiver(ctx context.Context, bm gopay.BodyMap) (*ProfitShareAddReceiverRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ProfitShareAddReceiver, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ProfitShareAddReceiver, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +211,7 @@ func (c *ClientV3) V3ProfitShareAddReceiver(ctx context.Context, bm gopay.BodyMa
 // 删除分账接收方API
 // Code = 0 is success
 func (c *ClientV3) V3ProfitShareDeleteReceiver(ctx context.Context, bm gopay.BodyMap) (*ProfitShareDeleteReceiverRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ProfitShareDeleteReceiver, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ProfitShareDeleteReceiver, bm)
 	if err != nil {
 		return nil, err
 	}
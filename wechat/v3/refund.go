@@ -13,7 +13,10 @@ import (
 // 退款申请
 // Code = 0 is success
 func (c *ClientV3) V3Refund(ctx context.Context, bm gopay.BodyMap) (wxRsp *RefundRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3DomesticRefund, bm)
+	if err = c.checkRequest(SchemaRefund, bm); err != nil {
+		return nil, err
+	}
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3DomesticRefund, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +41,7 @@ func (c *ClientV3) V3Refund(ctx context.Context, bm gopay.BodyMap) (wxRsp *Refun
 // Code = 0 is success
 func (c *ClientV3) V3AbnormalRefund(ctx context.Context, refundId string, bm gopay.BodyMap) (wxRsp *RefundRsp, err error) {
 	uri := fmt.Sprintf(v3DomesticAbnormalRefund, refundId)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +94,7 @@ func (c *ClientV3) V3RefundQuery(ctx context.Context, outRefundNo string, bm gop
 // 申请退款
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceRefund(ctx context.Context, bm gopay.BodyMap) (wxRsp *EcommerceRefundRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3CommerceRefund, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3CommerceRefund, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +169,7 @@ func (c *ClientV3) V3EcommerceRefundQueryByNo(ctx context.Context, outRefundNo s
 // Code = 0 is success
 func (c *ClientV3) V3EcommerceRefundAdvance(ctx context.Context, refundId string, bm gopay.BodyMap) (wxRsp *EcommerceRefundAdvanceRsp, err error) {
 	url := fmt.Sprintf(v3CommerceRefundAdvance, refundId)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
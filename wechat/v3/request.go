@@ -20,28 +20,29 @@ func (d *requestIdFunc) RequestId() string {
 }
 
 func (c *ClientV3) doProdPostWithHeader(ctx context.Context, headerMap map[string]string, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
-	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	for k, v := range headerMap {
 		req.Header.Add(k, v)
 	}
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Post(url).SendBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Post(url).SendBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -58,10 +59,10 @@ func (c *ClientV3) doProdPostWithHeader(ctx context.Context, headerMap map[strin
 
 func (c *ClientV3) doProdPostWithHost(ctx context.Context, bm gopay.BodyMap, host, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
 	var url = host + path
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
 		c.logger.Debugf("Wechat_V3_Url: %s", url)
@@ -88,25 +89,26 @@ func (c *ClientV3) doProdPostWithHost(ctx context.Context, bm gopay.BodyMap, hos
 }
 
 func (c *ClientV3) doProdPost(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
-	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Post(url).SendBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Post(url).SendBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -122,24 +124,25 @@ func (c *ClientV3) doProdPost(ctx context.Context, bm gopay.BodyMap, path, autho
 }
 
 func (c *ClientV3) doProdGet(ctx context.Context, uri, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + uri
-	if c.proxyHost != "" {
-		url = c.proxyHost + uri
-	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, uri) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.GetWxSerialNo())
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Get(url).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, uri, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Get(url).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -154,26 +157,52 @@ func (c *ClientV3) doProdGet(ctx context.Context, uri, authorization string) (re
 	return res, si, bs, nil
 }
 
-func (c *ClientV3) doProdPut(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
+// doProdGetStream 下载文件，应答 HTTP 200 时返回未读取的 res.Body，由调用方读取并关闭
+func (c *ClientV3) doProdGetStream(ctx context.Context, uri, authorization string) (res *http.Response, bs []byte, err error) {
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, uri) // default json
+	req.Header.Add(HeaderAuthorization, authorization)
+	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
+	req.Header.Add(HeaderSerial, c.GetWxSerialNo())
+	if c.DebugSwitch == gopay.DebugOn {
+		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
+	}
+	res, bs, _, err = c.doWithFailover(ctx, req, uri, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Get(url).EndStream(ctx)
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	req := c.hc.Req() // default json
+	if c.DebugSwitch == gopay.DebugOn {
+		c.logger.Debugf("Wechat_V3_Response: %d > %s", res.StatusCode, string(bs))
+		c.logger.Debugf("Wechat_V3_Rsp_Headers: %#v", res.Header)
+	}
+	return res, bs, nil
+}
+
+func (c *ClientV3) doProdPut(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Put(url).SendBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Put(url).SendBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -189,25 +218,26 @@ func (c *ClientV3) doProdPut(ctx context.Context, bm gopay.BodyMap, path, author
 }
 
 func (c *ClientV3) doProdDelete(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
-	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Delete(url).SendBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Delete(url).SendBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -223,25 +253,26 @@ func (c *ClientV3) doProdDelete(ctx context.Context, bm gopay.BodyMap, path, aut
 }
 
 func (c *ClientV3) doProdPostFile(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
-	}
-	req := c.hc.Req(xhttp.TypeMultipartFormData)
+	req := c.hc.Req(xhttp.TypeMultipartFormData).SetApi(gopay.ProviderWechatV3, path)
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Post(url).SendMultipartBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Post(url).SendMultipartBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
@@ -257,25 +288,26 @@ func (c *ClientV3) doProdPostFile(ctx context.Context, bm gopay.BodyMap, path, a
 }
 
 func (c *ClientV3) doProdPatch(ctx context.Context, bm gopay.BodyMap, path, authorization string) (res *http.Response, si *SignInfo, bs []byte, err error) {
-	var url = v3BaseUrlCh + path
-	if c.proxyHost != "" {
-		url = c.proxyHost + path
-	}
-	req := c.hc.Req() // default json
+	req := c.hc.Req().SetApi(gopay.ProviderWechatV3, path) // default json
 	req.Header.Add(HeaderAuthorization, authorization)
 	req.Header.Add(HeaderRequestID, c.requestIdFunc.RequestId())
-	req.Header.Add(HeaderSerial, c.WxSerialNo)
+	req.Header.Add(HeaderSerial, c.wxSerialNo(bm))
 	req.Header.Add("Accept", "application/json")
 	if c.DebugSwitch == gopay.DebugOn {
-		c.logger.Debugf("Wechat_V3_Url: %s", url)
 		c.logger.Debugf("Wechat_V3_Req_Body: %s", bm.JsonBody())
 		c.logger.Debugf("Wechat_V3_Req_Headers: %#v", req.Header)
 	}
-	res, bs, err = req.Patch(url).SendBodyMap(bm).EndBytes(ctx)
+	res, bs, host, err := c.doWithFailover(ctx, req, path, func(url string) (*http.Response, []byte, error) {
+		if c.DebugSwitch == gopay.DebugOn {
+			c.logger.Debugf("Wechat_V3_Url: %s", url)
+		}
+		return req.Patch(url).SendBodyMap(bm).EndBytes(ctx)
+	})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	si = &SignInfo{
+		Host:            host,
 		HeaderTimestamp: res.Header.Get(HeaderTimestamp),
 		HeaderNonce:     res.Header.Get(HeaderNonce),
 		HeaderSignature: res.Header.Get(HeaderSignature),
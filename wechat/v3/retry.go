@@ -0,0 +1,43 @@
+package wechat
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/w6xian/gopay/pkg/xhttp"
+)
+
+// retryErrCodes 微信支付 V3 可使用相同参数重试的业务错误码
+var retryErrCodes = map[string]bool{
+	"SYSTEM_ERROR":      true, // 系统超时等，请使用相同参数再次调用
+	"BANK_ERROR":        true, // 银行系统异常
+	"FREQUENCY_LIMITED": true, // 频率限制，降低频率后重试
+}
+
+// RetryClassifier 微信支付 V3 重试判断
+// 微信支付接口以 out_trade_no、out_refund_no 等业务单号保证幂等，按 xhttp.IdempotentRetryClassifier 重试网络错误与 HTTP 状态码，
+// 另外应答 code 为 SYSTEM_ERROR、BANK_ERROR、FREQUENCY_LIMITED 时重试
+func RetryClassifier(req *http.Request, res *http.Response, body []byte, err error) bool {
+	if xhttp.IdempotentRetryClassifier(req, res, body, err) {
+		return true
+	}
+	if err != nil || res == nil || res.StatusCode < http.StatusBadRequest || len(body) == 0 {
+		return false
+	}
+	rsp := new(struct {
+		Code string `json:"code"`
+	})
+	if json.Unmarshal(body, rsp) != nil {
+		return false
+	}
+	return retryErrCodes[rsp.Code]
+}
+
+func withClassifier(policy *xhttp.RetryPolicy) *xhttp.RetryPolicy {
+	if policy == nil || policy.Classifier != nil {
+		return policy
+	}
+	p := *policy
+	p.Classifier = RetryClassifier
+	return &p
+}
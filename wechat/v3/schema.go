@@ -0,0 +1,45 @@
+package wechat
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// 支付核心接口的 BodyMap 校验规则，由强类型请求参数（见 builder.go）的 validate 标签生成，对应方法默认不校验，可通过 client.SetValidateRequest(true) 开启请求前自动校验，或在调用前按需校验：wechat.SchemaTransactionJsapi.Validate(bm)
+// 错误中包含全部不合法的字段路径，如：[invalid parameter], amount.total: must be > 0; payer.openid: is required
+var (
+	// SchemaTransactionApp APP下单，用于 V3TransactionApp()
+	SchemaTransactionApp = gopay.SchemaOf(&PrepayRequest{})
+	// SchemaTransactionNative Native下单，用于 V3TransactionNative()
+	SchemaTransactionNative = SchemaTransactionApp
+	// SchemaTransactionJsapi JSAPI/小程序下单，用于 V3TransactionJsapi()
+	SchemaTransactionJsapi = gopay.SchemaOf(&JsapiRequest{})
+	// SchemaTransactionH5 H5下单，用于 V3TransactionH5()
+	SchemaTransactionH5 = gopay.SchemaOf(&H5Request{}).Require("scene_info", "scene_info.h5_info")
+
+	// SchemaPartnerTransactionApp （服务商、电商模式）APP下单
+	SchemaPartnerTransactionApp = SchemaTransactionApp.Omit("appid", "mchid").Extend(partnerFields()...)
+	// SchemaPartnerTransactionNative （服务商、电商模式）Native下单
+	SchemaPartnerTransactionNative = SchemaPartnerTransactionApp
+	// SchemaPartnerTransactionJsapi （服务商、电商模式）JSAPI/小程序下单
+	SchemaPartnerTransactionJsapi = SchemaPartnerTransactionApp.Extend(
+		gopay.Object("payer",
+			gopay.String("sp_openid").RequiredWithout("sub_openid").MaxLen(128),
+			gopay.String("sub_openid").MaxLen(128),
+		).Required(),
+	)
+	// SchemaPartnerTransactionH5 （服务商、电商模式）H5下单
+	SchemaPartnerTransactionH5 = SchemaTransactionH5.Omit("appid", "mchid").Extend(partnerFields()...)
+
+	// SchemaRefund 退款申请，用于 V3Refund()
+	SchemaRefund = gopay.SchemaOf(&RefundRequest{})
+)
+
+// partnerFields 服务商、电商模式下单的商户字段，替换 appid、mchid
+func partnerFields() []*gopay.Field {
+	return []*gopay.Field{
+		gopay.String("sp_appid").Required().MaxLen(32),
+		gopay.String("sp_mchid").Required().MaxLen(32),
+		gopay.String("sub_appid").MaxLen(32),
+		gopay.String("sub_mchid").Required().MaxLen(32),
+	}
+}
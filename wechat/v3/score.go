@@ -16,7 +16,7 @@ import (
 // Code = 0 is success
 // 注意：限制条件：【免确认订单模式】，用户已授权状态下，可调用该接口。
 func (c *ClientV3) V3ScoreDirectComplete(ctx context.Context, bm gopay.BodyMap) (wxRsp *ScoreDirectCompleteRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3ScoreDirectComplete, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ScoreDirectComplete, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +40,7 @@ func (c *ClientV3) V3ScoreDirectComplete(ctx context.Context, bm gopay.BodyMap)
 // 商户预授权API
 // Code = 0 is success
 func (c *ClientV3) V3ScorePermission(ctx context.Context, bm gopay.BodyMap) (wxRsp *ScorePermissionRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3ScorePermission, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ScorePermission, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +93,7 @@ func (c *ClientV3) V3ScorePermissionTerminate(ctx context.Context, authCode, ser
 	bm := make(gopay.BodyMap)
 	bm.Set("service_id", serviceId).
 		Set("reason", reason)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +144,7 @@ func (c *ClientV3) V3ScorePermissionOpenidTerminate(ctx context.Context, appid,
 	bm.Set("service_id", serviceid).
 		Set("appid", appid).
 		Set("reason", reason)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +165,7 @@ func (c *ClientV3) V3ScorePermissionOpenidTerminate(ctx context.Context, appid,
 // 创建支付分订单API
 // Code = 0 is success
 func (c *ClientV3) V3ScoreOrderCreate(ctx context.Context, bm gopay.BodyMap) (wxRsp *ScoreOrderCreateRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3ScoreOrderCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ScoreOrderCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +190,7 @@ func (c *ClientV3) V3ScoreOrderCreate(ctx context.Context, bm gopay.BodyMap) (wx
 // Code = 0 is success
 // 微信文档：https://pay.weixin.qq.com/wiki/doc/apiv3_partner/Offline/apis/chapter6_2_1.shtml
 func (c *ClientV3) V3ScoreOrderPartnerCreate(ctx context.Context, bm gopay.BodyMap) (*ScoreOrderPartnerCreateRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3ScoreOrderPartnerCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ScoreOrderPartnerCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -289,7 +289,7 @@ func (c *ClientV3) V3ScoreOrderCancel(ctx context.Context, appid, tradeNo, servi
 	bm.Set("appid", appid).
 		Set("service_id", serviceid).
 		Set("reason", reason)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -319,7 +319,7 @@ func (c *ClientV3) V3ScoreOrderPartnerCancel(ctx context.Context, subMchid, trad
 	bm.Set("sub_mchid", subMchid).
 		Set("service_id", serviceid).
 		Set("reason", reason)
-	authorization, err := c.authorization(MethodPost, path, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, path, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -341,7 +341,7 @@ func (c *ClientV3) V3ScoreOrderPartnerCancel(ctx context.Context, subMchid, trad
 // Code = 0 is success
 func (c *ClientV3) V3ScoreOrderModify(ctx context.Context, tradeNo string, bm gopay.BodyMap) (wxRsp *ScoreOrderModifyRsp, err error) {
 	uri := fmt.Sprintf(v3ScoreOrderModify, tradeNo)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +366,7 @@ func (c *ClientV3) V3ScoreOrderModify(ctx context.Context, tradeNo string, bm go
 // Code = 0 is success
 func (c *ClientV3) V3ScoreOrderComplete(ctx context.Context, tradeNo string, bm gopay.BodyMap) (wxRsp *ScoreOrderCompleteRsp, err error) {
 	uri := fmt.Sprintf(v3ScoreOrderComplete, tradeNo)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -392,7 +392,7 @@ func (c *ClientV3) V3ScoreOrderComplete(ctx context.Context, tradeNo string, bm
 // 微信文档: https://pay.weixin.qq.com/wiki/doc/apiv3_partner/Offline/apis/chapter6_2_5.shtml
 func (c *ClientV3) V3ScoreOrderPartnerComplete(ctx context.Context, tradeNo string, bm gopay.BodyMap) (*EmptyRsp, error) {
 	path := fmt.Sprintf(v3ScoreOrderPartnerComplete, tradeNo)
-	authorization, err := c.authorization(MethodPost, path, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, path, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -417,7 +417,7 @@ func (c *ClientV3) V3ScoreOrderPay(ctx context.Context, appid, tradeNo, servicei
 	bm := make(gopay.BodyMap)
 	bm.Set("appid", appid).
 		Set("service_id", serviceid)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -442,7 +442,7 @@ func (c *ClientV3) V3ScoreOrderPay(ctx context.Context, appid, tradeNo, servicei
 // Code = 0 is success
 func (c *ClientV3) V3ScoreOrderSync(ctx context.Context, tradeNo string, bm gopay.BodyMap) (wxRsp *ScoreOrderSyncRsp, err error) {
 	uri := fmt.Sprintf(v3ScoreOrderSync, tradeNo)
-	authorization, err := c.authorization(MethodPost, uri, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, uri, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,271 @@
+package wechat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/w6xian/gopay"
+)
+
+// SensitiveText 需加密的敏感信息明文，如姓名、证件号、银行卡号、手机号
+// 作为 BodyMap 参数值时，请求签名前自动使用微信平台证书加密到 BodyMap 副本，并设置对应的 Wechatpay-Serial 请求头，传入的 BodyMap 不变
+// 如：bm.Set("user_name", wechat.SensitiveText("张三"))
+type SensitiveText string
+
+// encryptedText 已加密的敏感信息，序列化为密文，serialNo 为加密使用的微信平台证书序列号
+type encryptedText struct {
+	serialNo   string
+	cipherText string
+}
+
+func (e *encryptedText) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.cipherText)
+}
+
+func (e *encryptedText) String() string {
+	return e.cipherText
+}
+
+// StructToBodyMap 结构体按 json 标签转换为 BodyMap，同 gopay.StructToBodyMap()
+// 标记 `sensitive:"true"` 的非空字符串字段转换为 SensitiveText，请求时自动加密
+// 如：UserName string `json:"user_name" sensitive:"true"`
+func StructToBodyMap(v any, extra gopay.BodyMap) (gopay.BodyMap, error) {
+	bm, err := gopay.StructToBodyMap(v, nil)
+	if err != nil {
+		return nil, err
+	}
+	markSensitive(reflect.ValueOf(v), bm)
+	for k, v := range extra {
+		bm.Set(k, v)
+	}
+	return bm, nil
+}
+
+// V3DecryptSensitive 解密结构体（指针）中标记 `sensitive:"true"` 的字符串字段，嵌套结构体及其切片递归解密
+// 已标记的返回参数：投诉人联系方式 payer_phone、转账收款方姓名 user_name 等，开启 SetAutoDecrypt(true) 后相应接口自动解密
+func (c *ClientV3) V3DecryptSensitive(v any) (err error) {
+	return walkSensitive(reflect.ValueOf(v), func(fv reflect.Value) error {
+		text, err := c.V3DecryptText(fv.String())
+		if err != nil {
+			return err
+		}
+		fv.SetString(text)
+		return nil
+	})
+}
+
+// decryptResponse 开启自动解密时，解密返回参数中的敏感信息
+func (c *ClientV3) decryptResponse(v any) error {
+	if !c.autoDecrypt {
+		return nil
+	}
+	return c.V3DecryptSensitive(v)
+}
+
+// encryptSensitive 加密 BodyMap 中的 SensitiveText，同一请求的敏感信息使用同一张微信平台证书加密
+// 包含敏感信息时返回加密后的副本（仅复制包含敏感信息的 BodyMap、切片），传入的 bm 不变；否则返回原 bm
+func (c *ClientV3) encryptSensitive(bm gopay.BodyMap) (gopay.BodyMap, error) {
+	if bm == nil {
+		return nil, nil
+	}
+	var pc *platformCert
+	v, _, err := walkBodyMap(bm, func(v any) (any, bool, error) {
+		text, ok := v.(SensitiveText)
+		if !ok {
+			return v, false, nil
+		}
+		var err error
+		if pc == nil {
+			if pc, err = c.encryptCert(); err != nil {
+				return nil, false, err
+			}
+		}
+		cipherText, err := pc.encrypt(string(text))
+		if err != nil {
+			return nil, false, err
+		}
+		return &encryptedText{serialNo: pc.serialNo, cipherText: cipherText}, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(gopay.BodyMap), nil
+}
+
+// wxSerialNo 请求头 Wechatpay-Serial：包含已加密的敏感信息时，使用加密所用的证书序列号
+func (c *ClientV3) wxSerialNo(bm gopay.BodyMap) string {
+	serialNo := gopay.NULL
+	_, _, _ = walkBodyMap(bm, func(v any) (any, bool, error) {
+		if e, ok := v.(*encryptedText); ok {
+			serialNo = e.serialNo
+		}
+		return v, false, nil
+	})
+	if serialNo != gopay.NULL {
+		return serialNo
+	}
+	return c.GetWxSerialNo()
+}
+
+// encryptCert 加密敏感信息使用的当前微信平台证书
+func (c *ClientV3) encryptCert() (*platformCert, error) {
+	if pc := c.cert.Load(); pc != nil && pc.publicKey != nil {
+		return pc, nil
+	}
+	return nil, errors.New("WxPublicKey or WxSerialNo is null")
+}
+
+// walkBodyMap 递归遍历 BodyMap 中的参数值，fn 返回值替换原参数值，changed 表示是否有参数值被替换
+// 不修改传入的值：有参数值被替换时，复制所在的 BodyMap、map、切片及其上层容器后返回
+func walkBodyMap(v any, fn func(v any) (nv any, changed bool, err error)) (any, bool, error) {
+	switch val := v.(type) {
+	case gopay.BodyMap:
+		return walkMap(val, fn)
+	case map[string]any:
+		return walkMap(val, fn)
+	case []gopay.BodyMap:
+		return walkSlice(val, fn)
+	case []map[string]any:
+		return walkSlice(val, fn)
+	case []any:
+		return walkSlice(val, fn)
+	}
+	return fn(v)
+}
+
+func walkMap[M ~map[string]any](m M, fn func(v any) (any, bool, error)) (M, bool, error) {
+	var out M
+	for k, item := range m {
+		nv, changed, err := walkBodyMap(item, fn)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed {
+			if out == nil {
+				out = maps.Clone(m)
+			}
+			out[k] = nv
+		}
+	}
+	if out == nil {
+		return m, false, nil
+	}
+	return out, true, nil
+}
+
+func walkSlice[S ~[]E, E any](s S, fn func(v any) (any, bool, error)) (S, bool, error) {
+	var out S
+	for i, item := range s {
+		nv, changed, err := walkBodyMap(item, fn)
+		if err != nil {
+			return nil, false, err
+		}
+		if changed {
+			if out == nil {
+				out = slices.Clone(s)
+			}
+			out[i] = nv.(E)
+		}
+	}
+	if out == nil {
+		return s, false, nil
+	}
+	return out, true, nil
+}
+
+// markSensitive 按结构体 sensitive 标签将 BodyMap 中对应的字段转换为 SensitiveText
+func markSensitive(rv reflect.Value, bm gopay.BodyMap) {
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf, fv := rt.Field(i), rv.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if sf.Anonymous && name == gopay.NULL {
+			markSensitive(fv, bm)
+			continue
+		}
+		if name == gopay.NULL {
+			name = sf.Name
+		}
+		item, ok := bm[name]
+		if !ok || !sf.IsExported() {
+			continue
+		}
+		if isSensitiveField(sf, fv) {
+			bm[name] = SensitiveText(fv.String())
+			continue
+		}
+		for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		switch val := item.(type) {
+		case gopay.BodyMap:
+			markSensitive(fv, val)
+		case []any:
+			if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+				continue
+			}
+			for j := 0; j < len(val) && j < fv.Len(); j++ {
+				if child, ok := val[j].(gopay.BodyMap); ok {
+					markSensitive(fv.Index(j), child)
+				}
+			}
+		}
+	}
+}
+
+// walkSensitive 遍历结构体中标记 sensitive 的非空字符串字段
+func walkSensitive(rv reflect.Value, fn func(fv reflect.Value) error) error {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf, fv := rt.Field(i), rv.Field(i)
+			if !sf.IsExported() && !sf.Anonymous {
+				continue
+			}
+			if isSensitiveField(sf, fv) {
+				if !fv.CanSet() {
+					return fmt.Errorf("sensitive field %s can't be set", sf.Name)
+				}
+				if err := fn(fv); err != nil {
+					return fmt.Errorf("%s: %w", sf.Name, err)
+				}
+				continue
+			}
+			if err := walkSensitive(fv, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := walkSensitive(rv.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isSensitiveField(sf reflect.StructField, fv reflect.Value) bool {
+	return sf.Tag.Get("sensitive") == "true" && fv.Kind() == reflect.String && fv.Len() > 0
+}
@@ -207,7 +207,21 @@ func (c *ClientV3) PaySignOfAppletScore(mchId, pkg string) (extraData *AppletSco
 	return extraData, nil
 }
 
-// v3 鉴权请求Header
+// v3 鉴权请求Header，返回加密敏感信息后的请求参数，请求 Body 及 Wechatpay-Serial 请求头须使用返回的 BodyMap
+// 参数中不包含 SensitiveText 时返回原 BodyMap，否则返回加密后的副本，传入的 bm 不变
+func (c *ClientV3) authorizationWithBody(method, path string, bm gopay.BodyMap) (gopay.BodyMap, string, error) {
+	bm, err := c.encryptSensitive(bm)
+	if err != nil {
+		return nil, "", err
+	}
+	authorization, err := c.authorization(method, path, bm)
+	if err != nil {
+		return nil, "", err
+	}
+	return bm, authorization, nil
+}
+
+// v3 鉴权请求Header，bm 中的敏感信息不会加密，包含 SensitiveText 时请使用 authorizationWithBody()
 func (c *ClientV3) authorization(method, path string, bm gopay.BodyMap) (string, error) {
 	var (
 		jb        = ""
@@ -258,8 +272,8 @@ func (c *ClientV3) verifySyncSign(si *SignInfo) (err error) {
 			// 直接报错，提示用户设置 微信支付公钥
 			return errors.New("auto verify sign, but wxPublicKey is nil, please call client.AutoVerifySignByPublicKey() set wxPublicKey and wxPublicKeyID")
 		}
-		// 老微信平台证书用户，尝试获取重新获取一次平台证书
-		err = c.AutoVerifySign(false)
+		// 老微信平台证书用户，尝试重新获取一次平台证书（多个请求同时触发时仅拉取一次）
+		err = c.certManager.refresh(c.ctx, true)
 		if err != nil {
 			return fmt.Errorf("[get all public key err]: %v", err)
 		}
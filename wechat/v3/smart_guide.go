@@ -11,10 +11,10 @@ import (
 )
 
 // 服务人员注册API
-// 注意：入参加密字段数据加密：client.V3EncryptText()
+// 注意：入参加密字段数据加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 // Code = 0 is success
 func (c *ClientV3) V3SmartGuideReg(ctx context.Context, bm gopay.BodyMap) (wxRsp *SmartGuideRegRsp, err error) {
-	authorization, err := c.authorization(MethodPost, v3GuideReg, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3GuideReg, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +41,7 @@ func (c *ClientV3) V3SmartGuideAssign(ctx context.Context, guideId, tradeNo stri
 	url := fmt.Sprintf(v3GuideAssign, guideId)
 	bm := make(gopay.BodyMap)
 	bm.Set("out_trade_no", tradeNo)
-	authorization, err := c.authorization(MethodPost, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, url, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +60,7 @@ func (c *ClientV3) V3SmartGuideAssign(ctx context.Context, guideId, tradeNo stri
 }
 
 // 服务人员查询API
-// 注意：入参加密字段数据加密：client.V3EncryptText()，返回参数加密字段解密：client.V3DecryptText()
+// 注意：入参加密字段数据加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密，返回参数加密字段解密：client.V3DecryptText()
 // Code = 0 is success
 func (c *ClientV3) V3SmartGuideQuery(ctx context.Context, bm gopay.BodyMap) (wxRsp *SmartGuideQueryRsp, err error) {
 	if err = bm.CheckEmptyError("store_id"); err != nil {
@@ -85,15 +85,18 @@ func (c *ClientV3) V3SmartGuideQuery(ctx context.Context, bm gopay.BodyMap) (wxR
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 服务人员信息更新API
-// 注意：入参加密字段数据加密：client.V3EncryptText()
+// 注意：入参加密字段数据加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 // Code = 0 is success
 func (c *ClientV3) V3SmartGuideUpdate(ctx context.Context, guideId string, bm gopay.BodyMap) (wxRsp *EmptyRsp, err error) {
 	url := fmt.Sprintf(v3GuideUpdate, guideId)
-	authorization, err := c.authorization(MethodPATCH, url, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPATCH, url, bm)
 	if err != nil {
 		return nil, err
 	}
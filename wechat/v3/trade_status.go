@@ -0,0 +1,25 @@
+package wechat
+
+import (
+	"github.com/w6xian/gopay"
+)
+
+// NormalizeTradeStatus 微信 trade_state 转统一订单状态
+// 适用于 client.V3TransactionQueryOrder() 返回及支付通知中的 trade_state
+func NormalizeTradeStatus(tradeState string) gopay.TradeStatus {
+	switch tradeState {
+	case TradeStateNoPay:
+		return gopay.TradeStatusPending
+	case TradeStatePaying:
+		return gopay.TradeStatusPaying
+	case TradeStateSuccess:
+		return gopay.TradeStatusSucceeded
+	case TradeStateClosed, TradeStateRevoked:
+		return gopay.TradeStatusClosed
+	case TradeStateRefund:
+		return gopay.TradeStatusRefunded
+	case TradeStatePayError:
+		return gopay.TradeStatusFailed
+	}
+	return gopay.TradeStatusUnknown
+}
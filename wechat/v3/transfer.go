@@ -11,10 +11,10 @@ import (
 )
 
 // 发起商家转账API
-// 注意：入参加密字段数据加密：client.V3EncryptText()
+// 注意：入参加密字段数据加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 // Code = 0 is success
 func (c *ClientV3) V3Transfer(ctx context.Context, bm gopay.BodyMap) (*TransferRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3Transfer, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3Transfer, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -36,10 +36,10 @@ func (c *ClientV3) V3Transfer(ctx context.Context, bm gopay.BodyMap) (*TransferR
 }
 
 // 发起批量转账API（服务商）
-// 注意：入参加密字段数据加密：client.V3EncryptText()
+// 注意：入参加密字段数据加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 // Code = 0 is success
 func (c *ClientV3) V3PartnerTransfer(ctx context.Context, bm gopay.BodyMap) (*TransferRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3PartnerTransfer, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3PartnerTransfer, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +136,10 @@ func (c *ClientV3) V3TransferDetail(ctx context.Context, batchId, detailId strin
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 微信明细单号查询明细单API（服务商）
@@ -161,7 +164,10 @@ func (c *ClientV3) V3PartnerTransferDetail(ctx context.Context, batchId, detailI
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 通过商家批次单号查询批次单
@@ -240,7 +246,10 @@ func (c *ClientV3) V3TransferMerchantDetail(ctx context.Context, outBatchNo, out
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 商家明细单号查询明细单API（服务商）
@@ -265,7 +274,10 @@ func (c *ClientV3) V3PartnerTransferMerchantDetail(ctx context.Context, outBatch
 	if err = json.Unmarshal(bs, wxRsp.Response); err != nil {
 		return nil, fmt.Errorf("[%w]: %v, bytes: %s", gopay.UnmarshalErr, err, string(bs))
 	}
-	return wxRsp, c.verifySyncSign(si)
+	if err = c.verifySyncSign(si); err != nil {
+		return wxRsp, err
+	}
+	return wxRsp, c.decryptResponse(wxRsp.Response)
 }
 
 // 转账账单电子回单申请受理接口
@@ -273,7 +285,7 @@ func (c *ClientV3) V3PartnerTransferMerchantDetail(ctx context.Context, outBatch
 func (c *ClientV3) V3TransferReceipt(ctx context.Context, outBatchNo string) (*TransferReceiptRsp, error) {
 	bm := make(gopay.BodyMap)
 	bm.Set("out_batch_no", outBatchNo)
-	authorization, err := c.authorization(MethodPost, v3TransferReceipt, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3TransferReceipt, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -322,7 +334,7 @@ func (c *ClientV3) V3TransferReceiptQuery(ctx context.Context, outBatchNo string
 // 转账明细电子回单受理API
 // Code = 0 is success
 func (c *ClientV3) V3TransferDetailReceipt(ctx context.Context, bm gopay.BodyMap) (*TransferDetailReceiptRsp, error) {
-	authorization, err := c.authorization(MethodPost, v3TransferDetailReceipt, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3TransferDetailReceipt, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -11,8 +11,9 @@ import (
 )
 
 // 发起转账
+// 注意：收款用户姓名 user_name 需加密：client.V3EncryptText() 或设置为 wechat.SensitiveText 自动加密
 func (c *ClientV3) V3TransferBills(ctx context.Context, bm gopay.BodyMap) (*TransferBillsRsp, error) {
-	authorization, err := c.authorization(MethodPost, V3TransferBills, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, V3TransferBills, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +108,7 @@ func (c *ClientV3) V3TransferBillsQuery(ctx context.Context, transferBillNo stri
 
 // 商户单号申请电子回单
 func (c *ClientV3) V3TransferElecsignMerchant(ctx context.Context, bm gopay.BodyMap) (*TransferElecsignMerchantRsp, error) {
-	authorization, err := c.authorization(MethodPost, V3TransferElecsignMerchant, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, V3TransferElecsignMerchant, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +131,7 @@ func (c *ClientV3) V3TransferElecsignMerchant(ctx context.Context, bm gopay.Body
 
 // 微信单号申请电子回单
 func (c *ClientV3) V3TransferElecsign(ctx context.Context, bm gopay.BodyMap) (*TransferElecsignRsp, error) {
-	authorization, err := c.authorization(MethodPost, V3TransferElecsign, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, V3TransferElecsign, bm)
 	if err != nil {
 		return nil, err
 	}
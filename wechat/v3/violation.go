@@ -15,7 +15,7 @@ import (
 func (c *ClientV3) V3ViolationNotifyUrlCreate(ctx context.Context, url string) (wxRsp *ViolationNotifyUrlRsp, err error) {
 	bm := make(gopay.BodyMap)
 	bm.Set("notify_url", url)
-	authorization, err := c.authorization(MethodPost, v3ViolationNotifyUrlCreate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3ViolationNotifyUrlCreate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (c *ClientV3) V3ViolationNotifyUrlQuery(ctx context.Context) (wxRsp *Violat
 func (c *ClientV3) V3ViolationNotifyUrlUpdate(ctx context.Context, url string) (wxRsp *ViolationNotifyUrlRsp, err error) {
 	bm := make(gopay.BodyMap)
 	bm.Set("notify_url", url)
-	authorization, err := c.authorization(MethodPut, v3ViolationNotifyUrlUpdate, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPut, v3ViolationNotifyUrlUpdate, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +19,7 @@ func (c *ClientV3) V3Withdraw(ctx context.Context, bm gopay.BodyMap) (*WithdrawR
 	if err := bm.CheckEmptyError("sub_mchid", "out_request_no", "amount"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3Withdraw, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3Withdraw, bm)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +82,7 @@ func (c *ClientV3) V3EcommerceWithdraw(ctx context.Context, bm gopay.BodyMap) (*
 	if err := bm.CheckEmptyError("out_request_no", "amount", "account_type"); err != nil {
 		return nil, err
 	}
-	authorization, err := c.authorization(MethodPost, v3EcommerceWithdraw, bm)
+	bm, authorization, err := c.authorizationWithBody(MethodPost, v3EcommerceWithdraw, bm)
 	if err != nil {
 		return nil, err
 	}